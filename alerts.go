@@ -16,7 +16,11 @@ limitations under the License.
 package main
 
 import (
+	"flag"
+	"fmt"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/newrelic/go-agent/v3/newrelic"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
@@ -28,15 +32,80 @@ import (
 	// +kubebuilder:scaffold:imports
 )
 
-func registerAlerts(mgr *ctrl.Manager, nrApp *newrelic.Application) error {
+// alertsOptions are the settings shared by all of the alerts controllers.
+type alertsOptions struct {
+	// ResyncInterval is how often resources are compared with New Relic, 0 disables it.
+	ResyncInterval time.Duration
+	// MaxConcurrentReconciles is the number of resources of each kind reconciled at the same time.
+	MaxConcurrentReconciles int
+	// KindConcurrentReconciles overrides MaxConcurrentReconciles for the kinds it holds a value
+	// above 0 for, by the lower case name of the kind.
+	KindConcurrentReconciles map[string]*int
+	// Ownership configures the markers of the objects the operator manages in New Relic.
+	Ownership controllers.Ownership
+	// Naming configures the names of the objects the operator manages in New Relic.
+	Naming controllers.Naming
+	// DryRun puts all resources in dry-run mode, as the dry-run annotation does for a single resource.
+	DryRun bool
+	// Webhooks configures the webhooks of the resources with an api_key.
+	Webhooks nrv1.WebhookOptions
+}
+
+// alertsKinds are the kinds reconciled by the alerts controllers.
+var alertsKinds = []string{
+	"NrqlAlertCondition",
+	"AlertsNrqlCondition",
+	"ApmAlertCondition",
+	"AlertsAPMCondition",
+	"Policy",
+	"AlertsChannel",
+	"AlertsPolicy",
+	"NewRelicAccount",
+}
+
+// addConcurrencyFlags adds a --max-concurrent-reconciles-<kind> flag for each of the alerts kinds.
+func (opts *alertsOptions) addConcurrencyFlags(flags *flag.FlagSet) {
+	opts.KindConcurrentReconciles = make(map[string]*int, len(alertsKinds))
+
+	for _, kind := range alertsKinds {
+		name := strings.ToLower(kind)
+		opts.KindConcurrentReconciles[name] = flags.Int("max-concurrent-reconciles-"+name, 0,
+			fmt.Sprintf("The number of %s resources that can be reconciled at the same time. Defaults to --max-concurrent-reconciles.", kind))
+	}
+}
+
+// concurrentReconciles returns the number of resources of kind reconciled at the same time.
+func (opts alertsOptions) concurrentReconciles(kind string) int {
+	if n, ok := opts.KindConcurrentReconciles[strings.ToLower(kind)]; ok && *n > 0 {
+		return *n
+	}
+
+	return opts.MaxConcurrentReconciles
+}
+
+func registerAlerts(mgr *ctrl.Manager, nrApp *newrelic.Application, opts alertsOptions) error {
+	if err := controllers.EvictClientsOnSecretChange(*mgr, interfaces.DefaultClientPool); err != nil {
+		setupLog.Error(err, "unable to watch secrets for the New Relic client pool")
+		os.Exit(1)
+	}
+
+	if err := controllers.RegisterResourceMetrics(*mgr); err != nil {
+		setupLog.Error(err, "unable to register resource metrics")
+		os.Exit(1)
+	}
 
 	// nrqlalertcondition
 	nrqlAlertConditionReconciler := &controllers.NrqlAlertConditionReconciler{
-		Client:          (*mgr).GetClient(),
-		Log:             ctrl.Log.WithName("controllers").WithName("NrqlAlertCondition"),
-		Scheme:          (*mgr).GetScheme(),
-		AlertClientFunc: interfaces.InitializeAlertsClient,
-		NewRelicAgent:   *nrApp,
+		Client:                  (*mgr).GetClient(),
+		Log:                     ctrl.Log.WithName("controllers").WithName("NrqlAlertCondition"),
+		Recorder:                controllers.WithDryRunEvents((*mgr).GetEventRecorderFor("nrqlalertcondition-controller"), opts.DryRun),
+		Scheme:                  (*mgr).GetScheme(),
+		AlertClientFunc:         interfaces.DefaultClientPool.AlertsClient,
+		NewRelicAgent:           *nrApp,
+		MaxConcurrentReconciles: opts.concurrentReconciles("NrqlAlertCondition"),
+		Ownership:               opts.Ownership,
+		Naming:                  opts.Naming,
+		DryRun:                  opts.DryRun,
 	}
 
 	if err := nrqlAlertConditionReconciler.SetupWithManager(*mgr); err != nil {
@@ -45,18 +114,24 @@ func registerAlerts(mgr *ctrl.Manager, nrApp *newrelic.Application) error {
 	}
 
 	nrqlAlertCondition := &nrv1.NrqlAlertCondition{}
-	if err := nrqlAlertCondition.SetupWebhookWithManager(*mgr); err != nil {
+	if err := nrqlAlertCondition.SetupWebhookWithManager(*mgr, opts.Webhooks); err != nil {
 		setupLog.Error(err, "unable to create webhook", "webhook", "NrqlAlertCondition")
 		os.Exit(1)
 	}
 
 	// alertsnrqlcondition
 	alertsNrqlConditionReconciler := &controllers.AlertsNrqlConditionReconciler{
-		Client:          (*mgr).GetClient(),
-		Log:             ctrl.Log.WithName("controllers").WithName("AlertsNrqlCondition"),
-		Scheme:          (*mgr).GetScheme(),
-		AlertClientFunc: interfaces.InitializeAlertsClient,
-		NewRelicAgent:   *nrApp,
+		Client:                  (*mgr).GetClient(),
+		Log:                     ctrl.Log.WithName("controllers").WithName("AlertsNrqlCondition"),
+		Recorder:                controllers.WithDryRunEvents((*mgr).GetEventRecorderFor("alertsnrqlcondition-controller"), opts.DryRun),
+		Scheme:                  (*mgr).GetScheme(),
+		AlertClientFunc:         interfaces.DefaultClientPool.AlertsClient,
+		NewRelicAgent:           *nrApp,
+		MaxConcurrentReconciles: opts.concurrentReconciles("AlertsNrqlCondition"),
+		Ownership:               opts.Ownership,
+		Naming:                  opts.Naming,
+		DryRun:                  opts.DryRun,
+		ResyncInterval:          opts.ResyncInterval,
 	}
 
 	if err := alertsNrqlConditionReconciler.SetupWithManager(*mgr); err != nil {
@@ -65,18 +140,23 @@ func registerAlerts(mgr *ctrl.Manager, nrApp *newrelic.Application) error {
 	}
 
 	alertsNrqlCondition := &nrv1.AlertsNrqlCondition{}
-	if err := alertsNrqlCondition.SetupWebhookWithManager(*mgr); err != nil {
+	if err := alertsNrqlCondition.SetupWebhookWithManager(*mgr, opts.Webhooks); err != nil {
 		setupLog.Error(err, "unable to create webhook", "webhook", "AlertsNrqlCondition")
 		os.Exit(1)
 	}
 
 	// apmalertcondition
 	apmReconciler := &controllers.ApmAlertConditionReconciler{
-		Client:          (*mgr).GetClient(),
-		Log:             ctrl.Log.WithName("controllers").WithName("ApmAlertCondition"),
-		Scheme:          (*mgr).GetScheme(),
-		AlertClientFunc: interfaces.InitializeAlertsClient,
-		NewRelicAgent:   *nrApp,
+		Client:                  (*mgr).GetClient(),
+		Log:                     ctrl.Log.WithName("controllers").WithName("ApmAlertCondition"),
+		Recorder:                controllers.WithDryRunEvents((*mgr).GetEventRecorderFor("apmalertcondition-controller"), opts.DryRun),
+		Scheme:                  (*mgr).GetScheme(),
+		AlertClientFunc:         interfaces.DefaultClientPool.AlertsClient,
+		NewRelicAgent:           *nrApp,
+		MaxConcurrentReconciles: opts.concurrentReconciles("ApmAlertCondition"),
+		Ownership:               opts.Ownership,
+		Naming:                  opts.Naming,
+		DryRun:                  opts.DryRun,
 	}
 
 	if err := apmReconciler.SetupWithManager(*mgr); err != nil {
@@ -85,18 +165,24 @@ func registerAlerts(mgr *ctrl.Manager, nrApp *newrelic.Application) error {
 	}
 
 	apmAlertCondition := &nrv1.ApmAlertCondition{}
-	if err := apmAlertCondition.SetupWebhookWithManager(*mgr); err != nil {
+	if err := apmAlertCondition.SetupWebhookWithManager(*mgr, opts.Webhooks); err != nil {
 		setupLog.Error(err, "unable to create webhook", "webhook", "ApmAlertCondition")
 		os.Exit(1)
 	}
 
 	// alertsapmcondition
 	alertsAPMReconciler := &controllers.AlertsAPMConditionReconciler{
-		Client:          (*mgr).GetClient(),
-		Log:             ctrl.Log.WithName("controllers").WithName("AlertsAPMCondition"),
-		Scheme:          (*mgr).GetScheme(),
-		AlertClientFunc: interfaces.InitializeAlertsClient,
-		NewRelicAgent:   *nrApp,
+		Client:                  (*mgr).GetClient(),
+		Log:                     ctrl.Log.WithName("controllers").WithName("AlertsAPMCondition"),
+		Recorder:                controllers.WithDryRunEvents((*mgr).GetEventRecorderFor("alertsapmcondition-controller"), opts.DryRun),
+		Scheme:                  (*mgr).GetScheme(),
+		AlertClientFunc:         interfaces.DefaultClientPool.AlertsClient,
+		NewRelicAgent:           *nrApp,
+		MaxConcurrentReconciles: opts.concurrentReconciles("AlertsAPMCondition"),
+		Ownership:               opts.Ownership,
+		Naming:                  opts.Naming,
+		DryRun:                  opts.DryRun,
+		ResyncInterval:          opts.ResyncInterval,
 	}
 
 	if err := alertsAPMReconciler.SetupWithManager(*mgr); err != nil {
@@ -105,18 +191,23 @@ func registerAlerts(mgr *ctrl.Manager, nrApp *newrelic.Application) error {
 	}
 
 	alertsAPMCondition := &nrv1.AlertsAPMCondition{}
-	if err := alertsAPMCondition.SetupWebhookWithManager(*mgr); err != nil {
+	if err := alertsAPMCondition.SetupWebhookWithManager(*mgr, opts.Webhooks); err != nil {
 		setupLog.Error(err, "unable to create webhook", "webhook", "AlertsAPMCondition")
 		os.Exit(1)
 	}
 
 	// policy
 	policyReconciler := &controllers.PolicyReconciler{
-		Client:          (*mgr).GetClient(),
-		Log:             ctrl.Log.WithName("controllers").WithName("Policy"),
-		Scheme:          (*mgr).GetScheme(),
-		AlertClientFunc: interfaces.InitializeAlertsClient,
-		NewRelicAgent:   *nrApp,
+		Client:                  (*mgr).GetClient(),
+		Log:                     ctrl.Log.WithName("controllers").WithName("Policy"),
+		Recorder:                controllers.WithDryRunEvents((*mgr).GetEventRecorderFor("policy-controller"), opts.DryRun),
+		Scheme:                  (*mgr).GetScheme(),
+		AlertClientFunc:         interfaces.DefaultClientPool.AlertsClient,
+		NewRelicAgent:           *nrApp,
+		MaxConcurrentReconciles: opts.concurrentReconciles("Policy"),
+		Ownership:               opts.Ownership,
+		Naming:                  opts.Naming,
+		DryRun:                  opts.DryRun,
 	}
 
 	if err := policyReconciler.SetupWithManager(*mgr); err != nil {
@@ -125,18 +216,24 @@ func registerAlerts(mgr *ctrl.Manager, nrApp *newrelic.Application) error {
 	}
 
 	policy := &nrv1.Policy{}
-	if err := policy.SetupWebhookWithManager(*mgr); err != nil {
+	if err := policy.SetupWebhookWithManager(*mgr, opts.Webhooks); err != nil {
 		setupLog.Error(err, "unable to create webhook", "webhook", "Policy")
 		os.Exit(1)
 	}
 
 	//alertsChannel
 	alertsChannelReconciler := &controllers.AlertsChannelReconciler{
-		Client:          (*mgr).GetClient(),
-		Log:             ctrl.Log.WithName("controllers").WithName("alertsChannel"),
-		Scheme:          (*mgr).GetScheme(),
-		AlertClientFunc: interfaces.InitializeAlertsClient,
-		NewRelicAgent:   *nrApp,
+		Client:                  (*mgr).GetClient(),
+		Log:                     ctrl.Log.WithName("controllers").WithName("alertsChannel"),
+		Recorder:                controllers.WithDryRunEvents((*mgr).GetEventRecorderFor("alertschannel-controller"), opts.DryRun),
+		Scheme:                  (*mgr).GetScheme(),
+		AlertClientFunc:         interfaces.DefaultClientPool.AlertsClient,
+		NewRelicAgent:           *nrApp,
+		MaxConcurrentReconciles: opts.concurrentReconciles("AlertsChannel"),
+		Ownership:               opts.Ownership,
+		Naming:                  opts.Naming,
+		DryRun:                  opts.DryRun,
+		ResyncInterval:          opts.ResyncInterval,
 	}
 
 	if err := alertsChannelReconciler.SetupWithManager(*mgr); err != nil {
@@ -145,18 +242,24 @@ func registerAlerts(mgr *ctrl.Manager, nrApp *newrelic.Application) error {
 	}
 
 	alertsChannel := &nrv1.AlertsChannel{}
-	if err := alertsChannel.SetupWebhookWithManager(*mgr); err != nil {
+	if err := alertsChannel.SetupWebhookWithManager(*mgr, opts.Webhooks); err != nil {
 		setupLog.Error(err, "unable to create webhook", "webhook", "AlertsChannel")
 		os.Exit(1)
 	}
 
 	// alertspolicy
 	alertsPolicyReconciler := &controllers.AlertsPolicyReconciler{
-		Client:          (*mgr).GetClient(),
-		Log:             ctrl.Log.WithName("controllers").WithName("AlertsPolicy"),
-		Scheme:          (*mgr).GetScheme(),
-		AlertClientFunc: interfaces.InitializeAlertsClient,
-		NewRelicAgent:   *nrApp,
+		Client:                  (*mgr).GetClient(),
+		Log:                     ctrl.Log.WithName("controllers").WithName("AlertsPolicy"),
+		Recorder:                controllers.WithDryRunEvents((*mgr).GetEventRecorderFor("alertspolicy-controller"), opts.DryRun),
+		Scheme:                  (*mgr).GetScheme(),
+		AlertClientFunc:         interfaces.DefaultClientPool.AlertsClient,
+		NewRelicAgent:           *nrApp,
+		MaxConcurrentReconciles: opts.concurrentReconciles("AlertsPolicy"),
+		Ownership:               opts.Ownership,
+		Naming:                  opts.Naming,
+		DryRun:                  opts.DryRun,
+		ResyncInterval:          opts.ResyncInterval,
 	}
 	if err := alertsPolicyReconciler.SetupWithManager(*mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "AlertsPolicy")
@@ -164,10 +267,26 @@ func registerAlerts(mgr *ctrl.Manager, nrApp *newrelic.Application) error {
 	}
 
 	alertsPolicy := &nrv1.AlertsPolicy{}
-	if err := alertsPolicy.SetupWebhookWithManager(*mgr); err != nil {
+	if err := alertsPolicy.SetupWebhookWithManager(*mgr, opts.Webhooks); err != nil {
 		setupLog.Error(err, "unable to create webhook", "webhook", "AlertsPolicy")
 		os.Exit(1)
 	}
 
+	// newrelicaccount
+	newRelicAccountReconciler := &controllers.NewRelicAccountReconciler{
+		Client:                  (*mgr).GetClient(),
+		Log:                     ctrl.Log.WithName("controllers").WithName("NewRelicAccount"),
+		Recorder:                controllers.WithDryRunEvents((*mgr).GetEventRecorderFor("newrelicaccount-controller"), opts.DryRun),
+		Scheme:                  (*mgr).GetScheme(),
+		AlertClientFunc:         interfaces.DefaultClientPool.AlertsClient,
+		NewRelicAgent:           *nrApp,
+		MaxConcurrentReconciles: opts.concurrentReconciles("NewRelicAccount"),
+		ResyncInterval:          opts.ResyncInterval,
+	}
+	if err := newRelicAccountReconciler.SetupWithManager(*mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "NewRelicAccount")
+		os.Exit(1)
+	}
+
 	return nil
 }
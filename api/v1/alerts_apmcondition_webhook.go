@@ -21,13 +21,12 @@ import (
 	"strings"
 
 	"github.com/newrelic/newrelic-client-go/pkg/alerts"
-	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
+	customErrors "github.com/newrelic/newrelic-kubernetes-operator/errors"
 	"github.com/newrelic/newrelic-kubernetes-operator/interfaces"
 )
 
@@ -36,9 +35,15 @@ var (
 	alertsapmconditionlog = logf.Log.WithName("alertsapmcondition-resource")
 )
 
-func (r *AlertsAPMCondition) SetupWebhookWithManager(mgr ctrl.Manager) error {
-	alertClientFunc = interfaces.InitializeAlertsClient
+func (r *AlertsAPMCondition) SetupWebhookWithManager(mgr ctrl.Manager, opts WebhookOptions) error {
+	alertClientFunc = interfaces.DefaultClientPool.AlertsClient
 	k8Client = mgr.GetClient()
+	policyReader = mgr.GetAPIReader()
+
+	if err := setupAPIKeyDefaulter(mgr, r, alertsapmconditionlog, opts); err != nil {
+		return err
+	}
+
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(r).
 		Complete()
@@ -46,7 +51,7 @@ func (r *AlertsAPMCondition) SetupWebhookWithManager(mgr ctrl.Manager) error {
 
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
 
-// +kubebuilder:webhook:path=/mutate-nr-k8s-newrelic-com-v1-alertsapmcondition,mutating=true,failurePolicy=fail,groups=nr.k8s.newrelic.com,resources=alertsapmconditions,verbs=create;update,versions=v1,name=malertsapmcondition.kb.io,sideEffects=None
+// +kubebuilder:webhook:path=/mutate-nr-k8s-newrelic-com-v1-alertsapmcondition,mutating=true,failurePolicy=fail,groups=nr.k8s.newrelic.com,resources=alertsapmconditions,verbs=create;update,versions=v1,name=malertsapmcondition.kb.io,sideEffects=NoneOnDryRun
 
 var _ webhook.Defaulter = &AlertsAPMCondition{}
 
@@ -58,6 +63,13 @@ func (r *AlertsAPMCondition) Default() {
 		alertsapmconditionlog.Info("Setting null Applied Spec to empty interface")
 		r.Status.AppliedSpec = &AlertsAPMConditionSpec{}
 	}
+
+	r.Status.AppliedSpec.APIKey = ""
+}
+
+// plaintextAPIKey implements apiKeyObject, the key is moved into a secret before Default.
+func (r *AlertsAPMCondition) plaintextAPIKey() (*string, *NewRelicAPIKeySecret) {
+	return &r.Spec.APIKey, &r.Spec.APIKeySecret
 }
 
 // +kubebuilder:webhook:verbs=create;update,path=/validate-nr-k8s-newrelic-com-v1-alertsapmcondition,mutating=false,failurePolicy=fail,groups=nr.k8s.newrelic.com,resources=alertsapmconditions,versions=v1,name=valertsapmcondition.kb.io,sideEffects=None
@@ -88,6 +100,22 @@ func (r *AlertsAPMCondition) ValidateCreate() error {
 	if len(invalidAttributes) > 0 {
 		return errors.New("error with invalid attributes: \n" + invalidAttributes.errorString())
 	}
+
+	err = CheckForPlaintextAPIKey(r, r.Spec.APIKey)
+	if err != nil {
+		return err
+	}
+
+	err = CheckDeletionPolicyAnnotation(r)
+	if err != nil {
+		return err
+	}
+
+	err = CheckAdoption(r.Spec.Adoption, r.Spec.ImportID)
+	if err != nil {
+		return err
+	}
+
 	return r.CheckExistingPolicyID()
 }
 
@@ -114,6 +142,31 @@ func (r *AlertsAPMCondition) ValidateUpdate(old runtime.Object) error {
 	if len(invalidAttributes) > 0 {
 		return errors.New("error with invalid attributes")
 	}
+
+	err = CheckForPlaintextAPIKey(r, r.Spec.APIKey)
+	if err != nil {
+		return err
+	}
+
+	var condition, previous AlertsPolicyCondition
+	condition.GenerateSpecFromApmConditionSpec(r.Spec)
+	previous.GenerateSpecFromApmConditionSpec(old.(*AlertsAPMCondition).Spec)
+
+	err = CheckPolicyOwnedEdit(r, condition, previous)
+	if err != nil {
+		return err
+	}
+
+	err = CheckDeletionPolicyAnnotation(r)
+	if err != nil {
+		return err
+	}
+
+	err = CheckAdoption(r.Spec.Adoption, r.Spec.ImportID)
+	if err != nil {
+		return err
+	}
+
 	return r.CheckExistingPolicyID()
 }
 
@@ -236,39 +289,38 @@ func (r *AlertsAPMCondition) ValidateUserDefinedValueFunction() InvalidAttribute
 }
 
 func (r *AlertsAPMCondition) CheckExistingPolicyID() error {
+	// the policy of a policy ref may not exist yet, the controller waits for it
+	if r.Spec.PolicyRef != nil {
+		return nil
+	}
+
 	alertsapmconditionlog.Info("Checking existing", "policyId", r.Spec.ExistingPolicyID)
 	ctx := context.Background()
-	var apiKey string
-	if r.Spec.APIKey == "" {
-		key := types.NamespacedName{Namespace: r.Spec.APIKeySecret.Namespace, Name: r.Spec.APIKeySecret.Name}
-		var apiKeySecret v1.Secret
-		getErr := k8Client.Get(ctx, key, &apiKeySecret)
-		if getErr != nil {
-			alertsapmconditionlog.Error(getErr, "Error getting secret")
-			return getErr
-		}
-		apiKey = string(apiKeySecret.Data[r.Spec.APIKeySecret.KeyName])
 
-	} else {
-		apiKey = r.Spec.APIKey
+	creds, err := r.Spec.Credentials().Resolve(ctx, k8Client, r.Namespace)
+	if err != nil {
+		alertsapmconditionlog.Error(err, "Error getting credentials")
+		return err
 	}
 
-	alertsClient, errAlertClient := alertClientFunc(apiKey, r.Spec.Region)
+	apiKey := creds.APIKey
+
+	alertsClient, errAlertClient := creds.AlertsClient(alertClientFunc)
 	if errAlertClient != nil {
 		alertsapmconditionlog.Error(errAlertClient, "failed to get policy",
 			"policyId", r.Spec.ExistingPolicyID,
 			"API Key", interfaces.PartialAPIKey(apiKey),
-			"accountID", r.Spec.AccountID,
-			"region", r.Spec.Region,
+			"accountID", creds.AccountID,
+			"region", creds.Region,
 		)
 		return errAlertClient
 	}
 
-	alertPolicy, errAlertPolicy := alertsClient.QueryPolicy(r.Spec.AccountID, r.Spec.ExistingPolicyID)
+	alertPolicy, errAlertPolicy := alertsClient.QueryPolicy(creds.AccountID, r.Spec.ExistingPolicyID)
 	if errAlertPolicy != nil {
 		if r.GetDeletionTimestamp() != nil {
 			alertsapmconditionlog.Info("Deleting resource", "errAlertPolicy", errAlertPolicy)
-			if strings.Contains(errAlertPolicy.Error(), "no alert policy found for id") {
+			if customErrors.IsNotFound(errAlertPolicy) {
 				log.Info("ExistingAlertPolicy not found but we are deleting the condition so this is ok")
 				return nil
 			}
@@ -276,9 +328,9 @@ func (r *AlertsAPMCondition) CheckExistingPolicyID() error {
 		alertsapmconditionlog.Error(errAlertPolicy, "failed to get policy",
 			"policyId", r.Spec.ExistingPolicyID,
 			"API Key", interfaces.PartialAPIKey(apiKey),
-			"region", r.Spec.Region,
+			"region", creds.Region,
 		)
-		return errAlertPolicy
+		return existingPolicyError(r.Spec.ExistingPolicyID, errAlertPolicy)
 	}
 
 	if alertPolicy.ID != r.Spec.ExistingPolicyID {
@@ -289,26 +341,21 @@ func (r *AlertsAPMCondition) CheckExistingPolicyID() error {
 }
 
 func (r *AlertsAPMCondition) CheckForAPIKeyOrSecret() error {
-	if r.Spec.APIKey != "" {
-		return nil
-	}
-	if r.Spec.APIKeySecret != (NewRelicAPIKeySecret{}) {
-		if r.Spec.APIKeySecret.Name != "" && r.Spec.APIKeySecret.Namespace != "" && r.Spec.APIKeySecret.KeyName != "" {
-			return nil
-		}
-	}
-	return errors.New("either api_key or api_key_secret must be set")
+	return r.Spec.Credentials().Check()
 }
 
 func (r *AlertsAPMCondition) CheckRequiredFields() error {
 
 	missingFields := []string{}
-	if r.Spec.Region == "" {
+	if r.Spec.Region == "" && r.Spec.AccountRef == nil {
 		missingFields = append(missingFields, "region")
 	}
-	if r.Spec.ExistingPolicyID == "" {
+	if r.Spec.ExistingPolicyID == "" && r.Spec.PolicyRef == nil {
 		missingFields = append(missingFields, "existing_policy_id")
 	}
+	if r.Spec.PolicyRef != nil && r.Spec.PolicyRef.Name == "" {
+		missingFields = append(missingFields, "policy_ref.name")
+	}
 	if len(missingFields) > 0 {
 		return errors.New(strings.Join(missingFields, " and ") + " must be set")
 	}
@@ -1,6 +1,7 @@
 package v1
 
 import (
+	"context"
 	"errors"
 	"time"
 
@@ -8,6 +9,7 @@ import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
 	"github.com/newrelic/newrelic-kubernetes-operator/interfaces"
 	"github.com/newrelic/newrelic-kubernetes-operator/interfaces/interfacesfakes"
@@ -28,7 +30,8 @@ var _ = Describe("alertsAPMCondition_webhook", func() {
 		alertClientFunc = fakeAlertFunc
 		r = AlertsAPMCondition{
 			ObjectMeta: v1.ObjectMeta{
-				Name: "test apm condition",
+				Name:      "test apm condition",
+				Namespace: "default",
 			},
 			Spec: AlertsAPMConditionSpec{
 				AlertsGenericConditionSpec{
@@ -63,6 +66,8 @@ var _ = Describe("alertsAPMCondition_webhook", func() {
 				ID: "46286",
 			}, nil
 		}
+
+		defaultAPIKeyObject(context.Background(), logf.Log, &r, false)
 	})
 
 	Context("ValidateCreate", func() {
@@ -29,6 +29,31 @@ type AlertsGenericConditionSpec struct {
 	Terms            []AlertsNrqlConditionTerm `json:"terms,omitempty"`
 	APMTerms         []AlertConditionTerm      `json:"apm_terms,omitempty"`
 	Type             alerts.NrqlConditionType  `json:"type,omitempty"`
+	// DriftPolicy is what to do when the condition was changed in New Relic, defaults to correct.
+	DriftPolicy DriftPolicy `json:"drift_policy,omitempty"`
+	// AccountRef refers to a NewRelicAccount with the credentials to use instead of api_key, region and account_id.
+	AccountRef *NewRelicAccountReference `json:"account_ref,omitempty"`
+	// PolicyRef refers to the AlertsPolicy to add the condition to instead of existing_policy_id.
+	// The ID of the policy is recorded in the applied spec in the status, not in existing_policy_id.
+	PolicyRef *AlertsPolicyReference `json:"policy_ref,omitempty"`
+	// DeletionPolicy is what to do with the condition in New Relic when this resource is deleted, defaults to Delete.
+	DeletionPolicy DeletionPolicy `json:"deletion_policy,omitempty"`
+	// ImportID is the ID of an existing condition in New Relic to adopt instead of creating a new one.
+	ImportID string `json:"import_id,omitempty"`
+	// Adoption is how an existing condition in New Relic is adopted when this resource has none yet,
+	// defaults to byID when import_id is set and to byNameIfUnowned otherwise.
+	Adoption AdoptionPolicy `json:"adoption,omitempty"`
+}
+
+// Credentials returns the credentials fields of the spec.
+func (in AlertsGenericConditionSpec) Credentials() Credentials {
+	return Credentials{
+		AccountRef:   in.AccountRef,
+		APIKey:       in.APIKey,
+		APIKeySecret: in.APIKeySecret,
+		Region:       in.Region,
+		AccountID:    in.AccountID,
+	}
 }
 
 type AlertsNrqlSpecificSpec struct {
@@ -73,12 +98,18 @@ type AlertsNrqlConditionTerm struct {
 
 // AlertsNrqlConditionStatus defines the observed state of AlertsNrqlCondition
 type AlertsNrqlConditionStatus struct {
+	ResourceStatus `json:",inline"`
+
 	AppliedSpec *AlertsNrqlConditionSpec `json:"applied_spec"`
 	ConditionID string                   `json:"condition_id"`
 }
 
 // +kubebuilder:object:root=true
-// +kubebuilder:printcolumn:name="Created",type="boolean",JSONPath=".status.created"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status"
+// +kubebuilder:printcolumn:name="Synced",type="string",JSONPath=".status.conditions[?(@.type==\"Synced\")].status"
+// +kubebuilder:printcolumn:name="Error",type="string",JSONPath=".status.conditions[?(@.type==\"Error\")].status"
+// +kubebuilder:printcolumn:name="Message",type="string",JSONPath=".status.conditions[?(@.type==\"Error\")].message",priority=1
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 
 // AlertsNrqlCondition is the Schema for the alertsnrqlconditions API
 type AlertsNrqlCondition struct {
@@ -102,6 +133,11 @@ func init() {
 	SchemeBuilder.Register(&AlertsNrqlCondition{}, &AlertsNrqlConditionList{})
 }
 
+// GetResourceStatus returns the status fields shared by all kinds.
+func (in *AlertsNrqlCondition) GetResourceStatus() *ResourceStatus {
+	return &in.Status.ResourceStatus
+}
+
 func (in AlertsNrqlConditionSpec) ToNrqlConditionInput() alerts.NrqlConditionInput {
 	conditionInput := alerts.NrqlConditionInput{}
 	conditionInput.Description = in.Description
@@ -20,9 +20,6 @@ import (
 	"errors"
 	"strings"
 
-	v1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/types"
-
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
@@ -36,9 +33,15 @@ var (
 	alertsNrqlConditionLog = logf.Log.WithName("alertsnrqlcondition-resource")
 )
 
-func (r *AlertsNrqlCondition) SetupWebhookWithManager(mgr ctrl.Manager) error {
-	alertClientFunc = interfaces.InitializeAlertsClient
+func (r *AlertsNrqlCondition) SetupWebhookWithManager(mgr ctrl.Manager, opts WebhookOptions) error {
+	alertClientFunc = interfaces.DefaultClientPool.AlertsClient
 	k8Client = mgr.GetClient()
+	policyReader = mgr.GetAPIReader()
+
+	if err := setupAPIKeyDefaulter(mgr, r, alertsNrqlConditionLog, opts); err != nil {
+		return err
+	}
+
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(r).
 		Complete()
@@ -46,7 +49,7 @@ func (r *AlertsNrqlCondition) SetupWebhookWithManager(mgr ctrl.Manager) error {
 
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
 
-// +kubebuilder:webhook:path=/mutate-nr-k8s-newrelic-com-v1-alertsnrqlcondition,mutating=true,failurePolicy=fail,groups=nr.k8s.newrelic.com,resources=alertsnrqlconditions,verbs=create;update,versions=v1,name=malertsnrqlcondition.kb.io,sideEffects=None
+// +kubebuilder:webhook:path=/mutate-nr-k8s-newrelic-com-v1-alertsnrqlcondition,mutating=true,failurePolicy=fail,groups=nr.k8s.newrelic.com,resources=alertsnrqlconditions,verbs=create;update,versions=v1,name=malertsnrqlcondition.kb.io,sideEffects=NoneOnDryRun
 
 var _ webhook.Defaulter = &AlertsNrqlCondition{}
 
@@ -58,9 +61,16 @@ func (r *AlertsNrqlCondition) Default() {
 		alertsNrqlConditionLog.Info("Setting null Applied Spec to empty interface")
 		r.Status.AppliedSpec = &AlertsNrqlConditionSpec{}
 	}
+
+	r.Status.AppliedSpec.APIKey = ""
 	alertsNrqlConditionLog.Info("r.Status.AppliedSpec after", "r.Status.AppliedSpec", r.Status.AppliedSpec)
 }
 
+// plaintextAPIKey implements apiKeyObject, the key is moved into a secret before Default.
+func (r *AlertsNrqlCondition) plaintextAPIKey() (*string, *NewRelicAPIKeySecret) {
+	return &r.Spec.APIKey, &r.Spec.APIKeySecret
+}
+
 // TODO(user): change verbs to "verbs=create;update;delete" if you want to enable deletion validation.
 // +kubebuilder:webhook:verbs=create;update,path=/validate-nr-k8s-newrelic-com-v1-alertsnrqlcondition,mutating=false,failurePolicy=fail,groups=nr.k8s.newrelic.com,resources=alertsnrqlconditions,versions=v1,name=valertsnrqlcondition.kb.io,sideEffects=None
 
@@ -69,7 +79,6 @@ var _ webhook.Validator = &AlertsNrqlCondition{}
 // ValidateCreate implements webhook.Validator so a webhook will be registered for the type
 func (r *AlertsNrqlCondition) ValidateCreate() error {
 	alertsNrqlConditionLog.Info("validate create", "name", r.Name)
-	//TODO this should write this value TO a new secret so code path always reads from a secret
 	err := r.CheckForAPIKeyOrSecret()
 	if err != nil {
 		return err
@@ -79,6 +88,22 @@ func (r *AlertsNrqlCondition) ValidateCreate() error {
 	if err != nil {
 		return err
 	}
+
+	err = CheckForPlaintextAPIKey(r, r.Spec.APIKey)
+	if err != nil {
+		return err
+	}
+
+	err = CheckDeletionPolicyAnnotation(r)
+	if err != nil {
+		return err
+	}
+
+	err = CheckAdoption(r.Spec.Adoption, r.Spec.ImportID)
+	if err != nil {
+		return err
+	}
+
 	return r.CheckExistingPolicyID()
 }
 
@@ -92,7 +117,26 @@ func (r *AlertsNrqlCondition) ValidateUpdate(old runtime.Object) error {
 		return errors.New("cannot change between condition types, you must delete and create a new alert")
 	}
 
-	return nil
+	err := CheckForPlaintextAPIKey(r, r.Spec.APIKey)
+	if err != nil {
+		return err
+	}
+
+	var condition, previous AlertsPolicyCondition
+	condition.GenerateSpecFromNrqlConditionSpec(r.Spec)
+	previous.GenerateSpecFromNrqlConditionSpec(prevCondition.Spec)
+
+	err = CheckPolicyOwnedEdit(r, condition, previous)
+	if err != nil {
+		return err
+	}
+
+	err = CheckDeletionPolicyAnnotation(r)
+	if err != nil {
+		return err
+	}
+
+	return CheckAdoption(r.Spec.Adoption, r.Spec.ImportID)
 }
 
 // ValidateDelete implements webhook.Validator so a webhook will be registered for the type
@@ -104,64 +148,59 @@ func (r *AlertsNrqlCondition) ValidateDelete() error {
 }
 
 func (r *AlertsNrqlCondition) CheckExistingPolicyID() error {
+	// the policy of a policy ref may not exist yet, the controller waits for it
+	if r.Spec.PolicyRef != nil {
+		return nil
+	}
+
 	alertsNrqlConditionLog.Info("Checking existing", "policyId", r.Spec.ExistingPolicyID)
-	var apiKey string
-	if r.Spec.APIKey == "" {
-		key := types.NamespacedName{Namespace: r.Spec.APIKeySecret.Namespace, Name: r.Spec.APIKeySecret.Name}
-		var apiKeySecret v1.Secret
-		getErr := k8Client.Get(context.Background(), key, &apiKeySecret)
-		if getErr != nil {
-			alertsNrqlConditionLog.Error(getErr, "Error getting secret")
-			return getErr
-		}
-		apiKey = string(apiKeySecret.Data[r.Spec.APIKeySecret.KeyName])
-
-	} else {
-		apiKey = r.Spec.APIKey
-	}
-
-	alertsClient, errAlertClient := alertClientFunc(apiKey, r.Spec.Region)
+	ctx := context.Background()
+
+	creds, err := r.Spec.Credentials().Resolve(ctx, k8Client, r.Namespace)
+	if err != nil {
+		alertsNrqlConditionLog.Error(err, "Error getting credentials")
+		return err
+	}
+
+	apiKey := creds.APIKey
+
+	alertsClient, errAlertClient := creds.AlertsClient(alertClientFunc)
 	if errAlertClient != nil {
 		alertsNrqlConditionLog.Error(errAlertClient, "failed to get policy",
 			"policyId", r.Spec.ExistingPolicyID,
 			"API Key", interfaces.PartialAPIKey(apiKey),
-			"region", r.Spec.Region,
+			"region", creds.Region,
 		)
 		return errAlertClient
 	}
-	_, errAlertPolicy := alertsClient.QueryPolicy(r.Spec.AccountID, r.Spec.ExistingPolicyID)
+	_, errAlertPolicy := alertsClient.QueryPolicy(creds.AccountID, r.Spec.ExistingPolicyID)
 	if errAlertPolicy != nil {
 		alertsNrqlConditionLog.Error(errAlertPolicy, "failed to get policy",
 			"policyId", r.Spec.ExistingPolicyID,
 			"API Key", interfaces.PartialAPIKey(apiKey),
-			"region", r.Spec.Region,
+			"region", creds.Region,
 		)
-		return errAlertPolicy
+		return existingPolicyError(r.Spec.ExistingPolicyID, errAlertPolicy)
 	}
 	return nil
 }
 
 func (r *AlertsNrqlCondition) CheckForAPIKeyOrSecret() error {
-	if r.Spec.APIKey != "" {
-		return nil
-	}
-	if r.Spec.APIKeySecret != (NewRelicAPIKeySecret{}) {
-		if r.Spec.APIKeySecret.Name != "" && r.Spec.APIKeySecret.Namespace != "" && r.Spec.APIKeySecret.KeyName != "" {
-			return nil
-		}
-	}
-	return errors.New("either api_key or api_key_secret must be set")
+	return r.Spec.Credentials().Check()
 }
 
 func (r *AlertsNrqlCondition) CheckRequiredFields() error {
 
 	missingFields := []string{}
-	if r.Spec.Region == "" {
+	if r.Spec.Region == "" && r.Spec.AccountRef == nil {
 		missingFields = append(missingFields, "region")
 	}
-	if r.Spec.ExistingPolicyID == "" {
+	if r.Spec.ExistingPolicyID == "" && r.Spec.PolicyRef == nil {
 		missingFields = append(missingFields, "existing_policy_id")
 	}
+	if r.Spec.PolicyRef != nil && r.Spec.PolicyRef.Name == "" {
+		missingFields = append(missingFields, "policy_ref.name")
+	}
 	if len(missingFields) > 0 {
 		return errors.New(strings.Join(missingFields, " and ") + " must be set")
 	}
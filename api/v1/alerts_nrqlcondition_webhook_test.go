@@ -8,6 +8,7 @@ import (
 
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
 	"github.com/newrelic/newrelic-kubernetes-operator/interfaces"
 
@@ -79,11 +80,22 @@ var _ = Describe("ValidateCreate", func() {
 		}
 	})
 
-	Context("When given a valid API key", func() {
-		It("should not return an error", func() {
+	Context("When given a plaintext API key", func() {
+		It("should move it into a secret", func() {
+			r.Namespace = "my-namespace"
+			defaultAPIKeyObject(context.Background(), logf.Log, &r, false)
+
+			Expect(r.Spec.APIKey).To(BeEmpty())
+			Expect(r.Spec.APIKeySecret.Namespace).To(Equal("my-namespace"))
+
 			err := r.ValidateCreate()
 			Expect(err).ToNot(HaveOccurred())
 		})
+
+		It("should return an error if the key was not moved", func() {
+			err := r.ValidateCreate()
+			Expect(err).To(HaveOccurred())
+		})
 	})
 
 	Context("When given an invalid API key", func() {
@@ -162,6 +174,25 @@ var _ = Describe("ValidateCreate", func() {
 		})
 	})
 
+	Context("when given a NRQL condition with a policy_ref instead of an ExistingPolicyId", func() {
+		BeforeEach(func() {
+			r.Spec.ExistingPolicyID = ""
+			r.Spec.PolicyRef = &AlertsPolicyReference{Name: "my-policy"}
+		})
+
+		It("should accept the condition without checking the policy in New Relic", func() {
+			Expect(r.CheckRequiredFields()).To(Succeed())
+			Expect(r.CheckExistingPolicyID()).To(Succeed())
+			Expect(alertsClient.QueryPolicyCallCount()).To(Equal(0))
+		})
+
+		It("should reject a policy_ref without a name", func() {
+			r.Spec.PolicyRef.Name = ""
+			err := r.CheckRequiredFields()
+			Expect(err).To(MatchError(errors.New("policy_ref.name must be set")))
+		})
+	})
+
 	Context("when updating an existing NRQL condition", func() {
 		Context("and changing the type from static to baseline", func() {
 			It("should fail validation", func() {
@@ -197,6 +228,24 @@ var _ = Describe("ValidateCreate", func() {
 				err := r.CheckExistingPolicyID()
 				Expect(err).To(Not(BeNil()))
 			})
+
+			It("says the API key was rejected", func() {
+				err := r.CheckExistingPolicyID()
+				Expect(err.Error()).To(HavePrefix("the API key was rejected while checking existing_policy_id 42"))
+			})
+		})
+
+		Context("With a policy that does not exist", func() {
+			BeforeEach(func() {
+				alertsClient.QueryPolicyStub = func(int, string) (*alerts.AlertsPolicy, error) {
+					return nil, errors.New("Not Found")
+				}
+			})
+
+			It("says the policy does not exist", func() {
+				err := r.CheckExistingPolicyID()
+				Expect(err.Error()).To(HavePrefix("existing_policy_id 42 does not exist in New Relic"))
+			})
 		})
 	})
 
@@ -17,9 +17,12 @@ package v1
 
 import (
 	"encoding/json"
-	"hash/fnv"
+	"fmt"
+	"reflect"
+	"strings"
 
 	"github.com/newrelic/newrelic-client-go/pkg/alerts"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 )
@@ -31,16 +34,40 @@ import (
 type AlertsPolicySpec struct {
 	IncidentPreference string                  `json:"incidentPreference,omitempty"`
 	Name               string                  `json:"name"`
-	Region             string                  `json:"region"`
+	Region             string                  `json:"region,omitempty"`
 	Conditions         []AlertsPolicyCondition `json:"conditions,omitempty"`
 	APIKey             string                  `json:"api_key,omitempty"`
 	APIKeySecret       NewRelicAPIKeySecret    `json:"api_key_secret,omitempty"`
 	AccountID          int                     `json:"account_id,omitempty"`
 	ChannelIDs         []int                   `json:"channel_ids,omitempty"`
+	// ChannelRefs refers to AlertsChannels to link to the policy, in addition to channel_ids.
+	ChannelRefs []AlertsChannelReference `json:"channel_refs,omitempty"`
+	// AccountRef refers to a NewRelicAccount with the credentials to use instead of api_key, region and account_id.
+	AccountRef *NewRelicAccountReference `json:"account_ref,omitempty"`
+	// DriftPolicy is what to do when the policy was changed in New Relic, defaults to correct.
+	DriftPolicy DriftPolicy `json:"drift_policy,omitempty"`
+	// DeletionPolicy is what to do with the policy in New Relic when this resource is deleted, defaults to Delete.
+	DeletionPolicy DeletionPolicy `json:"deletion_policy,omitempty"`
+	// ImportID is the ID of an existing policy in New Relic to adopt instead of creating a new one.
+	ImportID string `json:"import_id,omitempty"`
+	// Adoption is how an existing policy in New Relic is adopted when this resource has none yet,
+	// defaults to byID when import_id is set and to byNameIfUnowned otherwise.
+	Adoption AdoptionPolicy `json:"adoption,omitempty"`
+}
+
+// AlertsPolicyReference refers to an AlertsPolicy from another resource.
+type AlertsPolicyReference struct {
+	Name string `json:"name"`
+	// Namespace defaults to the namespace of the referring resource.
+	Namespace string `json:"namespace,omitempty"`
 }
 
 //AlertsPolicyCondition defined the conditions contained within an AlertsPolicy
 type AlertsPolicyCondition struct {
+	// Key identifies the condition among the conditions of the policy, so it is updated in place
+	// when any other field changes. Conditions without a key are matched by their spec, then by
+	// the name in their spec.
+	Key       string                    `json:"key,omitempty"`
 	Name      string                    `json:"name,omitempty"`
 	Namespace string                    `json:"namespace,omitempty"`
 	Spec      AlertsPolicyConditionSpec `json:"spec,omitempty"`
@@ -55,11 +82,62 @@ type AlertsPolicyConditionSpec struct {
 
 // AlertsPolicyStatus defines the observed state of AlertsPolicy
 type AlertsPolicyStatus struct {
+	ResourceStatus `json:",inline"`
+
 	AppliedSpec *AlertsPolicySpec `json:"applied_spec"`
 	PolicyID    string            `json:"policy_id"`
+	// ChannelIDs lists the channels linked to the policy, from channel_ids and the channels in
+	// channel_refs that were created in New Relic.
+	// +optional
+	ChannelIDs []int `json:"channel_ids,omitempty"`
+	// InlineConditions lists the state of the resource created for each condition of the policy.
+	// +optional
+	InlineConditions []InlineConditionStatus `json:"inline_conditions,omitempty"`
+	// ConditionNamesMigrated is true once the resources of the conditions created with a random
+	// name, by older versions of the operator, were renamed after the policy.
+	// +optional
+	ConditionNamesMigrated bool `json:"conditionNamesMigrated,omitempty"`
+}
+
+// States of the resources created for the conditions of an AlertsPolicy.
+const (
+	// InlineConditionReady is the state of a condition resource that is ready.
+	InlineConditionReady = "Ready"
+	// InlineConditionPending is the state of a condition resource its controller didn't reconcile yet.
+	InlineConditionPending = "Pending"
+	// InlineConditionFailed is the state of a condition resource that couldn't be written, or whose
+	// condition couldn't be written to New Relic.
+	InlineConditionFailed = "Failed"
+	// InlineConditionMissing is the state of a condition that has no resource.
+	InlineConditionMissing = "Missing"
+)
+
+// InlineConditionStatus is the state of the resource created for a condition of an AlertsPolicy.
+type InlineConditionStatus struct {
+	// Key of the condition in the policy, if it has one.
+	// +optional
+	Key string `json:"key,omitempty"`
+	// Name of the condition in its spec.
+	Name string `json:"name"`
+	// ConditionRef refers to the AlertsNrqlCondition or AlertsAPMCondition created for the condition.
+	// +optional
+	ConditionRef *corev1.ObjectReference `json:"condition_ref,omitempty"`
+	// ConditionID is the ID of the condition in New Relic.
+	// +optional
+	ConditionID string `json:"condition_id,omitempty"`
+	// State is Ready, Pending, Failed or Missing.
+	State string `json:"state"`
+	// LastError is the last error writing the condition resource or the condition in New Relic.
+	// +optional
+	LastError string `json:"last_error,omitempty"`
 }
 
 // +kubebuilder:object:root=true
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status"
+// +kubebuilder:printcolumn:name="Synced",type="string",JSONPath=".status.conditions[?(@.type==\"Synced\")].status"
+// +kubebuilder:printcolumn:name="Error",type="string",JSONPath=".status.conditions[?(@.type==\"Error\")].status"
+// +kubebuilder:printcolumn:name="Message",type="string",JSONPath=".status.conditions[?(@.type==\"Error\")].message",priority=1
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 
 // AlertsPolicy is the Schema for the policies API
 type AlertsPolicy struct {
@@ -83,6 +161,22 @@ func init() {
 	SchemeBuilder.Register(&AlertsPolicy{}, &AlertsPolicyList{})
 }
 
+// GetResourceStatus returns the status fields shared by all kinds.
+func (in *AlertsPolicy) GetResourceStatus() *ResourceStatus {
+	return &in.Status.ResourceStatus
+}
+
+// Credentials returns the credentials fields of the spec.
+func (in AlertsPolicySpec) Credentials() Credentials {
+	return Credentials{
+		AccountRef:   in.AccountRef,
+		APIKey:       in.APIKey,
+		APIKeySecret: in.APIKeySecret,
+		Region:       in.Region,
+		AccountID:    in.AccountID,
+	}
+}
+
 func (in AlertsPolicySpec) ToAlertsPolicy() alerts.AlertsPolicy {
 	jsonString, _ := json.Marshal(in)
 	var result alerts.AlertsPolicy
@@ -107,19 +201,27 @@ func (in AlertsPolicySpec) ToAlertsPolicyInput() alerts.AlertsPolicyInput {
 	return result
 }
 
-func (p *AlertsPolicyCondition) SpecHash() uint32 {
+// SpecIdentity returns the spec without the fields inherited from the policy as JSON, which is the
+// same for two conditions only when their specs are.
+func (p *AlertsPolicyCondition) SpecIdentity() string {
 	//remove api keys and condition from object to enable comparison minus inherited fields
-	strippedAlertsPolicy := AlertsPolicyCondition{
-		Spec: p.Spec,
-	}
-	strippedAlertsPolicy.Spec.APIKeySecret = NewRelicAPIKeySecret{}
-	strippedAlertsPolicy.Spec.APIKey = ""
-	strippedAlertsPolicy.Spec.Region = ""
-	strippedAlertsPolicy.Spec.ExistingPolicyID = ""
-	conditionTemplateSpecHasher := fnv.New32a()
-	DeepHashObject(conditionTemplateSpecHasher, strippedAlertsPolicy)
+	strippedSpec := p.Spec
+	strippedSpec.APIKeySecret = NewRelicAPIKeySecret{}
+	strippedSpec.APIKey = ""
+	strippedSpec.Region = ""
+	strippedSpec.AccountID = 0
+	strippedSpec.AccountRef = nil
+	strippedSpec.ExistingPolicyID = ""
+
+	jsonString, _ := json.Marshal(strippedSpec)
+
+	return string(jsonString)
+}
 
-	return conditionTemplateSpecHasher.Sum32()
+// identity returns the key and spec of the condition, which are the same for two conditions only
+// when both are.
+func (p *AlertsPolicyCondition) identity() string {
+	return p.Key + "/" + p.SpecIdentity()
 }
 
 func (p *AlertsPolicyCondition) GetNamespace() types.NamespacedName {
@@ -146,20 +248,28 @@ func (in AlertsPolicySpec) Equals(policyToCompare AlertsPolicySpec) bool {
 	if in.APIKeySecret != policyToCompare.APIKeySecret {
 		return false
 	}
+	if !reflect.DeepEqual(in.AccountRef, policyToCompare.AccountRef) {
+		return false
+	}
+	if in.DriftPolicy != policyToCompare.DriftPolicy {
+		return false
+	}
 	if len(in.Conditions) != len(policyToCompare.Conditions) {
 		return false
 	}
 
-	checkedHashes := make(map[uint32]bool)
+	checkedConditions := make(map[string]int)
 
 	for _, condition := range in.Conditions {
-		checkedHashes[condition.SpecHash()] = true
+		checkedConditions[condition.identity()]++
 	}
 
 	for _, conditionToCompare := range policyToCompare.Conditions {
-		if _, ok := checkedHashes[conditionToCompare.SpecHash()]; !ok {
+		if checkedConditions[conditionToCompare.identity()] == 0 {
 			return false
 		}
+
+		checkedConditions[conditionToCompare.identity()]--
 	}
 
 	if len(in.ChannelIDs) != len(policyToCompare.ChannelIDs) {
@@ -178,6 +288,22 @@ func (in AlertsPolicySpec) Equals(policyToCompare AlertsPolicySpec) bool {
 		}
 	}
 
+	if len(in.ChannelRefs) != len(policyToCompare.ChannelRefs) {
+		return false
+	}
+
+	checkedChannelRefs := make(map[AlertsChannelReference]bool)
+
+	for _, ref := range in.ChannelRefs {
+		checkedChannelRefs[ref] = true
+	}
+
+	for _, refToCompare := range policyToCompare.ChannelRefs {
+		if !checkedChannelRefs[refToCompare] {
+			return false
+		}
+	}
+
 	return true
 }
 
@@ -213,3 +339,49 @@ func (p *AlertsPolicyCondition) ReturnApmConditionSpec() (apmConditionSpec Alert
 
 	return
 }
+
+// maxNotReadyConditions is the number of conditions that aren't ready named in the Ready condition.
+const maxNotReadyConditions = 5
+
+// SetInlineConditions records the state of the resources of the conditions of the policy. A policy
+// that was synced is only ready once all of them are ready.
+func (in *AlertsPolicyStatus) SetInlineConditions(conditions []InlineConditionStatus) {
+	in.InlineConditions = conditions
+
+	ready := FindCondition(in.Conditions, ConditionReady)
+	if ready == nil || (ready.Status != metav1.ConditionTrue && ready.Reason != ReasonConditionsNotReady) {
+		return
+	}
+
+	var notReady []string
+
+	for _, condition := range conditions {
+		if condition.State != InlineConditionReady {
+			notReady = append(notReady, fmt.Sprintf("%s (%s)", condition.Name, condition.State))
+		}
+	}
+
+	if len(notReady) == 0 {
+		SetCondition(&in.Conditions, Condition{
+			Type:   ConditionReady,
+			Status: metav1.ConditionTrue,
+			Reason: ReasonSynced,
+		})
+
+		return
+	}
+
+	message := fmt.Sprintf("%d of %d conditions not ready: ", len(notReady), len(conditions))
+	if len(notReady) > maxNotReadyConditions {
+		message += strings.Join(notReady[:maxNotReadyConditions], ", ") + fmt.Sprintf(" and %d more", len(notReady)-maxNotReadyConditions)
+	} else {
+		message += strings.Join(notReady, ", ")
+	}
+
+	SetCondition(&in.Conditions, Condition{
+		Type:    ConditionReady,
+		Status:  metav1.ConditionFalse,
+		Reason:  ReasonConditionsNotReady,
+		Message: message,
+	})
+}
@@ -1,6 +1,8 @@
 package v1
 
 import (
+	"errors"
+
 	"github.com/newrelic/newrelic-client-go/pkg/alerts"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -179,6 +181,29 @@ var _ = Describe("Equals", func() {
 		})
 	})
 
+	Context("When condition keys don't match", func() {
+		It("should return false", func() {
+			keyedCondition := condition
+			keyedCondition.Key = "nrql-condition"
+			p.Conditions = []AlertsPolicyCondition{keyedCondition}
+
+			output = p.Equals(policyToCompare)
+			Expect(output).ToNot(BeTrue())
+		})
+	})
+
+	Context("When the same condition is listed twice in only one of them", func() {
+		It("should return false", func() {
+			otherCondition := condition
+			otherCondition.Spec.Name = "other condition"
+			p.Conditions = []AlertsPolicyCondition{condition, condition}
+			policyToCompare.Conditions = []AlertsPolicyCondition{condition, otherCondition}
+
+			output = p.Equals(policyToCompare)
+			Expect(output).ToNot(BeTrue())
+		})
+	})
+
 	Context("When different number of conditions exist", func() {
 		It("should return false", func() {
 			spec1 := AlertsPolicyConditionSpec{}
@@ -245,4 +270,72 @@ var _ = Describe("Equals", func() {
 			Expect(output).ToNot(BeTrue())
 		})
 	})
+
+	Context("When ChannelRefs are in another order", func() {
+		It("should return true", func() {
+			p.ChannelRefs = []AlertsChannelReference{{Name: "email"}, {Name: "slack", Namespace: "ops"}}
+			policyToCompare.ChannelRefs = []AlertsChannelReference{{Name: "slack", Namespace: "ops"}, {Name: "email"}}
+
+			output = p.Equals(policyToCompare)
+			Expect(output).To(BeTrue())
+		})
+	})
+
+	Context("When ChannelRefs don't match", func() {
+		It("should return false", func() {
+			p.ChannelRefs = []AlertsChannelReference{{Name: "email"}}
+			policyToCompare.ChannelRefs = []AlertsChannelReference{{Name: "email", Namespace: "ops"}}
+
+			output = p.Equals(policyToCompare)
+			Expect(output).ToNot(BeTrue())
+		})
+	})
+})
+
+var _ = Describe("SetInlineConditions", func() {
+	var (
+		status     AlertsPolicyStatus
+		conditions []InlineConditionStatus
+	)
+
+	BeforeEach(func() {
+		status = AlertsPolicyStatus{}
+		status.MarkSynced()
+
+		conditions = []InlineConditionStatus{
+			{Name: "High CPU", State: InlineConditionReady},
+			{Name: "Low memory", State: InlineConditionFailed, LastError: "422 response returned"},
+		}
+	})
+
+	It("marks a synced policy not ready while some of its conditions aren't", func() {
+		status.SetInlineConditions(conditions)
+
+		Expect(status.InlineConditions).To(Equal(conditions))
+		Expect(IsConditionTrue(status.Conditions, ConditionReady)).To(BeFalse())
+		Expect(IsConditionTrue(status.Conditions, ConditionSynced)).To(BeTrue())
+
+		ready := FindCondition(status.Conditions, ConditionReady)
+		Expect(ready.Reason).To(Equal(ReasonConditionsNotReady))
+		Expect(ready.Message).To(Equal("1 of 2 conditions not ready: Low memory (Failed)"))
+	})
+
+	It("marks the policy ready again once all of its conditions are", func() {
+		status.SetInlineConditions(conditions)
+
+		conditions[1].State = InlineConditionReady
+		status.SetInlineConditions(conditions)
+
+		Expect(IsConditionTrue(status.Conditions, ConditionReady)).To(BeTrue())
+		Expect(FindCondition(status.Conditions, ConditionReady).Reason).To(Equal(ReasonSynced))
+	})
+
+	It("keeps the reason of a policy that failed", func() {
+		status.MarkFailed(ReasonUpdateFailed, errors.New("oh no"))
+		conditions[1].State = InlineConditionReady
+		status.SetInlineConditions(conditions)
+
+		Expect(IsConditionTrue(status.Conditions, ConditionReady)).To(BeFalse())
+		Expect(FindCondition(status.Conditions, ConditionReady).Reason).To(Equal(ReasonUpdateFailed))
+	})
 })
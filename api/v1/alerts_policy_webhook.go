@@ -17,9 +17,11 @@ package v1
 
 import (
 	"errors"
+	"fmt"
 	"strings"
 
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
 	ctrl "sigs.k8s.io/controller-runtime"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
@@ -33,7 +35,13 @@ import (
 var AlertsPolicyLog = logf.Log.WithName("alerts-policy-resource")
 var defaultAlertsPolicyIncidentPreference = alerts.AlertsIncidentPreferenceTypes.PER_POLICY
 
-func (r *AlertsPolicy) SetupWebhookWithManager(mgr ctrl.Manager) error {
+func (r *AlertsPolicy) SetupWebhookWithManager(mgr ctrl.Manager, opts WebhookOptions) error {
+	k8Client = mgr.GetClient()
+
+	if err := setupAPIKeyDefaulter(mgr, r, AlertsPolicyLog, opts); err != nil {
+		return err
+	}
+
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(r).
 		Complete()
@@ -41,7 +49,7 @@ func (r *AlertsPolicy) SetupWebhookWithManager(mgr ctrl.Manager) error {
 
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
 
-// +kubebuilder:webhook:path=/mutate-nr-k8s-newrelic-com-v1-alertspolicy,mutating=true,failurePolicy=fail,groups=nr.k8s.newrelic.com,resources=alertspolicies,verbs=create;update,versions=v1,name=malertspolicy.kb.io,sideEffects=None
+// +kubebuilder:webhook:path=/mutate-nr-k8s-newrelic-com-v1-alertspolicy,mutating=true,failurePolicy=fail,groups=nr.k8s.newrelic.com,resources=alertspolicies,verbs=create;update,versions=v1,name=malertspolicy.kb.io,sideEffects=NoneOnDryRun
 
 var _ webhook.Defaulter = &AlertsPolicy{}
 
@@ -54,9 +62,16 @@ func (r *AlertsPolicy) Default() {
 		r.Status.AppliedSpec = &AlertsPolicySpec{}
 	}
 
+	r.Status.AppliedSpec.APIKey = ""
+
 	r.DefaultIncidentPreference()
 }
 
+// plaintextAPIKey implements apiKeyObject, the key is moved into a secret before Default.
+func (r *AlertsPolicy) plaintextAPIKey() (*string, *NewRelicAPIKeySecret) {
+	return &r.Spec.APIKey, &r.Spec.APIKeySecret
+}
+
 // TODO(user): change verbs to "verbs=create;update;delete" if you want to enable deletion validation.
 // +kubebuilder:webhook:verbs=create;update,path=/validate-nr-k8s-newrelic-com-v1-alertspolicy,mutating=false,failurePolicy=fail,groups=nr.k8s.newrelic.com,resources=alertspolicies,versions=v1,name=valertspolicy.kb.io,sideEffects=None
 
@@ -78,11 +93,41 @@ func (r *AlertsPolicy) ValidateCreate() error {
 		collectedErrors.Collect(err)
 	}
 
+	err = r.ValidateConditionKeys()
+	if err != nil {
+		collectedErrors.Collect(err)
+	}
+
+	err = r.CheckConditionPolicyRefs()
+	if err != nil {
+		collectedErrors.Collect(err)
+	}
+
+	err = r.CheckChannelRefs()
+	if err != nil {
+		collectedErrors.Collect(err)
+	}
+
 	err = r.ValidateIncidentPreference()
 	if err != nil {
 		collectedErrors.Collect(err)
 	}
 
+	err = CheckForPlaintextAPIKey(r, r.Spec.APIKey)
+	if err != nil {
+		collectedErrors.Collect(err)
+	}
+
+	err = CheckDeletionPolicyAnnotation(r)
+	if err != nil {
+		collectedErrors.Collect(err)
+	}
+
+	err = CheckAdoption(r.Spec.Adoption, r.Spec.ImportID)
+	if err != nil {
+		collectedErrors.Collect(err)
+	}
+
 	if len(*collectedErrors) > 0 {
 		AlertsPolicyLog.Info("Errors encountered validating policy", "collectedErrors", collectedErrors)
 		return collectedErrors
@@ -107,11 +152,41 @@ func (r *AlertsPolicy) ValidateUpdate(old runtime.Object) error {
 		collectedErrors.Collect(err)
 	}
 
+	err = r.ValidateConditionKeys()
+	if err != nil {
+		collectedErrors.Collect(err)
+	}
+
+	err = r.CheckConditionPolicyRefs()
+	if err != nil {
+		collectedErrors.Collect(err)
+	}
+
+	err = r.CheckChannelRefs()
+	if err != nil {
+		collectedErrors.Collect(err)
+	}
+
 	err = r.ValidateIncidentPreference()
 	if err != nil {
 		collectedErrors.Collect(err)
 	}
 
+	err = CheckForPlaintextAPIKey(r, r.Spec.APIKey)
+	if err != nil {
+		collectedErrors.Collect(err)
+	}
+
+	err = CheckDeletionPolicyAnnotation(r)
+	if err != nil {
+		collectedErrors.Collect(err)
+	}
+
+	err = CheckAdoption(r.Spec.Adoption, r.Spec.ImportID)
+	if err != nil {
+		collectedErrors.Collect(err)
+	}
+
 	if len(*collectedErrors) > 0 {
 		AlertsPolicyLog.Info("Errors encountered validating policy", "collectedErrors", collectedErrors)
 		return collectedErrors
@@ -140,16 +215,70 @@ func (r *AlertsPolicy) DefaultIncidentPreference() {
 	r.Spec.IncidentPreference = strings.ToUpper(r.Spec.IncidentPreference)
 }
 
+// CheckForDuplicateConditions returns an error when two conditions have the same key, or when two
+// conditions without a key have the same spec, as they could not be told apart.
 func (r *AlertsPolicy) CheckForDuplicateConditions() error {
-	var conditionHashMap = make(map[uint32]bool)
+	var conditionKeys = make(map[string]bool)
+
+	var conditionSpecs = make(map[string]bool)
+
+	for _, condition := range r.Spec.Conditions {
+		if condition.Key != "" {
+			if conditionKeys[condition.Key] {
+				AlertsPolicyLog.Info("duplicate condition key detected", "key", condition.Key)
+				return fmt.Errorf("duplicate condition key %q", condition.Key)
+			}
+
+			conditionKeys[condition.Key] = true
+
+			continue
+		}
+
+		if conditionSpecs[condition.SpecIdentity()] {
+			AlertsPolicyLog.Info("duplicate conditions detected", "conditionName", condition.Spec.Name)
+			return errors.New("duplicate conditions detected, set a key on each of them to keep both")
+		}
+
+		conditionSpecs[condition.SpecIdentity()] = true
+	}
+
+	return nil
+}
+
+// ValidateConditionKeys returns an error when the key of a condition can't be used in the name of
+// its condition resource.
+func (r *AlertsPolicy) ValidateConditionKeys() error {
+	for _, condition := range r.Spec.Conditions {
+		if condition.Key == "" {
+			continue
+		}
+
+		if errs := validation.IsDNS1123Label(condition.Key); len(errs) > 0 {
+			return fmt.Errorf("invalid condition key %q: %s", condition.Key, strings.Join(errs, ", "))
+		}
+	}
+
+	return nil
+}
 
+// CheckConditionPolicyRefs returns an error when a condition of the policy refers to a policy, as
+// its conditions are always added to the policy itself.
+func (r *AlertsPolicy) CheckConditionPolicyRefs() error {
 	for _, condition := range r.Spec.Conditions {
-		conditionHashMap[condition.SpecHash()] = true
+		if condition.Spec.PolicyRef != nil {
+			return fmt.Errorf("condition %q can't set policy_ref, the conditions of a policy are added to the policy", condition.Spec.Name)
+		}
 	}
 
-	if len(conditionHashMap) != len(r.Spec.Conditions) {
-		AlertsPolicyLog.Info("duplicate conditions detected or hash collision", "conditionHash", conditionHashMap)
-		return errors.New("duplicate conditions detected or hash collision")
+	return nil
+}
+
+// CheckChannelRefs returns an error when a channel ref of the policy doesn't name an AlertsChannel.
+func (r *AlertsPolicy) CheckChannelRefs() error {
+	for i, ref := range r.Spec.ChannelRefs {
+		if ref.Name == "" {
+			return fmt.Errorf("channel_refs[%d].name must be set", i)
+		}
 	}
 
 	return nil
@@ -167,15 +296,5 @@ func (r *AlertsPolicy) ValidateIncidentPreference() error {
 }
 
 func (r *AlertsPolicy) CheckForAPIKeyOrSecret() error {
-	if r.Spec.APIKey != "" {
-		return nil
-	}
-
-	if r.Spec.APIKeySecret != (NewRelicAPIKeySecret{}) {
-		if r.Spec.APIKeySecret.Name != "" && r.Spec.APIKeySecret.Namespace != "" && r.Spec.APIKeySecret.KeyName != "" {
-			return nil
-		}
-	}
-
-	return errors.New("either api_key or api_key_secret must be set")
+	return r.Spec.Credentials().Check()
 }
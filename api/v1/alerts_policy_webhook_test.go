@@ -7,6 +7,7 @@ import (
 
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
 	"github.com/newrelic/newrelic-kubernetes-operator/interfaces"
 
@@ -37,7 +38,11 @@ var _ = Describe("AlertsPolicy_webhooks", func() {
 				Spec: AlertsPolicySpec{
 					Name:               "Test AlertsPolicy",
 					IncidentPreference: "PER_POLICY",
-					APIKey:             "api-key",
+					APIKeySecret: NewRelicAPIKeySecret{
+						Name:      "my-api-key-secret",
+						Namespace: "my-namespace",
+						KeyName:   "my-api-key",
+					},
 				},
 			}
 
@@ -49,25 +54,40 @@ var _ = Describe("AlertsPolicy_webhooks", func() {
 			}
 		})
 
-		Context("When given a valid API key", func() {
-			It("should not return an error", func() {
+		Context("When given a plaintext API key", func() {
+			BeforeEach(func() {
+				r.Spec.APIKey = "api-key"
+				r.Spec.APIKeySecret = NewRelicAPIKeySecret{}
+			})
+
+			It("should move it into a secret", func() {
+				r.Namespace = "default"
+				defaultAPIKeyObject(context.Background(), logf.Log, &r, false)
+
+				Expect(r.Spec.APIKey).To(BeEmpty())
+				Expect(r.Spec.APIKeySecret.Namespace).To(Equal("default"))
+
 				err := r.ValidateCreate()
 				Expect(err).ToNot(HaveOccurred())
 			})
+
+			It("should return an error if the key was not moved", func() {
+				err := r.ValidateCreate()
+				Expect(err).To(HaveOccurred())
+			})
 		})
 
 		Context("When given an invalid API key", func() {
 			It("should return an error", func() {
-				r.Spec.APIKey = ""
+				r.Spec.APIKeySecret = NewRelicAPIKeySecret{}
 				err := r.ValidateCreate()
 				Expect(err).To(HaveOccurred())
-				Expect(err.Error()).To(Equal("either api_key or api_key_secret must be set"))
+				Expect(err.Error()).To(Equal("either api_key, api_key_secret or account_ref must be set"))
 			})
 		})
 
 		Context("when given a valid API key in a secret", func() {
 			It("should not return an error", func() {
-				r.Spec.APIKey = ""
 				r.Spec.APIKeySecret = NewRelicAPIKeySecret{
 					Name:      "my-api-key-secret",
 					Namespace: "my-namespace",
@@ -162,16 +182,72 @@ var _ = Describe("AlertsPolicy_webhooks", func() {
 			It("should reject the policy", func() {
 				err := r.ValidateCreate()
 				Expect(err).To(HaveOccurred())
-				Expect(err.Error()).To(Equal("duplicate conditions detected or hash collision"))
+				Expect(err.Error()).To(Equal("duplicate conditions detected, set a key on each of them to keep both"))
+			})
+
+			Context("with a different key on each of them", func() {
+				It("should accept the policy", func() {
+					r.Spec.Conditions[0].Key = "first"
+					r.Spec.Conditions[1].Key = "second"
+
+					Expect(r.ValidateCreate()).To(Succeed())
+				})
+			})
+
+			Context("with the same key on both of them", func() {
+				It("should reject the policy", func() {
+					r.Spec.Conditions[0].Key = "first"
+					r.Spec.Conditions[1].Key = "first"
+					r.Spec.Conditions[1].Spec.Name = "Other NRQL Condition"
+
+					err := r.ValidateCreate()
+					Expect(err).To(HaveOccurred())
+					Expect(err.Error()).To(Equal(`duplicate condition key "first"`))
+				})
+			})
+
+			Context("with a key that can't be part of a resource name", func() {
+				It("should reject the policy", func() {
+					r.Spec.Conditions[0].Key = "First_Condition"
+					r.Spec.Conditions[1].Key = "second"
+
+					err := r.ValidateCreate()
+					Expect(err).To(HaveOccurred())
+					Expect(err.Error()).To(ContainSubstring(`invalid condition key "First_Condition"`))
+				})
+			})
+
+			Context("with a policy_ref on a condition", func() {
+				It("should reject the policy", func() {
+					r.Spec.Conditions[0].Key = "first"
+					r.Spec.Conditions[1].Key = "second"
+					r.Spec.Conditions[0].Spec.PolicyRef = &AlertsPolicyReference{Name: "other-policy"}
+
+					err := r.ValidateCreate()
+					Expect(err).To(HaveOccurred())
+					Expect(err.Error()).To(ContainSubstring("can't set policy_ref"))
+				})
+			})
+
+			Context("with a channel ref without a name", func() {
+				It("should reject the policy", func() {
+					r.Spec.Conditions[0].Key = "first"
+					r.Spec.Conditions[1].Key = "second"
+					r.Spec.ChannelRefs = []AlertsChannelReference{{Name: "my-channel"}, {Namespace: "other"}}
+
+					err := r.ValidateCreate()
+					Expect(err).To(HaveOccurred())
+					Expect(err.Error()).To(ContainSubstring("channel_refs[1].name must be set"))
+				})
 			})
 
 			Context("and invalid API key and incident_preference", func() {
 				It("should include all errors", func() {
 					r.Spec.IncidentPreference = "totally bogus"
-					r.Spec.APIKey = ""
+					r.Spec.APIKeySecret = NewRelicAPIKeySecret{}
 					err := r.ValidateCreate()
 					Expect(err).To(HaveOccurred())
-					Expect(err.Error()).To(ContainSubstring("either api_key or api_key_secret must be set"))
+					Expect(err.Error()).To(ContainSubstring("either api_key, api_key_secret or account_ref must be set"))
 					Expect(err.Error()).To(ContainSubstring("duplicate conditions detected"))
 					Expect(err.Error()).To(ContainSubstring("incident preference must be"))
 				})
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -22,6 +23,30 @@ type AlertsChannelSpec struct {
 	Type          string                     `json:"type,omitempty"`
 	Links         ChannelLinks               `json:"links,omitempty"`
 	Configuration AlertsChannelConfiguration `json:"configuration,omitempty"`
+	// DriftPolicy is what to do when the channel was changed in New Relic, defaults to correct.
+	DriftPolicy DriftPolicy `json:"drift_policy,omitempty"`
+	// AccountRef refers to a NewRelicAccount with the credentials to use instead of api_key and region.
+	AccountRef *NewRelicAccountReference `json:"account_ref,omitempty"`
+	// DeletionPolicy is what to do with the channel in New Relic when this resource is deleted, defaults to Delete.
+	DeletionPolicy DeletionPolicy `json:"deletion_policy,omitempty"`
+	// ImportID is the ID of an existing channel in New Relic to adopt instead of creating a new one.
+	ImportID string `json:"import_id,omitempty"`
+	// Adoption is how an existing channel in New Relic is adopted when this resource has none yet,
+	// defaults to byID when import_id is set and to byNameIfUnowned otherwise.
+	Adoption AdoptionPolicy `json:"adoption,omitempty"`
+	// PolicySelector links the channel to the AlertsPolicies with matching labels, in addition to
+	// the policies in links.
+	PolicySelector *AlertsPolicySelector `json:"policy_selector,omitempty"`
+}
+
+// Credentials returns the credentials fields of the spec.
+func (in AlertsChannelSpec) Credentials() Credentials {
+	return Credentials{
+		AccountRef:   in.AccountRef,
+		APIKey:       in.APIKey,
+		APIKeySecret: in.APIKeySecret,
+		Region:       in.Region,
+	}
 }
 
 // ChannelLinks - copy of alerts.ChannelLinks
@@ -31,11 +56,57 @@ type ChannelLinks struct {
 	PolicyKubernetesObjects []metav1.ObjectMeta `json:"policy_kubernetes_objects,omitempty"`
 }
 
+// AlertsPolicySelector selects AlertsPolicies by their labels.
+type AlertsPolicySelector struct {
+	metav1.LabelSelector `json:",inline"`
+	// NamespaceSelector selects the namespaces of the policies by their labels, an empty selector
+	// selects all namespaces. Defaults to the namespace of the channel.
+	NamespaceSelector *metav1.LabelSelector `json:"namespace_selector,omitempty"`
+}
+
+// Selectors returns the selector of the labels of the policies and the selector of the labels of
+// their namespaces, which is nil when only the namespace of the channel is selected.
+func (in *AlertsPolicySelector) Selectors() (labels.Selector, labels.Selector, error) {
+	policySelector, err := metav1.LabelSelectorAsSelector(&in.LabelSelector)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if in.NamespaceSelector == nil {
+		return policySelector, nil, nil
+	}
+
+	namespaceSelector, err := metav1.LabelSelectorAsSelector(in.NamespaceSelector)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return policySelector, namespaceSelector, nil
+}
+
+// AlertsChannelReference refers to an AlertsChannel from another resource.
+type AlertsChannelReference struct {
+	Name string `json:"name"`
+	// Namespace defaults to the namespace of the referring resource.
+	Namespace string `json:"namespace,omitempty"`
+}
+
 // AlertsChannelStatus defines the observed state of AlertsChannel
 type AlertsChannelStatus struct {
+	ResourceStatus `json:",inline"`
+
 	AppliedSpec      *AlertsChannelSpec `json:"applied_spec"`
 	ChannelID        int                `json:"channel_id"`
 	AppliedPolicyIDs []int              `json:"appliedPolicyIDs"`
+	// AppliedHeadersHash is a hash of the headers last sent to New Relic, including the values
+	// read from secrets, so a changed header secret can be noticed without storing its value.
+	AppliedHeadersHash string `json:"appliedHeadersHash,omitempty"`
+	// SelectedPolicyIDs are the IDs of the policies last selected by the policy selector, so
+	// changes to the labels of policies can be noticed.
+	SelectedPolicyIDs []int `json:"selectedPolicyIDs,omitempty"`
+	// CarriedPolicyIDs are the IDs of the policies the channel was linked to in New Relic before it
+	// was created again, that are not in its spec. The new channel is linked to them as well.
+	CarriedPolicyIDs []int `json:"carriedPolicyIDs,omitempty"`
 }
 
 type ChannelHeader struct {
@@ -72,7 +143,11 @@ type AlertsChannelConfiguration struct {
 }
 
 // +kubebuilder:object:root=true
-// +kubebuilder:printcolumn:name="Created",type="boolean",JSONPath=".status.created"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status"
+// +kubebuilder:printcolumn:name="Synced",type="string",JSONPath=".status.conditions[?(@.type==\"Synced\")].status"
+// +kubebuilder:printcolumn:name="Error",type="string",JSONPath=".status.conditions[?(@.type==\"Error\")].status"
+// +kubebuilder:printcolumn:name="Message",type="string",JSONPath=".status.conditions[?(@.type==\"Error\")].message",priority=1
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 
 // AlertsChannel is the Schema for the AlertsChannel API
 type AlertsChannel struct {
@@ -96,6 +171,11 @@ func init() {
 	SchemeBuilder.Register(&AlertsChannel{}, &AlertsChannelList{})
 }
 
+// GetResourceStatus returns the status fields shared by all kinds.
+func (in *AlertsChannel) GetResourceStatus() *ResourceStatus {
+	return &in.Status.ResourceStatus
+}
+
 func getSecret(name types.NamespacedName, key string, k8sClient client.Client) (string, error) {
 	var apiKeySecret v1.Secret
 
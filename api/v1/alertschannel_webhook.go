@@ -17,6 +17,7 @@ package v1
 
 import (
 	"errors"
+	"fmt"
 
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -34,9 +35,14 @@ var (
 )
 
 // SetupWebhookWithManager - instantiates the Webhook
-func (r *AlertsChannel) SetupWebhookWithManager(mgr ctrl.Manager) error {
-	alertClientFunc = interfaces.InitializeAlertsClient
+func (r *AlertsChannel) SetupWebhookWithManager(mgr ctrl.Manager, opts WebhookOptions) error {
+	alertClientFunc = interfaces.DefaultClientPool.AlertsClient
 	k8Client = mgr.GetClient()
+
+	if err := setupAPIKeyDefaulter(mgr, r, alertschannellog, opts); err != nil {
+		return err
+	}
+
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(r).
 		Complete()
@@ -44,7 +50,7 @@ func (r *AlertsChannel) SetupWebhookWithManager(mgr ctrl.Manager) error {
 
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
 
-// +kubebuilder:webhook:path=/mutate-nr-k8s-newrelic-com-v1-alertschannel,mutating=true,failurePolicy=fail,groups=nr.k8s.newrelic.com,resources=alertschannels,verbs=create;update,versions=v1,name=malertschannel.kb.io,sideEffects=None
+// +kubebuilder:webhook:path=/mutate-nr-k8s-newrelic-com-v1-alertschannel,mutating=true,failurePolicy=fail,groups=nr.k8s.newrelic.com,resources=alertschannels,verbs=create;update,versions=v1,name=malertschannel.kb.io,sideEffects=NoneOnDryRun
 
 var _ webhook.Defaulter = &AlertsChannel{}
 
@@ -57,12 +63,19 @@ func (r *AlertsChannel) Default() {
 		r.Status.AppliedSpec = &AlertsChannelSpec{}
 	}
 
+	r.Status.AppliedSpec.APIKey = ""
+
 	if r.Status.AppliedPolicyIDs == nil {
 		log.Info("Setting null AppliedPolicyIDs to empty interface")
 		r.Status.AppliedPolicyIDs = []int{}
 	}
 }
 
+// plaintextAPIKey implements apiKeyObject, the key is moved into a secret before Default.
+func (r *AlertsChannel) plaintextAPIKey() (*string, *NewRelicAPIKeySecret) {
+	return &r.Spec.APIKey, &r.Spec.APIKeySecret
+}
+
 // +kubebuilder:webhook:verbs=create;update,path=/validate-nr-k8s-newrelic-com-v1-alertschannel,mutating=false,failurePolicy=fail,groups=nr.k8s.newrelic.com,resources=alertschannels,versions=v1,name=valertschannel.kb.io,sideEffects=None
 
 var _ webhook.Validator = &AlertsChannel{}
@@ -91,12 +104,33 @@ func (r *AlertsChannel) ValidateDelete() error {
 
 // ValidateAlertsChannel - Validates create/update of AlertsChannel
 func (r *AlertsChannel) ValidateAlertsChannel() error {
-	err := CheckForAPIKeyOrSecret(r.Spec.APIKey, r.Spec.APIKeySecret)
+	err := r.Spec.Credentials().Check()
+	if err != nil {
+		return err
+	}
+
+	err = CheckForPlaintextAPIKey(r, r.Spec.APIKey)
 	if err != nil {
 		return err
 	}
 
-	if !ValidRegion(r.Spec.Region) {
+	err = CheckDeletionPolicyAnnotation(r)
+	if err != nil {
+		return err
+	}
+
+	err = CheckAdoption(r.Spec.Adoption, r.Spec.ImportID)
+	if err != nil {
+		return err
+	}
+
+	if r.Spec.PolicySelector != nil {
+		if _, _, err := r.Spec.PolicySelector.Selectors(); err != nil {
+			return fmt.Errorf("invalid policy_selector: %v", err)
+		}
+	}
+
+	if r.Spec.AccountRef == nil && !ValidRegion(r.Spec.Region) {
 		return errors.New("Invalid region set, value was: " + r.Spec.Region)
 	}
 
@@ -1,10 +1,13 @@
 package v1
 
 import (
+	"context"
+
 	"github.com/newrelic/newrelic-client-go/pkg/alerts"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
 	"github.com/newrelic/newrelic-kubernetes-operator/interfaces"
 	"github.com/newrelic/newrelic-kubernetes-operator/interfaces/interfacesfakes"
@@ -25,7 +28,8 @@ var _ = Describe("AlertsChannel_webhook", func() {
 		alertClientFunc = fakeAlertFunc
 		r = AlertsChannel{
 			ObjectMeta: v1.ObjectMeta{
-				Name: "test alert channel",
+				Name:      "test alert channel",
+				Namespace: "default",
 			},
 			Spec: AlertsChannelSpec{
 				ID:           88,
@@ -62,11 +66,21 @@ var _ = Describe("AlertsChannel_webhook", func() {
 	Context("ValidateCreate", func() {
 		Context("With a valid Alert Channel", func() {
 			It("Should create the Alert Channel", func() {
+				defaultAPIKeyObject(context.Background(), logf.Log, &r, false)
+				Expect(r.Spec.APIKey).To(BeEmpty())
+
 				err := r.ValidateCreate()
 				Expect(err).ToNot(HaveOccurred())
 			})
 		})
 
+		Context("With a plaintext API key that was not moved", func() {
+			It("Should reject the Alert Channel creation", func() {
+				err := r.ValidateCreate()
+				Expect(err).To(HaveOccurred())
+			})
+		})
+
 		Context("With an invalid Region", func() {
 			BeforeEach(func() {
 				r.Spec.Region = "hamburgers"
@@ -91,6 +105,24 @@ var _ = Describe("AlertsChannel_webhook", func() {
 			})
 		})
 
+		Context("With an invalid policy selector", func() {
+			BeforeEach(func() {
+				r.Spec.PolicySelector = &AlertsPolicySelector{
+					LabelSelector: v1.LabelSelector{
+						MatchExpressions: []v1.LabelSelectorRequirement{{Key: "team", Operator: "Near"}},
+					},
+				}
+			})
+
+			It("Should reject the Alert Channel creation", func() {
+				defaultAPIKeyObject(context.Background(), logf.Log, &r, false)
+
+				err := r.ValidateCreate()
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("invalid policy_selector"))
+			})
+		})
+
 		Context("With no API Key or secret", func() {
 			BeforeEach(func() {
 				r.Spec.APIKey = ""
@@ -99,7 +131,7 @@ var _ = Describe("AlertsChannel_webhook", func() {
 			It("Should reject the Alert Channel creation", func() {
 				err := r.ValidateCreate()
 				Expect(err).To(HaveOccurred())
-				Expect(err.Error()).To(ContainSubstring("either api_key or api_key_secret must be set"))
+				Expect(err.Error()).To(ContainSubstring("either api_key, api_key_secret or account_ref must be set"))
 			})
 		})
 	})
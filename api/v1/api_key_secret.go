@@ -0,0 +1,209 @@
+package v1
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/go-logr/logr"
+	v1 "k8s.io/api/core/v1"
+	kErr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+const (
+	// ManagedByLabel marks the secrets the defaulting webhooks create for API keys moved out of a spec.
+	ManagedByLabel = "app.kubernetes.io/managed-by"
+	// ManagedByOperator is the value of ManagedByLabel on secrets the operator owns.
+	ManagedByOperator = "newrelic-kubernetes-operator"
+
+	managedAPIKeySecretPrefix  = "newrelic-api-key-"
+	managedAPIKeySecretKeyName = "api-key"
+)
+
+// WebhookOptions are the settings of the webhooks of the resources with an api_key.
+// +kubebuilder:object:generate=false
+type WebhookOptions struct {
+	// RejectPlaintextAPIKeys makes the webhooks reject resources with a plaintext api_key
+	// instead of moving the key into a secret. It is set from the --reject-plaintext-api-keys flag.
+	RejectPlaintextAPIKeys bool
+}
+
+// CheckForPlaintextAPIKey returns an error if a plaintext API key is still set. The defaulting
+// webhooks move plaintext keys into a secret, or reject them with RejectPlaintextAPIKeys, so a
+// key that reaches validation could not be moved. Either way it must not be stored.
+// Objects being deleted are let through so their finalizer can still be removed.
+func CheckForPlaintextAPIKey(obj metav1.Object, apiKey string) error {
+	if !hasPlaintextAPIKey(obj, apiKey) {
+		return nil
+	}
+
+	return errors.New("api_key could not be moved into a secret, use api_key_secret or account_ref")
+}
+
+// hasPlaintextAPIKey returns true if apiKey is set on obj and obj is not being deleted.
+func hasPlaintextAPIKey(obj metav1.Object, apiKey string) bool {
+	return apiKey != "" && obj.GetDeletionTimestamp() == nil
+}
+
+// apiKeyObject is a resource that can be given a plaintext API key.
+type apiKeyObject interface {
+	metav1.Object
+	webhook.Defaulter
+	// plaintextAPIKey returns the api_key and api_key_secret fields of the spec.
+	plaintextAPIKey() (*string, *NewRelicAPIKeySecret)
+}
+
+// setupAPIKeyDefaulter registers the defaulting webhook of obj, which moves a plaintext API key
+// into a secret before calling Default. It is registered at the path the webhook builder would
+// use, so the builder leaves it in place.
+func setupAPIKeyDefaulter(mgr ctrl.Manager, obj apiKeyObject, log logr.Logger, opts WebhookOptions) error {
+	gvk, err := apiutil.GVKForObject(obj, mgr.GetScheme())
+	if err != nil {
+		return err
+	}
+
+	path := "/mutate-" + strings.Replace(gvk.Group, ".", "-", -1) + "-" + gvk.Version + "-" + strings.ToLower(gvk.Kind)
+	mgr.GetWebhookServer().Register(path, &webhook.Admission{Handler: &apiKeyDefaulter{object: obj, log: log, options: opts}})
+
+	return nil
+}
+
+// apiKeyDefaulter is the defaulting webhook of the resources with an api_key. Unlike the one
+// the webhook builder sets up, it sees whether a request is a dry run: the secret a plaintext
+// key is moved into is only created for requests that are not, so the webhook has no side
+// effects on dry runs. With RejectPlaintextAPIKeys the object is rejected instead.
+type apiKeyDefaulter struct {
+	object  apiKeyObject
+	log     logr.Logger
+	options WebhookOptions
+	decoder *admission.Decoder
+}
+
+// Handle moves the plaintext API key of the object in req into a secret and defaults the object.
+func (d *apiKeyDefaulter) Handle(ctx context.Context, req admission.Request) admission.Response {
+	obj := d.object.DeepCopyObject().(apiKeyObject)
+	if err := d.decoder.Decode(req, obj); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	if obj.GetNamespace() == "" {
+		obj.SetNamespace(req.Namespace)
+	}
+
+	if apiKey, _ := obj.plaintextAPIKey(); d.options.RejectPlaintextAPIKeys && hasPlaintextAPIKey(obj, *apiKey) {
+		return admission.Denied("api_key is not allowed in this cluster, use api_key_secret or account_ref")
+	}
+
+	defaultAPIKeyObject(ctx, d.log, obj, req.DryRun != nil && *req.DryRun)
+
+	marshaled, err := json.Marshal(obj)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+
+	return admission.PatchResponseFromRaw(req.Object.Raw, marshaled)
+}
+
+// InjectDecoder implements admission.DecoderInjector.
+func (d *apiKeyDefaulter) InjectDecoder(decoder *admission.Decoder) error {
+	d.decoder = decoder
+	return nil
+}
+
+// defaultAPIKeyObject moves the plaintext API key of obj into a secret and calls Default.
+func defaultAPIKeyObject(ctx context.Context, log logr.Logger, obj apiKeyObject, dryRun bool) {
+	apiKey, apiKeySecret := obj.plaintextAPIKey()
+	moveAPIKeyToSecret(ctx, log, obj.GetNamespace(), dryRun, apiKey, apiKeySecret)
+
+	obj.Default()
+}
+
+// moveAPIKeyToSecret stores a plaintext API key in a secret owned by the operator and replaces
+// it with a reference to that secret. On a dry run the reference is set without creating the
+// secret. The key is left in place if it can't be moved, the validating webhook then rejects
+// the resource.
+func moveAPIKeyToSecret(ctx context.Context, log logr.Logger, namespace string, dryRun bool, apiKey *string, apiKeySecret *NewRelicAPIKeySecret) {
+	if *apiKey == "" {
+		return
+	}
+
+	secret, err := managedAPIKeySecret(ctx, namespace, *apiKey, dryRun)
+	if err != nil {
+		log.Error(err, "unable to move api_key into a secret", "namespace", namespace)
+		return
+	}
+
+	*apiKeySecret = secret
+	*apiKey = ""
+}
+
+// managedAPIKeySecret returns a reference to the operator owned secret holding apiKey, creating
+// the secret if needed and this is not a dry run. The secret is named after a hash of the key so
+// resources with the same key share it. The secret has no owner yet, as the resources using it
+// may not exist yet; the controllers add each resource using it as an owner, so it is garbage
+// collected once none of them is left.
+func managedAPIKeySecret(ctx context.Context, namespace string, apiKey string, dryRun bool) (NewRelicAPIKeySecret, error) {
+	if namespace == "" {
+		return NewRelicAPIKeySecret{}, errors.New("namespace is not set")
+	}
+
+	if k8Client == nil {
+		return NewRelicAPIKeySecret{}, errors.New("webhook client is not set up")
+	}
+
+	sum := sha256.Sum256([]byte(apiKey))
+	ref := NewRelicAPIKeySecret{
+		Name:      managedAPIKeySecretPrefix + hex.EncodeToString(sum[:8]),
+		Namespace: namespace,
+		KeyName:   managedAPIKeySecretKeyName,
+	}
+
+	if dryRun {
+		return ref, nil
+	}
+
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ref.Name,
+			Namespace: ref.Namespace,
+			Labels: map[string]string{
+				ManagedByLabel: ManagedByOperator,
+			},
+		},
+		Type: v1.SecretTypeOpaque,
+		Data: map[string][]byte{
+			ref.KeyName: []byte(apiKey),
+		},
+	}
+
+	err := k8Client.Create(ctx, secret)
+	if err == nil {
+		return ref, nil
+	}
+
+	if !kErr.IsAlreadyExists(err) {
+		return NewRelicAPIKeySecret{}, err
+	}
+
+	var existing v1.Secret
+	if err := k8Client.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, &existing); err != nil {
+		return NewRelicAPIKeySecret{}, err
+	}
+
+	if existing.Labels[ManagedByLabel] != ManagedByOperator || string(existing.Data[ref.KeyName]) != apiKey {
+		return NewRelicAPIKeySecret{}, fmt.Errorf("secret %s/%s already exists and is not the managed secret for this key", ref.Namespace, ref.Name)
+	}
+
+	return ref, nil
+}
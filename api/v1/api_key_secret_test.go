@@ -0,0 +1,197 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+var _ = Describe("moveAPIKeyToSecret", func() {
+	var (
+		ctx            context.Context
+		previousClient client.Client
+		apiKey         string
+		apiKeySecret   NewRelicAPIKeySecret
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		s := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(s)).To(Succeed())
+
+		previousClient = k8Client
+		k8Client = fake.NewFakeClientWithScheme(s)
+
+		apiKey = "plaintext-api-key"
+		apiKeySecret = NewRelicAPIKeySecret{}
+	})
+
+	AfterEach(func() {
+		k8Client = previousClient
+	})
+
+	It("moves the key into a managed secret", func() {
+		moveAPIKeyToSecret(ctx, logf.Log, "team", false, &apiKey, &apiKeySecret)
+
+		Expect(apiKey).To(BeEmpty())
+		Expect(apiKeySecret.Name).To(HavePrefix("newrelic-api-key-"))
+		Expect(apiKeySecret.Namespace).To(Equal("team"))
+		Expect(apiKeySecret.KeyName).To(Equal("api-key"))
+
+		var secret v1.Secret
+		Expect(k8Client.Get(ctx, types.NamespacedName{Namespace: "team", Name: apiKeySecret.Name}, &secret)).To(Succeed())
+		Expect(secret.Labels).To(HaveKeyWithValue(ManagedByLabel, ManagedByOperator))
+		Expect(string(secret.Data["api-key"])).To(Equal("plaintext-api-key"))
+	})
+
+	It("only sets the reference to the secret on a dry run", func() {
+		moveAPIKeyToSecret(ctx, logf.Log, "team", true, &apiKey, &apiKeySecret)
+
+		Expect(apiKey).To(BeEmpty())
+		Expect(apiKeySecret.Name).To(HavePrefix("newrelic-api-key-"))
+
+		var secrets v1.SecretList
+		Expect(k8Client.List(ctx, &secrets)).To(Succeed())
+		Expect(secrets.Items).To(BeEmpty())
+	})
+
+	It("reuses the secret of a key that was moved before", func() {
+		otherKey := apiKey
+		otherSecret := NewRelicAPIKeySecret{}
+		moveAPIKeyToSecret(ctx, logf.Log, "team", false, &otherKey, &otherSecret)
+
+		moveAPIKeyToSecret(ctx, logf.Log, "team", false, &apiKey, &apiKeySecret)
+
+		Expect(apiKey).To(BeEmpty())
+		Expect(apiKeySecret).To(Equal(otherSecret))
+	})
+
+	It("leaves the key in place if the secret name is taken", func() {
+		moveAPIKeyToSecret(ctx, logf.Log, "team", false, &apiKey, &apiKeySecret)
+
+		var secret v1.Secret
+		Expect(k8Client.Get(ctx, types.NamespacedName{Namespace: "team", Name: apiKeySecret.Name}, &secret)).To(Succeed())
+		secret.Labels = nil
+		Expect(k8Client.Update(ctx, &secret)).To(Succeed())
+
+		apiKey = "plaintext-api-key"
+		apiKeySecret = NewRelicAPIKeySecret{}
+		moveAPIKeyToSecret(ctx, logf.Log, "team", false, &apiKey, &apiKeySecret)
+
+		Expect(apiKey).To(Equal("plaintext-api-key"))
+		Expect(apiKeySecret).To(Equal(NewRelicAPIKeySecret{}))
+	})
+
+	It("lets objects being deleted through", func() {
+		policy := &AlertsPolicy{ObjectMeta: metav1.ObjectMeta{DeletionTimestamp: &metav1.Time{}}}
+
+		Expect(CheckForPlaintextAPIKey(policy, apiKey)).To(Succeed())
+		Expect(CheckForPlaintextAPIKey(&AlertsPolicy{}, apiKey)).To(HaveOccurred())
+	})
+})
+
+// patchedPaths returns the paths of the patches in response.
+func patchedPaths(response admission.Response) []string {
+	var paths []string
+	for _, patch := range response.Patches {
+		paths = append(paths, patch.Path)
+	}
+
+	return paths
+}
+
+var _ = Describe("apiKeyDefaulter", func() {
+	var (
+		ctx            context.Context
+		previousClient client.Client
+		defaulter      *apiKeyDefaulter
+		request        admission.Request
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		s := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(s)).To(Succeed())
+		Expect(AddToScheme(s)).To(Succeed())
+
+		previousClient = k8Client
+		k8Client = fake.NewFakeClientWithScheme(s)
+
+		decoder, err := admission.NewDecoder(s)
+		Expect(err).ToNot(HaveOccurred())
+
+		defaulter = &apiKeyDefaulter{object: &AlertsPolicy{}, log: logf.Log}
+		Expect(defaulter.InjectDecoder(decoder)).To(Succeed())
+
+		policy := &AlertsPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "my-policy", Namespace: "team"},
+			Spec:       AlertsPolicySpec{Name: "my policy", APIKey: "plaintext-api-key"},
+		}
+		raw, err := json.Marshal(policy)
+		Expect(err).ToNot(HaveOccurred())
+
+		request = admission.Request{AdmissionRequest: admissionv1beta1.AdmissionRequest{
+			Namespace: "team",
+			Operation: admissionv1beta1.Create,
+			Object:    runtime.RawExtension{Raw: raw},
+		}}
+	})
+
+	AfterEach(func() {
+		k8Client = previousClient
+	})
+
+	It("moves the key into a secret and defaults the object", func() {
+		response := defaulter.Handle(ctx, request)
+
+		Expect(response.Allowed).To(BeTrue())
+		Expect(patchedPaths(response)).To(ContainElement("/spec/api_key"))
+		Expect(patchedPaths(response)).To(ContainElement("/spec/api_key_secret/name"))
+		Expect(patchedPaths(response)).To(ContainElement("/spec/incidentPreference"))
+
+		var secrets v1.SecretList
+		Expect(k8Client.List(ctx, &secrets)).To(Succeed())
+		Expect(secrets.Items).To(HaveLen(1))
+	})
+
+	It("does not create the secret on a dry run", func() {
+		dryRun := true
+		request.DryRun = &dryRun
+
+		response := defaulter.Handle(ctx, request)
+
+		Expect(response.Allowed).To(BeTrue())
+		Expect(patchedPaths(response)).To(ContainElement("/spec/api_key"))
+
+		var secrets v1.SecretList
+		Expect(k8Client.List(ctx, &secrets)).To(Succeed())
+		Expect(secrets.Items).To(BeEmpty())
+	})
+
+	It("rejects the object when plaintext keys are rejected", func() {
+		defaulter.options.RejectPlaintextAPIKeys = true
+
+		response := defaulter.Handle(ctx, request)
+
+		Expect(response.Allowed).To(BeFalse())
+		Expect(string(response.Result.Reason)).To(ContainSubstring("not allowed"))
+
+		var secrets v1.SecretList
+		Expect(k8Client.List(ctx, &secrets)).To(Succeed())
+		Expect(secrets.Items).To(BeEmpty())
+	})
+})
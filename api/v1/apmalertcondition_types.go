@@ -24,12 +24,18 @@ type APMSpecificSpec struct {
 
 // ApmAlertConditionStatus defines the observed state of ApmAlertCondition
 type ApmAlertConditionStatus struct {
+	ResourceStatus `json:",inline"`
+
 	AppliedSpec *ApmAlertConditionSpec `json:"applied_spec"`
 	ConditionID int                    `json:"condition_id"`
 }
 
 // +kubebuilder:object:root=true
-// +kubebuilder:printcolumn:name="Created",type="boolean",JSONPath=".status.created"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status"
+// +kubebuilder:printcolumn:name="Synced",type="string",JSONPath=".status.conditions[?(@.type==\"Synced\")].status"
+// +kubebuilder:printcolumn:name="Error",type="string",JSONPath=".status.conditions[?(@.type==\"Error\")].status"
+// +kubebuilder:printcolumn:name="Message",type="string",JSONPath=".status.conditions[?(@.type==\"Error\")].message",priority=1
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 
 // ApmAlertCondition is the Schema for the apmalertconditions API
 type ApmAlertCondition struct {
@@ -53,6 +59,11 @@ func init() {
 	SchemeBuilder.Register(&ApmAlertCondition{}, &ApmAlertConditionList{})
 }
 
+// GetResourceStatus returns the status fields shared by all kinds.
+func (in *ApmAlertCondition) GetResourceStatus() *ResourceStatus {
+	return &in.Status.ResourceStatus
+}
+
 func (in ApmAlertConditionSpec) APICondition() alerts.Condition {
 	jsonString, _ := json.Marshal(in)
 	var APICondition alerts.Condition
@@ -21,14 +21,13 @@ import (
 	"strings"
 
 	"github.com/newrelic/newrelic-client-go/pkg/alerts"
-	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
+	customErrors "github.com/newrelic/newrelic-kubernetes-operator/errors"
 	"github.com/newrelic/newrelic-kubernetes-operator/interfaces"
 )
 
@@ -54,9 +53,14 @@ func (i InvalidAttributeSlice) errorString() string {
 	return errorMessage
 }
 
-func (r *ApmAlertCondition) SetupWebhookWithManager(mgr ctrl.Manager) error {
-	alertClientFunc = interfaces.InitializeAlertsClient
+func (r *ApmAlertCondition) SetupWebhookWithManager(mgr ctrl.Manager, opts WebhookOptions) error {
+	alertClientFunc = interfaces.DefaultClientPool.AlertsClient
 	k8Client = mgr.GetClient()
+
+	if err := setupAPIKeyDefaulter(mgr, r, apmalertconditionlog, opts); err != nil {
+		return err
+	}
+
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(r).
 		Complete()
@@ -64,7 +68,7 @@ func (r *ApmAlertCondition) SetupWebhookWithManager(mgr ctrl.Manager) error {
 
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
 
-// +kubebuilder:webhook:path=/mutate-nr-k8s-newrelic-com-v1-apmalertcondition,mutating=true,failurePolicy=fail,groups=nr.k8s.newrelic.com,resources=apmalertconditions,verbs=create;update,versions=v1,name=mapmalertcondition.kb.io,sideEffects=None
+// +kubebuilder:webhook:path=/mutate-nr-k8s-newrelic-com-v1-apmalertcondition,mutating=true,failurePolicy=fail,groups=nr.k8s.newrelic.com,resources=apmalertconditions,verbs=create;update,versions=v1,name=mapmalertcondition.kb.io,sideEffects=NoneOnDryRun
 
 var _ webhook.Defaulter = &ApmAlertCondition{}
 
@@ -76,6 +80,13 @@ func (r *ApmAlertCondition) Default() {
 		log.Info("Setting null Applied Spec to empty interface")
 		r.Status.AppliedSpec = &ApmAlertConditionSpec{}
 	}
+
+	r.Status.AppliedSpec.APIKey = ""
+}
+
+// plaintextAPIKey implements apiKeyObject, the key is moved into a secret before Default.
+func (r *ApmAlertCondition) plaintextAPIKey() (*string, *NewRelicAPIKeySecret) {
+	return &r.Spec.APIKey, &r.Spec.APIKeySecret
 }
 
 // +kubebuilder:webhook:verbs=create;update,path=/validate-nr-k8s-newrelic-com-v1-apmalertcondition,mutating=false,failurePolicy=fail,groups=nr.k8s.newrelic.com,resources=apmalertconditions,versions=v1,name=vapmalertcondition.kb.io,sideEffects=None
@@ -106,6 +117,22 @@ func (r *ApmAlertCondition) ValidateCreate() error {
 	if len(invalidAttributes) > 0 {
 		return errors.New("error with invalid attributes: \n" + invalidAttributes.errorString())
 	}
+
+	err = CheckForPlaintextAPIKey(r, r.Spec.APIKey)
+	if err != nil {
+		return err
+	}
+
+	err = CheckDeletionPolicyAnnotation(r)
+	if err != nil {
+		return err
+	}
+
+	err = CheckAdoption(r.Spec.Adoption, r.Spec.ImportID)
+	if err != nil {
+		return err
+	}
+
 	return r.CheckExistingPolicyID()
 }
 
@@ -133,6 +160,21 @@ func (r *ApmAlertCondition) ValidateUpdate(old runtime.Object) error {
 		return errors.New("error with invalid attributes")
 	}
 
+	err = CheckForPlaintextAPIKey(r, r.Spec.APIKey)
+	if err != nil {
+		return err
+	}
+
+	err = CheckDeletionPolicyAnnotation(r)
+	if err != nil {
+		return err
+	}
+
+	err = CheckAdoption(r.Spec.Adoption, r.Spec.ImportID)
+	if err != nil {
+		return err
+	}
+
 	return r.CheckExistingPolicyID()
 }
 
@@ -266,29 +308,21 @@ func (r *ApmAlertCondition) ValidateUserDefinedValueFunction() InvalidAttributeS
 func (r *ApmAlertCondition) CheckExistingPolicyID() error {
 	log.Info("Checking existing", "policyId", r.Spec.ExistingPolicyID)
 	ctx := context.Background()
-	var apiKey string
-
-	if r.Spec.APIKey == "" {
-		key := types.NamespacedName{Namespace: r.Spec.APIKeySecret.Namespace, Name: r.Spec.APIKeySecret.Name}
-		var apiKeySecret v1.Secret
 
-		getErr := k8Client.Get(ctx, key, &apiKeySecret)
-		if getErr != nil {
-			log.Error(getErr, "Error getting secret")
-			return getErr
-		}
-
-		apiKey = string(apiKeySecret.Data[r.Spec.APIKeySecret.KeyName])
-	} else {
-		apiKey = r.Spec.APIKey
+	creds, err := r.Spec.Credentials().Resolve(ctx, k8Client, r.Namespace)
+	if err != nil {
+		log.Error(err, "Error getting credentials")
+		return err
 	}
 
-	alertsClient, errAlertClient := alertClientFunc(apiKey, r.Spec.Region)
+	apiKey := creds.APIKey
+
+	alertsClient, errAlertClient := creds.AlertsClient(alertClientFunc)
 	if errAlertClient != nil {
 		log.Error(errAlertClient, "failed to get policy",
 			"policyId", r.Spec.ExistingPolicyID,
 			"API Key", interfaces.PartialAPIKey(apiKey),
-			"region", r.Spec.Region,
+			"region", creds.Region,
 		)
 
 		return errAlertClient
@@ -298,7 +332,7 @@ func (r *ApmAlertCondition) CheckExistingPolicyID() error {
 	if errAlertPolicy != nil {
 		if r.GetDeletionTimestamp() != nil {
 			log.Info("Deleting resource", "errAlertPolicy", errAlertPolicy)
-			if strings.Contains(errAlertPolicy.Error(), "no alert policy found for id") {
+			if customErrors.IsNotFound(errAlertPolicy) {
 				log.Info("ExistingAlertPolicy not found but we are deleting the condition so this is ok")
 
 				return nil
@@ -308,10 +342,10 @@ func (r *ApmAlertCondition) CheckExistingPolicyID() error {
 		log.Error(errAlertPolicy, "failed to get policy",
 			"policyId", r.Spec.ExistingPolicyID,
 			"API Key", interfaces.PartialAPIKey(apiKey),
-			"region", r.Spec.Region,
+			"region", creds.Region,
 		)
 
-		return errAlertPolicy
+		return existingPolicyError(r.Spec.ExistingPolicyID, errAlertPolicy)
 	}
 	if alertPolicy.ID != r.Spec.ExistingPolicyID {
 		log.Info("Alert policy returned by the API failed to match provided policy ID")
@@ -323,23 +357,13 @@ func (r *ApmAlertCondition) CheckExistingPolicyID() error {
 }
 
 func (r *ApmAlertCondition) CheckForAPIKeyOrSecret() error {
-	if r.Spec.APIKey != "" {
-		return nil
-	}
-
-	if r.Spec.APIKeySecret != (NewRelicAPIKeySecret{}) {
-		if r.Spec.APIKeySecret.Name != "" && r.Spec.APIKeySecret.Namespace != "" && r.Spec.APIKeySecret.KeyName != "" {
-			return nil
-		}
-	}
-
-	return errors.New("either api_key or api_key_secret must be set")
+	return r.Spec.Credentials().Check()
 }
 
 func (r *ApmAlertCondition) CheckRequiredFields() error {
 	missingFields := []string{}
 
-	if r.Spec.Region == "" {
+	if r.Spec.Region == "" && r.Spec.AccountRef == nil {
 		missingFields = append(missingFields, "region")
 	}
 
@@ -2,10 +2,14 @@ package v1
 
 import (
 	"errors"
+	"fmt"
 	"hash"
 
 	"github.com/davecgh/go-spew/spew"
 	"github.com/newrelic/newrelic-client-go/pkg/region"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	customErrors "github.com/newrelic/newrelic-kubernetes-operator/errors"
 )
 
 // DeepHashObject writes specified object to hash using the spew library
@@ -32,6 +36,145 @@ type AlertConditionTerm struct {
 	ViolationCloseTimer int    `json:"violation_close_timer,omitempty"`
 }
 
+// DriftPolicy is what the operator does when the object in New Relic no longer
+// matches the spec, for example after it was edited in the New Relic UI.
+// +kubebuilder:validation:Enum=correct;report-only;recreate
+type DriftPolicy string
+
+const (
+	// DriftPolicyCorrect overwrites the object in New Relic with the spec. This is the default.
+	DriftPolicyCorrect DriftPolicy = "correct"
+	// DriftPolicyReportOnly reports the drift in the status and leaves the object in New Relic as it is.
+	DriftPolicyReportOnly DriftPolicy = "report-only"
+	// DriftPolicyRecreate corrects drift like DriftPolicyCorrect, and also replaces AlertsChannels
+	// whose configuration drifted, or whose header secrets changed, with a new channel with a new
+	// ID, as channels can't be updated in New Relic. With the other policies it is only reported.
+	DriftPolicyRecreate DriftPolicy = "recreate"
+)
+
+// DeletionPolicy is what the operator does with the object in New Relic when the resource is deleted.
+// +kubebuilder:validation:Enum=Delete;Retain
+type DeletionPolicy string
+
+const (
+	// DeletionPolicyDelete deletes the object in New Relic along with the resource. This is the default.
+	DeletionPolicyDelete DeletionPolicy = "Delete"
+	// DeletionPolicyRetain leaves the object in New Relic as it is, it is no longer managed by the operator.
+	DeletionPolicyRetain DeletionPolicy = "Retain"
+)
+
+// DeletionPolicyAnnotation sets the deletion policy of a resource without changing its spec,
+// for example right before deleting it. It takes precedence over the deletion_policy field.
+const DeletionPolicyAnnotation = "nr.k8s.newrelic.com/deletion-policy"
+
+// GetDeletionPolicy returns the deletion policy of obj: the one of its annotation if set,
+// else the one of its spec, else Delete.
+func GetDeletionPolicy(obj metav1.Object, specPolicy DeletionPolicy) DeletionPolicy {
+	if annotated, ok := obj.GetAnnotations()[DeletionPolicyAnnotation]; ok && annotated != "" {
+		return DeletionPolicy(annotated)
+	}
+
+	if specPolicy != "" {
+		return specPolicy
+	}
+
+	return DeletionPolicyDelete
+}
+
+// CheckDeletionPolicyAnnotation returns an error if the deletion policy annotation of obj is set
+// to anything but Delete or Retain. A misspelled Retain would otherwise delete the object in New Relic.
+func CheckDeletionPolicyAnnotation(obj metav1.Object) error {
+	annotated, ok := obj.GetAnnotations()[DeletionPolicyAnnotation]
+	if !ok {
+		return nil
+	}
+
+	switch DeletionPolicy(annotated) {
+	case DeletionPolicyDelete, DeletionPolicyRetain:
+		return nil
+	}
+
+	return fmt.Errorf("annotation %s must be %s or %s, not %q", DeletionPolicyAnnotation, DeletionPolicyDelete, DeletionPolicyRetain, annotated)
+}
+
+// AdoptionPolicy is how a resource adopts an object that already exists in New Relic instead of
+// creating a new one.
+// +kubebuilder:validation:Enum=never;byID;byNameIfUnowned
+type AdoptionPolicy string
+
+const (
+	// AdoptionNever always creates a new object in New Relic.
+	AdoptionNever AdoptionPolicy = "never"
+	// AdoptionByID adopts the object with the ID in import_id, unless another cluster manages it.
+	AdoptionByID AdoptionPolicy = "byID"
+	// AdoptionByNameIfUnowned adopts an object with the same name, unless another resource manages it.
+	AdoptionByNameIfUnowned AdoptionPolicy = "byNameIfUnowned"
+)
+
+// GetAdoptionPolicy returns the adoption policy of a spec: specPolicy if set, else byID if the
+// spec has an import ID, else byNameIfUnowned.
+func GetAdoptionPolicy(specPolicy AdoptionPolicy, importID string) AdoptionPolicy {
+	switch {
+	case specPolicy != "":
+		return specPolicy
+	case importID != "":
+		return AdoptionByID
+	}
+
+	return AdoptionByNameIfUnowned
+}
+
+// CheckAdoption returns an error if the adoption policy and import ID of a spec contradict each other.
+func CheckAdoption(specPolicy AdoptionPolicy, importID string) error {
+	switch GetAdoptionPolicy(specPolicy, importID) {
+	case AdoptionByID:
+		if importID == "" {
+			return errors.New("adoption byID requires an import_id")
+		}
+	case AdoptionNever, AdoptionByNameIfUnowned:
+		if importID != "" {
+			return fmt.Errorf("import_id is only used with adoption byID, not %s", specPolicy)
+		}
+	}
+
+	return nil
+}
+
+// PausedAnnotation pauses the reconciliation of a resource when set to "true". The operator
+// doesn't write to New Relic while a resource is paused, but still reports drift in its status.
+// Set on a Namespace it pauses all resources in the namespace.
+const PausedAnnotation = "nr.k8s.newrelic.com/paused"
+
+// IsPaused returns true if obj carries the paused annotation.
+func IsPaused(obj metav1.Object) bool {
+	return obj.GetAnnotations()[PausedAnnotation] == "true"
+}
+
+// DryRunAnnotation puts a resource in dry-run mode when set to "true". The operator plans the
+// writes to New Relic the resource needs and records them in its status instead of sending them.
+// The --dry-run flag puts all resources in dry-run mode.
+const DryRunAnnotation = "nr.k8s.newrelic.com/dry-run"
+
+// IsDryRun returns true if obj carries the dry-run annotation.
+func IsDryRun(obj metav1.Object) bool {
+	return obj.GetAnnotations()[DryRunAnnotation] == "true"
+}
+
+// existingPolicyError returns the error a condition is rejected with when its existing policy
+// could not be looked up in New Relic, saying what has to be fixed when that is known.
+func existingPolicyError(policyID interface{}, err error) error {
+	switch {
+	case customErrors.IsNotFound(err):
+		return fmt.Errorf("existing_policy_id %v does not exist in New Relic: %w", policyID, err)
+	case customErrors.IsUnauthorized(err):
+		return fmt.Errorf("the API key was rejected while checking existing_policy_id %v: %w", policyID, err)
+	case customErrors.IsRateLimited(err):
+		return fmt.Errorf("existing_policy_id %v could not be checked as New Relic is rate limiting requests, try again later: %w", policyID, err)
+	}
+
+	return err
+}
+
 type NewRelicAPIKeySecret struct {
 	Name      string `json:"name,omitempty"`
 	Namespace string `json:"namespace,omitempty"`
@@ -62,5 +205,5 @@ func CheckForAPIKeyOrSecret(apiKey string, secret NewRelicAPIKeySecret) error {
 		}
 	}
 
-	return errors.New("either api_key or api_key_secret must be set")
+	return errors.New("either api_key, api_key_secret or account_ref must be set")
 }
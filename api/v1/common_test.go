@@ -0,0 +1,95 @@
+package v1
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("DeletionPolicy", func() {
+	var policy *AlertsPolicy
+
+	BeforeEach(func() {
+		policy = &AlertsPolicy{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "my-policy",
+				Namespace: "default",
+			},
+		}
+	})
+
+	Describe("GetDeletionPolicy", func() {
+		It("defaults to Delete", func() {
+			Expect(GetDeletionPolicy(policy, "")).To(Equal(DeletionPolicyDelete))
+		})
+
+		It("uses the policy in the spec", func() {
+			Expect(GetDeletionPolicy(policy, DeletionPolicyRetain)).To(Equal(DeletionPolicyRetain))
+		})
+
+		It("prefers the annotation over the spec", func() {
+			policy.SetAnnotations(map[string]string{DeletionPolicyAnnotation: "Delete"})
+			Expect(GetDeletionPolicy(policy, DeletionPolicyRetain)).To(Equal(DeletionPolicyDelete))
+		})
+	})
+
+	Describe("CheckDeletionPolicyAnnotation", func() {
+		It("accepts objects without the annotation", func() {
+			Expect(CheckDeletionPolicyAnnotation(policy)).To(Succeed())
+		})
+
+		It("accepts Retain", func() {
+			policy.SetAnnotations(map[string]string{DeletionPolicyAnnotation: "Retain"})
+			Expect(CheckDeletionPolicyAnnotation(policy)).To(Succeed())
+		})
+
+		It("rejects other values", func() {
+			policy.SetAnnotations(map[string]string{DeletionPolicyAnnotation: "retain"})
+			Expect(CheckDeletionPolicyAnnotation(policy)).To(MatchError(ContainSubstring("retain")))
+		})
+	})
+})
+
+var _ = Describe("IsPaused", func() {
+	It("is true when the paused annotation is true", func() {
+		obj := &metav1.ObjectMeta{Annotations: map[string]string{PausedAnnotation: "true"}}
+		Expect(IsPaused(obj)).To(BeTrue())
+	})
+
+	It("is false otherwise", func() {
+		Expect(IsPaused(&metav1.ObjectMeta{})).To(BeFalse())
+		Expect(IsPaused(&metav1.ObjectMeta{Annotations: map[string]string{PausedAnnotation: "false"}})).To(BeFalse())
+	})
+})
+
+var _ = Describe("AdoptionPolicy", func() {
+	Describe("GetAdoptionPolicy", func() {
+		It("defaults to byNameIfUnowned", func() {
+			Expect(GetAdoptionPolicy("", "")).To(Equal(AdoptionByNameIfUnowned))
+		})
+
+		It("defaults to byID when an import ID is set", func() {
+			Expect(GetAdoptionPolicy("", "123")).To(Equal(AdoptionByID))
+		})
+
+		It("uses the policy in the spec", func() {
+			Expect(GetAdoptionPolicy(AdoptionNever, "")).To(Equal(AdoptionNever))
+		})
+	})
+
+	Describe("CheckAdoption", func() {
+		It("accepts an import ID with byID", func() {
+			Expect(CheckAdoption(AdoptionByID, "123")).To(Succeed())
+			Expect(CheckAdoption("", "123")).To(Succeed())
+		})
+
+		It("rejects byID without an import ID", func() {
+			Expect(CheckAdoption(AdoptionByID, "")).To(MatchError(ContainSubstring("requires an import_id")))
+		})
+
+		It("rejects an import ID with the other policies", func() {
+			Expect(CheckAdoption(AdoptionNever, "123")).To(MatchError(ContainSubstring("not never")))
+			Expect(CheckAdoption(AdoptionByNameIfUnowned, "123")).To(MatchError(ContainSubstring("not byNameIfUnowned")))
+		})
+	})
+})
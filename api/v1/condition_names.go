@@ -0,0 +1,76 @@
+package v1
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// maxConditionNameNumber is the highest number IsConditionResourceName looks for in the names of
+// condition resources. Resources numbered higher are only recognized by the name recorded in the
+// policy.
+const maxConditionNameNumber = 100
+
+// ConditionResourceName returns the name of the resource for condition, a condition of the policy
+// named policyName: the name of the policy followed by the key of the condition, or the name in its
+// spec when it has no key. From n = 2 the name ends in n, for names that are already taken.
+func ConditionResourceName(policyName string, condition *AlertsPolicyCondition, n int) string {
+	id := condition.Key
+	if id == "" {
+		id = condition.Spec.Name
+	}
+
+	if n > 1 {
+		return dnsLabel(fmt.Sprintf("%s-%s-%d", policyName, id, n))
+	}
+
+	return dnsLabel(policyName + "-" + id)
+}
+
+// IsConditionResourceName returns true if name is one of the names ConditionResourceName gives
+// condition of the policy named policyName.
+func IsConditionResourceName(policyName string, condition *AlertsPolicyCondition, name string) bool {
+	for n := 1; n <= maxConditionNameNumber; n++ {
+		if ConditionResourceName(policyName, condition, n) == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// dnsLabel returns name as a DNS-1123 label: lowercase letters, digits and dashes, at most 63
+// characters. Longer names are cut and end in a hash of the whole name, so they stay distinct.
+func dnsLabel(name string) string {
+	var label strings.Builder
+
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			label.WriteRune(r)
+		case label.Len() > 0 && !strings.HasSuffix(label.String(), "-"):
+			label.WriteRune('-')
+		}
+	}
+
+	result := strings.TrimRight(label.String(), "-")
+	if result == "" {
+		result = "condition"
+	}
+
+	if len(result) > validation.DNS1123LabelMaxLength {
+		hash := shortHash(name)
+		result = strings.TrimRight(result[:validation.DNS1123LabelMaxLength-len(hash)-1], "-") + "-" + hash
+	}
+
+	return result
+}
+
+func shortHash(value string) string {
+	hasher := fnv.New32a()
+	_, _ = hasher.Write([]byte(value))
+
+	return fmt.Sprintf("%08x", hasher.Sum32())
+}
@@ -0,0 +1,110 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/newrelic/newrelic-kubernetes-operator/interfaces"
+)
+
+// Credentials are the fields of a spec that decide which New Relic account it is written to.
+type Credentials struct {
+	AccountRef   *NewRelicAccountReference
+	APIKey       string
+	APIKeySecret NewRelicAPIKeySecret
+	Region       string
+	AccountID    int
+}
+
+// ResolvedCredentials are what a New Relic client is created with.
+type ResolvedCredentials struct {
+	APIKey    string
+	Region    string
+	AccountID int
+	Endpoints NewRelicEndpoints
+}
+
+// Check returns an error if the credentials do not say where the API key comes from.
+func (in Credentials) Check() error {
+	if in.AccountRef != nil && in.AccountRef.Name != "" {
+		return nil
+	}
+
+	return CheckForAPIKeyOrSecret(in.APIKey, in.APIKeySecret)
+}
+
+// Resolve reads the API key from its secret and takes everything the resource leaves
+// out from the referenced NewRelicAccount. Fields set on the resource win over the account.
+// namespace is the namespace of the resource, account references default to it.
+func (in Credentials) Resolve(ctx context.Context, k8sClient client.Client, namespace string) (ResolvedCredentials, error) {
+	resolved := ResolvedCredentials{
+		APIKey:    in.APIKey,
+		Region:    in.Region,
+		AccountID: in.AccountID,
+	}
+
+	if resolved.APIKey == "" && in.APIKeySecret != (NewRelicAPIKeySecret{}) {
+		key := types.NamespacedName{Namespace: in.APIKeySecret.Namespace, Name: in.APIKeySecret.Name}
+
+		var apiKeySecret v1.Secret
+		if err := k8sClient.Get(ctx, key, &apiKeySecret); err != nil {
+			return ResolvedCredentials{}, err
+		}
+
+		resolved.APIKey = string(apiKeySecret.Data[in.APIKeySecret.KeyName])
+	}
+
+	if in.AccountRef == nil {
+		return resolved, nil
+	}
+
+	key := types.NamespacedName{Namespace: in.AccountRef.Namespace, Name: in.AccountRef.Name}
+	if key.Namespace == "" {
+		key.Namespace = namespace
+	}
+
+	var account NewRelicAccount
+	if err := k8sClient.Get(ctx, key, &account); err != nil {
+		return ResolvedCredentials{}, fmt.Errorf("unable to get NewRelicAccount %s: %w", key, err)
+	}
+
+	accountCredentials, err := account.Credentials().Resolve(ctx, k8sClient, account.Namespace)
+	if err != nil {
+		return ResolvedCredentials{}, fmt.Errorf("unable to read the API key of NewRelicAccount %s: %w", key, err)
+	}
+
+	if resolved.APIKey == "" {
+		resolved.APIKey = accountCredentials.APIKey
+	}
+
+	if resolved.Region == "" {
+		resolved.Region = accountCredentials.Region
+	}
+
+	if resolved.AccountID == 0 {
+		resolved.AccountID = accountCredentials.AccountID
+	}
+
+	resolved.Endpoints = account.Spec.Endpoints
+
+	return resolved, nil
+}
+
+// AlertsClient returns a client for the credentials. newClient is used unless the
+// account replaces the New Relic endpoints.
+func (in ResolvedCredentials) AlertsClient(newClient func(string, string) (interfaces.NewRelicAlertsClient, error)) (interfaces.NewRelicAlertsClient, error) {
+	if in.Endpoints == (NewRelicEndpoints{}) {
+		return newClient(in.APIKey, in.Region)
+	}
+
+	return interfaces.DefaultClientPool.AlertsClientWithConfig(interfaces.ClientConfig{
+		APIKey:       in.APIKey,
+		Region:       in.Region,
+		RestURL:      in.Endpoints.RestURL,
+		NerdGraphURL: in.Endpoints.NerdGraphURL,
+	})
+}
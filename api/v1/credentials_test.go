@@ -0,0 +1,164 @@
+package v1
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/newrelic/newrelic-kubernetes-operator/interfaces"
+	"github.com/newrelic/newrelic-kubernetes-operator/interfaces/interfacesfakes"
+)
+
+var _ = Describe("Credentials", func() {
+	var (
+		ctx       context.Context
+		k8sClient client.Client
+		account   *NewRelicAccount
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		s := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(s)).To(Succeed())
+		Expect(AddToScheme(s)).To(Succeed())
+
+		account = &NewRelicAccount{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "production",
+				Namespace: "accounts",
+			},
+			Spec: NewRelicAccountSpec{
+				APIKeySecret: NewRelicAPIKeySecret{
+					Name:    "new-relic",
+					KeyName: "api-key",
+				},
+				Region:    "EU",
+				AccountID: 42,
+			},
+		}
+
+		k8sClient = fake.NewFakeClientWithScheme(s,
+			account,
+			&v1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "new-relic",
+					Namespace: "accounts",
+				},
+				Data: map[string][]byte{
+					"api-key": []byte("account-api-key"),
+				},
+			},
+			&v1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "team-key",
+					Namespace: "team",
+				},
+				Data: map[string][]byte{
+					"api-key": []byte("team-api-key"),
+				},
+			},
+		)
+	})
+
+	Describe("Check", func() {
+		It("accepts an account_ref instead of an API key", func() {
+			creds := Credentials{AccountRef: &NewRelicAccountReference{Name: "production"}}
+			Expect(creds.Check()).To(Succeed())
+		})
+
+		It("requires an API key, secret or account_ref", func() {
+			creds := Credentials{AccountRef: &NewRelicAccountReference{}}
+			Expect(creds.Check()).To(MatchError("either api_key, api_key_secret or account_ref must be set"))
+		})
+	})
+
+	Describe("Resolve", func() {
+		It("uses the API key of the resource", func() {
+			creds := Credentials{APIKey: "resource-api-key", Region: "US", AccountID: 1}
+
+			resolved, err := creds.Resolve(ctx, k8sClient, "team")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(resolved).To(Equal(ResolvedCredentials{APIKey: "resource-api-key", Region: "US", AccountID: 1}))
+		})
+
+		It("reads the API key from a secret", func() {
+			creds := Credentials{
+				APIKeySecret: NewRelicAPIKeySecret{Name: "team-key", Namespace: "team", KeyName: "api-key"},
+				Region:       "US",
+			}
+
+			resolved, err := creds.Resolve(ctx, k8sClient, "team")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(resolved.APIKey).To(Equal("team-api-key"))
+		})
+
+		It("takes the API key, region and account ID from the account", func() {
+			creds := Credentials{AccountRef: &NewRelicAccountReference{Name: "production", Namespace: "accounts"}}
+
+			resolved, err := creds.Resolve(ctx, k8sClient, "team")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(resolved).To(Equal(ResolvedCredentials{APIKey: "account-api-key", Region: "EU", AccountID: 42}))
+		})
+
+		It("looks for the account in the namespace of the resource by default", func() {
+			creds := Credentials{AccountRef: &NewRelicAccountReference{Name: "production"}}
+
+			resolved, err := creds.Resolve(ctx, k8sClient, "accounts")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(resolved.APIKey).To(Equal("account-api-key"))
+
+			_, err = creds.Resolve(ctx, k8sClient, "team")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("unable to get NewRelicAccount team/production"))
+		})
+
+		It("prefers the fields set on the resource", func() {
+			creds := Credentials{
+				AccountRef:   &NewRelicAccountReference{Name: "production", Namespace: "accounts"},
+				APIKeySecret: NewRelicAPIKeySecret{Name: "team-key", Namespace: "team", KeyName: "api-key"},
+				AccountID:    7,
+			}
+
+			resolved, err := creds.Resolve(ctx, k8sClient, "team")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(resolved).To(Equal(ResolvedCredentials{APIKey: "team-api-key", Region: "EU", AccountID: 7}))
+		})
+
+		It("returns the endpoints of the account", func() {
+			account.Spec.Endpoints = NewRelicEndpoints{RestURL: "https://proxy.example.com/v2"}
+			Expect(k8sClient.Update(ctx, account)).To(Succeed())
+
+			creds := Credentials{AccountRef: &NewRelicAccountReference{Name: "production", Namespace: "accounts"}}
+
+			resolved, err := creds.Resolve(ctx, k8sClient, "team")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(resolved.Endpoints.RestURL).To(Equal("https://proxy.example.com/v2"))
+		})
+	})
+
+	Describe("AlertsClient", func() {
+		It("creates the client with newClient when no endpoints are set", func() {
+			fakeClient := &interfacesfakes.FakeNewRelicAlertsClient{}
+
+			var gotAPIKey, gotRegion string
+			newClient := func(apiKey string, region string) (interfaces.NewRelicAlertsClient, error) {
+				gotAPIKey, gotRegion = apiKey, region
+				return fakeClient, nil
+			}
+
+			alertsClient, err := ResolvedCredentials{APIKey: "api-key", Region: "EU"}.AlertsClient(newClient)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(alertsClient).To(BeIdenticalTo(fakeClient))
+			Expect(gotAPIKey).To(Equal("api-key"))
+			Expect(gotRegion).To(Equal("EU"))
+		})
+	})
+})
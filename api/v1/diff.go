@@ -0,0 +1,271 @@
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// MaxRecordedChanges is how many changes are recorded in the status for an update or a planned
+// operation, the rest are only counted.
+const MaxRecordedChanges = 20
+
+// maxChangeValueLength is how long the values of a change are recorded, longer values are cut.
+const maxChangeValueLength = 100
+
+// redactedValue replaces the values of secrets in changes.
+const redactedValue = `"<redacted>"`
+
+// FieldChange is a field that differs between two versions of an object, by its JSON path, for
+// example conditions[0].spec.terms[0].threshold.
+type FieldChange struct {
+	Path string
+	// From and To are the values as JSON, empty when the field is not set.
+	From string
+	To   string
+}
+
+// String describes the change as path: from -> to.
+func (c FieldChange) String() string {
+	switch {
+	case c.From == "":
+		return fmt.Sprintf("%s: %s", c.Path, c.To)
+	case c.To == "":
+		return fmt.Sprintf("%s: %s -> unset", c.Path, c.From)
+	}
+
+	return fmt.Sprintf("%s: %s -> %s", c.Path, c.From, c.To)
+}
+
+// Diff compares from and to as they are written in JSON and returns the fields that differ,
+// sorted by path. Fields left empty are the same as fields that are not set. Lists that changed
+// length are returned as one change of the whole list. The values of secrets, like API keys, are
+// redacted.
+func Diff(from, to interface{}) ([]FieldChange, error) {
+	return diff(from, to, false)
+}
+
+// DiffSetFields is Diff for only the fields set in to, or set to an empty value when from has them
+// too. It compares what would be written with an object read from New Relic, which returns
+// defaults for all of the fields left out. Paths listed in ignored are skipped.
+func DiffSetFields(from, to interface{}, ignored ...string) ([]FieldChange, error) {
+	return diff(from, to, true, ignored...)
+}
+
+// DescribeChanges returns the changes as strings, at most MaxRecordedChanges of them.
+func DescribeChanges(changes []FieldChange) []string {
+	var described []string
+
+	for i, change := range changes {
+		if i == MaxRecordedChanges {
+			described = append(described, fmt.Sprintf("and %d more", len(changes)-MaxRecordedChanges))
+			break
+		}
+
+		described = append(described, change.String())
+	}
+
+	return described
+}
+
+// ChangedPaths returns the paths of the changes.
+func ChangedPaths(changes []FieldChange) []string {
+	var paths []string
+	for _, change := range changes {
+		paths = append(paths, change.Path)
+	}
+
+	return paths
+}
+
+func diff(from, to interface{}, onlySet bool, ignored ...string) ([]FieldChange, error) {
+	fromValue, err := toJSONValue(from)
+	if err != nil {
+		return nil, err
+	}
+
+	toValue, err := toJSONValue(to)
+	if err != nil {
+		return nil, err
+	}
+
+	d := differ{onlySet: onlySet, skip: make(map[string]bool)}
+	for _, path := range ignored {
+		d.skip[path] = true
+	}
+
+	d.compare("", fromValue, toValue)
+	sort.Slice(d.changes, func(i, j int) bool { return d.changes[i].Path < d.changes[j].Path })
+
+	return d.changes, nil
+}
+
+func toJSONValue(obj interface{}) (interface{}, error) {
+	if obj == nil || (reflect.ValueOf(obj).Kind() == reflect.Ptr && reflect.ValueOf(obj).IsNil()) {
+		return nil, nil
+	}
+
+	jsonString, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	var value interface{}
+	err = json.Unmarshal(jsonString, &value)
+
+	return value, err
+}
+
+type differ struct {
+	onlySet bool
+	skip    map[string]bool
+	changes []FieldChange
+}
+
+func (d *differ) compare(path string, from, to interface{}) {
+	if d.skip[path] {
+		return
+	}
+
+	fromMap, fromIsMap := from.(map[string]interface{})
+	toMap, toIsMap := to.(map[string]interface{})
+	fromList, fromIsList := from.([]interface{})
+	toList, toIsList := to.([]interface{})
+
+	switch {
+	case (fromIsMap || from == nil) && (toIsMap || to == nil) && (fromIsMap || toIsMap):
+		for _, key := range d.keys(fromMap, toMap) {
+			fieldPath := key
+			if path != "" {
+				fieldPath = path + "." + key
+			}
+
+			fromField, ok := fromMap[key]
+			if d.onlySet && !ok && isZeroJSONValue(toMap[key]) {
+				continue
+			}
+
+			d.compare(fieldPath, fromField, toMap[key])
+		}
+	case (fromIsList || from == nil) && (toIsList || to == nil) && (fromIsList || toIsList):
+		if len(fromList) != len(toList) {
+			d.add(path, from, to)
+			return
+		}
+
+		for i := range toList {
+			d.compare(fmt.Sprintf("%s[%d]", path, i), fromList[i], toList[i])
+		}
+	case !d.onlySet && isZeroJSONValue(from) && isZeroJSONValue(to):
+		return
+	case !reflect.DeepEqual(from, to):
+		d.add(path, from, to)
+	}
+}
+
+// keys returns the keys of the fields compared, all of them or only those set in to.
+func (d *differ) keys(from, to map[string]interface{}) []string {
+	seen := make(map[string]bool)
+
+	var keys []string
+	for key := range to {
+		seen[key] = true
+		keys = append(keys, key)
+	}
+
+	if !d.onlySet {
+		for key := range from {
+			if !seen[key] {
+				keys = append(keys, key)
+			}
+		}
+	}
+
+	return keys
+}
+
+func (d *differ) add(path string, from, to interface{}) {
+	change := FieldChange{Path: path, From: changeValue(from), To: changeValue(to)}
+
+	if isSecretPath(path) {
+		if change.From != "" {
+			change.From = redactedValue
+		}
+
+		if change.To != "" {
+			change.To = redactedValue
+		}
+	}
+
+	d.changes = append(d.changes, change)
+}
+
+// changeValue returns value as it is recorded in a change.
+func changeValue(value interface{}) string {
+	if isZeroJSONValue(value) {
+		return ""
+	}
+
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+
+	if len(encoded) > maxChangeValueLength {
+		return string(encoded[:maxChangeValueLength]) + "..."
+	}
+
+	return string(encoded)
+}
+
+// secretFields are the names of the fields that hold secrets, in specs and in what is written to
+// New Relic.
+var secretFields = map[string]bool{
+	"api_key":       true,
+	"auth_password": true,
+	"auth_token":    true,
+	"service_key":   true,
+	"headers":       true,
+}
+
+// isSecretPath returns true if the field at path holds a secret.
+func isSecretPath(path string) bool {
+	segments := strings.Split(path, ".")
+	for i, segment := range segments {
+		if index := strings.Index(segment, "["); index >= 0 {
+			segment = segment[:index]
+		}
+
+		if secretFields[segment] {
+			return true
+		}
+
+		// the key of a channel, not the key of anything else
+		if segment == "key" && i > 0 && segments[i-1] == "configuration" {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isZeroJSONValue(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case bool:
+		return !v
+	case float64:
+		return v == 0
+	case string:
+		return v == ""
+	case map[string]interface{}:
+		return len(v) == 0
+	case []interface{}:
+		return len(v) == 0
+	}
+
+	return false
+}
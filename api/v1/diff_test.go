@@ -0,0 +1,127 @@
+package v1
+
+import (
+	"fmt"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Diff", func() {
+	var (
+		from AlertsNrqlConditionSpec
+		to   AlertsNrqlConditionSpec
+	)
+
+	BeforeEach(func() {
+		from = AlertsNrqlConditionSpec{
+			AlertsGenericConditionSpec: AlertsGenericConditionSpec{
+				Enabled: true,
+				Name:    "NRQL Condition",
+				Terms: []AlertsNrqlConditionTerm{
+					{Priority: "critical", Threshold: "5", ThresholdDuration: 60},
+				},
+			},
+		}
+		to = from
+		to.Terms = []AlertsNrqlConditionTerm{from.Terms[0]}
+	})
+
+	It("returns no changes for equal objects", func() {
+		changes, err := Diff(from, to)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changes).To(BeEmpty())
+	})
+
+	It("returns the JSON paths of the fields that changed, sorted", func() {
+		to.Name = "renamed"
+		to.Terms[0].Threshold = "10"
+		to.RunbookURL = "https://example.com"
+
+		changes, err := Diff(from, to)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changes).To(Equal([]FieldChange{
+			{Path: "name", From: `"NRQL Condition"`, To: `"renamed"`},
+			{Path: "runbook_url", To: `"https://example.com"`},
+			{Path: "terms[0].threshold", From: `"5"`, To: `"10"`},
+		}))
+	})
+
+	It("returns fields that were unset", func() {
+		to.Enabled = false
+
+		changes, err := Diff(&from, &to)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changes).To(Equal([]FieldChange{{Path: "enabled", From: "true"}}))
+		Expect(changes[0].String()).To(Equal("enabled: true -> unset"))
+	})
+
+	It("returns lists that changed length as one change", func() {
+		to.Terms = append(to.Terms, AlertsNrqlConditionTerm{Priority: "warning", Threshold: "3"})
+
+		changes, err := Diff(from, to)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ChangedPaths(changes)).To(Equal([]string{"terms"}))
+	})
+
+	It("compares with nothing when from is nil", func() {
+		var applied *AlertsNrqlConditionSpec
+
+		changes, err := Diff(applied, to)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ChangedPaths(changes)).To(Equal([]string{"enabled", "name", "terms"}))
+	})
+
+	It("redacts secrets", func() {
+		from.APIKey = "old-key"
+		to.APIKey = "new-key"
+
+		changes, err := Diff(from, to)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changes).To(Equal([]FieldChange{{Path: "api_key", From: redactedValue, To: redactedValue}}))
+	})
+
+	It("cuts long values", func() {
+		to.Name = strings.Repeat("a", 2*maxChangeValueLength)
+
+		changes, err := Diff(from, to)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changes[0].To).To(HaveLen(maxChangeValueLength + len("...")))
+		Expect(changes[0].To).To(HaveSuffix("..."))
+	})
+
+	Describe("DiffSetFields", func() {
+		It("skips the fields not set in to", func() {
+			remote := map[string]interface{}{"name": "NRQL Condition", "enabled": true, "violationTimeLimit": "ONE_HOUR"}
+			expected := map[string]interface{}{"name": "renamed", "enabled": false}
+
+			changes, err := DiffSetFields(remote, expected)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(changes).To(Equal([]FieldChange{
+				{Path: "enabled", From: "true"},
+				{Path: "name", From: `"NRQL Condition"`, To: `"renamed"`},
+			}))
+		})
+
+		It("skips ignored paths", func() {
+			changes, err := DiffSetFields(map[string]interface{}{"id": "1"}, map[string]interface{}{"id": "2"}, "id")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(changes).To(BeEmpty())
+		})
+	})
+
+	Describe("DescribeChanges", func() {
+		It("limits the number of changes", func() {
+			var changes []FieldChange
+			for i := 0; i < MaxRecordedChanges+2; i++ {
+				changes = append(changes, FieldChange{Path: fmt.Sprintf("field%d", i), To: "1"})
+			}
+
+			described := DescribeChanges(changes)
+			Expect(described).To(HaveLen(MaxRecordedChanges + 1))
+			Expect(described[0]).To(Equal("field0: 1"))
+			Expect(described[MaxRecordedChanges]).To(Equal("and 2 more"))
+		})
+	})
+})
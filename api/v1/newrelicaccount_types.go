@@ -0,0 +1,84 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NewRelicAccountSpec defines the desired state of NewRelicAccount
+type NewRelicAccountSpec struct {
+	// APIKeySecret is the secret holding the personal API key, its namespace defaults to the namespace of the account.
+	APIKeySecret NewRelicAPIKeySecret `json:"api_key_secret"`
+	Region       string               `json:"region,omitempty"`
+	// +kubebuilder:validation:Minimum=1
+	AccountID int `json:"account_id"`
+	// Endpoints replace the New Relic API endpoints of the region, for example to go through a proxy.
+	Endpoints NewRelicEndpoints `json:"endpoints,omitempty"`
+}
+
+// NewRelicEndpoints are the New Relic API endpoints a client uses.
+type NewRelicEndpoints struct {
+	RestURL      string `json:"rest_url,omitempty"`
+	NerdGraphURL string `json:"nerdgraph_url,omitempty"`
+}
+
+// NewRelicAccountStatus defines the observed state of NewRelicAccount
+type NewRelicAccountStatus struct {
+	ResourceStatus `json:",inline"`
+}
+
+// NewRelicAccountReference refers to a NewRelicAccount from another resource.
+type NewRelicAccountReference struct {
+	Name string `json:"name"`
+	// Namespace defaults to the namespace of the referring resource.
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:printcolumn:name="Account",type="integer",JSONPath=".spec.account_id"
+// +kubebuilder:printcolumn:name="Region",type="string",JSONPath=".spec.region"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status"
+// +kubebuilder:printcolumn:name="Error",type="string",JSONPath=".status.conditions[?(@.type==\"Error\")].status"
+// +kubebuilder:printcolumn:name="Message",type="string",JSONPath=".status.conditions[?(@.type==\"Error\")].message",priority=1
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// NewRelicAccount is the Schema for the NewRelicAccount API.
+// It holds the credentials other resources use through their account_ref.
+type NewRelicAccount struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   NewRelicAccountSpec   `json:"spec,omitempty"`
+	Status NewRelicAccountStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// NewRelicAccountList contains a list of NewRelicAccount
+type NewRelicAccountList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []NewRelicAccount `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&NewRelicAccount{}, &NewRelicAccountList{})
+}
+
+// GetResourceStatus returns the status fields shared by all kinds.
+func (in *NewRelicAccount) GetResourceStatus() *ResourceStatus {
+	return &in.Status.ResourceStatus
+}
+
+// Credentials returns the credentials of the account itself.
+func (in *NewRelicAccount) Credentials() Credentials {
+	secret := in.Spec.APIKeySecret
+	if secret.Namespace == "" {
+		secret.Namespace = in.Namespace
+	}
+
+	return Credentials{
+		APIKeySecret: secret,
+		Region:       in.Spec.Region,
+		AccountID:    in.Spec.AccountID,
+	}
+}
@@ -25,6 +25,25 @@ type GenericConditionSpec struct {
 	APIKey           string               `json:"api_key,omitempty"`
 	APIKeySecret     NewRelicAPIKeySecret `json:"api_key_secret,omitempty"`
 	Region           string               `json:"region,omitempty"`
+	// AccountRef refers to a NewRelicAccount with the credentials to use instead of api_key and region.
+	AccountRef *NewRelicAccountReference `json:"account_ref,omitempty"`
+	// DeletionPolicy is what to do with the condition in New Relic when this resource is deleted, defaults to Delete.
+	DeletionPolicy DeletionPolicy `json:"deletion_policy,omitempty"`
+	// ImportID is the ID of an existing condition in New Relic to adopt instead of creating a new one.
+	ImportID string `json:"import_id,omitempty"`
+	// Adoption is how an existing condition in New Relic is adopted when this resource has none yet,
+	// defaults to byID when import_id is set and to byNameIfUnowned otherwise.
+	Adoption AdoptionPolicy `json:"adoption,omitempty"`
+}
+
+// Credentials returns the credentials fields of the spec.
+func (in GenericConditionSpec) Credentials() Credentials {
+	return Credentials{
+		AccountRef:   in.AccountRef,
+		APIKey:       in.APIKey,
+		APIKeySecret: in.APIKeySecret,
+		Region:       in.Region,
+	}
 }
 
 type NrqlSpecificSpec struct {
@@ -43,12 +62,18 @@ type NrqlQuery struct {
 
 // NrqlAlertConditionStatus defines the observed state of NrqlAlertCondition
 type NrqlAlertConditionStatus struct {
+	ResourceStatus `json:",inline"`
+
 	AppliedSpec *NrqlAlertConditionSpec `json:"applied_spec"`
 	ConditionID int                     `json:"condition_id"`
 }
 
 // +kubebuilder:object:root=true
-// +kubebuilder:printcolumn:name="Created",type="boolean",JSONPath=".status.created"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status"
+// +kubebuilder:printcolumn:name="Synced",type="string",JSONPath=".status.conditions[?(@.type==\"Synced\")].status"
+// +kubebuilder:printcolumn:name="Error",type="string",JSONPath=".status.conditions[?(@.type==\"Error\")].status"
+// +kubebuilder:printcolumn:name="Message",type="string",JSONPath=".status.conditions[?(@.type==\"Error\")].message",priority=1
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 
 // NrqlAlertCondition is the Schema for the nrqlalertconditions API
 type NrqlAlertCondition struct {
@@ -72,6 +97,11 @@ func init() {
 	SchemeBuilder.Register(&NrqlAlertCondition{}, &NrqlAlertConditionList{})
 }
 
+// GetResourceStatus returns the status fields shared by all kinds.
+func (in *NrqlAlertCondition) GetResourceStatus() *ResourceStatus {
+	return &in.Status.ResourceStatus
+}
+
 func (in NrqlAlertConditionSpec) APICondition() alerts.NrqlCondition {
 	jsonString, _ := json.Marshal(in)
 	var APICondition alerts.NrqlCondition
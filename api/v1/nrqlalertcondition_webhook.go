@@ -20,13 +20,12 @@ import (
 	"errors"
 	"strings"
 
-	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
+	customErrors "github.com/newrelic/newrelic-kubernetes-operator/errors"
 	"github.com/newrelic/newrelic-kubernetes-operator/interfaces"
 )
 
@@ -35,9 +34,14 @@ var (
 	log = logf.Log.WithName("nrqlalertcondition-resource")
 )
 
-func (r *NrqlAlertCondition) SetupWebhookWithManager(mgr ctrl.Manager) error {
-	alertClientFunc = interfaces.InitializeAlertsClient
+func (r *NrqlAlertCondition) SetupWebhookWithManager(mgr ctrl.Manager, opts WebhookOptions) error {
+	alertClientFunc = interfaces.DefaultClientPool.AlertsClient
 	k8Client = mgr.GetClient()
+
+	if err := setupAPIKeyDefaulter(mgr, r, log, opts); err != nil {
+		return err
+	}
+
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(r).
 		Complete()
@@ -45,7 +49,7 @@ func (r *NrqlAlertCondition) SetupWebhookWithManager(mgr ctrl.Manager) error {
 
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
 
-// +kubebuilder:webhook:path=/mutate-nr-k8s-newrelic-com-v1-nrqlalertcondition,mutating=true,failurePolicy=fail,groups=nr.k8s.newrelic.com,resources=nrqlalertconditions,verbs=create;update,versions=v1,name=mnrqlalertcondition.kb.io,sideEffects=None
+// +kubebuilder:webhook:path=/mutate-nr-k8s-newrelic-com-v1-nrqlalertcondition,mutating=true,failurePolicy=fail,groups=nr.k8s.newrelic.com,resources=nrqlalertconditions,verbs=create;update,versions=v1,name=mnrqlalertcondition.kb.io,sideEffects=NoneOnDryRun
 
 var _ webhook.Defaulter = &NrqlAlertCondition{}
 
@@ -57,9 +61,16 @@ func (r *NrqlAlertCondition) Default() {
 		log.Info("Setting null Applied Spec to empty interface")
 		r.Status.AppliedSpec = &NrqlAlertConditionSpec{}
 	}
+
+	r.Status.AppliedSpec.APIKey = ""
 	log.Info("r.Status.AppliedSpec after", "r.Status.AppliedSpec", r.Status.AppliedSpec)
 }
 
+// plaintextAPIKey implements apiKeyObject, the key is moved into a secret before Default.
+func (r *NrqlAlertCondition) plaintextAPIKey() (*string, *NewRelicAPIKeySecret) {
+	return &r.Spec.APIKey, &r.Spec.APIKeySecret
+}
+
 // +kubebuilder:webhook:verbs=create;update,path=/validate-nr-k8s-newrelic-com-v1-nrqlalertcondition,mutating=false,failurePolicy=fail,groups=nr.k8s.newrelic.com,resources=nrqlalertconditions,versions=v1,name=vnrqlalertcondition.kb.io,sideEffects=None
 
 var _ webhook.Validator = &NrqlAlertCondition{}
@@ -67,7 +78,6 @@ var _ webhook.Validator = &NrqlAlertCondition{}
 // ValidateCreate implements webhook.Validator so a webhook will be registered for the type
 func (r *NrqlAlertCondition) ValidateCreate() error {
 	log.Info("validate create", "name", r.Name)
-	//TODO this should write this value TO a new secret so code path always reads from a secret
 	err := r.CheckForAPIKeyOrSecret()
 	if err != nil {
 		return err
@@ -78,6 +88,21 @@ func (r *NrqlAlertCondition) ValidateCreate() error {
 		return err
 	}
 
+	err = CheckForPlaintextAPIKey(r, r.Spec.APIKey)
+	if err != nil {
+		return err
+	}
+
+	err = CheckDeletionPolicyAnnotation(r)
+	if err != nil {
+		return err
+	}
+
+	err = CheckAdoption(r.Spec.Adoption, r.Spec.ImportID)
+	if err != nil {
+		return err
+	}
+
 	return r.CheckExistingPolicyID()
 }
 
@@ -94,6 +119,21 @@ func (r *NrqlAlertCondition) ValidateUpdate(old runtime.Object) error {
 		return err
 	}
 
+	err = CheckForPlaintextAPIKey(r, r.Spec.APIKey)
+	if err != nil {
+		return err
+	}
+
+	err = CheckDeletionPolicyAnnotation(r)
+	if err != nil {
+		return err
+	}
+
+	err = CheckAdoption(r.Spec.Adoption, r.Spec.ImportID)
+	if err != nil {
+		return err
+	}
+
 	return r.CheckExistingPolicyID()
 }
 
@@ -108,27 +148,21 @@ func (r *NrqlAlertCondition) ValidateDelete() error {
 func (r *NrqlAlertCondition) CheckExistingPolicyID() error {
 	log.Info("Checking existing", "policyId", r.Spec.ExistingPolicyID)
 	ctx := context.Background()
-	var apiKey string
-
-	if r.Spec.APIKey == "" {
-		key := types.NamespacedName{Namespace: r.Spec.APIKeySecret.Namespace, Name: r.Spec.APIKeySecret.Name}
-		var apiKeySecret v1.Secret
-		getErr := k8Client.Get(ctx, key, &apiKeySecret)
-		if getErr != nil {
-			log.Error(getErr, "Error getting secret")
-			return getErr
-		}
-		apiKey = string(apiKeySecret.Data[r.Spec.APIKeySecret.KeyName])
-	} else {
-		apiKey = r.Spec.APIKey
+
+	creds, err := r.Spec.Credentials().Resolve(ctx, k8Client, r.Namespace)
+	if err != nil {
+		log.Error(err, "Error getting credentials")
+		return err
 	}
 
-	alertsClient, errAlertClient := alertClientFunc(apiKey, r.Spec.Region)
+	apiKey := creds.APIKey
+
+	alertsClient, errAlertClient := creds.AlertsClient(alertClientFunc)
 	if errAlertClient != nil {
 		log.Error(errAlertClient, "failed to get policy",
 			"policyId", r.Spec.ExistingPolicyID,
 			"API Key", interfaces.PartialAPIKey(apiKey),
-			"region", r.Spec.Region,
+			"region", creds.Region,
 		)
 		return errAlertClient
 	}
@@ -137,7 +171,7 @@ func (r *NrqlAlertCondition) CheckExistingPolicyID() error {
 	if errAlertPolicy != nil {
 		if r.GetDeletionTimestamp() != nil {
 			log.Info("Deleting resource", "errAlertPolicy", errAlertPolicy)
-			if strings.Contains(errAlertPolicy.Error(), "no alert policy found for id") {
+			if customErrors.IsNotFound(errAlertPolicy) {
 				log.Info("ExistingAlertPolicy not found but we are deleting the condition so this is ok")
 				return nil
 			}
@@ -145,9 +179,9 @@ func (r *NrqlAlertCondition) CheckExistingPolicyID() error {
 		log.Error(errAlertPolicy, "failed to get policy",
 			"policyId", r.Spec.ExistingPolicyID,
 			"API Key", interfaces.PartialAPIKey(apiKey),
-			"region", r.Spec.Region,
+			"region", creds.Region,
 		)
-		return errAlertPolicy
+		return existingPolicyError(r.Spec.ExistingPolicyID, errAlertPolicy)
 	}
 
 	if alertPolicy.ID != r.Spec.ExistingPolicyID {
@@ -159,23 +193,13 @@ func (r *NrqlAlertCondition) CheckExistingPolicyID() error {
 }
 
 func (r *NrqlAlertCondition) CheckForAPIKeyOrSecret() error {
-	if r.Spec.APIKey != "" {
-		return nil
-	}
-
-	if r.Spec.APIKeySecret != (NewRelicAPIKeySecret{}) {
-		if r.Spec.APIKeySecret.Name != "" && r.Spec.APIKeySecret.Namespace != "" && r.Spec.APIKeySecret.KeyName != "" {
-			return nil
-		}
-	}
-
-	return errors.New("either api_key or api_key_secret must be set")
+	return r.Spec.Credentials().Check()
 }
 
 func (r *NrqlAlertCondition) CheckRequiredFields() error {
 	missingFields := []string{}
 
-	if r.Spec.Region == "" {
+	if r.Spec.Region == "" && r.Spec.AccountRef == nil {
 		missingFields = append(missingFields, "region")
 	}
 
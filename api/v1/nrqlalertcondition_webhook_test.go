@@ -9,6 +9,7 @@ import (
 
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
 	"github.com/newrelic/newrelic-kubernetes-operator/interfaces"
 
@@ -88,11 +89,22 @@ var _ = Describe("ValidateCreate", func() {
 	})
 
 	Context("ValidateCreate", func() {
-		Context("When given a valid API key", func() {
-			It("should not return an error", func() {
+		Context("When given a plaintext API key", func() {
+			It("should move it into a secret", func() {
+				r.Namespace = "my-namespace"
+				defaultAPIKeyObject(context.Background(), logf.Log, &r, false)
+
+				Expect(r.Spec.APIKey).To(BeEmpty())
+				Expect(r.Spec.APIKeySecret.Namespace).To(Equal("my-namespace"))
+
 				err := r.ValidateCreate()
 				Expect(err).ToNot(HaveOccurred())
 			})
+
+			It("should return an error if the key was not moved", func() {
+				err := r.ValidateCreate()
+				Expect(err).To(HaveOccurred())
+			})
 		})
 
 		Context("When given an invalid API key", func() {
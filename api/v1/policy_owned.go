@@ -0,0 +1,118 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+
+	kErr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// policyReader reads the policies of condition resources bypassing the cache, so an edit is checked
+// against the latest spec of its policy. It is set up with the condition webhooks.
+var policyReader client.Reader
+
+// BreakGlassAnnotation allows editing the spec of a condition resource created for an AlertsPolicy
+// when set to "true". Its policy leaves the condition as it is until the annotation is removed.
+const BreakGlassAnnotation = "nr.k8s.newrelic.com/break-glass"
+
+// HasBreakGlass returns true if obj carries the break-glass annotation.
+func HasBreakGlass(obj metav1.Object) bool {
+	return obj.GetAnnotations()[BreakGlassAnnotation] == "true"
+}
+
+// OwningPolicy returns the name of the AlertsPolicy controlling obj, or "" if none does.
+func OwningPolicy(obj metav1.Object) string {
+	owner := metav1.GetControllerOf(obj)
+	if owner == nil || owner.Kind != "AlertsPolicy" || owner.APIVersion != GroupVersion.String() {
+		return ""
+	}
+
+	return owner.Name
+}
+
+// CheckPolicyOwnedEdit returns an error if the spec of a condition resource controlled by an
+// AlertsPolicy was changed from previous to a spec that isn't the one the policy has for the
+// resource, as the policy would overwrite it. The policy itself only writes its own conditions.
+// Only the fields of the conditions in the policy are compared, the fields the policy sets from
+// its own spec can be changed, as can conditions with the break-glass annotation.
+func CheckPolicyOwnedEdit(obj metav1.Object, condition, previous AlertsPolicyCondition) error {
+	policyName := OwningPolicy(obj)
+	if policyName == "" || HasBreakGlass(obj) || policyReader == nil {
+		return nil
+	}
+
+	if condition.SpecIdentity() == previous.SpecIdentity() {
+		return nil
+	}
+
+	var policy AlertsPolicy
+
+	err := policyReader.Get(context.Background(), types.NamespacedName{Namespace: obj.GetNamespace(), Name: policyName}, &policy)
+	if kErr.IsNotFound(err) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	for _, policyCondition := range policyConditionsOf(&policy, obj) {
+		if policyCondition.SpecIdentity() == condition.SpecIdentity() {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%s is managed by AlertsPolicy %s, change the condition in the policy or set the %s annotation to \"true\"", obj.GetName(), policyName, BreakGlassAnnotation)
+}
+
+// policyConditionsOf returns the conditions in the spec of policy, and in the spec it applied last,
+// that are for the condition resource obj: the ones naming obj, the ones with the key of such a
+// condition or the key obj is named after, and when obj was created for a condition without a key,
+// the conditions without a key or a resource yet that have the same name or spec. The spec of
+// another condition of the policy is never the one of obj.
+func policyConditionsOf(policy *AlertsPolicy, obj metav1.Object) []AlertsPolicyCondition {
+	var conditions []AlertsPolicyCondition
+
+	conditions = append(conditions, policy.Spec.Conditions...)
+	if policy.Status.AppliedSpec != nil {
+		conditions = append(conditions, policy.Status.AppliedSpec.Conditions...)
+	}
+
+	keys := make(map[string]bool)
+	var unkeyed []AlertsPolicyCondition
+
+	for _, c := range conditions {
+		if c.Name == obj.GetName() {
+			if c.Key == "" {
+				unkeyed = append(unkeyed, c)
+			} else {
+				keys[c.Key] = true
+			}
+		}
+
+		if c.Key != "" && IsConditionResourceName(policy.Name, &c, obj.GetName()) {
+			keys[c.Key] = true
+		}
+	}
+
+	var matching []AlertsPolicyCondition
+
+	for _, c := range conditions {
+		switch {
+		case c.Name == obj.GetName(), c.Key != "" && keys[c.Key]:
+			matching = append(matching, c)
+		case c.Name == "" && c.Key == "":
+			for _, named := range unkeyed {
+				if c.Spec.Name == named.Spec.Name || c.SpecIdentity() == named.SpecIdentity() {
+					matching = append(matching, c)
+					break
+				}
+			}
+		}
+	}
+
+	return matching
+}
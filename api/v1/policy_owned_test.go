@@ -0,0 +1,146 @@
+package v1
+
+import (
+	"context"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("CheckPolicyOwnedEdit", func() {
+	var (
+		previousReader client.Reader
+		condition      *AlertsNrqlCondition
+		inline         AlertsPolicyCondition
+		other          AlertsPolicyCondition
+	)
+
+	BeforeEach(func() {
+		s := runtime.NewScheme()
+		Expect(AddToScheme(s)).To(Succeed())
+
+		reader := fake.NewFakeClientWithScheme(s)
+		previousReader = policyReader
+		policyReader = reader
+
+		inline = AlertsPolicyCondition{Key: "high-cpu"}
+		inline.Spec.Type = "NRQL"
+		inline.Spec.Name = "High CPU"
+		inline.Spec.Terms = []AlertsNrqlConditionTerm{{Threshold: "5"}}
+
+		other = inline
+		other.Key = "high-memory"
+		other.Spec.Name = "High memory"
+
+		policy := &AlertsPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "my-policy", Namespace: "default"},
+			Spec:       AlertsPolicySpec{Name: "my policy", Conditions: []AlertsPolicyCondition{inline, other}},
+		}
+		Expect(reader.Create(context.Background(), policy)).To(Succeed())
+
+		controller := true
+		condition = &AlertsNrqlCondition{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "my-policy-high-cpu",
+				Namespace: "default",
+				OwnerReferences: []metav1.OwnerReference{{
+					APIVersion: GroupVersion.String(),
+					Kind:       "AlertsPolicy",
+					Name:       "my-policy",
+					Controller: &controller,
+				}},
+			},
+		}
+	})
+
+	AfterEach(func() {
+		policyReader = previousReader
+	})
+
+	edited := func() AlertsPolicyCondition {
+		changed := inline
+		changed.Spec.Terms = []AlertsNrqlConditionTerm{{Threshold: "10"}}
+
+		return changed
+	}
+
+	It("rejects changes to conditions of a policy", func() {
+		err := CheckPolicyOwnedEdit(condition, edited(), inline)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("managed by AlertsPolicy my-policy"))
+	})
+
+	It("accepts the spec of a condition of the policy", func() {
+		Expect(CheckPolicyOwnedEdit(condition, inline, edited())).To(Succeed())
+	})
+
+	It("rejects the spec of another condition of the policy", func() {
+		err := CheckPolicyOwnedEdit(condition, other, inline)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("managed by AlertsPolicy my-policy"))
+	})
+
+	It("recognizes a numbered resource name of a condition of the policy", func() {
+		condition.Name = ConditionResourceName("my-policy", &inline, 2)
+
+		Expect(CheckPolicyOwnedEdit(condition, inline, edited())).To(Succeed())
+		Expect(CheckPolicyOwnedEdit(condition, other, inline)).ToNot(Succeed())
+	})
+
+	It("recognizes a cut resource name of a condition of the policy", func() {
+		policy := &AlertsPolicy{}
+		Expect(policyReader.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "my-policy"}, policy)).To(Succeed())
+
+		inline.Key = strings.Repeat("high-cpu-", 10)
+		policy.Spec.Conditions = []AlertsPolicyCondition{inline, other}
+		Expect(policyReader.(client.Client).Update(context.Background(), policy)).To(Succeed())
+
+		condition.Name = ConditionResourceName("my-policy", &inline, 1)
+		Expect(len(condition.Name)).To(BeNumerically("<=", 63))
+
+		Expect(CheckPolicyOwnedEdit(condition, inline, edited())).To(Succeed())
+		Expect(CheckPolicyOwnedEdit(condition, other, inline)).ToNot(Succeed())
+	})
+
+	It("accepts the spec the policy applied last", func() {
+		policy := &AlertsPolicy{}
+		Expect(policyReader.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "my-policy"}, policy)).To(Succeed())
+
+		policy.Status.AppliedSpec = policy.Spec.DeepCopy()
+		policy.Spec.Conditions = []AlertsPolicyCondition{edited(), other}
+		Expect(policyReader.(client.Client).Update(context.Background(), policy)).To(Succeed())
+
+		Expect(CheckPolicyOwnedEdit(condition, inline, edited())).To(Succeed())
+		Expect(CheckPolicyOwnedEdit(condition, edited(), inline)).To(Succeed())
+	})
+
+	It("accepts the spec of a condition with the account ID of its policy", func() {
+		policy := &AlertsPolicy{}
+		Expect(policyReader.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "my-policy"}, policy)).To(Succeed())
+
+		policy.Spec.AccountID = 42
+		Expect(policyReader.(client.Client).Update(context.Background(), policy)).To(Succeed())
+
+		child := inline
+		child.Spec.AccountID = policy.Spec.AccountID
+
+		Expect(CheckPolicyOwnedEdit(condition, child, edited())).To(Succeed())
+	})
+
+	It("accepts changes to conditions with the break-glass annotation", func() {
+		condition.Annotations = map[string]string{BreakGlassAnnotation: "true"}
+
+		Expect(CheckPolicyOwnedEdit(condition, edited(), inline)).To(Succeed())
+	})
+
+	It("accepts changes to conditions without a policy", func() {
+		condition.OwnerReferences = nil
+
+		Expect(CheckPolicyOwnedEdit(condition, edited(), inline)).To(Succeed())
+	})
+})
@@ -18,6 +18,7 @@ package v1
 import (
 	"encoding/json"
 	"hash/fnv"
+	"reflect"
 
 	"github.com/newrelic/newrelic-client-go/pkg/alerts"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -33,8 +34,17 @@ type PolicySpec struct {
 	Name               string               `json:"name"`
 	APIKey             string               `json:"api_key,omitempty"`
 	APIKeySecret       NewRelicAPIKeySecret `json:"api_key_secret,omitempty"`
-	Region             string               `json:"region"`
+	Region             string               `json:"region,omitempty"`
 	Conditions         []PolicyCondition    `json:"conditions,omitempty"`
+	// AccountRef refers to a NewRelicAccount with the credentials to use instead of api_key and region.
+	AccountRef *NewRelicAccountReference `json:"account_ref,omitempty"`
+	// DeletionPolicy is what to do with the policy in New Relic when this resource is deleted, defaults to Delete.
+	DeletionPolicy DeletionPolicy `json:"deletion_policy,omitempty"`
+	// ImportID is the ID of an existing policy in New Relic to adopt instead of creating a new one.
+	ImportID string `json:"import_id,omitempty"`
+	// Adoption is how an existing policy in New Relic is adopted when this resource has none yet,
+	// defaults to byID when import_id is set and to byNameIfUnowned otherwise.
+	Adoption AdoptionPolicy `json:"adoption,omitempty"`
 }
 
 //PolicyCondition defined the conditions contained within a a policy
@@ -53,11 +63,18 @@ type ConditionSpec struct {
 
 // PolicyStatus defines the observed state of Policy
 type PolicyStatus struct {
+	ResourceStatus `json:",inline"`
+
 	AppliedSpec *PolicySpec `json:"applied_spec"`
 	PolicyID    int         `json:"policy_id"`
 }
 
 // +kubebuilder:object:root=true
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status"
+// +kubebuilder:printcolumn:name="Synced",type="string",JSONPath=".status.conditions[?(@.type==\"Synced\")].status"
+// +kubebuilder:printcolumn:name="Error",type="string",JSONPath=".status.conditions[?(@.type==\"Error\")].status"
+// +kubebuilder:printcolumn:name="Message",type="string",JSONPath=".status.conditions[?(@.type==\"Error\")].message",priority=1
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 
 // Policy is the Schema for the policies API
 type Policy struct {
@@ -81,6 +98,21 @@ func init() {
 	SchemeBuilder.Register(&Policy{}, &PolicyList{})
 }
 
+// GetResourceStatus returns the status fields shared by all kinds.
+func (in *Policy) GetResourceStatus() *ResourceStatus {
+	return &in.Status.ResourceStatus
+}
+
+// Credentials returns the credentials fields of the spec.
+func (in PolicySpec) Credentials() Credentials {
+	return Credentials{
+		AccountRef:   in.AccountRef,
+		APIKey:       in.APIKey,
+		APIKeySecret: in.APIKeySecret,
+		Region:       in.Region,
+	}
+}
+
 func (in PolicySpec) APIPolicy() alerts.Policy {
 	jsonString, _ := json.Marshal(in)
 	var APIPolicy alerts.Policy
@@ -99,6 +131,7 @@ func (p *PolicyCondition) SpecHash() uint32 {
 	strippedPolicy.Spec.APIKeySecret = NewRelicAPIKeySecret{}
 	strippedPolicy.Spec.APIKey = ""
 	strippedPolicy.Spec.Region = ""
+	strippedPolicy.Spec.AccountRef = nil
 	strippedPolicy.Spec.ExistingPolicyID = 0
 	conditionTemplateSpecHasher := fnv.New32a()
 	DeepHashObject(conditionTemplateSpecHasher, strippedPolicy)
@@ -135,6 +168,10 @@ func (in PolicySpec) Equals(policyToCompare PolicySpec) bool {
 		return false
 	}
 
+	if !reflect.DeepEqual(in.AccountRef, policyToCompare.AccountRef) {
+		return false
+	}
+
 	if len(in.Conditions) != len(policyToCompare.Conditions) {
 		return false
 	}
@@ -31,7 +31,13 @@ import (
 var Log = logf.Log.WithName("policy-resource")
 var defaultPolicyIncidentPreference = "PER_POLICY"
 
-func (r *Policy) SetupWebhookWithManager(mgr ctrl.Manager) error {
+func (r *Policy) SetupWebhookWithManager(mgr ctrl.Manager, opts WebhookOptions) error {
+	k8Client = mgr.GetClient()
+
+	if err := setupAPIKeyDefaulter(mgr, r, Log, opts); err != nil {
+		return err
+	}
+
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(r).
 		Complete()
@@ -39,7 +45,7 @@ func (r *Policy) SetupWebhookWithManager(mgr ctrl.Manager) error {
 
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
 
-// +kubebuilder:webhook:path=/mutate-nr-k8s-newrelic-com-v1-policy,mutating=true,failurePolicy=fail,groups=nr.k8s.newrelic.com,resources=policies,verbs=create;update,versions=v1,name=mpolicy.kb.io,sideEffects=None
+// +kubebuilder:webhook:path=/mutate-nr-k8s-newrelic-com-v1-policy,mutating=true,failurePolicy=fail,groups=nr.k8s.newrelic.com,resources=policies,verbs=create;update,versions=v1,name=mpolicy.kb.io,sideEffects=NoneOnDryRun
 
 var _ webhook.Defaulter = &Policy{}
 
@@ -52,9 +58,16 @@ func (r *Policy) Default() {
 		r.Status.AppliedSpec = &PolicySpec{}
 	}
 
+	r.Status.AppliedSpec.APIKey = ""
+
 	r.DefaultIncidentPreference()
 }
 
+// plaintextAPIKey implements apiKeyObject, the key is moved into a secret before Default.
+func (r *Policy) plaintextAPIKey() (*string, *NewRelicAPIKeySecret) {
+	return &r.Spec.APIKey, &r.Spec.APIKeySecret
+}
+
 // +kubebuilder:webhook:verbs=create;update,path=/validate-nr-k8s-newrelic-com-v1-policy,mutating=false,failurePolicy=fail,groups=nr.k8s.newrelic.com,resources=policies,versions=v1,name=vpolicy.kb.io,sideEffects=None
 
 var _ webhook.Validator = &Policy{}
@@ -80,6 +93,21 @@ func (r *Policy) ValidateCreate() error {
 		collectedErrors.Collect(err)
 	}
 
+	err = CheckForPlaintextAPIKey(r, r.Spec.APIKey)
+	if err != nil {
+		collectedErrors.Collect(err)
+	}
+
+	err = CheckDeletionPolicyAnnotation(r)
+	if err != nil {
+		collectedErrors.Collect(err)
+	}
+
+	err = CheckAdoption(r.Spec.Adoption, r.Spec.ImportID)
+	if err != nil {
+		collectedErrors.Collect(err)
+	}
+
 	if len(*collectedErrors) > 0 {
 		Log.Info("Errors encountered validating policy", "collectedErrors", collectedErrors)
 		return collectedErrors
@@ -109,6 +137,21 @@ func (r *Policy) ValidateUpdate(old runtime.Object) error {
 		collectedErrors.Collect(err)
 	}
 
+	err = CheckForPlaintextAPIKey(r, r.Spec.APIKey)
+	if err != nil {
+		collectedErrors.Collect(err)
+	}
+
+	err = CheckDeletionPolicyAnnotation(r)
+	if err != nil {
+		collectedErrors.Collect(err)
+	}
+
+	err = CheckAdoption(r.Spec.Adoption, r.Spec.ImportID)
+	if err != nil {
+		collectedErrors.Collect(err)
+	}
+
 	if len(*collectedErrors) > 0 {
 		Log.Info("Errors encountered validating policy", "collectedErrors", collectedErrors)
 		return collectedErrors
@@ -165,15 +208,5 @@ func (r *Policy) ValidateIncidentPreference() error {
 }
 
 func (r *Policy) CheckForAPIKeyOrSecret() error {
-	if r.Spec.APIKey != "" {
-		return nil
-	}
-
-	if r.Spec.APIKeySecret != (NewRelicAPIKeySecret{}) {
-		if r.Spec.APIKeySecret.Name != "" && r.Spec.APIKeySecret.Namespace != "" && r.Spec.APIKeySecret.KeyName != "" {
-			return nil
-		}
-	}
-
-	return errors.New("either api_key or api_key_secret must be set")
+	return r.Spec.Credentials().Check()
 }
@@ -7,6 +7,7 @@ import (
 
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
 	"github.com/newrelic/newrelic-kubernetes-operator/interfaces"
 
@@ -48,7 +49,11 @@ var _ = Describe("Policy_webhooks", func() {
 				Spec: PolicySpec{
 					Name:               "Test Policy",
 					IncidentPreference: "PER_POLICY",
-					APIKey:             "api-key",
+					APIKeySecret: NewRelicAPIKeySecret{
+						Name:      "my-api-key-secret",
+						Namespace: "my-namespace",
+						KeyName:   "my-api-key",
+					},
 				},
 			}
 
@@ -60,25 +65,40 @@ var _ = Describe("Policy_webhooks", func() {
 			}
 		})
 
-		Context("When given a valid API key", func() {
-			It("should not return an error", func() {
+		Context("When given a plaintext API key", func() {
+			BeforeEach(func() {
+				r.Spec.APIKey = "api-key"
+				r.Spec.APIKeySecret = NewRelicAPIKeySecret{}
+			})
+
+			It("should move it into a secret", func() {
+				r.Namespace = "my-namespace"
+				defaultAPIKeyObject(context.Background(), logf.Log, &r, false)
+
+				Expect(r.Spec.APIKey).To(BeEmpty())
+				Expect(r.Spec.APIKeySecret.Namespace).To(Equal("my-namespace"))
+
 				err := r.ValidateCreate()
 				Expect(err).ToNot(HaveOccurred())
 			})
+
+			It("should return an error if the key was not moved", func() {
+				err := r.ValidateCreate()
+				Expect(err).To(HaveOccurred())
+			})
 		})
 
 		Context("When given an invalid API key", func() {
 			It("should return an error", func() {
-				r.Spec.APIKey = ""
+				r.Spec.APIKeySecret = NewRelicAPIKeySecret{}
 				err := r.ValidateCreate()
 				Expect(err).To(HaveOccurred())
-				Expect(err.Error()).To(Equal("either api_key or api_key_secret must be set"))
+				Expect(err.Error()).To(Equal("either api_key, api_key_secret or account_ref must be set"))
 			})
 		})
 
 		Context("when given a valid API key in a secret", func() {
 			It("should not return an error", func() {
-				r.Spec.APIKey = ""
 				r.Spec.APIKeySecret = NewRelicAPIKeySecret{
 					Name:      "my-api-key-secret",
 					Namespace: "my-namespace",
@@ -193,10 +213,10 @@ var _ = Describe("Policy_webhooks", func() {
 			Context("and invalid API key and incident_preference", func() {
 				It("should include all errors", func() {
 					r.Spec.IncidentPreference = "totally bogus"
-					r.Spec.APIKey = ""
+					r.Spec.APIKeySecret = NewRelicAPIKeySecret{}
 					err := r.ValidateCreate()
 					Expect(err).To(HaveOccurred())
-					Expect(err.Error()).To(ContainSubstring("either api_key or api_key_secret must be set"))
+					Expect(err.Error()).To(ContainSubstring("either api_key, api_key_secret or account_ref must be set"))
 					Expect(err.Error()).To(ContainSubstring("duplicate conditions detected"))
 					Expect(err.Error()).To(ContainSubstring("incident preference must be"))
 				})
@@ -0,0 +1,336 @@
+package v1
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/newrelic/newrelic-kubernetes-operator/internal/redact"
+)
+
+// Condition types reported on every resource managed by the operator.
+const (
+	// ConditionReady is True when the resource exists in New Relic and matches the spec.
+	ConditionReady = "Ready"
+	// ConditionSynced is True when the last write to the New Relic API succeeded.
+	ConditionSynced = "Synced"
+	// ConditionError is True when the last reconcile failed, the message holds the error.
+	ConditionError = "Error"
+	// ConditionDrifted is True when the object in New Relic no longer matches the spec.
+	// It is only reported by kinds that are checked for drift: AlertsPolicy, AlertsNrqlCondition,
+	// AlertsAPMCondition and AlertsChannel. The legacy Policy, NrqlAlertCondition and
+	// ApmAlertCondition kinds are not checked and never report it.
+	ConditionDrifted = "Drifted"
+	// ConditionPaused is True while reconciliation of the resource is paused, the message
+	// says what is waiting to be written to New Relic.
+	ConditionPaused = "Paused"
+	// ConditionPlanned is True when the resource is in dry-run mode and the writes it needs were
+	// planned, they are listed in the plan. It is False when planning them failed.
+	ConditionPlanned = "Planned"
+)
+
+// Reasons used on the conditions above.
+const (
+	ReasonSynced            = "Synced"
+	ReasonCredentialsFailed = "CredentialsFailed"
+	ReasonCreateFailed      = "CreateFailed"
+	ReasonUpdateFailed      = "UpdateFailed"
+	ReasonDeleteFailed      = "DeleteFailed"
+	ReasonReadFailed        = "ReadFailed"
+	ReasonLinkFailed        = "LinkFailed"
+	ReasonValidationFailed  = "ValidationFailed"
+	ReasonConflict          = "Conflict"
+	ReasonImportFailed      = "ImportFailed"
+	ReasonInSync            = "InSync"
+	ReasonDriftDetected     = "DriftDetected"
+	ReasonDriftCorrected    = "DriftCorrected"
+	ReasonPaused            = "Paused"
+	ReasonResumed           = "Resumed"
+	ReasonPlanned           = "Planned"
+	// ReasonConditionsNotReady is the reason an AlertsPolicy is not ready while some of the
+	// resources of its conditions are not.
+	ReasonConditionsNotReady = "ConditionsNotReady"
+	// ReasonWaitingForPolicy is the reason a condition is not ready while the AlertsPolicy it
+	// refers to has no ID yet.
+	ReasonWaitingForPolicy = "WaitingForPolicy"
+	// ReasonWaitingForChannels is the reason an AlertsPolicy is not synced while some of the
+	// AlertsChannels it refers to have no ID yet.
+	ReasonWaitingForChannels = "WaitingForChannels"
+)
+
+// Condition contains details for one aspect of the current state of a resource.
+// It mirrors metav1.Condition, which is not available in the apimachinery version
+// used by the operator yet.
+type Condition struct {
+	// Type of condition in CamelCase.
+	// +kubebuilder:validation:Required
+	Type string `json:"type"`
+	// Status of the condition, one of True, False, Unknown.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Enum=True;False;Unknown
+	Status metav1.ConditionStatus `json:"status"`
+	// ObservedGeneration is the metadata.generation the condition was set for.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// LastTransitionTime is the last time the condition changed status.
+	// +kubebuilder:validation:Required
+	LastTransitionTime metav1.Time `json:"lastTransitionTime"`
+	// Reason is a programmatic identifier for the last transition in CamelCase.
+	// +kubebuilder:validation:Required
+	Reason string `json:"reason"`
+	// Message is a human readable message about the transition.
+	// +optional
+	Message string `json:"message"`
+}
+
+// ResourceStatus holds the status fields shared by all kinds.
+type ResourceStatus struct {
+	// ObservedGeneration is the metadata.generation last reconciled by the operator.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Conditions describe the current state of the resource.
+	Conditions []Condition `json:"conditions,omitempty"`
+	// Plan lists the writes to New Relic the resource needs, while it is in dry-run mode.
+	// +optional
+	Plan []PlannedOperation `json:"plan,omitempty"`
+	// Changes lists the fields that differed the last time the resource was updated in New Relic,
+	// between the spec and the spec applied before, or found changed in New Relic, between the
+	// object in New Relic and the spec. They are JSON paths with the old and the new value.
+	// +optional
+	Changes []string `json:"changes,omitempty"`
+}
+
+// PlannedOperation is a write to New Relic planned in dry-run mode.
+type PlannedOperation struct {
+	// Operation is Create, Update or Delete.
+	Operation string `json:"operation"`
+	// Resource is the kind of object written, a New Relic object like "policy" or a resource
+	// like "AlertsNrqlCondition".
+	Resource string `json:"resource"`
+	// ID is the ID of the New Relic object or the name of the resource written, empty for New
+	// Relic objects that would be created.
+	// +optional
+	ID string `json:"id,omitempty"`
+	// Changes lists the fields written, as JSON paths with the value they would have, and the
+	// value they have now when it is known.
+	// +optional
+	Changes []string `json:"changes,omitempty"`
+}
+
+// StatusObject is implemented by all kinds that carry a ResourceStatus.
+// +kubebuilder:object:generate=false
+type StatusObject interface {
+	metav1.Object
+	runtime.Object
+	GetResourceStatus() *ResourceStatus
+}
+
+// SetCondition adds the condition to the list, or replaces the condition of the same type.
+// LastTransitionTime is only changed when the status of the condition changes. New Relic keys
+// in the message are masked.
+func SetCondition(conditions *[]Condition, newCondition Condition) {
+	if conditions == nil {
+		return
+	}
+
+	if newCondition.LastTransitionTime.IsZero() {
+		newCondition.LastTransitionTime = metav1.Now()
+	}
+
+	newCondition.Message = redact.String(newCondition.Message)
+
+	existing := FindCondition(*conditions, newCondition.Type)
+	if existing == nil {
+		*conditions = append(*conditions, newCondition)
+		return
+	}
+
+	if existing.Status != newCondition.Status {
+		existing.Status = newCondition.Status
+		existing.LastTransitionTime = newCondition.LastTransitionTime
+	}
+
+	existing.Reason = newCondition.Reason
+	existing.Message = newCondition.Message
+	existing.ObservedGeneration = newCondition.ObservedGeneration
+}
+
+// FindCondition returns the condition of the given type, or nil if it is not set.
+func FindCondition(conditions []Condition, conditionType string) *Condition {
+	for i := range conditions {
+		if conditions[i].Type == conditionType {
+			return &conditions[i]
+		}
+	}
+
+	return nil
+}
+
+// IsConditionTrue returns true if the condition of the given type has status True.
+func IsConditionTrue(conditions []Condition, conditionType string) bool {
+	condition := FindCondition(conditions, conditionType)
+
+	return condition != nil && condition.Status == metav1.ConditionTrue
+}
+
+// MarkSynced records that the spec was applied to New Relic.
+func (in *ResourceStatus) MarkSynced() {
+	in.setConditions(metav1.ConditionTrue, ReasonSynced, "")
+
+	if IsConditionTrue(in.Conditions, ConditionDrifted) {
+		in.setDrifted(metav1.ConditionFalse, ReasonSynced, "")
+	}
+}
+
+// MarkInSync records that the object in New Relic matches the spec.
+func (in *ResourceStatus) MarkInSync() {
+	in.setDrifted(metav1.ConditionFalse, ReasonInSync, "")
+}
+
+// MarkDrifted records that the object in New Relic no longer matches the spec and
+// was left as it is. The message describes the difference.
+func (in *ResourceStatus) MarkDrifted(message string) {
+	in.setDrifted(metav1.ConditionTrue, ReasonDriftDetected, message)
+	SetCondition(&in.Conditions, Condition{
+		Type:    ConditionReady,
+		Status:  metav1.ConditionFalse,
+		Reason:  ReasonDriftDetected,
+		Message: message,
+	})
+}
+
+// MarkDriftCorrected records that the object in New Relic no longer matched the spec
+// and is being written again. The message describes the difference.
+func (in *ResourceStatus) MarkDriftCorrected(message string) {
+	in.setDrifted(metav1.ConditionFalse, ReasonDriftCorrected, message)
+}
+
+// MarkPaused records that reconciliation is paused. The message says what is waiting to be
+// written to New Relic.
+func (in *ResourceStatus) MarkPaused(message string) {
+	SetCondition(&in.Conditions, Condition{
+		Type:    ConditionPaused,
+		Status:  metav1.ConditionTrue,
+		Reason:  ReasonPaused,
+		Message: message,
+	})
+}
+
+// MarkResumed records that reconciliation is no longer paused, if it was.
+func (in *ResourceStatus) MarkResumed() {
+	if !IsConditionTrue(in.Conditions, ConditionPaused) {
+		return
+	}
+
+	SetCondition(&in.Conditions, Condition{
+		Type:   ConditionPaused,
+		Status: metav1.ConditionFalse,
+		Reason: ReasonResumed,
+	})
+}
+
+// MarkPlanned records that the writes in plan are what the resource needs, without writing them.
+func (in *ResourceStatus) MarkPlanned(plan []PlannedOperation) {
+	in.Plan = plan
+
+	message := fmt.Sprintf("%d writes to New Relic planned", len(plan))
+	if len(plan) == 0 {
+		message = "nothing to write to New Relic"
+	}
+
+	SetCondition(&in.Conditions, Condition{
+		Type:    ConditionPlanned,
+		Status:  metav1.ConditionTrue,
+		Reason:  ReasonPlanned,
+		Message: message,
+	})
+}
+
+// MarkPlanFailed records that planning the writes the resource needs failed, the message says why.
+func (in *ResourceStatus) MarkPlanFailed(reason, message string) {
+	in.Plan = nil
+
+	SetCondition(&in.Conditions, Condition{
+		Type:    ConditionPlanned,
+		Status:  metav1.ConditionFalse,
+		Reason:  reason,
+		Message: message,
+	})
+}
+
+// ClearPlan removes the plan and the Planned condition, once the resource is no longer in
+// dry-run mode.
+func (in *ResourceStatus) ClearPlan() {
+	in.Plan = nil
+
+	for i := range in.Conditions {
+		if in.Conditions[i].Type == ConditionPlanned {
+			in.Conditions = append(in.Conditions[:i], in.Conditions[i+1:]...)
+			return
+		}
+	}
+}
+
+// MarkWaiting records that the spec can't be applied to New Relic yet, without failing. The message
+// says what it is waiting for.
+func (in *ResourceStatus) MarkWaiting(reason, message string) {
+	in.setConditions(metav1.ConditionFalse, reason, message)
+	SetCondition(&in.Conditions, Condition{
+		Type:   ConditionError,
+		Status: metav1.ConditionFalse,
+		Reason: reason,
+	})
+}
+
+// MarkFailed records that applying the spec to New Relic failed with err.
+func (in *ResourceStatus) MarkFailed(reason string, err error) {
+	message := ""
+	if err != nil {
+		message = redact.Error(err).Error()
+	}
+
+	in.setConditions(metav1.ConditionFalse, reason, message)
+}
+
+// SetObservedGeneration stamps the status and all of its conditions with the given generation.
+func (in *ResourceStatus) SetObservedGeneration(generation int64) {
+	in.ObservedGeneration = generation
+	for i := range in.Conditions {
+		in.Conditions[i].ObservedGeneration = generation
+	}
+}
+
+func (in *ResourceStatus) setDrifted(status metav1.ConditionStatus, reason, message string) {
+	SetCondition(&in.Conditions, Condition{
+		Type:    ConditionDrifted,
+		Status:  status,
+		Reason:  reason,
+		Message: message,
+	})
+}
+
+func (in *ResourceStatus) setConditions(status metav1.ConditionStatus, reason, message string) {
+	errorStatus := metav1.ConditionFalse
+	if status != metav1.ConditionTrue {
+		errorStatus = metav1.ConditionTrue
+	}
+
+	SetCondition(&in.Conditions, Condition{
+		Type:    ConditionReady,
+		Status:  status,
+		Reason:  reason,
+		Message: message,
+	})
+	SetCondition(&in.Conditions, Condition{
+		Type:    ConditionSynced,
+		Status:  status,
+		Reason:  reason,
+		Message: message,
+	})
+	SetCondition(&in.Conditions, Condition{
+		Type:    ConditionError,
+		Status:  errorStatus,
+		Reason:  reason,
+		Message: message,
+	})
+}
@@ -0,0 +1,167 @@
+package v1
+
+import (
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("ResourceStatus", func() {
+	var status ResourceStatus
+
+	BeforeEach(func() {
+		status = ResourceStatus{}
+	})
+
+	Describe("MarkSynced", func() {
+		It("sets Ready and Synced to True and Error to False", func() {
+			status.MarkSynced()
+
+			Expect(status.Conditions).To(HaveLen(3))
+			Expect(IsConditionTrue(status.Conditions, ConditionReady)).To(BeTrue())
+			Expect(IsConditionTrue(status.Conditions, ConditionSynced)).To(BeTrue())
+			Expect(IsConditionTrue(status.Conditions, ConditionError)).To(BeFalse())
+			Expect(FindCondition(status.Conditions, ConditionError).Reason).To(Equal(ReasonSynced))
+		})
+	})
+
+	Describe("MarkFailed", func() {
+		It("records the error message and reason", func() {
+			status.MarkFailed(ReasonCreateFailed, errors.New("422 response returned"))
+
+			Expect(IsConditionTrue(status.Conditions, ConditionReady)).To(BeFalse())
+			Expect(IsConditionTrue(status.Conditions, ConditionSynced)).To(BeFalse())
+			Expect(IsConditionTrue(status.Conditions, ConditionError)).To(BeTrue())
+
+			errorCondition := FindCondition(status.Conditions, ConditionError)
+			Expect(errorCondition.Reason).To(Equal(ReasonCreateFailed))
+			Expect(errorCondition.Message).To(Equal("422 response returned"))
+		})
+
+		It("clears the error once synced again", func() {
+			status.MarkFailed(ReasonUpdateFailed, errors.New("oh no"))
+			status.MarkSynced()
+
+			Expect(status.Conditions).To(HaveLen(3))
+			Expect(IsConditionTrue(status.Conditions, ConditionError)).To(BeFalse())
+			Expect(FindCondition(status.Conditions, ConditionError).Message).To(BeEmpty())
+		})
+	})
+
+	Describe("MarkDrifted", func() {
+		It("reports the drift and that the resource is not ready", func() {
+			status.MarkSynced()
+			status.MarkDrifted("changed in New Relic: name")
+
+			Expect(IsConditionTrue(status.Conditions, ConditionDrifted)).To(BeTrue())
+			Expect(IsConditionTrue(status.Conditions, ConditionReady)).To(BeFalse())
+			Expect(IsConditionTrue(status.Conditions, ConditionSynced)).To(BeTrue())
+			Expect(FindCondition(status.Conditions, ConditionReady).Message).To(Equal("changed in New Relic: name"))
+		})
+
+		It("is cleared once the spec is applied again", func() {
+			status.MarkDrifted("changed in New Relic: name")
+			status.MarkSynced()
+
+			Expect(IsConditionTrue(status.Conditions, ConditionDrifted)).To(BeFalse())
+			Expect(IsConditionTrue(status.Conditions, ConditionReady)).To(BeTrue())
+		})
+	})
+
+	Describe("MarkInSync", func() {
+		It("reports that there is no drift", func() {
+			status.MarkInSync()
+
+			drifted := FindCondition(status.Conditions, ConditionDrifted)
+			Expect(drifted).ToNot(BeNil())
+			Expect(drifted.Status).To(Equal(metav1.ConditionFalse))
+			Expect(drifted.Reason).To(Equal(ReasonInSync))
+		})
+	})
+
+	Describe("MarkPaused", func() {
+		It("reports that reconciliation is paused", func() {
+			status.MarkPaused("changes to the spec are written to New Relic when reconciliation is resumed")
+
+			Expect(IsConditionTrue(status.Conditions, ConditionPaused)).To(BeTrue())
+			Expect(FindCondition(status.Conditions, ConditionPaused).Message).To(HavePrefix("changes to the spec"))
+		})
+	})
+
+	Describe("MarkResumed", func() {
+		It("does nothing for resources that were never paused", func() {
+			status.MarkResumed()
+
+			Expect(status.Conditions).To(BeEmpty())
+		})
+
+		It("reports that reconciliation was resumed", func() {
+			status.MarkPaused("nothing is written to New Relic until reconciliation is resumed")
+			status.MarkResumed()
+
+			paused := FindCondition(status.Conditions, ConditionPaused)
+			Expect(paused.Status).To(Equal(metav1.ConditionFalse))
+			Expect(paused.Reason).To(Equal(ReasonResumed))
+			Expect(paused.Message).To(BeEmpty())
+		})
+	})
+
+	Describe("MarkPlanned", func() {
+		It("records the plan", func() {
+			status.MarkPlanned([]PlannedOperation{{Operation: "Create", Resource: "policy"}})
+
+			Expect(status.Plan).To(HaveLen(1))
+			Expect(IsConditionTrue(status.Conditions, ConditionPlanned)).To(BeTrue())
+			Expect(FindCondition(status.Conditions, ConditionPlanned).Message).To(Equal("1 writes to New Relic planned"))
+		})
+
+		It("reports when there is nothing to write", func() {
+			status.MarkPlanned(nil)
+
+			Expect(FindCondition(status.Conditions, ConditionPlanned).Message).To(Equal("nothing to write to New Relic"))
+		})
+	})
+
+	Describe("ClearPlan", func() {
+		It("removes the plan and the Planned condition", func() {
+			status.MarkSynced()
+			status.MarkPlanned([]PlannedOperation{{Operation: "Create", Resource: "policy"}})
+			status.ClearPlan()
+
+			Expect(status.Plan).To(BeEmpty())
+			Expect(FindCondition(status.Conditions, ConditionPlanned)).To(BeNil())
+			Expect(status.Conditions).To(HaveLen(3))
+		})
+	})
+
+	Describe("SetCondition", func() {
+		It("only moves LastTransitionTime when the status changes", func() {
+			transition := metav1.NewTime(time.Now().Add(-time.Hour))
+			status.Conditions = []Condition{
+				{Type: ConditionReady, Status: metav1.ConditionTrue, LastTransitionTime: transition, Reason: ReasonSynced},
+			}
+
+			SetCondition(&status.Conditions, Condition{Type: ConditionReady, Status: metav1.ConditionTrue, Reason: "StillSynced"})
+			Expect(status.Conditions[0].LastTransitionTime).To(Equal(transition))
+			Expect(status.Conditions[0].Reason).To(Equal("StillSynced"))
+
+			SetCondition(&status.Conditions, Condition{Type: ConditionReady, Status: metav1.ConditionFalse, Reason: ReasonUpdateFailed})
+			Expect(status.Conditions[0].LastTransitionTime).ToNot(Equal(transition))
+		})
+	})
+
+	Describe("SetObservedGeneration", func() {
+		It("stamps the status and its conditions", func() {
+			status.MarkSynced()
+			status.SetObservedGeneration(7)
+
+			Expect(status.ObservedGeneration).To(Equal(int64(7)))
+			for _, condition := range status.Conditions {
+				Expect(condition.ObservedGeneration).To(Equal(int64(7)))
+			}
+		})
+	})
+})
@@ -21,6 +21,7 @@ package v1
 
 import (
 	"github.com/newrelic/newrelic-client-go/pkg/alerts"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
@@ -140,6 +141,7 @@ func (in *AlertsAPMConditionSpec) DeepCopy() *AlertsAPMConditionSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AlertsAPMConditionStatus) DeepCopyInto(out *AlertsAPMConditionStatus) {
 	*out = *in
+	in.ResourceStatus.DeepCopyInto(&out.ResourceStatus)
 	if in.AppliedSpec != nil {
 		in, out := &in.AppliedSpec, &out.AppliedSpec
 		*out = new(AlertsAPMConditionSpec)
@@ -284,12 +286,37 @@ func (in *AlertsChannelList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AlertsChannelReference) DeepCopyInto(out *AlertsChannelReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertsChannelReference.
+func (in *AlertsChannelReference) DeepCopy() *AlertsChannelReference {
+	if in == nil {
+		return nil
+	}
+	out := new(AlertsChannelReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AlertsChannelSpec) DeepCopyInto(out *AlertsChannelSpec) {
 	*out = *in
 	out.APIKeySecret = in.APIKeySecret
 	in.Links.DeepCopyInto(&out.Links)
 	in.Configuration.DeepCopyInto(&out.Configuration)
+	if in.AccountRef != nil {
+		in, out := &in.AccountRef, &out.AccountRef
+		*out = new(NewRelicAccountReference)
+		**out = **in
+	}
+	if in.PolicySelector != nil {
+		in, out := &in.PolicySelector, &out.PolicySelector
+		*out = new(AlertsPolicySelector)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertsChannelSpec.
@@ -305,6 +332,7 @@ func (in *AlertsChannelSpec) DeepCopy() *AlertsChannelSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AlertsChannelStatus) DeepCopyInto(out *AlertsChannelStatus) {
 	*out = *in
+	in.ResourceStatus.DeepCopyInto(&out.ResourceStatus)
 	if in.AppliedSpec != nil {
 		in, out := &in.AppliedSpec, &out.AppliedSpec
 		*out = new(AlertsChannelSpec)
@@ -315,6 +343,16 @@ func (in *AlertsChannelStatus) DeepCopyInto(out *AlertsChannelStatus) {
 		*out = make([]int, len(*in))
 		copy(*out, *in)
 	}
+	if in.SelectedPolicyIDs != nil {
+		in, out := &in.SelectedPolicyIDs, &out.SelectedPolicyIDs
+		*out = make([]int, len(*in))
+		copy(*out, *in)
+	}
+	if in.CarriedPolicyIDs != nil {
+		in, out := &in.CarriedPolicyIDs, &out.CarriedPolicyIDs
+		*out = make([]int, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertsChannelStatus.
@@ -341,6 +379,16 @@ func (in *AlertsGenericConditionSpec) DeepCopyInto(out *AlertsGenericConditionSp
 		*out = make([]AlertConditionTerm, len(*in))
 		copy(*out, *in)
 	}
+	if in.AccountRef != nil {
+		in, out := &in.AccountRef, &out.AccountRef
+		*out = new(NewRelicAccountReference)
+		**out = **in
+	}
+	if in.PolicyRef != nil {
+		in, out := &in.PolicyRef, &out.PolicyRef
+		*out = new(AlertsPolicyReference)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertsGenericConditionSpec.
@@ -488,6 +536,7 @@ func (in *AlertsNrqlConditionSpec) DeepCopy() *AlertsNrqlConditionSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AlertsNrqlConditionStatus) DeepCopyInto(out *AlertsNrqlConditionStatus) {
 	*out = *in
+	in.ResourceStatus.DeepCopyInto(&out.ResourceStatus)
 	if in.AppliedSpec != nil {
 		in, out := &in.AppliedSpec, &out.AppliedSpec
 		*out = new(AlertsNrqlConditionSpec)
@@ -645,6 +694,42 @@ func (in *AlertsPolicyList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AlertsPolicyReference) DeepCopyInto(out *AlertsPolicyReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertsPolicyReference.
+func (in *AlertsPolicyReference) DeepCopy() *AlertsPolicyReference {
+	if in == nil {
+		return nil
+	}
+	out := new(AlertsPolicyReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AlertsPolicySelector) DeepCopyInto(out *AlertsPolicySelector) {
+	*out = *in
+	in.LabelSelector.DeepCopyInto(&out.LabelSelector)
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertsPolicySelector.
+func (in *AlertsPolicySelector) DeepCopy() *AlertsPolicySelector {
+	if in == nil {
+		return nil
+	}
+	out := new(AlertsPolicySelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AlertsPolicySpec) DeepCopyInto(out *AlertsPolicySpec) {
 	*out = *in
@@ -661,6 +746,16 @@ func (in *AlertsPolicySpec) DeepCopyInto(out *AlertsPolicySpec) {
 		*out = make([]int, len(*in))
 		copy(*out, *in)
 	}
+	if in.ChannelRefs != nil {
+		in, out := &in.ChannelRefs, &out.ChannelRefs
+		*out = make([]AlertsChannelReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.AccountRef != nil {
+		in, out := &in.AccountRef, &out.AccountRef
+		*out = new(NewRelicAccountReference)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertsPolicySpec.
@@ -676,11 +771,24 @@ func (in *AlertsPolicySpec) DeepCopy() *AlertsPolicySpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AlertsPolicyStatus) DeepCopyInto(out *AlertsPolicyStatus) {
 	*out = *in
+	in.ResourceStatus.DeepCopyInto(&out.ResourceStatus)
 	if in.AppliedSpec != nil {
 		in, out := &in.AppliedSpec, &out.AppliedSpec
 		*out = new(AlertsPolicySpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ChannelIDs != nil {
+		in, out := &in.ChannelIDs, &out.ChannelIDs
+		*out = make([]int, len(*in))
+		copy(*out, *in)
+	}
+	if in.InlineConditions != nil {
+		in, out := &in.InlineConditions, &out.InlineConditions
+		*out = make([]InlineConditionStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertsPolicyStatus.
@@ -772,6 +880,7 @@ func (in *ApmAlertConditionSpec) DeepCopy() *ApmAlertConditionSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ApmAlertConditionStatus) DeepCopyInto(out *ApmAlertConditionStatus) {
 	*out = *in
+	in.ResourceStatus.DeepCopyInto(&out.ResourceStatus)
 	if in.AppliedSpec != nil {
 		in, out := &in.AppliedSpec, &out.AppliedSpec
 		*out = new(ApmAlertConditionSpec)
@@ -836,6 +945,22 @@ func (in *ChannelLinks) DeepCopy() *ChannelLinks {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Condition) DeepCopyInto(out *Condition) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Condition.
+func (in *Condition) DeepCopy() *Condition {
+	if in == nil {
+		return nil
+	}
+	out := new(Condition)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ConditionSpec) DeepCopyInto(out *ConditionSpec) {
 	*out = *in
@@ -854,6 +979,42 @@ func (in *ConditionSpec) DeepCopy() *ConditionSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Credentials) DeepCopyInto(out *Credentials) {
+	*out = *in
+	if in.AccountRef != nil {
+		in, out := &in.AccountRef, &out.AccountRef
+		*out = new(NewRelicAccountReference)
+		**out = **in
+	}
+	out.APIKeySecret = in.APIKeySecret
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Credentials.
+func (in *Credentials) DeepCopy() *Credentials {
+	if in == nil {
+		return nil
+	}
+	out := new(Credentials)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FieldChange) DeepCopyInto(out *FieldChange) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FieldChange.
+func (in *FieldChange) DeepCopy() *FieldChange {
+	if in == nil {
+		return nil
+	}
+	out := new(FieldChange)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GenericConditionSpec) DeepCopyInto(out *GenericConditionSpec) {
 	*out = *in
@@ -863,6 +1024,11 @@ func (in *GenericConditionSpec) DeepCopyInto(out *GenericConditionSpec) {
 		copy(*out, *in)
 	}
 	out.APIKeySecret = in.APIKeySecret
+	if in.AccountRef != nil {
+		in, out := &in.AccountRef, &out.AccountRef
+		*out = new(NewRelicAccountReference)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GenericConditionSpec.
@@ -875,6 +1041,26 @@ func (in *GenericConditionSpec) DeepCopy() *GenericConditionSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InlineConditionStatus) DeepCopyInto(out *InlineConditionStatus) {
+	*out = *in
+	if in.ConditionRef != nil {
+		in, out := &in.ConditionRef, &out.ConditionRef
+		*out = new(corev1.ObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InlineConditionStatus.
+func (in *InlineConditionStatus) DeepCopy() *InlineConditionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(InlineConditionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in InvalidAttributeSlice) DeepCopyInto(out *InvalidAttributeSlice) {
 	{
@@ -909,6 +1095,128 @@ func (in *NewRelicAPIKeySecret) DeepCopy() *NewRelicAPIKeySecret {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NewRelicAccount) DeepCopyInto(out *NewRelicAccount) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NewRelicAccount.
+func (in *NewRelicAccount) DeepCopy() *NewRelicAccount {
+	if in == nil {
+		return nil
+	}
+	out := new(NewRelicAccount)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NewRelicAccount) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NewRelicAccountList) DeepCopyInto(out *NewRelicAccountList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]NewRelicAccount, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NewRelicAccountList.
+func (in *NewRelicAccountList) DeepCopy() *NewRelicAccountList {
+	if in == nil {
+		return nil
+	}
+	out := new(NewRelicAccountList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NewRelicAccountList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NewRelicAccountReference) DeepCopyInto(out *NewRelicAccountReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NewRelicAccountReference.
+func (in *NewRelicAccountReference) DeepCopy() *NewRelicAccountReference {
+	if in == nil {
+		return nil
+	}
+	out := new(NewRelicAccountReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NewRelicAccountSpec) DeepCopyInto(out *NewRelicAccountSpec) {
+	*out = *in
+	out.APIKeySecret = in.APIKeySecret
+	out.Endpoints = in.Endpoints
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NewRelicAccountSpec.
+func (in *NewRelicAccountSpec) DeepCopy() *NewRelicAccountSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NewRelicAccountSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NewRelicAccountStatus) DeepCopyInto(out *NewRelicAccountStatus) {
+	*out = *in
+	in.ResourceStatus.DeepCopyInto(&out.ResourceStatus)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NewRelicAccountStatus.
+func (in *NewRelicAccountStatus) DeepCopy() *NewRelicAccountStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NewRelicAccountStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NewRelicEndpoints) DeepCopyInto(out *NewRelicEndpoints) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NewRelicEndpoints.
+func (in *NewRelicEndpoints) DeepCopy() *NewRelicEndpoints {
+	if in == nil {
+		return nil
+	}
+	out := new(NewRelicEndpoints)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NrqlAlertCondition) DeepCopyInto(out *NrqlAlertCondition) {
 	*out = *in
@@ -988,6 +1296,7 @@ func (in *NrqlAlertConditionSpec) DeepCopy() *NrqlAlertConditionSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NrqlAlertConditionStatus) DeepCopyInto(out *NrqlAlertConditionStatus) {
 	*out = *in
+	in.ResourceStatus.DeepCopyInto(&out.ResourceStatus)
 	if in.AppliedSpec != nil {
 		in, out := &in.AppliedSpec, &out.AppliedSpec
 		*out = new(NrqlAlertConditionSpec)
@@ -1036,6 +1345,26 @@ func (in *NrqlSpecificSpec) DeepCopy() *NrqlSpecificSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PlannedOperation) DeepCopyInto(out *PlannedOperation) {
+	*out = *in
+	if in.Changes != nil {
+		in, out := &in.Changes, &out.Changes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlannedOperation.
+func (in *PlannedOperation) DeepCopy() *PlannedOperation {
+	if in == nil {
+		return nil
+	}
+	out := new(PlannedOperation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Policy) DeepCopyInto(out *Policy) {
 	*out = *in
@@ -1122,6 +1451,11 @@ func (in *PolicySpec) DeepCopyInto(out *PolicySpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.AccountRef != nil {
+		in, out := &in.AccountRef, &out.AccountRef
+		*out = new(NewRelicAccountReference)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PolicySpec.
@@ -1137,6 +1471,7 @@ func (in *PolicySpec) DeepCopy() *PolicySpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PolicyStatus) DeepCopyInto(out *PolicyStatus) {
 	*out = *in
+	in.ResourceStatus.DeepCopyInto(&out.ResourceStatus)
 	if in.AppliedSpec != nil {
 		in, out := &in.AppliedSpec, &out.AppliedSpec
 		*out = new(PolicySpec)
@@ -1153,3 +1488,53 @@ func (in *PolicyStatus) DeepCopy() *PolicyStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResolvedCredentials) DeepCopyInto(out *ResolvedCredentials) {
+	*out = *in
+	out.Endpoints = in.Endpoints
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResolvedCredentials.
+func (in *ResolvedCredentials) DeepCopy() *ResolvedCredentials {
+	if in == nil {
+		return nil
+	}
+	out := new(ResolvedCredentials)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceStatus) DeepCopyInto(out *ResourceStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Plan != nil {
+		in, out := &in.Plan, &out.Plan
+		*out = make([]PlannedOperation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Changes != nil {
+		in, out := &in.Changes, &out.Changes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceStatus.
+func (in *ResourceStatus) DeepCopy() *ResourceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -32,6 +32,10 @@ test-integration:
 	@mkdir -p $(COVERAGE_DIR)
 	@$(GO) test -v -parallel 4 -timeout 30m -tags integration -covermode=$(COVERMODE) -coverprofile $(COVERAGE_DIR)/integration.tmp $(GO_PKGS)
 
+test-race:
+	@echo "=== $(PROJECT_NAME) === [ test-race        ]: running integration tests with the race detector..."
+	@$(GO) test -race -timeout 30m -tags integration ./controllers/...
+
 
 #
 # Coverage
@@ -51,4 +55,4 @@ cover-report:
 cover-view: cover-report
 	@$(GO) tool cover -html=$(COVERAGE_DIR)/coverage.out
 
-.PHONY: test test-only test-unit test-integration cover-report cover-view
+.PHONY: test test-only test-unit test-integration test-race cover-report cover-view
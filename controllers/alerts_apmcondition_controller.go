@@ -18,34 +18,45 @@ package controllers
 import (
 	"context"
 	"errors"
+	"fmt"
 	"reflect"
 	"strconv"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
 	kErr "k8s.io/apimachinery/pkg/api/errors"
-	"k8s.io/apimachinery/pkg/types"
 
 	"github.com/go-logr/logr"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	newrelic "github.com/newrelic/go-agent/v3/newrelic"
+	"github.com/newrelic/newrelic-client-go/pkg/alerts"
 
 	nralertsv1 "github.com/newrelic/newrelic-kubernetes-operator/api/v1"
+	customErrors "github.com/newrelic/newrelic-kubernetes-operator/errors"
 	"github.com/newrelic/newrelic-kubernetes-operator/interfaces"
 )
 
 // AlertsAPMConditionReconciler reconciles a AlertsAPMCondition object
 type AlertsAPMConditionReconciler struct {
 	client.Client
-	Log             logr.Logger
-	Scheme          *runtime.Scheme
-	AlertClientFunc func(string, string) (interfaces.NewRelicAlertsClient, error)
-	apiKey          string
-	Alerts          interfaces.NewRelicAlertsClient
-	NewRelicAgent   newrelic.Application
-	txn             *newrelic.Transaction
+	stopper
+	Log                     logr.Logger
+	Recorder                record.EventRecorder
+	Scheme                  *runtime.Scheme
+	AlertClientFunc         func(string, string) (interfaces.NewRelicAlertsClient, error)
+	NewRelicAgent           newrelic.Application
+	ResyncInterval          time.Duration
+	MaxConcurrentReconciles int
+	Ownership               Ownership
+	Naming                  Naming
+	DryRun                  bool
 }
 
 // +kubebuilder:rbac:groups=nr.k8s.newrelic.com,resources=alertsapmconditions,verbs=get;list;watch;create;update;patch;delete
@@ -53,10 +64,11 @@ type AlertsAPMConditionReconciler struct {
 
 // nolint:gocyclo
 func (r *AlertsAPMConditionReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
-	r.txn = r.NewRelicAgent.StartTransaction("Reconcile/Alerts/apmCondition")
-	defer r.txn.End()
+	txn := r.NewRelicAgent.StartTransaction("Reconcile/Alerts/apmCondition")
+	defer txn.End()
+	ctx, cancel := r.withStop(newrelic.NewContext(context.Background(), txn))
+	defer cancel()
 
-	ctx := context.Background()
 	_ = r.Log.WithValues("alertsapmcondition", req.NamespacedName)
 
 	r.Log.Info("Starting reconcile action")
@@ -71,23 +83,51 @@ func (r *AlertsAPMConditionReconciler) Reconcile(req ctrl.Request) (ctrl.Result,
 		return ctrl.Result{}, err
 	}
 
-	r.apiKey, err = r.getAPIKeyOrSecret(condition)
+	original := condition.DeepCopy()
+
+	deleteFinalizer := "alertsapmconditions.finalizers.nr.k8s.newrelic.com"
+
+	if isRetained(&condition, condition.Spec.DeletionPolicy) {
+		r.Log.Info("Retaining New Relic condition, just removing finalizer", "id", condition.Status.ConditionID)
+		return ctrl.Result{}, orphan(ctx, r.Client, r.Recorder, &condition, deleteFinalizer, "condition", fmt.Sprint(condition.Status.ConditionID))
+	}
+
+	creds, err := r.getCredentials(ctx, condition)
 	if err != nil {
-		return ctrl.Result{}, err
+		return ctrl.Result{}, r.markFailed(ctx, original, &condition, nralertsv1.ReasonCredentialsFailed, err)
 	}
 
-	if r.apiKey == "" {
-		return ctrl.Result{}, errors.New("api key is blank")
+	if creds.APIKey == "" {
+		return ctrl.Result{}, r.markFailed(ctx, original, &condition, nralertsv1.ReasonCredentialsFailed, errors.New("api key is blank"))
 	}
 	//initial alertsClient
-	alertsClient, errAlertsClient := r.AlertClientFunc(r.apiKey, condition.Spec.Region)
+	alertsClient, errAlertsClient := creds.AlertsClient(r.AlertClientFunc)
 	if errAlertsClient != nil {
 		r.Log.Error(errAlertsClient, "Error thrown")
-		return ctrl.Result{}, errAlertsClient
+		return ctrl.Result{}, r.markFailed(ctx, original, &condition, nralertsv1.ReasonCredentialsFailed, errAlertsClient)
 	}
-	r.Alerts = alertsClient
 
-	deleteFinalizer := "alertsapmconditions.finalizers.nr.k8s.newrelic.com"
+	paused, err := isPaused(ctx, r.Client, &condition)
+	if err != nil {
+		r.Log.Error(err, "Failed to check if reconciliation is paused")
+		return ctrl.Result{}, err
+	}
+
+	if paused {
+		r.Log.Info("Reconciliation is paused, not writing to New Relic", "conditionId", condition.Status.ConditionID)
+		if markPaused(r.Recorder, &condition, !reflect.DeepEqual(&condition.Spec, condition.Status.AppliedSpec)) {
+			if _, err := r.checkForDrift(ctx, alertsClient, &condition); err != nil {
+				return retryLater(ctrl.Result{}, r.markFailed(ctx, original, &condition, nralertsv1.ReasonReadFailed, err))
+			}
+		}
+
+		return ctrl.Result{RequeueAfter: r.ResyncInterval}, updateResource(ctx, r.Client, original, &condition)
+	}
+
+	markResumed(r.Recorder, &condition)
+	warnNoAccountID(r.Recorder, creds.AccountID, &condition)
+	alertsClient = interfaces.WithContext(alertsClient, ctx)
+	ctx, alertsClient = startDryRun(ctx, r.DryRun, r.Recorder, alertsClient, &condition)
 
 	//examine DeletionTimestamp to determine if object is under deletion
 	if condition.DeletionTimestamp.IsZero() {
@@ -95,6 +135,10 @@ func (r *AlertsAPMConditionReconciler) Reconcile(req ctrl.Request) (ctrl.Result,
 			condition.Finalizers = append(condition.Finalizers, deleteFinalizer)
 		}
 	} else {
+		if planDeletion(ctx, &condition, "condition", strconv.Itoa(condition.Status.ConditionID)) {
+			return ctrl.Result{}, updateResource(ctx, r.Client, original, &condition)
+		}
+
 		// The object is being deleted
 		if containsString(condition.Finalizers, deleteFinalizer) {
 			// catch invalid state
@@ -107,23 +151,23 @@ func (r *AlertsAPMConditionReconciler) Reconcile(req ctrl.Request) (ctrl.Result,
 				}
 			} else {
 				// our finalizer is present, so lets handle any external dependency
-				if err := r.deleteNewRelicAlertCondition(condition); err != nil {
+				if err := r.deleteNewRelicAlertCondition(ctx, alertsClient, creds.AccountID, condition); err != nil {
 					// if fail to delete the external dependency here, return with error
 					// so that it can be retried
 					r.Log.Error(err, "Failed to delete API Condition",
 						"conditionId", condition.Status.ConditionID,
 						"region", condition.Spec.Region,
-						"Api Key", interfaces.PartialAPIKey(r.apiKey),
+						"Api Key", interfaces.PartialAPIKey(creds.APIKey),
 					)
-					if err.Error() == "resource not found" {
+					if customErrors.IsNotFound(err) {
 						r.Log.Info("New Relic API returned resource not found, deleting condition resource")
 					} else {
 						r.Log.Error(err, "Failed to delete API Condition",
 							"conditionId", condition.Status.ConditionID,
 							"region", condition.Spec.Region,
-							"Api Key", interfaces.PartialAPIKey(r.apiKey),
+							"Api Key", interfaces.PartialAPIKey(creds.APIKey),
 						)
-						return ctrl.Result{}, err
+						return retryLater(ctrl.Result{}, r.markFailed(ctx, original, &condition, nralertsv1.ReasonDeleteFailed, err))
 					}
 				}
 				// remove our finalizer from the list and update it.
@@ -142,139 +186,340 @@ func (r *AlertsAPMConditionReconciler) Reconcile(req ctrl.Request) (ctrl.Result,
 		return ctrl.Result{}, nil
 	}
 
+	waiting, err := resolvePolicyRef(ctx, r.Client, condition.Namespace, &condition.Spec.AlertsGenericConditionSpec)
+	if err != nil {
+		r.Log.Error(err, "failed to read the policy of the condition", "policyRef", condition.Spec.PolicyRef)
+		return ctrl.Result{}, r.markFailed(ctx, original, &condition, nralertsv1.ReasonReadFailed, err)
+	}
+
+	if waiting != "" {
+		r.Log.Info("Waiting for the policy of the condition", "policyRef", condition.Spec.PolicyRef)
+		condition.Status.MarkWaiting(nralertsv1.ReasonWaitingForPolicy, waiting)
+		return ctrl.Result{RequeueAfter: policyRefRetryInterval}, updateResource(ctx, r.Client, original, &condition)
+	}
+
 	if reflect.DeepEqual(&condition.Spec, condition.Status.AppliedSpec) {
-		return ctrl.Result{}, nil
+		correctDrift, err := r.checkForDrift(ctx, alertsClient, &condition)
+		if err != nil {
+			r.Log.Error(err, "failed to read condition from New Relic API",
+				"conditionId", condition.Status.ConditionID,
+				"region", condition.Spec.Region,
+				"Api Key", interfaces.PartialAPIKey(creds.APIKey),
+			)
+			return retryLater(ctrl.Result{}, r.markFailed(ctx, original, &condition, nralertsv1.ReasonReadFailed, err))
+		}
+
+		if !correctDrift {
+			return ctrl.Result{RequeueAfter: r.ResyncInterval}, updateResource(ctx, r.Client, original, &condition)
+		}
 	}
 
 	r.Log.Info("Reconciling", "condition", condition.Name)
 
 	//check if condition has condition id
-	r.checkForExistingCondition(&condition)
+	if err := r.checkForExistingCondition(ctx, alertsClient, creds.AccountID, &condition); err != nil {
+		r.Log.Error(err, "failed to adopt existing condition", "importId", condition.Spec.ImportID)
+		return retryLater(ctrl.Result{}, r.markFailed(ctx, original, &condition, nralertsv1.ReasonImportFailed, err))
+	}
 
-	r.writeNewRelicAlertCondition(ctx, req, alertsClient, condition)
+	err = r.writeNewRelicAlertCondition(ctx, req, alertsClient, creds.AccountID, original, condition)
 
-	return ctrl.Result{}, nil
+	return retryLater(ctrl.Result{RequeueAfter: r.ResyncInterval}, err)
+}
+
+// checkForDrift compares the condition with the condition in New Relic when resyncing is enabled.
+// It returns true if the condition has to be written again to correct the drift.
+func (r *AlertsAPMConditionReconciler) checkForDrift(ctx context.Context, alertsClient interfaces.NewRelicAlertsClient, condition *nralertsv1.AlertsAPMCondition) (bool, error) {
+	if r.ResyncInterval == 0 || condition.Status.ConditionID == 0 {
+		condition.Status.MarkSynced()
+		return false, nil
+	}
+
+	defer newrelic.FromContext(ctx).StartSegment("checkForDrift").End()
+
+	existingPolicyIDInt, err := strconv.Atoi(condition.Spec.ExistingPolicyID)
+	if err != nil {
+		return false, err
+	}
+
+	var drift string
+
+	remoteConditions, err := alertsClient.ListConditions(existingPolicyIDInt)
+	if err != nil && !customErrors.IsNotFound(err) {
+		return false, err
+	}
+
+	var remoteCondition *alerts.Condition
+	for _, c := range remoteConditions {
+		if c.ID == condition.Status.ConditionID {
+			remoteCondition = c
+			break
+		}
+	}
+
+	if remoteCondition == nil {
+		drift = "condition was deleted in New Relic"
+	} else {
+		APICondition := condition.Spec.APICondition()
+		APICondition.Name = r.Naming.remoteName(condition, condition.Spec.Name)
+
+		fields, err := driftedFields(APICondition, remoteCondition)
+		if err != nil {
+			return false, err
+		}
+
+		if len(fields) > 0 {
+			drift = describeDrift(fields)
+			condition.Status.Changes = nralertsv1.DescribeChanges(fields)
+		}
+	}
+
+	if !recordDrift(r.Recorder, condition, condition.Spec.DriftPolicy, drift) {
+		return false, nil
+	}
+
+	r.Log.Info("correcting drift", "conditionId", condition.Status.ConditionID, "drift", drift)
+
+	if remoteCondition == nil {
+		condition.Status.ConditionID = 0
+	}
+	condition.Status.AppliedSpec = nil
+
+	return true, nil
+}
+
+// markFailed records err in the status of the condition and returns it.
+func (r *AlertsAPMConditionReconciler) markFailed(ctx context.Context, original, condition *nralertsv1.AlertsAPMCondition, reason string, err error) error {
+	reason = failureReason(reason, err)
+	condition.Status.MarkFailed(reason, err)
+	r.Recorder.Event(condition, v1.EventTypeWarning, reason, err.Error())
+
+	if updateErr := updateResource(ctx, r.Client, original, condition); updateErr != nil {
+		r.Log.Error(updateErr, "tried updating condition status", "name", condition.Name)
+	}
+
+	return err
 }
 
 func (r *AlertsAPMConditionReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	r.AlertClientFunc = interfaces.InitializeAlertsClient
+	if r.AlertClientFunc == nil {
+		r.AlertClientFunc = interfaces.DefaultClientPool.AlertsClient
+	}
+
+	if err := indexSecretReferences(mgr, &nralertsv1.AlertsAPMCondition{}); err != nil {
+		return err
+	}
+
+	if err := indexPolicyReferences(mgr, &nralertsv1.AlertsAPMCondition{}); err != nil {
+		return err
+	}
 
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&nralertsv1.AlertsAPMCondition{}).
+		Watches(&source.Kind{Type: &v1.Secret{}}, enqueueSecretDependents(mgr.GetClient(), &nralertsv1.AlertsAPMConditionList{})).
+		Watches(&source.Kind{Type: &nralertsv1.NewRelicAccount{}}, enqueueAccountDependents(mgr.GetClient(), &nralertsv1.AlertsAPMConditionList{})).
+		Watches(&source.Kind{Type: &v1.Namespace{}}, enqueueNamespaceDependents(mgr.GetClient(), &nralertsv1.AlertsAPMConditionList{})).
+		Watches(&source.Kind{Type: &nralertsv1.AlertsPolicy{}}, &handler.EnqueueRequestForOwner{OwnerType: &nralertsv1.AlertsPolicy{}, IsController: true}).
+		Watches(&source.Kind{Type: &nralertsv1.AlertsPolicy{}}, enqueuePolicyDependents(mgr.GetClient(), &nralertsv1.AlertsAPMConditionList{})).
+		WithOptions(controller.Options{MaxConcurrentReconciles: r.MaxConcurrentReconciles}).
 		Complete(r)
 }
 
-func (r *AlertsAPMConditionReconciler) checkForExistingCondition(condition *nralertsv1.AlertsAPMCondition) {
-	defer r.txn.StartSegment("checkForExistingCondition").End()
-	if condition.Status.ConditionID == 0 {
-		r.Log.Info("Checking for existing condition", "conditionName", condition.Name)
+func (r *AlertsAPMConditionReconciler) checkForExistingCondition(ctx context.Context, alertsClient interfaces.NewRelicAlertsClient, accountID int, condition *nralertsv1.AlertsAPMCondition) error {
+	defer newrelic.FromContext(ctx).StartSegment("checkForExistingCondition").End()
+	if condition.Status.ConditionID != 0 {
+		return nil
+	}
 
-		//if no conditionId, get list of conditions and compare name
-		existingPolicyIDInt, err := strconv.Atoi(condition.Spec.ExistingPolicyID)
-		if err != nil {
-			r.Log.Error(err, "failed to read existing policy ID", "existingPolicyID", condition.Spec.ExistingPolicyID)
-			return
+	adoption := nralertsv1.GetAdoptionPolicy(condition.Spec.Adoption, condition.Spec.ImportID)
+	if adoption == nralertsv1.AdoptionNever {
+		return nil
+	}
+
+	r.Log.Info("Checking for existing condition", "conditionName", condition.Name)
+
+	//if no conditionId, get list of conditions and compare name
+	existingPolicyIDInt, err := strconv.Atoi(condition.Spec.ExistingPolicyID)
+	if err != nil {
+		r.Log.Error(err, "failed to read existing policy ID", "existingPolicyID", condition.Spec.ExistingPolicyID)
+		return err
+	}
+
+	existingConditions, err := alertsClient.ListConditions(existingPolicyIDInt)
+	if err != nil {
+		r.Log.Error(err, "failed to get list of APM conditions from New Relic API",
+			"conditionId", condition.Status.ConditionID,
+			"region", condition.Spec.Region,
+		)
+
+		return err
+	}
+
+	if adoption == nralertsv1.AdoptionByID {
+		for _, existingCondition := range existingConditions {
+			if strconv.Itoa(existingCondition.ID) != condition.Spec.ImportID {
+				continue
+			}
+
+			if err := r.Ownership.checkImportable(alertsClient, accountID, ownedAPMCondition, condition.Spec.ImportID); err != nil {
+				return err
+			}
+
+			r.Log.Info("Importing existing condition", "conditionName", condition.Name, "conditionId", existingCondition.ID)
+			condition.Status.ConditionID = existingCondition.ID
+			r.Recorder.Eventf(condition, v1.EventTypeNormal, eventReasonImported, "Imported existing New Relic condition %v", existingCondition.ID)
+
+			return r.Ownership.claim(alertsClient, accountID, ownedAPMCondition, condition.Spec.ImportID, condition)
 		}
 
-		existingConditions, err := r.Alerts.ListConditions(existingPolicyIDInt)
+		return &customErrors.Error{
+			Kind:     customErrors.ErrNotFound,
+			Resource: ownedAPMCondition,
+			ID:       condition.Spec.ImportID,
+			Err:      fmt.Errorf("no condition %s in policy %s", condition.Spec.ImportID, condition.Spec.ExistingPolicyID),
+		}
+	}
+
+	for _, existingCondition := range existingConditions {
+		if existingCondition.Name != r.Naming.remoteName(condition, condition.Spec.Name) {
+			continue
+		}
+
+		unowned, err := r.Ownership.isUnowned(alertsClient, accountID, ownedAPMCondition, strconv.Itoa(existingCondition.ID), condition)
 		if err != nil {
-			r.Log.Error(err, "failed to get list of NRQL conditions from New Relic API",
-				"conditionId", condition.Status.ConditionID,
-				"region", condition.Spec.Region,
-				"Api Key", interfaces.PartialAPIKey(r.apiKey),
-			)
-		} else {
-			for _, existingCondition := range existingConditions {
-				if existingCondition.Name == condition.Spec.Name {
-					r.Log.Info("Matched on existing condition, updating ConditionId", "conditionId", existingCondition.ID)
-					condition.Status.ConditionID = existingCondition.ID
-					break
-				}
-			}
+			return err
 		}
+
+		if !unowned {
+			r.Log.Info("Skipping condition with the same name managed by another resource", "conditionId", existingCondition.ID)
+			continue
+		}
+
+		r.Log.Info("Matched on existing condition, updating ConditionId", "conditionId", existingCondition.ID)
+		condition.Status.ConditionID = existingCondition.ID
+		r.Recorder.Eventf(condition, v1.EventTypeNormal, eventReasonAdopted, "Adopted existing New Relic condition %v", existingCondition.ID)
+
+		return r.Ownership.claim(alertsClient, accountID, ownedAPMCondition, strconv.Itoa(existingCondition.ID), condition)
 	}
+
+	return nil
 }
 
-func (r *AlertsAPMConditionReconciler) writeNewRelicAlertCondition(ctx context.Context, req ctrl.Request, alertsClient interfaces.NewRelicAlertsClient, condition nralertsv1.AlertsAPMCondition) {
+func (r *AlertsAPMConditionReconciler) writeNewRelicAlertCondition(ctx context.Context, req ctrl.Request, alertsClient interfaces.NewRelicAlertsClient, accountID int, original *nralertsv1.AlertsAPMCondition, condition nralertsv1.AlertsAPMCondition) error {
 	APICondition := condition.Spec.APICondition()
+	APICondition.Name = r.Naming.remoteName(&condition, condition.Spec.Name)
+
+	if condition.Status.AppliedSpec != nil && movedPolicy(strconv.Itoa(condition.Status.ConditionID), condition.Status.AppliedSpec.ExistingPolicyID, condition.Spec.ExistingPolicyID) {
+		r.Log.Info("moving condition to another policy", "conditionId", condition.Status.ConditionID, "policyId", condition.Spec.ExistingPolicyID)
+		r.Recorder.Eventf(&condition, v1.EventTypeNormal, eventReasonMoved, "Moving New Relic condition %v from policy %s to policy %s",
+			condition.Status.ConditionID, condition.Status.AppliedSpec.ExistingPolicyID, condition.Spec.ExistingPolicyID)
+
+		err := r.deleteNewRelicAlertCondition(ctx, alertsClient, accountID, condition)
+		if err != nil && !customErrors.IsNotFound(err) {
+			r.Log.Error(err, "failed to delete condition from its previous policy")
+			return r.markFailed(ctx, original, &condition, nralertsv1.ReasonDeleteFailed, err)
+		}
+
+		condition.Status.ConditionID = 0
+	}
 
 	if condition.Status.ConditionID != 0 && !reflect.DeepEqual(&condition.Spec, condition.Status.AppliedSpec) {
 		r.Log.Info("updating condition", "ConditionName", condition.Name, "API fields", APICondition)
+		recordChanges(r.Recorder, &condition, condition.Status.AppliedSpec, &condition.Spec)
 		APICondition.ID = condition.Status.ConditionID
-		updatedCondition, err := alertsClient.UpdateCondition(APICondition)
+
+		err := r.Ownership.ensureOwner(alertsClient, accountID, ownedAPMCondition, strconv.Itoa(condition.Status.ConditionID), &condition)
 		if err != nil {
 			r.Log.Error(err, "failed to update condition")
-		} else {
-			condition.Status.AppliedSpec = &condition.Spec
-			condition.Status.ConditionID = updatedCondition.ID
+			return r.markFailed(ctx, original, &condition, nralertsv1.ReasonUpdateFailed, err)
 		}
 
-		err = r.Client.Update(ctx, &condition)
+		updatedCondition, err := alertsClient.UpdateCondition(APICondition)
 		if err != nil {
-			r.Log.Error(err, "tried updating condition status", "name", req.NamespacedName)
+			r.Log.Error(err, "failed to update condition")
+			return r.markFailed(ctx, original, &condition, nralertsv1.ReasonUpdateFailed, err)
 		}
+
+		condition.Status.AppliedSpec = &condition.Spec
+		condition.Status.ConditionID = updatedCondition.ID
+		r.Recorder.Eventf(&condition, v1.EventTypeNormal, eventReasonUpdated, "Updated New Relic condition %v", updatedCondition.ID)
 	} else {
 		r.Log.Info("Creating condition", "ConditionName", condition.Name, "API fields", APICondition)
 		existingPolicyIDInt, err := strconv.Atoi(condition.Spec.ExistingPolicyID)
 		if err != nil {
 			r.Log.Error(err, "failed to read existing policy ID", "existingPolicyID", condition.Spec.ExistingPolicyID)
-		} else {
-			createdCondition, err := alertsClient.CreateCondition(existingPolicyIDInt, APICondition)
-			if err != nil {
-				r.Log.Error(err, "failed to create condition",
-					"conditionId", condition.Status.ConditionID,
-					"region", condition.Spec.Region,
-					"Api Key", interfaces.PartialAPIKey(r.apiKey),
-				)
-			} else {
-				condition.Status.AppliedSpec = &condition.Spec
-				condition.Status.ConditionID = createdCondition.ID
-			}
+			return r.markFailed(ctx, original, &condition, nralertsv1.ReasonCreateFailed, err)
+		}
 
-			err = r.Client.Update(ctx, &condition)
-			if err != nil {
-				r.Log.Error(err, "tried updating condition status", "name", req.NamespacedName)
-			}
+		createdCondition, err := alertsClient.CreateCondition(existingPolicyIDInt, APICondition)
+		if err != nil {
+			r.Log.Error(err, "failed to create condition",
+				"conditionId", condition.Status.ConditionID,
+				"region", condition.Spec.Region,
+			)
+			return r.markFailed(ctx, original, &condition, nralertsv1.ReasonCreateFailed, err)
+		}
+
+		condition.Status.AppliedSpec = &condition.Spec
+		condition.Status.ConditionID = createdCondition.ID
+		r.Recorder.Eventf(&condition, v1.EventTypeNormal, eventReasonCreated, "Created New Relic condition %v", createdCondition.ID)
+
+		if err := r.Ownership.claim(alertsClient, accountID, ownedAPMCondition, strconv.Itoa(createdCondition.ID), &condition); err != nil {
+			r.Log.Error(err, "failed to mark condition as managed by this resource", "conditionId", createdCondition.ID)
 		}
 	}
+
+	condition.Status.MarkSynced()
+
+	err := updateResource(ctx, r.Client, original, &condition)
+	if err != nil {
+		r.Log.Error(err, "tried updating condition status", "name", req.NamespacedName)
+		return err
+	}
+
+	return nil
 }
 
-func (r *AlertsAPMConditionReconciler) deleteNewRelicAlertCondition(condition nralertsv1.AlertsAPMCondition) error {
-	defer r.txn.StartSegment("deleteNewRelicAlertCondition").End()
+func (r *AlertsAPMConditionReconciler) deleteNewRelicAlertCondition(ctx context.Context, alertsClient interfaces.NewRelicAlertsClient, accountID int, condition nralertsv1.AlertsAPMCondition) error {
+	defer newrelic.FromContext(ctx).StartSegment("deleteNewRelicAlertCondition").End()
 	r.Log.Info("Deleting condition", "conditionName", condition.Spec.Name)
 
-	_, err := r.Alerts.DeleteCondition(condition.Status.ConditionID)
+	skip, err := r.Ownership.skipDelete(r.Recorder, alertsClient, accountID, ownedAPMCondition, strconv.Itoa(condition.Status.ConditionID), &condition)
+	if skip || err != nil {
+		return err
+	}
+
+	_, err = alertsClient.DeleteCondition(condition.Status.ConditionID)
 	if err != nil {
 		r.Log.Error(err, "Error deleting condition",
 			"conditionId", condition.Status.ConditionID,
 			"region", condition.Spec.Region,
-			"Api Key", interfaces.PartialAPIKey(r.apiKey),
 		)
 
 		return err
 	}
 
-	return nil
-}
+	r.Recorder.Eventf(&condition, v1.EventTypeNormal, eventReasonDeleted, "Deleted New Relic condition %v", condition.Status.ConditionID)
 
-func (r *AlertsAPMConditionReconciler) getAPIKeyOrSecret(condition nralertsv1.AlertsAPMCondition) (string, error) {
-	defer r.txn.StartSegment("getAPIKeyOrSecret").End()
-	if condition.Spec.APIKey != "" {
-		return condition.Spec.APIKey, nil
+	if err := r.Ownership.release(alertsClient, accountID, ownedAPMCondition, strconv.Itoa(condition.Status.ConditionID)); err != nil {
+		r.Log.Error(err, "failed to remove ownership marker of deleted condition", "conditionId", condition.Status.ConditionID)
 	}
 
-	if condition.Spec.APIKeySecret != (nralertsv1.NewRelicAPIKeySecret{}) {
-		var apiKeySecret v1.Secret
+	return nil
+}
 
-		key := types.NamespacedName{Namespace: condition.Spec.APIKeySecret.Namespace, Name: condition.Spec.APIKeySecret.Name}
+func (r *AlertsAPMConditionReconciler) getCredentials(ctx context.Context, condition nralertsv1.AlertsAPMCondition) (nralertsv1.ResolvedCredentials, error) {
+	defer newrelic.FromContext(ctx).StartSegment("getCredentials").End()
 
-		if getErr := r.Client.Get(context.Background(), key, &apiKeySecret); getErr != nil {
-			r.Log.Error(getErr, "Error retrieving secret", "secret", apiKeySecret)
-			return "", getErr
-		}
+	creds, err := condition.Spec.Credentials().Resolve(ctx, r.Client, condition.Namespace)
+	if err != nil {
+		r.Log.Error(err, "Failed to resolve credentials")
+		return creds, err
+	}
 
-		return string(apiKeySecret.Data[condition.Spec.APIKeySecret.KeyName]), nil
+	if err := ownAPIKeySecret(ctx, r.Client, &condition, condition.Spec.APIKeySecret); err != nil {
+		r.Log.Error(err, "Failed to add the resource to the owners of its API key secret")
 	}
 
-	return "", nil
+	return creds, nil
 }
@@ -18,11 +18,12 @@ package controllers
 import (
 	"context"
 	"errors"
+	"fmt"
 	"reflect"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
 	kErr "k8s.io/apimachinery/pkg/api/errors"
-	"k8s.io/apimachinery/pkg/types"
 
 	newrelic "github.com/newrelic/go-agent/v3/newrelic"
 	"github.com/newrelic/newrelic-client-go/pkg/alerts"
@@ -31,10 +32,15 @@ import (
 
 	"github.com/go-logr/logr"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	nrv1 "github.com/newrelic/newrelic-kubernetes-operator/api/v1"
+	customErrors "github.com/newrelic/newrelic-kubernetes-operator/errors"
 )
 
 const (
@@ -44,13 +50,17 @@ const (
 // AlertsNrqlConditionReconciler reconciles a AlertsNrqlCondition object
 type AlertsNrqlConditionReconciler struct {
 	client.Client
-	Alerts          interfaces.NewRelicAlertsClient
-	Log             logr.Logger
-	Scheme          *runtime.Scheme
-	AlertClientFunc func(string, string) (interfaces.NewRelicAlertsClient, error)
-	apiKey          string
-	NewRelicAgent   newrelic.Application
-	txn             *newrelic.Transaction
+	stopper
+	Log                     logr.Logger
+	Recorder                record.EventRecorder
+	Scheme                  *runtime.Scheme
+	AlertClientFunc         func(string, string) (interfaces.NewRelicAlertsClient, error)
+	NewRelicAgent           newrelic.Application
+	ResyncInterval          time.Duration
+	MaxConcurrentReconciles int
+	Ownership               Ownership
+	Naming                  Naming
+	DryRun                  bool
 }
 
 // +kubebuilder:rbac:groups=nr.k8s.newrelic.com,resources=alertsnrqlconditions,verbs=get;list;watch;create;update;patch;delete
@@ -58,10 +68,11 @@ type AlertsNrqlConditionReconciler struct {
 
 // Reconcile is responsible for reconciling the spec and state of the AlertsNrqlCondition.
 func (r *AlertsNrqlConditionReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) { //nolint: gocyclo
-	ctx := context.Background()
+	txn := r.NewRelicAgent.StartTransaction("Reconcile/Alerts/NrqlCondition")
+	defer txn.End()
+	ctx, cancel := r.withStop(newrelic.NewContext(context.Background(), txn))
+	defer cancel()
 	_ = r.Log.WithValues("alertsnrqlcondition", req.NamespacedName)
-	r.txn = r.NewRelicAgent.StartTransaction("Reconcile/Alerts/NrqlCondition")
-	defer r.txn.End()
 
 	r.Log.Info("starting reconcile action")
 	var condition nrv1.AlertsNrqlCondition
@@ -76,21 +87,49 @@ func (r *AlertsNrqlConditionReconciler) Reconcile(req ctrl.Request) (ctrl.Result
 		return ctrl.Result{}, err
 	}
 
-	r.apiKey, err = r.getAPIKeyOrSecret(condition)
+	original := condition.DeepCopy()
+
+	if isRetained(&condition, condition.Spec.DeletionPolicy) {
+		r.Log.Info("Retaining New Relic condition, just removing finalizer", "id", condition.Status.ConditionID)
+		return ctrl.Result{}, orphan(ctx, r.Client, r.Recorder, &condition, alertsNrqlConditionDeleteFinalizer, "condition", fmt.Sprint(condition.Status.ConditionID))
+	}
+
+	creds, err := r.getCredentials(ctx, condition)
 	if err != nil {
-		return ctrl.Result{}, err
+		return ctrl.Result{}, r.markFailed(ctx, original, &condition, nrv1.ReasonCredentialsFailed, err)
 	}
 
-	if r.apiKey == "" {
-		return ctrl.Result{}, errors.New("api key is blank")
+	if creds.APIKey == "" {
+		return ctrl.Result{}, r.markFailed(ctx, original, &condition, nrv1.ReasonCredentialsFailed, errors.New("api key is blank"))
 	}
 
-	alertsClient, errAlertsClient := r.AlertClientFunc(r.apiKey, condition.Spec.Region)
+	alertsClient, errAlertsClient := creds.AlertsClient(r.AlertClientFunc)
 	if errAlertsClient != nil {
 		r.Log.Error(errAlertsClient, "Error thrown")
-		return ctrl.Result{}, errAlertsClient
+		return ctrl.Result{}, r.markFailed(ctx, original, &condition, nrv1.ReasonCredentialsFailed, errAlertsClient)
+	}
+
+	paused, err := isPaused(ctx, r.Client, &condition)
+	if err != nil {
+		r.Log.Error(err, "Failed to check if reconciliation is paused")
+		return ctrl.Result{}, err
 	}
-	r.Alerts = alertsClient
+
+	if paused {
+		r.Log.Info("Reconciliation is paused, not writing to New Relic", "conditionId", condition.Status.ConditionID)
+		if markPaused(r.Recorder, &condition, !reflect.DeepEqual(&condition.Spec, condition.Status.AppliedSpec)) {
+			if _, err := r.checkForDrift(ctx, alertsClient, creds.AccountID, &condition); err != nil {
+				return retryLater(ctrl.Result{}, r.markFailed(ctx, original, &condition, nrv1.ReasonReadFailed, err))
+			}
+		}
+
+		return ctrl.Result{RequeueAfter: r.ResyncInterval}, updateResource(ctx, r.Client, original, &condition)
+	}
+
+	markResumed(r.Recorder, &condition)
+	warnNoAccountID(r.Recorder, creds.AccountID, &condition)
+	alertsClient = interfaces.WithContext(alertsClient, ctx)
+	ctx, alertsClient = startDryRun(ctx, r.DryRun, r.Recorder, alertsClient, &condition)
 
 	// examine DeletionTimestamp to determine if object is under deletion
 	if condition.DeletionTimestamp.IsZero() {
@@ -98,6 +137,10 @@ func (r *AlertsNrqlConditionReconciler) Reconcile(req ctrl.Request) (ctrl.Result
 			condition.Finalizers = append(condition.Finalizers, alertsNrqlConditionDeleteFinalizer)
 		}
 	} else {
+		if planDeletion(ctx, &condition, "nrql condition", condition.Status.ConditionID) {
+			return ctrl.Result{}, updateResource(ctx, r.Client, original, &condition)
+		}
+
 		// the object is being deleted
 		if containsString(condition.Finalizers, alertsNrqlConditionDeleteFinalizer) {
 			// catch invalid state
@@ -110,23 +153,23 @@ func (r *AlertsNrqlConditionReconciler) Reconcile(req ctrl.Request) (ctrl.Result
 				}
 			} else {
 				// our finalizer is present, so lets handle any external dependency
-				if err := r.deleteNewRelicAlertCondition(condition); err != nil {
+				if err := r.deleteNewRelicAlertCondition(ctx, alertsClient, creds.AccountID, condition); err != nil {
 					// if fail to delete the external dependency here, return with error
 					// so that it can be retried
 					r.Log.Error(err, "Failed to delete API Condition",
 						"conditionId", condition.Status.ConditionID,
 						"region", condition.Spec.Region,
-						"apiKey", interfaces.PartialAPIKey(r.apiKey),
+						"apiKey", interfaces.PartialAPIKey(creds.APIKey),
 					)
-					if err.Error() == "resource not found" {
+					if customErrors.IsNotFound(err) {
 						r.Log.Info("New Relic API returned resource not found, deleting condition resource")
 					} else {
 						r.Log.Error(err, "Failed to delete API Condition",
 							"conditionId", condition.Status.ConditionID,
 							"region", condition.Spec.Region,
-							"apiKey", interfaces.PartialAPIKey(r.apiKey),
+							"apiKey", interfaces.PartialAPIKey(creds.APIKey),
 						)
-						return ctrl.Result{}, err
+						return retryLater(ctrl.Result{}, r.markFailed(ctx, original, &condition, nrv1.ReasonDeleteFailed, err))
 					}
 				}
 				// remove our finalizer from the list and update it.
@@ -145,140 +188,320 @@ func (r *AlertsNrqlConditionReconciler) Reconcile(req ctrl.Request) (ctrl.Result
 		return ctrl.Result{}, nil
 	}
 
+	waiting, err := resolvePolicyRef(ctx, r.Client, condition.Namespace, &condition.Spec.AlertsGenericConditionSpec)
+	if err != nil {
+		r.Log.Error(err, "failed to read the policy of the condition", "policyRef", condition.Spec.PolicyRef)
+		return ctrl.Result{}, r.markFailed(ctx, original, &condition, nrv1.ReasonReadFailed, err)
+	}
+
+	if waiting != "" {
+		r.Log.Info("Waiting for the policy of the condition", "policyRef", condition.Spec.PolicyRef)
+		condition.Status.MarkWaiting(nrv1.ReasonWaitingForPolicy, waiting)
+		return ctrl.Result{RequeueAfter: policyRefRetryInterval}, updateResource(ctx, r.Client, original, &condition)
+	}
+
 	if reflect.DeepEqual(&condition.Spec, condition.Status.AppliedSpec) {
-		return ctrl.Result{}, nil
+		correctDrift, err := r.checkForDrift(ctx, alertsClient, creds.AccountID, &condition)
+		if err != nil {
+			r.Log.Error(err, "failed to read condition from New Relic API",
+				"conditionId", condition.Status.ConditionID,
+				"region", condition.Spec.Region,
+				"apiKey", interfaces.PartialAPIKey(creds.APIKey),
+			)
+			return retryLater(ctrl.Result{}, r.markFailed(ctx, original, &condition, nrv1.ReasonReadFailed, err))
+		}
+
+		if !correctDrift {
+			return ctrl.Result{RequeueAfter: r.ResyncInterval}, updateResource(ctx, r.Client, original, &condition)
+		}
 	}
 
 	r.Log.Info("Reconciling", "condition", condition.Name)
 
 	//check if condition has condition id
-	r.checkForExistingCondition(&condition)
+	if err := r.checkForExistingCondition(ctx, alertsClient, creds.AccountID, &condition); err != nil {
+		r.Log.Error(err, "failed to adopt existing condition", "importId", condition.Spec.ImportID)
+		return retryLater(ctrl.Result{}, r.markFailed(ctx, original, &condition, nrv1.ReasonImportFailed, err))
+	}
 
-	r.writeNewRelicAlertCondition(ctx, req, alertsClient, condition)
+	err = r.writeNewRelicAlertCondition(ctx, req, alertsClient, creds.AccountID, original, condition)
 
-	return ctrl.Result{}, nil
+	return retryLater(ctrl.Result{RequeueAfter: r.ResyncInterval}, err)
 }
 
-func (r *AlertsNrqlConditionReconciler) checkForExistingCondition(condition *nrv1.AlertsNrqlCondition) {
-	defer r.txn.StartSegment("checkForExistingCondition").End()
-	if condition.Status.ConditionID == "" {
-		r.Log.Info("Checking for existing condition", "conditionName", condition.Name)
-		//if no conditionId, get list of conditions and compare name
-		searchParams := alerts.NrqlConditionsSearchCriteria{
-			PolicyID: condition.Spec.ExistingPolicyID,
+// checkForDrift compares the condition with the condition in New Relic when resyncing is enabled.
+// It returns true if the condition has to be written again to correct the drift.
+func (r *AlertsNrqlConditionReconciler) checkForDrift(ctx context.Context, alertsClient interfaces.NewRelicAlertsClient, accountID int, condition *nrv1.AlertsNrqlCondition) (bool, error) {
+	if r.ResyncInterval == 0 || condition.Status.ConditionID == "" {
+		condition.Status.MarkSynced()
+		return false, nil
+	}
+
+	defer newrelic.FromContext(ctx).StartSegment("checkForDrift").End()
+
+	var drift string
+
+	remoteCondition, err := alertsClient.GetNrqlConditionQuery(accountID, condition.Status.ConditionID)
+	deleted := customErrors.IsNotFound(err) || (err == nil && remoteCondition == nil)
+
+	switch {
+	case deleted:
+		drift = "condition was deleted in New Relic"
+	case err != nil:
+		return false, err
+	default:
+		input := condition.Spec.ToNrqlConditionInput()
+		input.Name = r.Naming.remoteName(condition, condition.Spec.Name)
+
+		fields, err := driftedFields(input, remoteCondition)
+		if err != nil {
+			return false, err
+		}
+
+		if len(fields) > 0 {
+			drift = describeDrift(fields)
+			condition.Status.Changes = nrv1.DescribeChanges(fields)
+		}
+	}
+
+	if !recordDrift(r.Recorder, condition, condition.Spec.DriftPolicy, drift) {
+		return false, nil
+	}
+
+	r.Log.Info("correcting drift", "conditionId", condition.Status.ConditionID, "drift", drift)
+
+	if deleted {
+		condition.Status.ConditionID = ""
+	}
+	condition.Status.AppliedSpec = nil
+
+	return true, nil
+}
+
+// markFailed records err in the status of the condition and returns it.
+func (r *AlertsNrqlConditionReconciler) markFailed(ctx context.Context, original, condition *nrv1.AlertsNrqlCondition, reason string, err error) error {
+	reason = failureReason(reason, err)
+	condition.Status.MarkFailed(reason, err)
+	r.Recorder.Event(condition, v1.EventTypeWarning, reason, err.Error())
+
+	if updateErr := updateResource(ctx, r.Client, original, condition); updateErr != nil {
+		r.Log.Error(updateErr, "tried updating condition status", "name", condition.Name)
+	}
+
+	return err
+}
+
+func (r *AlertsNrqlConditionReconciler) checkForExistingCondition(ctx context.Context, alertsClient interfaces.NewRelicAlertsClient, accountID int, condition *nrv1.AlertsNrqlCondition) error {
+	defer newrelic.FromContext(ctx).StartSegment("checkForExistingCondition").End()
+	if condition.Status.ConditionID != "" {
+		return nil
+	}
+
+	switch nrv1.GetAdoptionPolicy(condition.Spec.Adoption, condition.Spec.ImportID) {
+	case nrv1.AdoptionNever:
+		return nil
+	case nrv1.AdoptionByID:
+		r.Log.Info("Importing existing condition", "conditionName", condition.Name, "conditionId", condition.Spec.ImportID)
+
+		if _, err := alertsClient.GetNrqlConditionQuery(accountID, condition.Spec.ImportID); err != nil {
+			return err
+		}
+
+		if err := r.Ownership.checkImportable(alertsClient, accountID, ownedNrqlCondition, condition.Spec.ImportID); err != nil {
+			return err
+		}
+
+		condition.Status.ConditionID = condition.Spec.ImportID
+		r.Recorder.Eventf(condition, v1.EventTypeNormal, eventReasonImported, "Imported existing New Relic condition %v", condition.Spec.ImportID)
+
+		return r.Ownership.claim(alertsClient, accountID, ownedNrqlCondition, condition.Status.ConditionID, condition)
+	}
+
+	r.Log.Info("Checking for existing condition", "conditionName", condition.Name)
+	//if no conditionId, get list of conditions and compare name
+	searchParams := alerts.NrqlConditionsSearchCriteria{
+		PolicyID: condition.Spec.ExistingPolicyID,
+	}
+	existingConditions, err := alertsClient.SearchNrqlConditionsQuery(accountID, searchParams)
+	if err != nil {
+		r.Log.Error(err, "failed to get list of NRQL conditions from New Relic API",
+			"conditionId", condition.Status.ConditionID,
+			"region", condition.Spec.Region,
+		)
+
+		return err
+	}
+
+	for _, existingCondition := range existingConditions {
+		if existingCondition.Name != r.Naming.remoteName(condition, condition.Spec.Name) {
+			continue
 		}
-		existingConditions, err := r.Alerts.SearchNrqlConditionsQuery(condition.Spec.AccountID, searchParams)
+
+		unowned, err := r.Ownership.isUnowned(alertsClient, accountID, ownedNrqlCondition, existingCondition.ID, condition)
 		if err != nil {
-			r.Log.Error(err, "failed to get list of NRQL conditions from New Relic API",
-				"conditionId", condition.Status.ConditionID,
-				"region", condition.Spec.Region,
-				"apiKey", interfaces.PartialAPIKey(r.apiKey),
-			)
-		} else {
-			for _, existingCondition := range existingConditions {
-				if existingCondition.Name == condition.Spec.Name {
-					r.Log.Info("Matched on existing condition, updating ConditionId", "conditionId", existingCondition.ID)
-					condition.Status.ConditionID = existingCondition.ID
-					break
-				}
-			}
+			return err
+		}
+
+		if !unowned {
+			r.Log.Info("Skipping condition with the same name managed by another resource", "conditionId", existingCondition.ID)
+			continue
 		}
+
+		r.Log.Info("Matched on existing condition, updating ConditionId", "conditionId", existingCondition.ID)
+		condition.Status.ConditionID = existingCondition.ID
+		r.Recorder.Eventf(condition, v1.EventTypeNormal, eventReasonAdopted, "Adopted existing New Relic condition %v", existingCondition.ID)
+
+		return r.Ownership.claim(alertsClient, accountID, ownedNrqlCondition, condition.Status.ConditionID, condition)
 	}
+
+	return nil
 }
 
 func (r *AlertsNrqlConditionReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	r.AlertClientFunc = interfaces.InitializeAlertsClient
+	if r.AlertClientFunc == nil {
+		r.AlertClientFunc = interfaces.DefaultClientPool.AlertsClient
+	}
+
+	if err := indexSecretReferences(mgr, &nrv1.AlertsNrqlCondition{}); err != nil {
+		return err
+	}
+
+	if err := indexPolicyReferences(mgr, &nrv1.AlertsNrqlCondition{}); err != nil {
+		return err
+	}
 
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&nrv1.AlertsNrqlCondition{}).
+		Watches(&source.Kind{Type: &v1.Secret{}}, enqueueSecretDependents(mgr.GetClient(), &nrv1.AlertsNrqlConditionList{})).
+		Watches(&source.Kind{Type: &nrv1.NewRelicAccount{}}, enqueueAccountDependents(mgr.GetClient(), &nrv1.AlertsNrqlConditionList{})).
+		Watches(&source.Kind{Type: &v1.Namespace{}}, enqueueNamespaceDependents(mgr.GetClient(), &nrv1.AlertsNrqlConditionList{})).
+		Watches(&source.Kind{Type: &nrv1.AlertsPolicy{}}, &handler.EnqueueRequestForOwner{OwnerType: &nrv1.AlertsPolicy{}, IsController: true}).
+		Watches(&source.Kind{Type: &nrv1.AlertsPolicy{}}, enqueuePolicyDependents(mgr.GetClient(), &nrv1.AlertsNrqlConditionList{})).
+		WithOptions(controller.Options{MaxConcurrentReconciles: r.MaxConcurrentReconciles}).
 		Complete(r)
 }
 
-func (r *AlertsNrqlConditionReconciler) writeNewRelicAlertCondition(ctx context.Context, req ctrl.Request, alertsClient interfaces.NewRelicAlertsClient, condition nrv1.AlertsNrqlCondition) {
+func (r *AlertsNrqlConditionReconciler) writeNewRelicAlertCondition(ctx context.Context, req ctrl.Request, alertsClient interfaces.NewRelicAlertsClient, accountID int, original *nrv1.AlertsNrqlCondition, condition nrv1.AlertsNrqlCondition) error {
 	updateInput := condition.Spec.ToNrqlConditionInput()
+	updateInput.Name = r.Naming.remoteName(&condition, condition.Spec.Name)
+
+	if condition.Status.AppliedSpec != nil && movedPolicy(condition.Status.ConditionID, condition.Status.AppliedSpec.ExistingPolicyID, condition.Spec.ExistingPolicyID) {
+		r.Log.Info("moving condition to another policy", "conditionId", condition.Status.ConditionID, "policyId", condition.Spec.ExistingPolicyID)
+		r.Recorder.Eventf(&condition, v1.EventTypeNormal, eventReasonMoved, "Moving New Relic condition %v from policy %s to policy %s",
+			condition.Status.ConditionID, condition.Status.AppliedSpec.ExistingPolicyID, condition.Spec.ExistingPolicyID)
+
+		err := r.deleteNewRelicAlertCondition(ctx, alertsClient, accountID, condition)
+		if err != nil && !customErrors.IsNotFound(err) {
+			r.Log.Error(err, "failed to delete condition from its previous policy")
+			return r.markFailed(ctx, original, &condition, nrv1.ReasonDeleteFailed, err)
+		}
+
+		condition.Status.ConditionID = ""
+	}
 
 	if condition.Status.ConditionID != "" && !reflect.DeepEqual(&condition.Spec, condition.Status.AppliedSpec) {
 		r.Log.Info("updating condition", "ConditionName", condition.Name, "API fields", updateInput)
+		recordChanges(r.Recorder, &condition, condition.Status.AppliedSpec, &condition.Spec)
 		var updatedCondition *alerts.NrqlAlertCondition
-		var err error
+
+		err := r.Ownership.ensureOwner(alertsClient, accountID, ownedNrqlCondition, condition.Status.ConditionID, &condition)
+		if err != nil {
+			r.Log.Error(err, "failed to update condition")
+			return r.markFailed(ctx, original, &condition, nrv1.ReasonUpdateFailed, err)
+		}
 
 		if condition.Spec.BaselineDirection != nil {
-			updatedCondition, err = alertsClient.UpdateNrqlConditionBaselineMutation(condition.Spec.AccountID, condition.Status.ConditionID, updateInput)
+			updatedCondition, err = alertsClient.UpdateNrqlConditionBaselineMutation(accountID, condition.Status.ConditionID, updateInput)
 		} else {
-			updatedCondition, err = alertsClient.UpdateNrqlConditionStaticMutation(condition.Spec.AccountID, condition.Status.ConditionID, updateInput)
+			updatedCondition, err = alertsClient.UpdateNrqlConditionStaticMutation(accountID, condition.Status.ConditionID, updateInput)
 		}
 
 		if err != nil {
 			r.Log.Error(err, "failed to update condition")
-		} else {
-			condition.Status.AppliedSpec = &condition.Spec
-			condition.Status.ConditionID = updatedCondition.ID
+			return r.markFailed(ctx, original, &condition, nrv1.ReasonUpdateFailed, err)
 		}
 
-		err = r.Client.Update(ctx, &condition)
-		if err != nil {
-			r.Log.Error(err, "tried updating condition status", "name", req.NamespacedName)
-		}
+		condition.Status.AppliedSpec = &condition.Spec
+		condition.Status.ConditionID = updatedCondition.ID
+		r.Recorder.Eventf(&condition, v1.EventTypeNormal, eventReasonUpdated, "Updated New Relic condition %v", updatedCondition.ID)
 	} else {
 		r.Log.Info("Creating condition", "ConditionName", condition.Name, "API fields", updateInput)
 		var createdCondition *alerts.NrqlAlertCondition
 		var err error
 
 		if condition.Spec.BaselineDirection != nil {
-			createdCondition, err = alertsClient.CreateNrqlConditionBaselineMutation(condition.Spec.AccountID, condition.Spec.ExistingPolicyID, updateInput)
+			createdCondition, err = alertsClient.CreateNrqlConditionBaselineMutation(accountID, condition.Spec.ExistingPolicyID, updateInput)
 		} else {
-			createdCondition, err = alertsClient.CreateNrqlConditionStaticMutation(condition.Spec.AccountID, condition.Spec.ExistingPolicyID, updateInput)
+			createdCondition, err = alertsClient.CreateNrqlConditionStaticMutation(accountID, condition.Spec.ExistingPolicyID, updateInput)
 		}
 
 		if err != nil {
 			r.Log.Error(err, "failed to create condition",
 				"conditionId", condition.Status.ConditionID,
 				"region", condition.Spec.Region,
-				"apiKey", interfaces.PartialAPIKey(r.apiKey),
 			)
-		} else {
-			condition.Status.AppliedSpec = &condition.Spec
-			condition.Status.ConditionID = createdCondition.ID
+			return r.markFailed(ctx, original, &condition, nrv1.ReasonCreateFailed, err)
 		}
 
-		err = r.Client.Update(ctx, &condition)
-		if err != nil {
-			r.Log.Error(err, "tried updating condition status", "name", req.NamespacedName)
+		condition.Status.AppliedSpec = &condition.Spec
+		condition.Status.ConditionID = createdCondition.ID
+		r.Recorder.Eventf(&condition, v1.EventTypeNormal, eventReasonCreated, "Created New Relic condition %v", createdCondition.ID)
+
+		if err := r.Ownership.claim(alertsClient, accountID, ownedNrqlCondition, condition.Status.ConditionID, &condition); err != nil {
+			r.Log.Error(err, "failed to mark condition as managed by this resource", "conditionId", condition.Status.ConditionID)
 		}
 	}
+
+	condition.Status.MarkSynced()
+
+	err := updateResource(ctx, r.Client, original, &condition)
+	if err != nil {
+		r.Log.Error(err, "tried updating condition status", "name", req.NamespacedName)
+		return err
+	}
+
+	return nil
 }
 
-func (r *AlertsNrqlConditionReconciler) deleteNewRelicAlertCondition(condition nrv1.AlertsNrqlCondition) error {
-	defer r.txn.StartSegment("deleteNewRelicAlertCondition").End()
+func (r *AlertsNrqlConditionReconciler) deleteNewRelicAlertCondition(ctx context.Context, alertsClient interfaces.NewRelicAlertsClient, accountID int, condition nrv1.AlertsNrqlCondition) error {
+	defer newrelic.FromContext(ctx).StartSegment("deleteNewRelicAlertCondition").End()
 	r.Log.Info("Deleting condition", "conditionName", condition.Spec.Name)
-	_, err := r.Alerts.DeleteConditionMutation(condition.Spec.AccountID, condition.Status.ConditionID)
+
+	skip, err := r.Ownership.skipDelete(r.Recorder, alertsClient, accountID, ownedNrqlCondition, condition.Status.ConditionID, &condition)
+	if skip || err != nil {
+		return err
+	}
+
+	_, err = alertsClient.DeleteConditionMutation(accountID, condition.Status.ConditionID)
 	if err != nil {
 		r.Log.Error(err, "Error deleting condition",
 			"conditionId", condition.Status.ConditionID,
 			"region", condition.Spec.Region,
-			"apiKey", interfaces.PartialAPIKey(r.apiKey),
 		)
 		return err
 	}
 
+	r.Recorder.Eventf(&condition, v1.EventTypeNormal, eventReasonDeleted, "Deleted New Relic condition %v", condition.Status.ConditionID)
+
+	if err := r.Ownership.release(alertsClient, accountID, ownedNrqlCondition, condition.Status.ConditionID); err != nil {
+		r.Log.Error(err, "failed to remove ownership marker of deleted condition", "conditionId", condition.Status.ConditionID)
+	}
+
 	return nil
 }
 
-func (r *AlertsNrqlConditionReconciler) getAPIKeyOrSecret(condition nrv1.AlertsNrqlCondition) (string, error) {
-	defer r.txn.StartSegment("getAPIKeyOrSecret").End()
-	if condition.Spec.APIKey != "" {
-		return condition.Spec.APIKey, nil
+func (r *AlertsNrqlConditionReconciler) getCredentials(ctx context.Context, condition nrv1.AlertsNrqlCondition) (nrv1.ResolvedCredentials, error) {
+	defer newrelic.FromContext(ctx).StartSegment("getCredentials").End()
+
+	creds, err := condition.Spec.Credentials().Resolve(ctx, r.Client, condition.Namespace)
+	if err != nil {
+		r.Log.Error(err, "Failed to resolve credentials")
+		return creds, err
 	}
 
-	if condition.Spec.APIKeySecret != (nrv1.NewRelicAPIKeySecret{}) {
-		key := types.NamespacedName{Namespace: condition.Spec.APIKeySecret.Namespace, Name: condition.Spec.APIKeySecret.Name}
-		var apiKeySecret v1.Secret
-		if getErr := r.Client.Get(context.Background(), key, &apiKeySecret); getErr != nil {
-			r.Log.Error(getErr, "Error retrieving secret", "secret", apiKeySecret)
-			return "", getErr
-		}
-		return string(apiKeySecret.Data[condition.Spec.APIKeySecret.KeyName]), nil
+	if err := ownAPIKeySecret(ctx, r.Client, &condition, condition.Spec.APIKeySecret); err != nil {
+		r.Log.Error(err, "Failed to add the resource to the owners of its API key secret")
 	}
 
-	return "", nil
+	return creds, nil
 }
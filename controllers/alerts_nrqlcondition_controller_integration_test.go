@@ -6,17 +6,22 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
 	newrelic "github.com/newrelic/go-agent/v3/newrelic"
 	"github.com/newrelic/newrelic-client-go/pkg/alerts"
+	nrErrors "github.com/newrelic/newrelic-client-go/pkg/errors"
+	"github.com/newrelic/newrelic-client-go/pkg/nerdstorage"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
 	nrv1 "github.com/newrelic/newrelic-kubernetes-operator/api/v1"
@@ -38,13 +43,15 @@ var _ = Describe("AlertsNrqlCondition reconciliation", func() {
 		conditionName        string
 
 		k8sClient        client.Client
+		specEnv          *envtest.Environment
 		mockAlertsClient *interfacesfakes.FakeNewRelicAlertsClient
+		recorder         *record.FakeRecorder
 	)
 
 	BeforeEach(func() {
 		ctx = context.Background()
 		t = &testing.T{}
-		k8sClient = testutil.AlertsPolicyTestSetup(t)
+		k8sClient, specEnv = testutil.AlertsPolicyTestEnv(t)
 		mockAlertsClient = &interfacesfakes.FakeNewRelicAlertsClient{}
 
 		mockAlertsClient.CreateNrqlConditionStaticMutationStub = func(accountID int, policyID string, a alerts.NrqlConditionInput) (*alerts.NrqlAlertCondition, error) {
@@ -111,15 +118,21 @@ var _ = Describe("AlertsNrqlCondition reconciliation", func() {
 		Expect(err).ToNot(HaveOccurred())
 
 		newrelicAgent := newrelic.Application{}
+		recorder = record.NewFakeRecorder(100)
 
 		r = &AlertsNrqlConditionReconciler{
 			Client:          k8sClient,
 			Log:             logf.Log,
+			Recorder:        recorder,
 			AlertClientFunc: mockAlertsClientFunc,
 			NewRelicAgent:   newrelicAgent,
 		}
 	})
 
+	AfterEach(func() {
+		Expect(specEnv.Stop()).To(Succeed())
+	})
+
 	Context("when starting with no conditions", func() {
 		Context("and given a new AlertsNrqlCondition", func() {
 			Context("with a valid condition", func() {
@@ -145,6 +158,239 @@ var _ = Describe("AlertsNrqlCondition reconciliation", func() {
 					Expect(err).To(BeNil())
 					Expect(endStateCondition.Status.AppliedSpec).To(Equal(&condition.Spec))
 				})
+
+				It("reports the condition as ready", func() {
+					err := k8sClient.Create(ctx, condition)
+					Expect(err).ToNot(HaveOccurred())
+
+					_, err = r.Reconcile(request)
+					Expect(err).ToNot(HaveOccurred())
+
+					var endStateCondition nrv1.AlertsNrqlCondition
+					err = k8sClient.Get(ctx, namespacedName, &endStateCondition)
+					Expect(err).To(BeNil())
+					Expect(nrv1.IsConditionTrue(endStateCondition.Status.Conditions, nrv1.ConditionReady)).To(BeTrue())
+					Expect(nrv1.IsConditionTrue(endStateCondition.Status.Conditions, nrv1.ConditionSynced)).To(BeTrue())
+					Expect(nrv1.IsConditionTrue(endStateCondition.Status.Conditions, nrv1.ConditionError)).To(BeFalse())
+					Expect(endStateCondition.Status.ObservedGeneration).To(Equal(endStateCondition.Generation))
+				})
+
+				It("records a Created event", func() {
+					err := k8sClient.Create(ctx, condition)
+					Expect(err).ToNot(HaveOccurred())
+
+					_, err = r.Reconcile(request)
+					Expect(err).ToNot(HaveOccurred())
+
+					Expect(recorder.Events).To(Receive(HavePrefix("Normal Created")))
+				})
+			})
+
+			Context("when the condition was changed in New Relic", func() {
+				var remoteCondition *alerts.NrqlAlertCondition
+
+				BeforeEach(func() {
+					r.ResyncInterval = time.Minute
+
+					remoteCondition = &alerts.NrqlAlertCondition{ID: "111"}
+					remoteCondition.NrqlConditionBase = condition.Spec.ToNrqlConditionInput().NrqlConditionBase
+					remoteCondition.ValueFunction = condition.Spec.ValueFunction
+					remoteCondition.Name = "renamed in the UI"
+
+					mockAlertsClient.GetNrqlConditionQueryStub = func(int, string) (*alerts.NrqlAlertCondition, error) {
+						return remoteCondition, nil
+					}
+				})
+
+				It("writes the spec to New Relic again", func() {
+					err := k8sClient.Create(ctx, condition)
+					Expect(err).ToNot(HaveOccurred())
+
+					_, err = r.Reconcile(request)
+					Expect(err).ToNot(HaveOccurred())
+
+					result, err := r.Reconcile(request)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(result.RequeueAfter).To(Equal(time.Minute))
+					Expect(mockAlertsClient.UpdateNrqlConditionStaticMutationCallCount()).To(Equal(1))
+
+					var endStateCondition nrv1.AlertsNrqlCondition
+					err = k8sClient.Get(ctx, namespacedName, &endStateCondition)
+					Expect(err).To(BeNil())
+					Expect(nrv1.IsConditionTrue(endStateCondition.Status.Conditions, nrv1.ConditionReady)).To(BeTrue())
+					Expect(nrv1.FindCondition(endStateCondition.Status.Conditions, nrv1.ConditionDrifted).Reason).To(Equal(nrv1.ReasonDriftCorrected))
+				})
+
+				It("only reports the drift with the report-only drift policy", func() {
+					condition.Spec.DriftPolicy = nrv1.DriftPolicyReportOnly
+					err := k8sClient.Create(ctx, condition)
+					Expect(err).ToNot(HaveOccurred())
+
+					_, err = r.Reconcile(request)
+					Expect(err).ToNot(HaveOccurred())
+
+					_, err = r.Reconcile(request)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(mockAlertsClient.UpdateNrqlConditionStaticMutationCallCount()).To(Equal(0))
+
+					var endStateCondition nrv1.AlertsNrqlCondition
+					err = k8sClient.Get(ctx, namespacedName, &endStateCondition)
+					Expect(err).To(BeNil())
+					Expect(nrv1.IsConditionTrue(endStateCondition.Status.Conditions, nrv1.ConditionDrifted)).To(BeTrue())
+					Expect(nrv1.FindCondition(endStateCondition.Status.Conditions, nrv1.ConditionDrifted).Message).To(Equal("changed in New Relic: name"))
+				})
+			})
+
+			Context("when reconciliation is paused", func() {
+				BeforeEach(func() {
+					condition.SetAnnotations(map[string]string{nrv1.PausedAnnotation: "true"})
+				})
+
+				It("writes nothing to New Relic until it is resumed", func() {
+					err := k8sClient.Create(ctx, condition)
+					Expect(err).ToNot(HaveOccurred())
+
+					_, err = r.Reconcile(request)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(mockAlertsClient.CreateNrqlConditionStaticMutationCallCount()).To(Equal(0))
+
+					var endStateCondition nrv1.AlertsNrqlCondition
+					err = k8sClient.Get(ctx, namespacedName, &endStateCondition)
+					Expect(err).To(BeNil())
+					Expect(nrv1.IsConditionTrue(endStateCondition.Status.Conditions, nrv1.ConditionPaused)).To(BeTrue())
+					Expect(recorder.Events).To(Receive(Equal("Normal Paused Reconciliation paused, changes to the spec are written to New Relic when reconciliation is resumed")))
+
+					endStateCondition.SetAnnotations(nil)
+					err = k8sClient.Update(ctx, &endStateCondition)
+					Expect(err).ToNot(HaveOccurred())
+
+					_, err = r.Reconcile(request)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(mockAlertsClient.CreateNrqlConditionStaticMutationCallCount()).To(Equal(1))
+
+					err = k8sClient.Get(ctx, namespacedName, &endStateCondition)
+					Expect(err).To(BeNil())
+					Expect(nrv1.FindCondition(endStateCondition.Status.Conditions, nrv1.ConditionPaused).Reason).To(Equal(nrv1.ReasonResumed))
+					Expect(nrv1.IsConditionTrue(endStateCondition.Status.Conditions, nrv1.ConditionReady)).To(BeTrue())
+				})
+
+				It("reports drift without correcting it", func() {
+					r.ResyncInterval = time.Minute
+
+					remoteCondition := &alerts.NrqlAlertCondition{ID: "111"}
+					remoteCondition.NrqlConditionBase = condition.Spec.ToNrqlConditionInput().NrqlConditionBase
+					remoteCondition.ValueFunction = condition.Spec.ValueFunction
+					remoteCondition.Name = "renamed in the UI"
+
+					mockAlertsClient.GetNrqlConditionQueryStub = func(int, string) (*alerts.NrqlAlertCondition, error) {
+						return remoteCondition, nil
+					}
+
+					condition.SetAnnotations(nil)
+					err := k8sClient.Create(ctx, condition)
+					Expect(err).ToNot(HaveOccurred())
+
+					_, err = r.Reconcile(request)
+					Expect(err).ToNot(HaveOccurred())
+
+					var endStateCondition nrv1.AlertsNrqlCondition
+					err = k8sClient.Get(ctx, namespacedName, &endStateCondition)
+					Expect(err).To(BeNil())
+
+					endStateCondition.SetAnnotations(map[string]string{nrv1.PausedAnnotation: "true"})
+					err = k8sClient.Update(ctx, &endStateCondition)
+					Expect(err).ToNot(HaveOccurred())
+
+					_, err = r.Reconcile(request)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(mockAlertsClient.UpdateNrqlConditionStaticMutationCallCount()).To(Equal(0))
+
+					err = k8sClient.Get(ctx, namespacedName, &endStateCondition)
+					Expect(err).To(BeNil())
+					Expect(nrv1.IsConditionTrue(endStateCondition.Status.Conditions, nrv1.ConditionPaused)).To(BeTrue())
+					Expect(nrv1.FindCondition(endStateCondition.Status.Conditions, nrv1.ConditionDrifted).Message).To(Equal("changed in New Relic: name"))
+				})
+
+				It("pauses every condition in a paused namespace", func() {
+					err := ignoreAlreadyExists(k8sClient.Create(ctx, &v1.Namespace{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:        "paused-namespace",
+							Annotations: map[string]string{nrv1.PausedAnnotation: "true"},
+						},
+					}))
+					Expect(err).ToNot(HaveOccurred())
+
+					condition.SetAnnotations(nil)
+					condition.Namespace = "paused-namespace"
+					err = k8sClient.Create(ctx, condition)
+					Expect(err).ToNot(HaveOccurred())
+
+					_, err = r.Reconcile(ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "paused-namespace", Name: conditionName}})
+					Expect(err).ToNot(HaveOccurred())
+					Expect(mockAlertsClient.CreateNrqlConditionStaticMutationCallCount()).To(Equal(0))
+				})
+			})
+
+			Context("when the New Relic API returns an error", func() {
+				BeforeEach(func() {
+					mockAlertsClient.CreateNrqlConditionStaticMutationStub = func(int, string, alerts.NrqlConditionInput) (*alerts.NrqlAlertCondition, error) {
+						return nil, errors.New("500 response returned: invalid threshold")
+					}
+				})
+
+				It("returns the error and records it in the status", func() {
+					err := k8sClient.Create(ctx, condition)
+					Expect(err).ToNot(HaveOccurred())
+
+					_, err = r.Reconcile(request)
+					Expect(err).To(HaveOccurred())
+
+					var endStateCondition nrv1.AlertsNrqlCondition
+					err = k8sClient.Get(ctx, namespacedName, &endStateCondition)
+					Expect(err).To(BeNil())
+					Expect(nrv1.IsConditionTrue(endStateCondition.Status.Conditions, nrv1.ConditionReady)).To(BeFalse())
+
+					errorCondition := nrv1.FindCondition(endStateCondition.Status.Conditions, nrv1.ConditionError)
+					Expect(errorCondition).ToNot(BeNil())
+					Expect(errorCondition.Reason).To(Equal(nrv1.ReasonCreateFailed))
+					Expect(errorCondition.Message).To(Equal("500 response returned: invalid threshold"))
+				})
+
+				It("records a warning event", func() {
+					err := k8sClient.Create(ctx, condition)
+					Expect(err).ToNot(HaveOccurred())
+
+					_, err = r.Reconcile(request)
+					Expect(err).To(HaveOccurred())
+
+					Expect(recorder.Events).To(Receive(Equal("Warning CreateFailed 500 response returned: invalid threshold")))
+				})
+			})
+
+			Context("when the New Relic API rejects the condition as invalid", func() {
+				BeforeEach(func() {
+					mockAlertsClient.CreateNrqlConditionStaticMutationStub = func(int, string, alerts.NrqlConditionInput) (*alerts.NrqlAlertCondition, error) {
+						return nil, nrErrors.NewUnexpectedStatusCode(422, "invalid threshold")
+					}
+				})
+
+				It("records the failure and retries it later instead of returning it", func() {
+					err := k8sClient.Create(ctx, condition)
+					Expect(err).ToNot(HaveOccurred())
+
+					result, err := r.Reconcile(request)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(result.RequeueAfter).To(Equal(failedRetryInterval))
+
+					var endStateCondition nrv1.AlertsNrqlCondition
+					err = k8sClient.Get(ctx, namespacedName, &endStateCondition)
+					Expect(err).To(BeNil())
+
+					errorCondition := nrv1.FindCondition(endStateCondition.Status.Conditions, nrv1.ConditionError)
+					Expect(errorCondition).ToNot(BeNil())
+					Expect(errorCondition.Reason).To(Equal(nrv1.ReasonValidationFailed))
+					Expect(recorder.Events).To(Receive(Equal("Warning ValidationFailed 422 response returned: invalid threshold")))
+				})
 			})
 		})
 
@@ -293,6 +539,176 @@ var _ = Describe("AlertsNrqlCondition reconciliation", func() {
 			})
 		})
 
+		Context("and given a AlertsNrqlCondition with the name of a condition in New Relic", func() {
+			BeforeEach(func() {
+				condition.Spec.Name = "NRQL Condition matches"
+			})
+
+			Context("when the condition has no ownership marker", func() {
+				It("adopts the condition and marks it as managed by the resource", func() {
+					Expect(k8sClient.Create(ctx, condition)).To(Succeed())
+
+					_, err := r.Reconcile(request)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(mockAlertsClient.CreateNrqlConditionStaticMutationCallCount()).To(Equal(0))
+
+					var endStateCondition nrv1.AlertsNrqlCondition
+					Expect(k8sClient.Get(ctx, namespacedName, &endStateCondition)).To(Succeed())
+					Expect(endStateCondition.Status.ConditionID).To(Equal("112"))
+
+					Expect(mockAlertsClient.WriteDocumentWithAccountScopeCallCount()).ToNot(BeZero())
+					_, input := mockAlertsClient.WriteDocumentWithAccountScopeArgsForCall(0)
+					Expect(input.DocumentID).To(Equal("nrql-condition-112"))
+					Expect(input.Document).To(Equal(r.Ownership.ownerOf(condition)))
+				})
+			})
+
+			Context("when another resource manages the condition", func() {
+				BeforeEach(func() {
+					mockAlertsClient.GetDocumentWithAccountScopeStub = func(int, nerdstorage.GetDocumentInput) (interface{}, error) {
+						return map[string]interface{}{"clusterUID": "other-cluster", "namespace": "default", "name": "other"}, nil
+					}
+				})
+
+				It("creates a new condition instead of adopting it", func() {
+					Expect(k8sClient.Create(ctx, condition)).To(Succeed())
+
+					_, err := r.Reconcile(request)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(mockAlertsClient.CreateNrqlConditionStaticMutationCallCount()).To(Equal(1))
+
+					var endStateCondition nrv1.AlertsNrqlCondition
+					Expect(k8sClient.Get(ctx, namespacedName, &endStateCondition)).To(Succeed())
+					Expect(endStateCondition.Status.ConditionID).To(Equal("111"))
+				})
+			})
+		})
+
+		Context("and given a AlertsNrqlCondition with an import_id", func() {
+			BeforeEach(func() {
+				condition.Spec.ImportID = "555"
+
+				mockAlertsClient.GetNrqlConditionQueryStub = func(accountID int, conditionID string) (*alerts.NrqlAlertCondition, error) {
+					return &alerts.NrqlAlertCondition{ID: conditionID}, nil
+				}
+			})
+
+			It("imports the condition with that ID", func() {
+				Expect(k8sClient.Create(ctx, condition)).To(Succeed())
+
+				_, err := r.Reconcile(request)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(mockAlertsClient.SearchNrqlConditionsQueryCallCount()).To(Equal(0))
+				Expect(mockAlertsClient.CreateNrqlConditionStaticMutationCallCount()).To(Equal(0))
+				Expect(mockAlertsClient.UpdateNrqlConditionStaticMutationCallCount()).To(Equal(1))
+
+				var endStateCondition nrv1.AlertsNrqlCondition
+				Expect(k8sClient.Get(ctx, namespacedName, &endStateCondition)).To(Succeed())
+				Expect(endStateCondition.Status.ConditionID).To(Equal("112"))
+				Eventually(recorder.Events).Should(Receive(Equal("Normal Imported Imported existing New Relic condition 555")))
+			})
+
+			Context("when a resource in another cluster manages the condition", func() {
+				BeforeEach(func() {
+					mockAlertsClient.GetDocumentWithAccountScopeStub = func(int, nerdstorage.GetDocumentInput) (interface{}, error) {
+						return map[string]interface{}{"clusterUID": "other-cluster", "namespace": "default", "name": "other"}, nil
+					}
+				})
+
+				It("does not import it and reports the conflict", func() {
+					Expect(k8sClient.Create(ctx, condition)).To(Succeed())
+
+					_, err := r.Reconcile(request)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(mockAlertsClient.CreateNrqlConditionStaticMutationCallCount()).To(Equal(0))
+					Expect(mockAlertsClient.UpdateNrqlConditionStaticMutationCallCount()).To(Equal(0))
+
+					var endStateCondition nrv1.AlertsNrqlCondition
+					Expect(k8sClient.Get(ctx, namespacedName, &endStateCondition)).To(Succeed())
+					Expect(endStateCondition.Status.ConditionID).To(Equal(""))
+					Expect(nrv1.FindCondition(endStateCondition.Status.Conditions, nrv1.ConditionError).Reason).To(Equal(nrv1.ReasonConflict))
+				})
+			})
+		})
+
+		Context("and given a new AlertsNrqlCondition with a policy_ref", func() {
+			var policy *nrv1.AlertsPolicy
+
+			BeforeEach(func() {
+				condition.Spec.ExistingPolicyID = ""
+				condition.Spec.PolicyRef = &nrv1.AlertsPolicyReference{Name: "my-policy"}
+
+				policy = &nrv1.AlertsPolicy{
+					ObjectMeta: metav1.ObjectMeta{Name: "my-policy", Namespace: "default"},
+					Spec:       nrv1.AlertsPolicySpec{Name: "my policy"},
+					Status:     nrv1.AlertsPolicyStatus{PolicyID: "456"},
+				}
+
+				Expect(k8sClient.Create(ctx, condition)).To(Succeed())
+			})
+
+			It("waits for the policy to be created", func() {
+				result, err := r.Reconcile(request)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result.RequeueAfter).To(Equal(policyRefRetryInterval))
+				Expect(mockAlertsClient.CreateNrqlConditionStaticMutationCallCount()).To(Equal(0))
+
+				var endStateCondition nrv1.AlertsNrqlCondition
+				Expect(k8sClient.Get(ctx, namespacedName, &endStateCondition)).To(Succeed())
+				Expect(nrv1.IsConditionTrue(endStateCondition.Status.Conditions, nrv1.ConditionReady)).To(BeFalse())
+				Expect(nrv1.IsConditionTrue(endStateCondition.Status.Conditions, nrv1.ConditionError)).To(BeFalse())
+				Expect(nrv1.FindCondition(endStateCondition.Status.Conditions, nrv1.ConditionReady).Reason).To(Equal(nrv1.ReasonWaitingForPolicy))
+			})
+
+			It("creates the condition in the policy once it has an ID", func() {
+				Expect(k8sClient.Create(ctx, policy)).To(Succeed())
+
+				_, err := r.Reconcile(request)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(mockAlertsClient.CreateNrqlConditionStaticMutationCallCount()).To(Equal(1))
+
+				_, policyID, _ := mockAlertsClient.CreateNrqlConditionStaticMutationArgsForCall(0)
+				Expect(policyID).To(Equal("456"))
+
+				var endStateCondition nrv1.AlertsNrqlCondition
+				Expect(k8sClient.Get(ctx, namespacedName, &endStateCondition)).To(Succeed())
+				Expect(endStateCondition.Spec.ExistingPolicyID).To(BeEmpty())
+				Expect(endStateCondition.Status.AppliedSpec.ExistingPolicyID).To(Equal("456"))
+				Expect(endStateCondition.Status.ConditionID).To(Equal("111"))
+			})
+
+			It("moves the condition to the policy when the policy is recreated", func() {
+				Expect(k8sClient.Create(ctx, policy)).To(Succeed())
+
+				_, err := r.Reconcile(request)
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(k8sClient.Delete(ctx, policy)).To(Succeed())
+				recreated := &nrv1.AlertsPolicy{
+					ObjectMeta: metav1.ObjectMeta{Name: "my-policy", Namespace: "default"},
+					Spec:       nrv1.AlertsPolicySpec{Name: "my policy"},
+					Status:     nrv1.AlertsPolicyStatus{PolicyID: "789"},
+				}
+				Expect(k8sClient.Create(ctx, recreated)).To(Succeed())
+
+				_, err = r.Reconcile(request)
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(mockAlertsClient.DeleteConditionMutationCallCount()).To(Equal(1))
+				_, deletedID := mockAlertsClient.DeleteConditionMutationArgsForCall(0)
+				Expect(deletedID).To(Equal("111"))
+
+				Expect(mockAlertsClient.CreateNrqlConditionStaticMutationCallCount()).To(Equal(2))
+				_, policyID, _ := mockAlertsClient.CreateNrqlConditionStaticMutationArgsForCall(1)
+				Expect(policyID).To(Equal("789"))
+
+				var endStateCondition nrv1.AlertsNrqlCondition
+				Expect(k8sClient.Get(ctx, namespacedName, &endStateCondition)).To(Succeed())
+				Expect(endStateCondition.Spec.ExistingPolicyID).To(BeEmpty())
+				Expect(endStateCondition.Status.AppliedSpec.ExistingPolicyID).To(Equal("789"))
+			})
+		})
+
 		Context("and condition has already been created", func() {
 			BeforeEach(func() {
 				err := k8sClient.Create(ctx, condition)
@@ -350,6 +766,28 @@ var _ = Describe("AlertsNrqlCondition reconciliation", func() {
 				})
 			})
 
+			Context("when condition has been changed while another resource manages it", func() {
+				BeforeEach(func() {
+					condition.Spec.Nrql.Query = "SELECT 1 FROM NewEventType"
+
+					mockAlertsClient.GetDocumentWithAccountScopeStub = func(int, nerdstorage.GetDocumentInput) (interface{}, error) {
+						return map[string]interface{}{"clusterUID": "other-cluster", "namespace": "default", "name": "other"}, nil
+					}
+				})
+
+				It("does not overwrite the condition and reports the conflict", func() {
+					Expect(k8sClient.Update(ctx, condition)).To(Succeed())
+
+					_, err := r.Reconcile(request)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(mockAlertsClient.UpdateNrqlConditionStaticMutationCallCount()).To(Equal(0))
+
+					var endStateCondition nrv1.AlertsNrqlCondition
+					Expect(k8sClient.Get(ctx, namespacedName, &endStateCondition)).To(Succeed())
+					Expect(nrv1.FindCondition(endStateCondition.Status.Conditions, nrv1.ConditionError).Reason).To(Equal(nrv1.ReasonConflict))
+				})
+			})
+
 			Context("when condition has not changed", func() {
 				It("does not make an API call with the client", func() {
 					err := k8sClient.Update(ctx, condition)
@@ -442,6 +880,25 @@ var _ = Describe("AlertsNrqlCondition reconciliation", func() {
 				})
 			})
 
+			Context("when another resource manages the condition", func() {
+				BeforeEach(func() {
+					mockAlertsClient.GetDocumentWithAccountScopeStub = func(int, nerdstorage.GetDocumentInput) (interface{}, error) {
+						return map[string]interface{}{"clusterUID": "other-cluster", "namespace": "default", "name": "other"}, nil
+					}
+				})
+
+				It("leaves the condition in New Relic", func() {
+					Expect(k8sClient.Delete(ctx, condition)).To(Succeed())
+
+					_, err := r.Reconcile(request)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(mockAlertsClient.DeleteConditionMutationCallCount()).To(Equal(0))
+
+					var endStateCondition nrv1.AlertsNrqlCondition
+					Expect(k8sClient.Get(ctx, namespacedName, &endStateCondition)).ToNot(Succeed())
+				})
+			})
+
 			Context("with a condition with no condition ID", func() {
 				BeforeEach(func() {
 					condition.Status.ConditionID = "0"
@@ -18,20 +18,28 @@ package controllers
 import (
 	"context"
 	"errors"
+	"fmt"
 	"reflect"
 	"strconv"
+	"time"
 
 	newrelic "github.com/newrelic/go-agent/v3/newrelic"
 	"github.com/newrelic/newrelic-client-go/pkg/alerts"
 	v1 "k8s.io/api/core/v1"
 	kErr "k8s.io/apimachinery/pkg/api/errors"
-	"k8s.io/apimachinery/pkg/types"
 
 	"github.com/go-logr/logr"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	nrv1 "github.com/newrelic/newrelic-kubernetes-operator/api/v1"
 	customErrors "github.com/newrelic/newrelic-kubernetes-operator/errors"
@@ -47,27 +55,31 @@ var trueVar = true
 // AlertsPolicyReconciler reconciles a AlertsPolicy object
 type AlertsPolicyReconciler struct {
 	client.Client
-	Log             logr.Logger
-	Scheme          *runtime.Scheme
-	AlertClientFunc func(string, string) (interfaces.NewRelicAlertsClient, error)
-	apiKey          string
-	Alerts          interfaces.NewRelicAlertsClient
-	ctx             context.Context
-	NewRelicAgent   newrelic.Application
-	txn             *newrelic.Transaction
+	stopper
+	Log                     logr.Logger
+	Recorder                record.EventRecorder
+	Scheme                  *runtime.Scheme
+	AlertClientFunc         func(string, string) (interfaces.NewRelicAlertsClient, error)
+	NewRelicAgent           newrelic.Application
+	ResyncInterval          time.Duration
+	MaxConcurrentReconciles int
+	Ownership               Ownership
+	Naming                  Naming
+	DryRun                  bool
 }
 
 // +kubebuilder:rbac:groups=nr.k8s.newrelic.com,resources=alertspolicies,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=nr.k8s.newrelic.com,resources=alertspolicies/status,verbs=get;update;patch
 
 func (r *AlertsPolicyReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
-	r.ctx = context.Background()
 	_ = r.Log.WithValues("policy", req.NamespacedName)
-	r.txn = r.NewRelicAgent.StartTransaction("Reconcile/Alerts/AlertsPolicy")
-	defer r.txn.End()
+	txn := r.NewRelicAgent.StartTransaction("Reconcile/Alerts/AlertsPolicy")
+	defer txn.End()
+	ctx, cancel := r.withStop(newrelic.NewContext(context.Background(), txn))
+	defer cancel()
 
 	var policy nrv1.AlertsPolicy
-	err := r.Client.Get(r.ctx, req.NamespacedName, &policy)
+	err := r.Client.Get(ctx, req.NamespacedName, &policy)
 	if err != nil {
 		if kErr.IsNotFound(err) {
 			r.Log.Info("AlertsPolicy 'not found' after being deleted. This is expected and no cause for alarm", "error", err)
@@ -79,23 +91,55 @@ func (r *AlertsPolicyReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error
 	r.Log.Info("Starting reconcile action")
 	r.Log.Info("policy", "policy.Spec.Condition", policy.Spec.Conditions, "policy.status.applied.conditions", policy.Status.AppliedSpec.Conditions)
 
-	r.apiKey, err = r.getAPIKeyOrSecret(policy)
+	original := policy.DeepCopy()
+
+	if isRetained(&policy, policy.Spec.DeletionPolicy) {
+		r.Log.Info("Retaining New Relic policy, just removing finalizer", "id", policy.Status.PolicyID)
+		if err := r.retainConditions(ctx, &policy); err != nil {
+			r.Log.Error(err, "Failed to retain condition resources")
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{}, orphan(ctx, r.Client, r.Recorder, &policy, alertsPolicyDeleteFinalizer, "policy", fmt.Sprint(policy.Status.PolicyID))
+	}
+
+	creds, err := r.getCredentials(ctx, policy)
 	if err != nil {
-		return ctrl.Result{}, err
+		return ctrl.Result{}, r.markFailed(ctx, original, &policy, nrv1.ReasonCredentialsFailed, err)
 	}
 
-	if r.apiKey == "" {
-		return ctrl.Result{}, errors.New("api key is blank")
+	if creds.APIKey == "" {
+		return ctrl.Result{}, r.markFailed(ctx, original, &policy, nrv1.ReasonCredentialsFailed, errors.New("api key is blank"))
 	}
 
 	//initial alertsClient
-	alertsClient, errAlertsClient := r.AlertClientFunc(r.apiKey, policy.Spec.Region)
+	alertsClient, errAlertsClient := creds.AlertsClient(r.AlertClientFunc)
 	if errAlertsClient != nil {
 		r.Log.Error(errAlertsClient, "Failed to create AlertsClient")
-		return ctrl.Result{}, errAlertsClient
+		return ctrl.Result{}, r.markFailed(ctx, original, &policy, nrv1.ReasonCredentialsFailed, errAlertsClient)
 	}
 
-	r.Alerts = alertsClient
+	paused, err := isPaused(ctx, r.Client, &policy)
+	if err != nil {
+		r.Log.Error(err, "Failed to check if reconciliation is paused")
+		return ctrl.Result{}, err
+	}
+
+	if paused {
+		r.Log.Info("Reconciliation is paused, not writing to New Relic", "policyId", policy.Status.PolicyID)
+		if markPaused(r.Recorder, &policy, !policy.Spec.Equals(*policy.Status.AppliedSpec)) {
+			if _, err := r.checkForDrift(ctx, alertsClient, creds.AccountID, &policy); err != nil {
+				return retryLater(ctrl.Result{}, r.markFailed(ctx, original, &policy, nrv1.ReasonReadFailed, err))
+			}
+		}
+
+		return ctrl.Result{RequeueAfter: r.ResyncInterval}, updateResource(ctx, r.Client, original, &policy)
+	}
+
+	markResumed(r.Recorder, &policy)
+	warnNoAccountID(r.Recorder, creds.AccountID, &policy)
+	alertsClient = interfaces.WithContext(alertsClient, ctx)
+	ctx, alertsClient = startDryRun(ctx, r.DryRun, r.Recorder, alertsClient, &policy)
 
 	//examine DeletionTimestamp to determine if object is under deletion
 	if policy.DeletionTimestamp.IsZero() {
@@ -103,55 +147,199 @@ func (r *AlertsPolicyReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error
 			policy.Finalizers = append(policy.Finalizers, alertsPolicyDeleteFinalizer)
 		}
 	} else {
-		return r.deleteAlertsPolicy(r.ctx, &policy, alertsPolicyDeleteFinalizer)
+		if planDeletion(ctx, &policy, "policy", policy.Status.PolicyID) {
+			return ctrl.Result{}, updateResource(ctx, r.Client, original, &policy)
+		}
+
+		result, err := r.deleteAlertsPolicy(ctx, alertsClient, creds.AccountID, &policy, alertsPolicyDeleteFinalizer)
+		if err != nil {
+			return result, r.markFailed(ctx, original, &policy, nrv1.ReasonDeleteFailed, err)
+		}
+
+		return result, nil
 	}
 
-	if policy.Spec.Equals(*policy.Status.AppliedSpec) {
-		return ctrl.Result{}, nil
+	if !policy.Status.ConditionNamesMigrated {
+		if err := r.migrateConditionNames(ctx, alertsClient, creds.AccountID, &policy); err != nil {
+			r.Log.Error(err, "failed to rename condition resources")
+			return retryLater(ctrl.Result{}, r.markFailed(ctx, original, &policy, nrv1.ReasonUpdateFailed, err))
+		}
+
+		// the renames are only planned in dry-run mode, they are still to be done
+		policy.Status.ConditionNamesMigrated = planFrom(ctx) == nil
+	}
+
+	channelIDs, waitingForChannels, err := r.resolveChannelIDs(ctx, &policy)
+	if err != nil {
+		r.Log.Error(err, "failed to resolve channel refs")
+		return retryLater(ctrl.Result{}, r.markFailed(ctx, original, &policy, nrv1.ReasonLinkFailed, err))
+	}
+
+	if policy.Spec.Equals(*policy.Status.AppliedSpec) && sameInts(channelIDs, appliedChannelIDs(&policy)) {
+		if err := r.repairConditions(ctx, &policy); err != nil {
+			r.Log.Error(err, "failed to repair condition resources")
+			return retryLater(ctrl.Result{}, r.markFailed(ctx, original, &policy, nrv1.ReasonUpdateFailed, err))
+		}
+
+		correctDrift, err := r.checkForDrift(ctx, alertsClient, creds.AccountID, &policy)
+		if err != nil {
+			r.Log.Error(err, "failed to read policy from New Relic API",
+				"policyId", policy.Status.PolicyID,
+				"region", policy.Spec.Region,
+				"apiKey", interfaces.PartialAPIKey(creds.APIKey),
+			)
+			return retryLater(ctrl.Result{}, r.markFailed(ctx, original, &policy, nrv1.ReasonReadFailed, err))
+		}
+
+		if !correctDrift {
+			r.rollUpConditions(ctx, &policy, nil)
+			result := waitForChannels(&policy, waitingForChannels, ctrl.Result{RequeueAfter: r.ResyncInterval})
+
+			return result, updateResource(ctx, r.Client, original, &policy)
+		}
 	}
 
 	r.Log.Info("Reconciling", "policy", policy.Name)
 
-	r.checkForExistingAlertsPolicy(&policy)
+	if err := r.checkForExistingAlertsPolicy(ctx, alertsClient, creds.AccountID, &policy); err != nil {
+		r.Log.Error(err, "failed to adopt existing policy", "importId", policy.Spec.ImportID)
+		return retryLater(ctrl.Result{}, r.markFailed(ctx, original, &policy, nrv1.ReasonImportFailed, err))
+	}
 
 	if policy.Status.PolicyID != "" {
-		err := r.updateAlertsPolicy(&policy)
+		err := r.updateAlertsPolicy(ctx, alertsClient, creds.AccountID, &policy, channelIDs)
 		if err != nil {
 			r.Log.Error(err, "error updating policy")
-			return ctrl.Result{}, err
+			return retryLater(ctrl.Result{}, r.markFailed(ctx, original, &policy, nrv1.ReasonUpdateFailed, err))
 		}
 	} else {
-		err := r.createAlertsPolicy(&policy)
+		err := r.createAlertsPolicy(ctx, alertsClient, creds.AccountID, &policy, channelIDs)
 		if err != nil {
 			r.Log.Error(err, "Error creating policy")
-			return ctrl.Result{}, err
+			return retryLater(ctrl.Result{}, r.markFailed(ctx, original, &policy, nrv1.ReasonCreateFailed, err))
 		}
 	}
 
-	return ctrl.Result{}, nil
+	policy.Status.AppliedSpec = &policy.Spec
+	policy.Status.MarkSynced()
+	r.rollUpConditions(ctx, &policy, nil)
+	result := waitForChannels(&policy, waitingForChannels, ctrl.Result{RequeueAfter: r.ResyncInterval})
+
+	err = updateResource(ctx, r.Client, original, &policy)
+	if err != nil {
+		r.Log.Error(err, "failed to update policy status", "name", policy.Name)
+		return ctrl.Result{}, err
+	}
+
+	return result, nil
 }
 
-func (r *AlertsPolicyReconciler) createAlertsPolicy(policy *nrv1.AlertsPolicy) error {
-	defer r.txn.StartSegment("createAlertsPolicy").End()
+// checkForDrift compares the policy with the policy in New Relic when resyncing is enabled.
+// It returns true if the policy has to be written again to correct the drift.
+// Conditions and channels are checked by their own controllers.
+func (r *AlertsPolicyReconciler) checkForDrift(ctx context.Context, alertsClient interfaces.NewRelicAlertsClient, accountID int, policy *nrv1.AlertsPolicy) (bool, error) {
+	if r.ResyncInterval == 0 || policy.Status.PolicyID == "" {
+		policy.Status.MarkSynced()
+		return false, nil
+	}
+
+	defer newrelic.FromContext(ctx).StartSegment("checkForDrift").End()
+
+	var drift string
+
+	remotePolicy, err := alertsClient.QueryPolicy(accountID, policy.Status.PolicyID)
+	deleted := customErrors.IsNotFound(err) || (err == nil && remotePolicy == nil)
+
+	switch {
+	case deleted:
+		drift = "policy was deleted in New Relic"
+	case err != nil:
+		return false, err
+	default:
+		input := policy.Spec.ToAlertsPolicyInput()
+		input.Name = r.Naming.remoteName(policy, policy.Spec.Name)
+
+		fields, err := driftedFields(input, remotePolicy)
+		if err != nil {
+			return false, err
+		}
+
+		if len(fields) > 0 {
+			drift = describeDrift(fields)
+			policy.Status.Changes = nrv1.DescribeChanges(fields)
+		}
+	}
+
+	if !recordDrift(r.Recorder, policy, policy.Spec.DriftPolicy, drift) {
+		return false, nil
+	}
+
+	r.Log.Info("correcting drift", "policyId", policy.Status.PolicyID, "drift", drift)
+
+	if !deleted {
+		// record what is in New Relic as applied so the update writes the spec again
+		appliedSpec := policy.Status.AppliedSpec.DeepCopy()
+		appliedSpec.Name = remotePolicy.Name
+		appliedSpec.IncidentPreference = string(remotePolicy.IncidentPreference)
+		policy.Status.AppliedSpec = appliedSpec
+
+		return true, nil
+	}
+
+	// the conditions were deleted along with the policy, they are created again with the policy
+	for _, condition := range policy.Status.AppliedSpec.Conditions {
+		err := r.deleteCondition(ctx, policy, &condition)
+		if err != nil {
+			r.Log.Error(err, "error deleting condition resource", "condition", condition.Name)
+		}
+	}
+
+	policy.Status.PolicyID = ""
+	policy.Status.AppliedSpec = &nrv1.AlertsPolicySpec{}
+	policy.Status.ChannelIDs = nil
+
+	return true, nil
+}
+
+// markFailed records err in the status of the policy and returns it.
+func (r *AlertsPolicyReconciler) markFailed(ctx context.Context, original, policy *nrv1.AlertsPolicy, reason string, err error) error {
+	reason = failureReason(reason, err)
+	policy.Status.MarkFailed(reason, err)
+	r.rollUpConditions(ctx, policy, err)
+	r.Recorder.Event(policy, v1.EventTypeWarning, reason, err.Error())
+
+	if updateErr := updateResource(ctx, r.Client, original, policy); updateErr != nil {
+		r.Log.Error(updateErr, "failed to update policy status", "name", policy.Name)
+	}
+
+	return err
+}
+
+func (r *AlertsPolicyReconciler) createAlertsPolicy(ctx context.Context, alertsClient interfaces.NewRelicAlertsClient, accountID int, policy *nrv1.AlertsPolicy, channelIDs []int) error {
+	defer newrelic.FromContext(ctx).StartSegment("createAlertsPolicy").End()
 	p := alerts.AlertsPolicyInput{}
 	p.IncidentPreference = alerts.AlertsIncidentPreference(policy.Spec.IncidentPreference)
-	p.Name = policy.Spec.Name
+	p.Name = r.Naming.remoteName(policy, policy.Spec.Name)
 
 	r.Log.Info("Creating policy", "PolicyName", p.Name)
-	createResult, err := r.Alerts.CreatePolicyMutation(policy.Spec.AccountID, p)
+	createResult, err := alertsClient.CreatePolicyMutation(accountID, p)
 	if err != nil {
 		r.Log.Error(err, "failed to create policy via New Relic API",
 			"policyId", policy.Status.PolicyID,
 			"region", policy.Spec.Region,
-			"apiKey", interfaces.PartialAPIKey(r.apiKey),
 		)
 
 		return err
 	}
 
 	policy.Status.PolicyID = createResult.ID
+	r.Recorder.Eventf(policy, v1.EventTypeNormal, eventReasonCreated, "Created New Relic policy %s", createResult.ID)
 
-	err = r.createConditions(policy)
+	if err := r.Ownership.claim(alertsClient, accountID, ownedPolicy, policy.Status.PolicyID, policy); err != nil {
+		r.Log.Error(err, "failed to mark policy as managed by this resource", "policyId", policy.Status.PolicyID)
+	}
+
+	err = r.createConditions(ctx, policy)
 	if err != nil {
 		r.Log.Error(err, "error creating or updating conditions")
 
@@ -159,42 +347,42 @@ func (r *AlertsPolicyReconciler) createAlertsPolicy(policy *nrv1.AlertsPolicy) e
 	}
 	r.Log.Info("policy after condition creation", "policyCondition", policy.Spec.Conditions, "pointer", &policy)
 
-	err = r.createAlertsChannels(policy)
+	err = r.createAlertsChannels(alertsClient, policy, channelIDs)
 	if err != nil {
 		r.Log.Error(err, "error updating alert channels")
 
 		return err
 	}
 
-	policy.Status.AppliedSpec = &policy.Spec
-
-	err = r.Client.Update(r.ctx, policy)
-	if err != nil {
-		r.Log.Error(err, "tried updating policy status", "name", policy.Name)
-		return err
-	}
-
 	return nil
 }
 
-func (r *AlertsPolicyReconciler) createConditions(policy *nrv1.AlertsPolicy) error {
-	defer r.txn.StartSegment("createConditions").End()
+func (r *AlertsPolicyReconciler) createConditions(ctx context.Context, policy *nrv1.AlertsPolicy) error {
+	defer newrelic.FromContext(ctx).StartSegment("createConditions").End()
 	r.Log.Info("creating conditions for policy")
 
 	collectedErrors := new(customErrors.ErrorCollector)
+	taken := make(map[string]bool)
+
+	if err := r.takeOtherConditionNames(ctx, policy, taken); err != nil {
+		return err
+	}
 
 	for i, condition := range policy.Spec.Conditions {
+		name := newConditionName(policy.Name, &condition, taken)
+		taken[name] = true
+
 		var err error
 		switch nrv1.GetAlertsConditionType(condition) {
 		case "AlertsAPMCondition":
-			err = r.createApmCondition(policy, &condition)
+			err = r.createApmCondition(ctx, policy, &condition, name)
 		case "AlertsNrqlCondition":
-			err = r.createNrqlCondition(policy, &condition)
+			err = r.createNrqlCondition(ctx, policy, &condition, name)
 		}
 
 		if err != nil {
 			r.Log.Error(err, "error creating condition")
-			collectedErrors.Collect(err)
+			collectedErrors.Collect(newConditionError(&condition, i, err))
 		} else {
 			policy.Spec.Conditions[i] = condition
 		}
@@ -213,51 +401,52 @@ type processedAlertsConditions struct {
 	condition nrv1.AlertsPolicyCondition
 }
 
-func (r *AlertsPolicyReconciler) createOrUpdateCondition(policy *nrv1.AlertsPolicy, condition *nrv1.AlertsPolicyCondition) (*nrv1.AlertsPolicyCondition, error) {
-	defer r.txn.StartSegment("createOrUpdateCondition").End()
+// createOrUpdateCondition updates the resource of a condition matched to one, or creates one with a
+// name not in taken.
+func (r *AlertsPolicyReconciler) createOrUpdateCondition(ctx context.Context, policy *nrv1.AlertsPolicy, condition *nrv1.AlertsPolicyCondition, taken map[string]bool) (*nrv1.AlertsPolicyCondition, error) {
+	defer newrelic.FromContext(ctx).StartSegment("createOrUpdateCondition").End()
 	//loop through the policies, creating/updating as needed
 	r.Log.Info("Checking on condition", "resourceName", condition.Name, "conditionName", condition.Spec.Name)
-	//first we check to see if the name is set
+	//conditions not matched to a condition resource are new
 	if condition.Name == "" {
-		//If resource name is not set, let's see if a appliedSpec matches the NR condition name
-		r.Log.Info("Condition name not set, checking name values")
-		for _, appliedCondition := range policy.Status.AppliedSpec.Conditions {
-			if appliedCondition.Spec.Name == condition.Spec.Name {
-				r.Log.Info("Found matching name")
-				condition.Namespace = appliedCondition.Namespace
-				condition.Name = appliedCondition.Name
-				break
-			}
-		}
+		r.Log.Info("no existing condition matches, creating a new one")
 
-		if condition.Name == "" {
-			r.Log.Info("made it through all existing appliedConditions, creating a new one")
-
-			var err error
-			switch nrv1.GetAlertsConditionType(*condition) {
-			case "AlertsAPMCondition":
-				err = r.createApmCondition(policy, condition)
-			case "AlertsNrqlCondition":
-				err = r.createNrqlCondition(policy, condition)
-			}
-			return condition, err
+		name := newConditionName(policy.Name, condition, taken)
+		taken[name] = true
+
+		var err error
+		switch nrv1.GetAlertsConditionType(*condition) {
+		case "AlertsAPMCondition":
+			err = r.createApmCondition(ctx, policy, condition, name)
+		case "AlertsNrqlCondition":
+			err = r.createNrqlCondition(ctx, policy, condition, name)
 		}
+		return condition, err
 	}
 
 	var err error
 	switch nrv1.GetAlertsConditionType(*condition) {
 	case "AlertsAPMCondition":
-		err = r.updateApmCondition(policy, condition)
+		err = r.updateApmCondition(ctx, policy, condition)
 	case "AlertsNrqlCondition":
-		err = r.updateNrqlCondition(policy, condition)
+		err = r.updateNrqlCondition(ctx, policy, condition)
 	}
 
 	return condition, err
 }
 
-func (r *AlertsPolicyReconciler) updateNrqlCondition(policy *nrv1.AlertsPolicy, condition *nrv1.AlertsPolicyCondition) error {
-	defer r.txn.StartSegment("updateNrqlCondition").End()
-	nrqlCondition := r.getAlertsNrqlConditionFromAlertsPolicyCondition(condition)
+func (r *AlertsPolicyReconciler) updateNrqlCondition(ctx context.Context, policy *nrv1.AlertsPolicy, condition *nrv1.AlertsPolicyCondition) error {
+	defer newrelic.FromContext(ctx).StartSegment("updateNrqlCondition").End()
+	nrqlCondition := r.getAlertsNrqlConditionFromAlertsPolicyCondition(ctx, condition)
+	if nrqlCondition.Name == "" {
+		r.Log.Info("nrql condition resource is missing, creating it again", "condition", condition.Name)
+		return r.createNrqlCondition(ctx, policy, condition, condition.Name)
+	}
+
+	if nrv1.HasBreakGlass(&nrqlCondition) {
+		r.Log.Info("nrql condition resource has the break-glass annotation, leaving it as it is", "condition", condition.Name)
+		return nil
+	}
 
 	r.Log.Info("Found nrql condition to update", "retrievedCondition", nrqlCondition)
 
@@ -266,7 +455,7 @@ func (r *AlertsPolicyReconciler) updateNrqlCondition(policy *nrv1.AlertsPolicy,
 	retrievedPolicyCondition.GenerateSpecFromNrqlConditionSpec(nrqlCondition.Spec)
 	r.Log.Info("conditions", "retrieved", retrievedPolicyCondition, "condition", condition)
 
-	if retrievedPolicyCondition.SpecHash() == condition.SpecHash() {
+	if retrievedPolicyCondition.SpecIdentity() == condition.SpecIdentity() {
 		r.Log.Info("existing NrqlCondition matches going to next")
 		return nil
 	}
@@ -279,16 +468,29 @@ func (r *AlertsPolicyReconciler) updateNrqlCondition(policy *nrv1.AlertsPolicy,
 	nrqlCondition.Spec.ExistingPolicyID = policy.Status.PolicyID
 	nrqlCondition.Spec.APIKey = policy.Spec.APIKey
 	nrqlCondition.Spec.APIKeySecret = policy.Spec.APIKeySecret
+	nrqlCondition.Spec.AccountRef = policy.Spec.AccountRef
 	nrqlCondition.Spec.AccountID = policy.Spec.AccountID
 
-	err := r.Client.Update(r.ctx, &nrqlCondition)
+	err := childWriter(ctx, r.Client).Update(ctx, &nrqlCondition)
+	if err == nil {
+		r.Recorder.Eventf(policy, v1.EventTypeNormal, eventReasonChildUpdated, "Updated AlertsNrqlCondition %s", nrqlCondition.Name)
+	}
 
 	return err
 }
 
-func (r *AlertsPolicyReconciler) updateApmCondition(policy *nrv1.AlertsPolicy, condition *nrv1.AlertsPolicyCondition) error {
-	defer r.txn.StartSegment("updateApmCondition").End()
-	apmCondition := r.getApmConditionFromAlertsPolicyCondition(condition)
+func (r *AlertsPolicyReconciler) updateApmCondition(ctx context.Context, policy *nrv1.AlertsPolicy, condition *nrv1.AlertsPolicyCondition) error {
+	defer newrelic.FromContext(ctx).StartSegment("updateApmCondition").End()
+	apmCondition := r.getApmConditionFromAlertsPolicyCondition(ctx, condition)
+	if apmCondition.Name == "" {
+		r.Log.Info("apm condition resource is missing, creating it again", "condition", condition.Name)
+		return r.createApmCondition(ctx, policy, condition, condition.Name)
+	}
+
+	if nrv1.HasBreakGlass(&apmCondition) {
+		r.Log.Info("apm condition resource has the break-glass annotation, leaving it as it is", "condition", condition.Name)
+		return nil
+	}
 
 	r.Log.Info("Found apm condition to update", "retrievedCondition", apmCondition)
 
@@ -297,7 +499,7 @@ func (r *AlertsPolicyReconciler) updateApmCondition(policy *nrv1.AlertsPolicy, c
 	retrievedPolicyCondition.GenerateSpecFromApmConditionSpec(apmCondition.Spec)
 	r.Log.Info("conditions", "retrieved", retrievedPolicyCondition, "condition", condition)
 
-	if retrievedPolicyCondition.SpecHash() == condition.SpecHash() {
+	if retrievedPolicyCondition.SpecIdentity() == condition.SpecIdentity() {
 		r.Log.Info("existing ApmCondition matches going to next")
 		return nil
 	}
@@ -309,23 +511,64 @@ func (r *AlertsPolicyReconciler) updateApmCondition(policy *nrv1.AlertsPolicy, c
 	apmCondition.Spec.Region = policy.Spec.Region
 	apmCondition.Spec.APIKey = policy.Spec.APIKey
 	apmCondition.Spec.APIKeySecret = policy.Spec.APIKeySecret
+	apmCondition.Spec.AccountRef = policy.Spec.AccountRef
 	apmCondition.Spec.AccountID = policy.Spec.AccountID
 
 	apmCondition.Spec.ExistingPolicyID = policy.Status.PolicyID
 
 	r.Log.Info("updating existing condition", "alertsAPMCondition", apmCondition)
 
-	err := r.Client.Update(r.ctx, &apmCondition)
+	err := childWriter(ctx, r.Client).Update(ctx, &apmCondition)
+	if err == nil {
+		r.Recorder.Eventf(policy, v1.EventTypeNormal, eventReasonChildUpdated, "Updated AlertsAPMCondition %s", apmCondition.Name)
+	}
 
 	return err
 }
 
-func (r *AlertsPolicyReconciler) createOrUpdateConditions(policy *nrv1.AlertsPolicy) error {
-	defer r.txn.StartSegment("createOrUpdateConditions").End()
-	if reflect.DeepEqual(policy.Spec.Conditions, policy.Status.AppliedSpec.Conditions) {
+// repairConditions writes the conditions of the applied spec again to condition resources that
+// were changed or deleted, unless they have the break-glass annotation.
+func (r *AlertsPolicyReconciler) repairConditions(ctx context.Context, policy *nrv1.AlertsPolicy) error {
+	defer newrelic.FromContext(ctx).StartSegment("repairConditions").End()
+
+	if policy.Status.AppliedSpec == nil || policy.Status.PolicyID == "" {
 		return nil
 	}
 
+	collectedErrors := new(customErrors.ErrorCollector)
+
+	for _, condition := range policy.Status.AppliedSpec.Conditions {
+		if condition.Name == "" {
+			continue
+		}
+
+		var err error
+		switch nrv1.GetAlertsConditionType(condition) {
+		case "AlertsAPMCondition":
+			err = r.updateApmCondition(ctx, policy, &condition)
+		case "AlertsNrqlCondition":
+			err = r.updateNrqlCondition(ctx, policy, &condition)
+		}
+
+		if err != nil {
+			r.Log.Error(err, "error repairing condition resource", "condition", condition.Name)
+			collectedErrors.Collect(newConditionError(&condition, -1, err))
+		}
+	}
+
+	if len(*collectedErrors) > 0 {
+		return collectedErrors
+	}
+
+	return nil
+}
+
+func (r *AlertsPolicyReconciler) createOrUpdateConditions(ctx context.Context, policy *nrv1.AlertsPolicy) error {
+	defer newrelic.FromContext(ctx).StartSegment("createOrUpdateConditions").End()
+	if reflect.DeepEqual(policy.Spec.Conditions, policy.Status.AppliedSpec.Conditions) {
+		return r.repairConditions(ctx, policy)
+	}
+
 	//build map of existing conditions so we can mark them off as processed and delete anything left over
 	var existingConditions = make(map[string]processedAlertsConditions)
 	for _, existing := range policy.Status.AppliedSpec.Conditions {
@@ -337,11 +580,32 @@ func (r *AlertsPolicyReconciler) createOrUpdateConditions(policy *nrv1.AlertsPol
 
 	collectedErrors := new(customErrors.ErrorCollector)
 
+	matches := matchAppliedConditions(policy.Spec.Conditions, policy.Status.AppliedSpec.Conditions)
+
+	taken := make(map[string]bool)
+	for _, condition := range policy.Spec.Conditions {
+		taken[condition.Name] = true
+	}
+
+	for _, applied := range policy.Status.AppliedSpec.Conditions {
+		taken[applied.Name] = true
+	}
+
+	if err := r.takeOtherConditionNames(ctx, policy, taken); err != nil {
+		return err
+	}
+
 	for i, condition := range policy.Spec.Conditions {
-		condition, err := r.createOrUpdateCondition(policy, &condition)
+		if applied, ok := matches[i]; ok && condition.Name == "" {
+			r.Log.Info("Found matching condition", "resourceName", applied.Name, "key", condition.Key)
+			condition.Name = applied.Name
+			condition.Namespace = applied.Namespace
+		}
+
+		condition, err := r.createOrUpdateCondition(ctx, policy, &condition, taken)
 		if err != nil {
 			r.Log.Error(err, "error creating condition")
-			collectedErrors.Collect(err)
+			collectedErrors.Collect(newConditionError(condition, i, err))
 		}
 		r.Log.Info("processed condition", "conditionName", condition.Name, "condition", condition)
 
@@ -361,7 +625,7 @@ func (r *AlertsPolicyReconciler) createOrUpdateConditions(policy *nrv1.AlertsPol
 		r.Log.Info("checking "+processedCondition.condition.Name, "bool is", processedCondition.processed)
 		if !processedCondition.processed {
 			r.Log.Info("Need to delete", "ppliedConditionName", conditionName)
-			err := r.deleteCondition(&processedCondition.condition)
+			err := r.deleteCondition(ctx, policy, &processedCondition.condition)
 			if err != nil {
 				r.Log.Error(err, "error deleting condition resource")
 				collectedErrors.Collect(err)
@@ -379,6 +643,50 @@ func (r *AlertsPolicyReconciler) createOrUpdateConditions(policy *nrv1.AlertsPol
 	return nil
 }
 
+// matchAppliedConditions returns the conditions of the applied spec that the conditions of spec
+// update, by their index in spec. A condition naming its resource is matched to that resource.
+// Other conditions are matched to an applied condition with the same key, then, when there is
+// none, to one without a key and with the same spec, and last to one without a key and with the
+// same name in its spec. Each applied condition is matched at most once.
+func matchAppliedConditions(spec, applied []nrv1.AlertsPolicyCondition) map[int]nrv1.AlertsPolicyCondition {
+	rules := []func(condition, appliedCondition *nrv1.AlertsPolicyCondition) bool{
+		func(condition, appliedCondition *nrv1.AlertsPolicyCondition) bool {
+			return condition.Name != "" && condition.GetNamespace() == appliedCondition.GetNamespace()
+		},
+		func(condition, appliedCondition *nrv1.AlertsPolicyCondition) bool {
+			return condition.Name == "" && condition.Key != "" && condition.Key == appliedCondition.Key
+		},
+		func(condition, appliedCondition *nrv1.AlertsPolicyCondition) bool {
+			return condition.Name == "" && appliedCondition.Key == "" && condition.SpecIdentity() == appliedCondition.SpecIdentity()
+		},
+		func(condition, appliedCondition *nrv1.AlertsPolicyCondition) bool {
+			return condition.Name == "" && appliedCondition.Key == "" && condition.Spec.Name == appliedCondition.Spec.Name
+		},
+	}
+
+	matches := make(map[int]nrv1.AlertsPolicyCondition)
+	matched := make(map[int]bool)
+
+	for _, match := range rules {
+		for i := range spec {
+			if _, ok := matches[i]; ok {
+				continue
+			}
+
+			for j := range applied {
+				if !matched[j] && match(&spec[i], &applied[j]) {
+					matches[i] = applied[j]
+					matched[j] = true
+
+					break
+				}
+			}
+		}
+	}
+
+	return matches
+}
+
 func asOwner(p *nrv1.AlertsPolicy) metav1.OwnerReference {
 	return metav1.OwnerReference{
 		APIVersion: nrv1.GroupVersion.String(),
@@ -389,10 +697,12 @@ func asOwner(p *nrv1.AlertsPolicy) metav1.OwnerReference {
 	}
 }
 
-func (r *AlertsPolicyReconciler) createNrqlCondition(policy *nrv1.AlertsPolicy, condition *nrv1.AlertsPolicyCondition) error {
-	defer r.txn.StartSegment("createNrqlCondition").End()
+// createNrqlCondition creates the resource of a NRQL condition with the given name. A resource of
+// the policy left with that name, when the policy failed to record it, is updated instead.
+func (r *AlertsPolicyReconciler) createNrqlCondition(ctx context.Context, policy *nrv1.AlertsPolicy, condition *nrv1.AlertsPolicyCondition, name string) error {
+	defer newrelic.FromContext(ctx).StartSegment("createNrqlCondition").End()
 	var alertsNrqlCondition nrv1.AlertsNrqlCondition
-	alertsNrqlCondition.GenerateName = policy.Name + "-condition-"
+	alertsNrqlCondition.Name = name
 	alertsNrqlCondition.Namespace = policy.Namespace
 	alertsNrqlCondition.Labels = policy.Labels
 	alertsNrqlCondition.Spec = condition.ReturnNrqlConditionSpec()
@@ -400,13 +710,21 @@ func (r *AlertsPolicyReconciler) createNrqlCondition(policy *nrv1.AlertsPolicy,
 	alertsNrqlCondition.Spec.ExistingPolicyID = policy.Status.PolicyID
 	alertsNrqlCondition.Spec.APIKey = policy.Spec.APIKey
 	alertsNrqlCondition.Spec.APIKeySecret = policy.Spec.APIKeySecret
+	alertsNrqlCondition.Spec.AccountRef = policy.Spec.AccountRef
 	alertsNrqlCondition.Spec.AccountID = policy.Spec.AccountID
 	alertsNrqlCondition.Status.AppliedSpec = &nrv1.AlertsNrqlConditionSpec{}
 	alertsNrqlCondition.OwnerReferences = append(alertsNrqlCondition.OwnerReferences, asOwner(policy))
 
 	r.Log.Info("creating condition", "condition", condition.Name, "conditionName", condition.Spec.Name, "alertsNrqlCondition", alertsNrqlCondition)
 
-	errCondition := r.Create(r.ctx, &alertsNrqlCondition)
+	errCondition := childWriter(ctx, r.Client).Create(ctx, &alertsNrqlCondition)
+	if kErr.IsAlreadyExists(errCondition) && r.isOwnCondition(ctx, policy, name, &nrv1.AlertsNrqlCondition{}) {
+		condition.Name = name
+		condition.Namespace = policy.Namespace
+
+		return r.updateNrqlCondition(ctx, policy, condition)
+	}
+
 	if errCondition != nil {
 		r.Log.Error(errCondition, "error creating condition")
 		return errCondition
@@ -414,16 +732,19 @@ func (r *AlertsPolicyReconciler) createNrqlCondition(policy *nrv1.AlertsPolicy,
 
 	condition.Name = alertsNrqlCondition.Name
 	condition.Namespace = alertsNrqlCondition.Namespace
+	r.Recorder.Eventf(policy, v1.EventTypeNormal, eventReasonChildCreated, "Created AlertsNrqlCondition %s", alertsNrqlCondition.Name)
 
 	r.Log.Info("created condition", "condition", condition.Name, "conditionName", condition.Spec.Name, "alertsNrqlCondition", alertsNrqlCondition)
 
 	return nil
 }
 
-func (r *AlertsPolicyReconciler) createApmCondition(policy *nrv1.AlertsPolicy, condition *nrv1.AlertsPolicyCondition) error {
-	defer r.txn.StartSegment("createApmCondition").End()
+// createApmCondition creates the resource of an APM condition with the given name. A resource of
+// the policy left with that name, when the policy failed to record it, is updated instead.
+func (r *AlertsPolicyReconciler) createApmCondition(ctx context.Context, policy *nrv1.AlertsPolicy, condition *nrv1.AlertsPolicyCondition, name string) error {
+	defer newrelic.FromContext(ctx).StartSegment("createApmCondition").End()
 	var apmCondition nrv1.AlertsAPMCondition
-	apmCondition.GenerateName = policy.Name + "-condition-"
+	apmCondition.Name = name
 	apmCondition.Namespace = policy.Namespace
 	apmCondition.Labels = policy.Labels
 
@@ -431,6 +752,7 @@ func (r *AlertsPolicyReconciler) createApmCondition(policy *nrv1.AlertsPolicy, c
 	apmCondition.Spec.Region = policy.Spec.Region
 	apmCondition.Spec.APIKey = policy.Spec.APIKey
 	apmCondition.Spec.APIKeySecret = policy.Spec.APIKeySecret
+	apmCondition.Spec.AccountRef = policy.Spec.AccountRef
 	apmCondition.Spec.AccountID = policy.Spec.AccountID
 	apmCondition.Status.AppliedSpec = &nrv1.AlertsAPMConditionSpec{}
 
@@ -438,7 +760,14 @@ func (r *AlertsPolicyReconciler) createApmCondition(policy *nrv1.AlertsPolicy, c
 	apmCondition.OwnerReferences = append(apmCondition.OwnerReferences, asOwner(policy))
 
 	r.Log.Info("creating apm condition", "condition", condition.Name, "conditionName", condition.Spec.Name, "alertsAPMCondition", apmCondition)
-	errCondition := r.Create(r.ctx, &apmCondition)
+	errCondition := childWriter(ctx, r.Client).Create(ctx, &apmCondition)
+	if kErr.IsAlreadyExists(errCondition) && r.isOwnCondition(ctx, policy, name, &nrv1.AlertsAPMCondition{}) {
+		condition.Name = name
+		condition.Namespace = policy.Namespace
+
+		return r.updateApmCondition(ctx, policy, condition)
+	}
+
 	if errCondition != nil {
 		r.Log.Error(errCondition, "error creating condition")
 		return errCondition
@@ -446,114 +775,331 @@ func (r *AlertsPolicyReconciler) createApmCondition(policy *nrv1.AlertsPolicy, c
 
 	condition.Name = apmCondition.Name
 	condition.Namespace = apmCondition.Namespace
+	r.Recorder.Eventf(policy, v1.EventTypeNormal, eventReasonChildCreated, "Created AlertsAPMCondition %s", apmCondition.Name)
 
 	r.Log.Info("created apm condition", "condition", condition.Name, "conditionName", condition.Spec.Name, "alertsAPMCondition", apmCondition, "actualCondition", condition.Spec)
 
 	return nil
 }
 
-func (r *AlertsPolicyReconciler) deleteCondition(condition *nrv1.AlertsPolicyCondition) error {
-	defer r.txn.StartSegment("deleteCondition").End()
+func (r *AlertsPolicyReconciler) deleteCondition(ctx context.Context, policy *nrv1.AlertsPolicy, condition *nrv1.AlertsPolicyCondition) error {
+	defer newrelic.FromContext(ctx).StartSegment("deleteCondition").End()
 	r.Log.Info("Deleting condition", "condition", condition.Name, "conditionName", condition.Spec.Name)
 
 	var retrievedCondition runtime.Object
 	switch nrv1.GetAlertsConditionType(*condition) {
 	case "AlertsAPMCondition":
-		returnedCondition := r.getApmConditionFromAlertsPolicyCondition(condition)
+		returnedCondition := r.getApmConditionFromAlertsPolicyCondition(ctx, condition)
 		retrievedCondition = &returnedCondition
 	case "AlertsNrqlCondition":
-		returnedCondition := r.getAlertsNrqlConditionFromAlertsPolicyCondition(condition)
+		returnedCondition := r.getAlertsNrqlConditionFromAlertsPolicyCondition(ctx, condition)
 		retrievedCondition = &returnedCondition
 	}
 
 	r.Log.Info("retrieved condition for deletion", "retrievedCondition", retrievedCondition)
 
-	err := r.Delete(r.ctx, retrievedCondition)
+	err := childWriter(ctx, r.Client).Delete(ctx, retrievedCondition)
 	if err != nil {
 		r.Log.Error(err, "error deleting condition resource")
 		return err
 	}
 
+	r.Recorder.Eventf(policy, v1.EventTypeNormal, eventReasonChildDeleted, "Deleted %s %s", nrv1.GetAlertsConditionType(*condition), condition.Name)
+
 	return nil
 }
 
-func (r *AlertsPolicyReconciler) getAlertsNrqlConditionFromAlertsPolicyCondition(condition *nrv1.AlertsPolicyCondition) (nrqlCondition nrv1.AlertsNrqlCondition) {
-	defer r.txn.StartSegment("getAlertsNrqlConditionFromAlertsPolicyCondition").End()
+// retainConditions deletes the condition resources of a policy that is retained, after setting
+// their deletion policy to Retain so the conditions in New Relic are left in place with the policy.
+func (r *AlertsPolicyReconciler) retainConditions(ctx context.Context, policy *nrv1.AlertsPolicy) error {
+	if policy.Status.AppliedSpec == nil {
+		return nil
+	}
+
+	collectedErrors := new(customErrors.ErrorCollector)
+
+	for _, condition := range policy.Status.AppliedSpec.Conditions {
+		var child nrv1.StatusObject
+		switch nrv1.GetAlertsConditionType(condition) {
+		case "AlertsAPMCondition":
+			returnedCondition := r.getApmConditionFromAlertsPolicyCondition(ctx, &condition)
+			child = &returnedCondition
+		case "AlertsNrqlCondition":
+			returnedCondition := r.getAlertsNrqlConditionFromAlertsPolicyCondition(ctx, &condition)
+			child = &returnedCondition
+		default:
+			continue
+		}
+
+		if child.GetName() == "" {
+			continue
+		}
+
+		if err := markRetained(ctx, r.Client, child); err != nil {
+			collectedErrors.Collect(err)
+			continue
+		}
+
+		collectedErrors.Collect(r.deleteCondition(ctx, policy, &condition))
+	}
+
+	if len(*collectedErrors) > 0 {
+		return collectedErrors
+	}
+
+	return nil
+}
+
+// migrateConditionNames renames the resources of conditions created with a random name, before
+// the names were derived from the policy, to the name a new resource would get. The resource with
+// the new name takes over the condition in New Relic along with the status of the old one, so the
+// condition isn't created again, and the old resource is deleted with a Retain deletion policy.
+// It runs until it succeeds once for the policy, as recorded in the status.
+func (r *AlertsPolicyReconciler) migrateConditionNames(ctx context.Context, alertsClient interfaces.NewRelicAlertsClient, accountID int, policy *nrv1.AlertsPolicy) error {
+	if policy.Status.AppliedSpec == nil {
+		return nil
+	}
+
+	applied := policy.Status.AppliedSpec.Conditions
+
+	taken := make(map[string]bool)
+	for _, condition := range applied {
+		taken[condition.Name] = true
+	}
+
+	if err := r.takeOtherConditionNames(ctx, policy, taken); err != nil {
+		return err
+	}
+
+	// the spec may have a key the applied spec doesn't have yet, which names the resource
+	named := make(map[string]*nrv1.AlertsPolicyCondition)
+	for i, condition := range matchAppliedConditions(policy.Spec.Conditions, applied) {
+		named[condition.Name] = &policy.Spec.Conditions[i]
+	}
+
+	collectedErrors := new(customErrors.ErrorCollector)
+
+	for i := range applied {
+		oldName := applied[i].Name
+		if !isGeneratedConditionName(policy.Name, oldName) {
+			continue
+		}
+
+		condition := &applied[i]
+		if specCondition, ok := named[oldName]; ok {
+			condition = specCondition
+		}
+
+		name := newConditionName(policy.Name, condition, taken)
+
+		renamed, err := r.renameCondition(ctx, alertsClient, accountID, policy, &applied[i], name)
+		if err != nil {
+			r.Log.Error(err, "error renaming condition resource", "condition", oldName)
+			collectedErrors.Collect(err)
+
+			continue
+		}
+
+		if !renamed {
+			continue
+		}
+
+		taken[name] = true
+		r.Recorder.Eventf(policy, v1.EventTypeNormal, eventReasonChildRenamed, "Renamed %s %s to %s", nrv1.GetAlertsConditionType(applied[i]), oldName, name)
+
+		for j := range policy.Spec.Conditions {
+			if policy.Spec.Conditions[j].Name == oldName {
+				policy.Spec.Conditions[j].Name = name
+			}
+		}
+
+		applied[i].Name = name
+	}
+
+	if len(*collectedErrors) > 0 {
+		return collectedErrors
+	}
+
+	return nil
+}
+
+// renameCondition creates a copy of the resource of condition with the given name and deletes the
+// resource, leaving the condition in New Relic in place. It returns false if neither resource exists.
+func (r *AlertsPolicyReconciler) renameCondition(ctx context.Context, alertsClient interfaces.NewRelicAlertsClient, accountID int, policy *nrv1.AlertsPolicy, condition *nrv1.AlertsPolicyCondition, name string) (bool, error) {
+	var old, renamed, existing nrv1.StatusObject
+
+	var kind, id string
+
+	switch nrv1.GetAlertsConditionType(*condition) {
+	case "AlertsAPMCondition":
+		apmCondition := r.getApmConditionFromAlertsPolicyCondition(ctx, condition)
+		old = &apmCondition
+		renamed = &nrv1.AlertsAPMCondition{ObjectMeta: renamedObjectMeta(&apmCondition, name), Spec: apmCondition.Spec, Status: apmCondition.Status}
+		existing = &nrv1.AlertsAPMCondition{}
+		kind, id = ownedAPMCondition, strconv.Itoa(apmCondition.Status.ConditionID)
+	case "AlertsNrqlCondition":
+		nrqlCondition := r.getAlertsNrqlConditionFromAlertsPolicyCondition(ctx, condition)
+		old = &nrqlCondition
+		renamed = &nrv1.AlertsNrqlCondition{ObjectMeta: renamedObjectMeta(&nrqlCondition, name), Spec: nrqlCondition.Spec, Status: nrqlCondition.Status}
+		existing = &nrv1.AlertsNrqlCondition{}
+		kind, id = ownedNrqlCondition, nrqlCondition.Status.ConditionID
+	default:
+		return false, nil
+	}
+
+	// the old resource is gone when it was deleted after the new one took over
+	if old.GetName() == "" {
+		return r.isOwnCondition(ctx, policy, name, existing), nil
+	}
+
+	if !isGeneratedCondition(policy, old) {
+		return false, nil
+	}
+
+	writer := childWriter(ctx, r.Client)
+
+	err := writer.Create(ctx, renamed)
+	if err != nil && !(kErr.IsAlreadyExists(err) && r.isOwnCondition(ctx, policy, name, existing)) {
+		return false, err
+	}
+
+	if err := r.Ownership.claim(alertsClient, accountID, kind, id, renamed); err != nil {
+		return false, err
+	}
+
+	if err := markRetained(ctx, writer, old); err != nil {
+		return false, err
+	}
+
+	if err := writer.Delete(ctx, old); err != nil && !kErr.IsNotFound(err) {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// renamedObjectMeta returns the metadata of a copy of obj with the given name.
+func renamedObjectMeta(obj metav1.Object, name string) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:            name,
+		Namespace:       obj.GetNamespace(),
+		Labels:          obj.GetLabels(),
+		Annotations:     obj.GetAnnotations(),
+		OwnerReferences: obj.GetOwnerReferences(),
+	}
+}
+
+// isOwnCondition returns true if the condition resource with the given name is controlled by
+// policy. The resource is read into condition, which is expected to be empty.
+// takeOtherConditionNames marks the names of the condition resources in the namespace of the
+// policy that the policy doesn't control as taken, so a condition isn't named like the resource of
+// another policy, as "web" with the key "api-cpu" and "web-api" with the key "cpu" would be.
+func (r *AlertsPolicyReconciler) takeOtherConditionNames(ctx context.Context, policy *nrv1.AlertsPolicy, taken map[string]bool) error {
+	var nrqlConditions nrv1.AlertsNrqlConditionList
+	if err := r.Client.List(ctx, &nrqlConditions, client.InNamespace(policy.Namespace)); err != nil {
+		return err
+	}
+
+	for i := range nrqlConditions.Items {
+		if !metav1.IsControlledBy(&nrqlConditions.Items[i], policy) {
+			taken[nrqlConditions.Items[i].Name] = true
+		}
+	}
+
+	var apmConditions nrv1.AlertsAPMConditionList
+	if err := r.Client.List(ctx, &apmConditions, client.InNamespace(policy.Namespace)); err != nil {
+		return err
+	}
+
+	for i := range apmConditions.Items {
+		if !metav1.IsControlledBy(&apmConditions.Items[i], policy) {
+			taken[apmConditions.Items[i].Name] = true
+		}
+	}
+
+	return nil
+}
+
+func (r *AlertsPolicyReconciler) isOwnCondition(ctx context.Context, policy *nrv1.AlertsPolicy, name string, condition nrv1.StatusObject) bool {
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: policy.Namespace, Name: name}, condition); err != nil {
+		return false
+	}
+
+	return metav1.IsControlledBy(condition, policy)
+}
+
+func (r *AlertsPolicyReconciler) getAlertsNrqlConditionFromAlertsPolicyCondition(ctx context.Context, condition *nrv1.AlertsPolicyCondition) (nrqlCondition nrv1.AlertsNrqlCondition) {
+	defer newrelic.FromContext(ctx).StartSegment("getAlertsNrqlConditionFromAlertsPolicyCondition").End()
 	r.Log.Info("condition before retrieval", "condition", condition)
 
 	//throw away the error since empty conditions are expected
-	_ = r.Client.Get(r.ctx, condition.GetNamespace(), &nrqlCondition)
+	_ = r.Client.Get(ctx, condition.GetNamespace(), &nrqlCondition)
 	r.Log.Info("retrieved condition", "alertsNrqlCondition", nrqlCondition, "namespace", condition.GetNamespace())
 
 	return
 }
 
-func (r *AlertsPolicyReconciler) getApmConditionFromAlertsPolicyCondition(condition *nrv1.AlertsPolicyCondition) (apmCondition nrv1.AlertsAPMCondition) {
-	defer r.txn.StartSegment("getApmConditionFromAlertsPolicyCondition").End()
+func (r *AlertsPolicyReconciler) getApmConditionFromAlertsPolicyCondition(ctx context.Context, condition *nrv1.AlertsPolicyCondition) (apmCondition nrv1.AlertsAPMCondition) {
+	defer newrelic.FromContext(ctx).StartSegment("getApmConditionFromAlertsPolicyCondition").End()
 	r.Log.Info("apm condition before retrieval", "condition", condition)
 
 	//throw away the error since empty conditions are expected
-	_ = r.Client.Get(r.ctx, condition.GetNamespace(), &apmCondition)
+	_ = r.Client.Get(ctx, condition.GetNamespace(), &apmCondition)
 	r.Log.Info("retrieved condition", "alertsAPMCondition", apmCondition, "namespace", condition.GetNamespace())
 
 	return
 }
 
-func (r *AlertsPolicyReconciler) updateAlertsPolicy(policy *nrv1.AlertsPolicy) error {
-	defer r.txn.StartSegment("updateAlertsPolicy").End()
+func (r *AlertsPolicyReconciler) updateAlertsPolicy(ctx context.Context, alertsClient interfaces.NewRelicAlertsClient, accountID int, policy *nrv1.AlertsPolicy, channelIDs []int) error {
+	defer newrelic.FromContext(ctx).StartSegment("updateAlertsPolicy").End()
 	r.Log.Info("updating policy", "PolicyName", policy.Name)
+	recordChanges(r.Recorder, policy, policy.Status.AppliedSpec, &policy.Spec)
+
+	if err := r.Ownership.ensureOwner(alertsClient, accountID, ownedPolicy, policy.Status.PolicyID, policy); err != nil {
+		return err
+	}
 
 	//only update policy if policy fields have changed
 	updateInput := policy.Spec.ToAlertsPolicyUpdateInput()
+	updateInput.Name = r.Naming.remoteName(policy, policy.Spec.Name)
 	var updateResult *alerts.AlertsPolicy
 	var err error
 
-	if string(updateInput.IncidentPreference) != policy.Status.AppliedSpec.IncidentPreference || updateInput.Name != policy.Status.AppliedSpec.Name {
+	if string(updateInput.IncidentPreference) != policy.Status.AppliedSpec.IncidentPreference || updateInput.Name != r.Naming.remoteName(policy, policy.Status.AppliedSpec.Name) {
 		r.Log.Info("need to update alert policy via New Relic API",
 			"Alert AlertsPolicy Name", updateInput.Name,
 			"incident preference ", policy.Status.AppliedSpec.IncidentPreference,
 		)
-		updateResult, err = r.Alerts.UpdatePolicyMutation(policy.Spec.AccountID, policy.Status.PolicyID, updateInput)
+		updateResult, err = alertsClient.UpdatePolicyMutation(accountID, policy.Status.PolicyID, updateInput)
 		if err != nil {
 			r.Log.Error(err, "failed to update policy via New Relic API",
 				"policyId", policy.Status.PolicyID,
 				"region", policy.Spec.Region,
-				"apiKey", interfaces.PartialAPIKey(r.apiKey),
 			)
 			return err
 		}
 		policy.Status.PolicyID = updateResult.ID
+		r.Recorder.Eventf(policy, v1.EventTypeNormal, eventReasonUpdated, "Updated New Relic policy %s", updateResult.ID)
 	}
 
-	err = r.createOrUpdateConditions(policy)
+	err = r.createOrUpdateConditions(ctx, policy)
 	if err != nil {
 		r.Log.Error(err, "error creating or updating conditions")
 		return err
 	}
 	r.Log.Info("policySpec before update", "policy.Spec", policy.Spec)
 
-	if len(policy.Spec.ChannelIDs) > 0 {
-		r.Log.Info("May need to udpate policy Channels")
-		err = r.updateAlertsChannels(policy)
-		if err != nil {
-			r.Log.Error(err, "error creating or updating conditions")
-			return err
-		}
-	}
-
-	policy.Status.AppliedSpec = &policy.Spec
-
-	err = r.Client.Update(r.ctx, policy)
+	err = r.updateAlertsChannels(ctx, alertsClient, policy, channelIDs)
 	if err != nil {
-		r.Log.Error(err, "failed to update policy status", "name", policy.Name)
+		r.Log.Error(err, "error updating alert channels")
 		return err
 	}
 
 	return nil
 }
 
-func (r *AlertsPolicyReconciler) deleteAlertsPolicy(ctx context.Context, policy *nrv1.AlertsPolicy, deleteFinalizer string) (ctrl.Result, error) {
-	defer r.txn.StartSegment("deleteAlertsPolicy").End()
+func (r *AlertsPolicyReconciler) deleteAlertsPolicy(ctx context.Context, alertsClient interfaces.NewRelicAlertsClient, accountID int, policy *nrv1.AlertsPolicy, deleteFinalizer string) (ctrl.Result, error) {
+	defer newrelic.FromContext(ctx).StartSegment("deleteAlertsPolicy").End()
 	// The object is being deleted
 	if containsString(policy.Finalizers, deleteFinalizer) {
 		// catch invalid state
@@ -564,7 +1110,7 @@ func (r *AlertsPolicyReconciler) deleteAlertsPolicy(ctx context.Context, policy
 			// our finalizer is present, so lets handle any external dependency
 			collectedErrors := new(customErrors.ErrorCollector)
 			for _, condition := range policy.Status.AppliedSpec.Conditions {
-				err := r.deleteCondition(&condition)
+				err := r.deleteCondition(ctx, policy, &condition)
 				if err != nil {
 					r.Log.Error(err, "error deleting condition resources")
 					collectedErrors.Collect(err)
@@ -575,15 +1121,14 @@ func (r *AlertsPolicyReconciler) deleteAlertsPolicy(ctx context.Context, policy
 				return ctrl.Result{}, collectedErrors
 			}
 
-			if err := r.deleteNewRelicAlertPolicy(policy); err != nil {
+			if err := r.deleteNewRelicAlertPolicy(ctx, alertsClient, accountID, policy); err != nil {
 				// if fail to delete the external dependency here, return with error
 				// so that it can be retried
 				r.Log.Error(err, "Failed to delete Alert AlertsPolicy via New Relic API",
 					"policyId", policy.Status.PolicyID,
 					"region", policy.Spec.Region,
-					"apiKey", interfaces.PartialAPIKey(r.apiKey),
 				)
-				return ctrl.Result{}, err
+				return retryLater(ctrl.Result{}, err)
 			}
 
 			// remove our finalizer from the list and update it.
@@ -602,108 +1147,225 @@ func (r *AlertsPolicyReconciler) deleteAlertsPolicy(ctx context.Context, policy
 	return ctrl.Result{}, nil
 }
 
+// conditionChanged passes the events of condition resources their policy repairs or reports on:
+// deletes, and changes to the spec, the annotations or the readiness. The rest of their status
+// changes with every reconcile.
+var conditionChanged = predicate.Funcs{
+	CreateFunc: func(event.CreateEvent) bool { return false },
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		return !reflect.DeepEqual(specOf(e.ObjectOld), specOf(e.ObjectNew)) ||
+			!reflect.DeepEqual(e.MetaOld.GetAnnotations(), e.MetaNew.GetAnnotations()) ||
+			readinessOf(e.ObjectOld) != readinessOf(e.ObjectNew)
+	},
+}
+
+// readinessOf returns whether the resource obj is ready, and its error if it has one.
+func readinessOf(obj runtime.Object) string {
+	statusObj, ok := obj.(nrv1.StatusObject)
+	if !ok {
+		return ""
+	}
+
+	conditions := statusObj.GetResourceStatus().Conditions
+	readiness := fmt.Sprint(nrv1.IsConditionTrue(conditions, nrv1.ConditionReady))
+
+	if nrv1.IsConditionTrue(conditions, nrv1.ConditionError) {
+		readiness += ": " + nrv1.FindCondition(conditions, nrv1.ConditionError).Message
+	}
+
+	return readiness
+}
+
 func (r *AlertsPolicyReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if err := indexSecretReferences(mgr, &nrv1.AlertsPolicy{}); err != nil {
+		return err
+	}
+
+	if err := indexChannelReferences(mgr); err != nil {
+		return err
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&nrv1.AlertsPolicy{}).
+		Owns(&nrv1.AlertsNrqlCondition{}, builder.WithPredicates(conditionChanged)).
+		Owns(&nrv1.AlertsAPMCondition{}, builder.WithPredicates(conditionChanged)).
+		Watches(&source.Kind{Type: &v1.Secret{}}, enqueueSecretDependents(mgr.GetClient(), &nrv1.AlertsPolicyList{})).
+		Watches(&source.Kind{Type: &nrv1.NewRelicAccount{}}, enqueueAccountDependents(mgr.GetClient(), &nrv1.AlertsPolicyList{})).
+		Watches(&source.Kind{Type: &nrv1.AlertsChannel{}}, enqueueChannelDependents(mgr.GetClient())).
+		Watches(&source.Kind{Type: &v1.Namespace{}}, enqueueNamespaceDependents(mgr.GetClient(), &nrv1.AlertsPolicyList{})).
+		WithOptions(controller.Options{MaxConcurrentReconciles: r.MaxConcurrentReconciles}).
 		Complete(r)
 }
 
-func (r *AlertsPolicyReconciler) checkForExistingAlertsPolicy(policy *nrv1.AlertsPolicy) {
-	defer r.txn.StartSegment("checkForExistingAlertsPolicy").End()
+func (r *AlertsPolicyReconciler) checkForExistingAlertsPolicy(ctx context.Context, alertsClient interfaces.NewRelicAlertsClient, accountID int, policy *nrv1.AlertsPolicy) error {
+	defer newrelic.FromContext(ctx).StartSegment("checkForExistingAlertsPolicy").End()
 	if policy.Status.PolicyID != "" {
-		return
+		return nil
+	}
+
+	switch nrv1.GetAdoptionPolicy(policy.Spec.Adoption, policy.Spec.ImportID) {
+	case nrv1.AdoptionNever:
+		return nil
+	case nrv1.AdoptionByID:
+		r.Log.Info("importing existing policy", "policy", policy.Name, "policyId", policy.Spec.ImportID)
+
+		if _, err := alertsClient.QueryPolicy(accountID, policy.Spec.ImportID); err != nil {
+			return err
+		}
+
+		if err := r.Ownership.checkImportable(alertsClient, accountID, ownedPolicy, policy.Spec.ImportID); err != nil {
+			return err
+		}
+
+		policy.Status.PolicyID = policy.Spec.ImportID
+		r.Recorder.Eventf(policy, v1.EventTypeNormal, eventReasonImported, "Imported existing New Relic policy %s", policy.Spec.ImportID)
+
+		return r.Ownership.claim(alertsClient, accountID, ownedPolicy, policy.Status.PolicyID, policy)
 	}
 
 	r.Log.Info("checking for existing policy", "policy", policy.Name, "policyName", policy.Spec.Name)
 
 	//if no policyId, get list of policies and compare name
 	searchParams := alerts.AlertsPoliciesSearchCriteriaInput{}
-	existingPolicies, err := r.Alerts.QueryPolicySearch(policy.Spec.AccountID, searchParams)
+	existingPolicies, err := alertsClient.QueryPolicySearch(accountID, searchParams)
 
 	if err != nil {
 		r.Log.Error(err, "failed to get list of policies from New Relic API",
 			"policyId", policy.Status.PolicyID,
 			"region", policy.Spec.Region,
-			"apiKey", interfaces.PartialAPIKey(r.apiKey),
 		)
-	} else {
-		for _, existingAlertsPolicy := range existingPolicies {
-			if existingAlertsPolicy.Name == policy.Spec.Name {
-				r.Log.Info("matched on existing policy, updating PolicyId", "policyId", existingAlertsPolicy.ID)
-				policy.Status.PolicyID = existingAlertsPolicy.ID
 
-				break
-			}
+		return err
+	}
+
+	for _, existingAlertsPolicy := range existingPolicies {
+		if existingAlertsPolicy.Name != r.Naming.remoteName(policy, policy.Spec.Name) {
+			continue
+		}
+
+		unowned, err := r.Ownership.isUnowned(alertsClient, accountID, ownedPolicy, existingAlertsPolicy.ID, policy)
+		if err != nil {
+			return err
 		}
+
+		if !unowned {
+			r.Log.Info("skipping policy with the same name managed by another resource", "policyId", existingAlertsPolicy.ID)
+			continue
+		}
+
+		r.Log.Info("matched on existing policy, updating PolicyId", "policyId", existingAlertsPolicy.ID)
+		policy.Status.PolicyID = existingAlertsPolicy.ID
+		r.Recorder.Eventf(policy, v1.EventTypeNormal, eventReasonAdopted, "Adopted existing New Relic policy %s", existingAlertsPolicy.ID)
+
+		return r.Ownership.claim(alertsClient, accountID, ownedPolicy, policy.Status.PolicyID, policy)
 	}
+
+	return nil
 }
 
-func (r *AlertsPolicyReconciler) deleteNewRelicAlertPolicy(policy *nrv1.AlertsPolicy) error {
-	defer r.txn.StartSegment("deleteNewRelicAlertPolicy").End()
+func (r *AlertsPolicyReconciler) deleteNewRelicAlertPolicy(ctx context.Context, alertsClient interfaces.NewRelicAlertsClient, accountID int, policy *nrv1.AlertsPolicy) error {
+	defer newrelic.FromContext(ctx).StartSegment("deleteNewRelicAlertPolicy").End()
 	r.Log.Info("Deleting policy", "policyName", policy.Spec.Name)
 
-	_, err := r.Alerts.DeletePolicyMutation(policy.Spec.AccountID, policy.Status.PolicyID)
+	skip, err := r.Ownership.skipDelete(r.Recorder, alertsClient, accountID, ownedPolicy, policy.Status.PolicyID, policy)
+	if skip || err != nil {
+		return err
+	}
+
+	_, err = alertsClient.DeletePolicyMutation(accountID, policy.Status.PolicyID)
 	if err != nil {
 		r.Log.Error(err, "error deleting policy via New Relic API",
 			"policyId", policy.Status.PolicyID,
 			"region", policy.Spec.Region,
-			"apiKey", interfaces.PartialAPIKey(r.apiKey),
 		)
 
 		return err
 	}
 
+	r.Recorder.Eventf(policy, v1.EventTypeNormal, eventReasonDeleted, "Deleted New Relic policy %s", policy.Status.PolicyID)
+
+	if err := r.Ownership.release(alertsClient, accountID, ownedPolicy, policy.Status.PolicyID); err != nil {
+		r.Log.Error(err, "failed to remove ownership marker of deleted policy", "policyId", policy.Status.PolicyID)
+	}
+
 	return nil
 }
 
-func (r *AlertsPolicyReconciler) createAlertsChannels(policy *nrv1.AlertsPolicy) error {
-	if len(policy.Spec.ChannelIDs) > 0 {
-		r.Log.Info("creating channels to policy", "channelIds", policy.Spec.ChannelIDs, "policyId", policy.Status.PolicyID)
+func (r *AlertsPolicyReconciler) createAlertsChannels(alertsClient interfaces.NewRelicAlertsClient, policy *nrv1.AlertsPolicy, channelIDs []int) error {
+	if len(channelIDs) > 0 {
+		r.Log.Info("creating channels to policy", "channelIds", channelIDs, "policyId", policy.Status.PolicyID)
 		policyID, errInt := strconv.Atoi(policy.Status.PolicyID)
 		if errInt != nil {
 			r.Log.Error(errInt, "Failed to parse policyID as an int")
 			return errInt
 		}
 
-		alertsChannels, err := r.Alerts.UpdatePolicyChannels(policyID, policy.Spec.ChannelIDs)
+		alertsChannels, err := alertsClient.UpdatePolicyChannels(policyID, channelIDs)
 		if err != nil {
 			r.Log.Error(err, "error creating channels")
 			return err
 		}
+		r.Recorder.Eventf(policy, v1.EventTypeNormal, eventReasonLinked, "Linked channels %v", channelIDs)
 		r.Log.Info("alertsChannels", "", alertsChannels)
-
-		return nil
 	}
 
+	policy.Status.ChannelIDs = channelIDs
+
 	return nil
 }
 
-func (r *AlertsPolicyReconciler) updateAlertsChannels(policy *nrv1.AlertsPolicy) error {
+// updateAlertsChannels links the channels in channelIDs to the policy and unlinks the channels it
+// is no longer meant for. Status.ChannelIDs holds the channels linked to the policy, channels that
+// were unlinked already are skipped when unlinking them again after a failure. Channels an
+// AlertsChannel links to the policy itself, by its links or its policy selector, are left linked:
+// the channel owns those links and would only add them back.
+func (r *AlertsPolicyReconciler) updateAlertsChannels(ctx context.Context, alertsClient interfaces.NewRelicAlertsClient, policy *nrv1.AlertsPolicy, channelIDs []int) error {
 	policyID, errInt := strconv.Atoi(policy.Status.PolicyID)
 	if errInt != nil {
 		r.Log.Error(errInt, "Failed to parse policyID as an int")
 		return errInt
 	}
-	r.Log.Info("updating channels to policy", "channelIds", policy.Spec.ChannelIDs, "policyId", policy.Status.PolicyID)
+	r.Log.Info("updating channels to policy", "channelIds", channelIDs, "policyId", policy.Status.PolicyID)
 
-	channelsToAdd := diffIntSlice(policy.Spec.ChannelIDs, policy.Status.AppliedSpec.ChannelIDs)
-	channelsToRemove := diffIntSlice(policy.Status.AppliedSpec.ChannelIDs, policy.Spec.ChannelIDs)
+	linkedChannelIDs := appliedChannelIDs(policy)
+	channelsToAdd := diffIntSlice(channelIDs, linkedChannelIDs)
+	channelsToRemove := diffIntSlice(linkedChannelIDs, channelIDs)
 	r.Log.Info("channel differences found", "channelsToAdd", channelsToAdd, "channelsToRemove", channelsToRemove)
 
+	var channelLinked map[int]bool
+	if len(channelsToRemove) > 0 {
+		var err error
+		if channelLinked, err = channelsLinkingPolicy(ctx, r.Client, policyID); err != nil {
+			r.Log.Error(err, "error listing the channels linked to the policy")
+			return err
+		}
+	}
+
 	for _, channel := range channelsToRemove {
-		deleteChannel, err := r.Alerts.DeletePolicyChannel(policyID, channel)
-		if err != nil {
+		if channelLinked[channel] {
+			r.Log.Info("leaving channel linked by its AlertsChannel", "channelId", channel, "policyId", policyID)
+			continue
+		}
+
+		deleteChannel, err := alertsClient.DeletePolicyChannel(policyID, channel)
+		if err != nil && !customErrors.IsNotFound(err) {
 			r.Log.Error(err, "error removing channels", "deleteChannel", deleteChannel)
 			return err
 		}
+		r.Recorder.Eventf(policy, v1.EventTypeNormal, eventReasonUnlinked, "Unlinked channel %d", channel)
 	}
 
-	alertsChannel, err := r.Alerts.UpdatePolicyChannels(policyID, channelsToAdd)
-	if err != nil {
-		r.Log.Error(err, "error updating channels")
-		return err
+	if len(channelsToAdd) > 0 {
+		alertsChannel, err := alertsClient.UpdatePolicyChannels(policyID, channelsToAdd)
+		if err != nil {
+			r.Log.Error(err, "error updating channels")
+			return err
+		}
+		r.Recorder.Eventf(policy, v1.EventTypeNormal, eventReasonLinked, "Linked channels %v", channelsToAdd)
+		r.Log.Info("alertsChannels", "", alertsChannel)
 	}
-	r.Log.Info("alertsChannels", "", alertsChannel)
+
+	policy.Status.ChannelIDs = channelIDs
 
 	return nil
 }
@@ -728,23 +1390,18 @@ func diffIntSlice(first, second []int) []int {
 	return diff
 }
 
-func (r *AlertsPolicyReconciler) getAPIKeyOrSecret(policy nrv1.AlertsPolicy) (string, error) {
-	defer r.txn.StartSegment("getAPIKeyOrSecret").End()
-	if policy.Spec.APIKey != "" {
-		return policy.Spec.APIKey, nil
-	}
+func (r *AlertsPolicyReconciler) getCredentials(ctx context.Context, policy nrv1.AlertsPolicy) (nrv1.ResolvedCredentials, error) {
+	defer newrelic.FromContext(ctx).StartSegment("getCredentials").End()
 
-	if policy.Spec.APIKeySecret != (nrv1.NewRelicAPIKeySecret{}) {
-		key := types.NamespacedName{Namespace: policy.Spec.APIKeySecret.Namespace, Name: policy.Spec.APIKeySecret.Name}
-		var apiKeySecret v1.Secret
-		getErr := r.Client.Get(context.Background(), key, &apiKeySecret)
-		if getErr != nil {
-			r.Log.Error(getErr, "Failed to retrieve secret", "secret", apiKeySecret)
-			return "", getErr
-		}
+	creds, err := policy.Spec.Credentials().Resolve(ctx, r.Client, policy.Namespace)
+	if err != nil {
+		r.Log.Error(err, "Failed to resolve credentials")
+		return creds, err
+	}
 
-		return string(apiKeySecret.Data[policy.Spec.APIKeySecret.KeyName]), nil
+	if err := ownAPIKeySecret(ctx, r.Client, &policy, policy.Spec.APIKeySecret); err != nil {
+		r.Log.Error(err, "Failed to add the resource to the owners of its API key secret")
 	}
 
-	return "", nil
+	return creds, nil
 }
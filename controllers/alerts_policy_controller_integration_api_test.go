@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
@@ -19,7 +20,8 @@ func TestIntegrationAlertsPolicyController(t *testing.T) {
 	t.Parallel()
 
 	// Must come before calling reconciler.Reconcile()
-	k8sClient := testutil.AlertsPolicyTestSetup(t)
+	k8sClient, testEnv := testutil.AlertsPolicyTestEnv(t)
+	defer testEnv.Stop()
 
 	namespacedName := types.NamespacedName{
 		Namespace: "default",
@@ -33,6 +35,7 @@ func TestIntegrationAlertsPolicyController(t *testing.T) {
 	reconciler := &AlertsPolicyReconciler{
 		Client:          k8sClient,
 		Log:             logf.Log,
+		Recorder:        record.NewFakeRecorder(100),
 		AlertClientFunc: interfaces.InitializeAlertsClient,
 	}
 
@@ -3,9 +3,11 @@ package controllers
 import (
 	"context"
 	"errors"
+	"time"
 
 	newrelic "github.com/newrelic/go-agent/v3/newrelic"
 	"github.com/newrelic/newrelic-client-go/pkg/alerts"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	. "github.com/onsi/ginkgo"
@@ -74,6 +76,7 @@ var _ = Describe("alertspolicy reconciliation", func() {
 		r = &AlertsPolicyReconciler{
 			Client:          k8sClient,
 			Log:             logf.Log,
+			Recorder:        record.NewFakeRecorder(100),
 			AlertClientFunc: fakeAlertFunc,
 			NewRelicAgent:   newRelicAgent,
 		}
@@ -110,6 +113,7 @@ var _ = Describe("alertspolicy reconciliation", func() {
 			r = &AlertsPolicyReconciler{
 				Client:          k8sClient,
 				Log:             logf.Log,
+				Recorder:        record.NewFakeRecorder(100),
 				AlertClientFunc: fakeAlertFunc,
 				NewRelicAgent:   newrelic.Application{},
 			}
@@ -309,6 +313,162 @@ var _ = Describe("alertspolicy reconciliation", func() {
 				Expect(getErr).ToNot(HaveOccurred())
 				Expect(endStateAlertsPolicy.Status.PolicyID).To(Equal(""))
 			})
+
+			It("should record the error in the status", func() {
+				createErr := k8sClient.Create(ctx, alertspolicy)
+				Expect(createErr).ToNot(HaveOccurred())
+
+				// call reconcile
+				_, reconcileErr := r.Reconcile(request)
+				Expect(reconcileErr).To(HaveOccurred())
+
+				var endStateAlertsPolicy nrv1.AlertsPolicy
+				getErr := k8sClient.Get(ctx, namespacedName, &endStateAlertsPolicy)
+				Expect(getErr).ToNot(HaveOccurred())
+				Expect(nrv1.IsConditionTrue(endStateAlertsPolicy.Status.Conditions, nrv1.ConditionReady)).To(BeFalse())
+
+				errorCondition := nrv1.FindCondition(endStateAlertsPolicy.Status.Conditions, nrv1.ConditionError)
+				Expect(errorCondition).ToNot(BeNil())
+				Expect(errorCondition.Status).To(Equal(metav1.ConditionTrue))
+				Expect(errorCondition.Message).To(Equal("any Error Goes Here"))
+			})
+		})
+
+		Context("when the alertspolicy is in dry-run mode", func() {
+			var recorder *record.FakeRecorder
+
+			BeforeEach(func() {
+				recorder = record.NewFakeRecorder(100)
+				r.Recorder = WithDryRunEvents(recorder, false)
+
+				alertspolicy.Annotations = map[string]string{nrv1.DryRunAnnotation: "true"}
+
+				err := k8sClient.Create(ctx, alertspolicy)
+				Expect(err).ToNot(HaveOccurred())
+
+				_, err = r.Reconcile(request)
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			AfterEach(func() {
+				var endStateAlertsPolicy nrv1.AlertsPolicy
+				err := k8sClient.Get(ctx, namespacedName, &endStateAlertsPolicy)
+				Expect(err).ToNot(HaveOccurred())
+
+				// let the outer AfterEach delete the policy
+				delete(endStateAlertsPolicy.Annotations, nrv1.DryRunAnnotation)
+				err = k8sClient.Update(ctx, &endStateAlertsPolicy)
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("should not write to New Relic or create the conditions", func() {
+				Expect(mockAlertsClient.CreatePolicyMutationCallCount()).To(Equal(0))
+				Expect(mockAlertsClient.UpdatePolicyChannelsCallCount()).To(Equal(0))
+
+				var endStateAlertsPolicy nrv1.AlertsPolicy
+				err := k8sClient.Get(ctx, namespacedName, &endStateAlertsPolicy)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(endStateAlertsPolicy.Status.PolicyID).To(Equal(""))
+				Expect(endStateAlertsPolicy.Status.AppliedSpec).To(Equal(&nrv1.AlertsPolicySpec{}))
+				Expect(endStateAlertsPolicy.Spec.Conditions[0].Name).To(Equal(""))
+			})
+
+			It("should record the plan in the status", func() {
+				var endStateAlertsPolicy nrv1.AlertsPolicy
+				err := k8sClient.Get(ctx, namespacedName, &endStateAlertsPolicy)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(nrv1.IsConditionTrue(endStateAlertsPolicy.Status.Conditions, nrv1.ConditionPlanned)).To(BeTrue())
+				Expect(nrv1.IsConditionTrue(endStateAlertsPolicy.Status.Conditions, nrv1.ConditionReady)).To(BeFalse())
+
+				plan := endStateAlertsPolicy.Status.Plan
+				Expect(plan).ToNot(BeEmpty())
+				Expect(plan[0]).To(Equal(nrv1.PlannedOperation{
+					Operation: "Create",
+					Resource:  "policy",
+					Changes:   []string{`incidentPreference: "PER_POLICY"`, `name: "test alertspolicy"`},
+				}))
+
+				var resources []string
+				for _, operation := range plan {
+					resources = append(resources, operation.Operation+" "+operation.Resource+" "+operation.ID)
+				}
+				Expect(resources).To(ContainElement("Create AlertsNrqlCondition test-alertspolicy-nrql-condition"))
+			})
+
+			It("should record the plan in events instead of the writes", func() {
+				Eventually(recorder.Events).Should(Receive(Equal(`Normal Planned Planned create of policy: incidentPreference: "PER_POLICY", name: "test alertspolicy"`)))
+
+				close(recorder.Events)
+				for event := range recorder.Events {
+					Expect(event).ToNot(ContainSubstring("Created"))
+				}
+			})
+
+			It("should clear the plan and write to New Relic once the annotation is removed", func() {
+				var endStateAlertsPolicy nrv1.AlertsPolicy
+				err := k8sClient.Get(ctx, namespacedName, &endStateAlertsPolicy)
+				Expect(err).ToNot(HaveOccurred())
+
+				delete(endStateAlertsPolicy.Annotations, nrv1.DryRunAnnotation)
+				err = k8sClient.Update(ctx, &endStateAlertsPolicy)
+				Expect(err).ToNot(HaveOccurred())
+
+				_, err = r.Reconcile(request)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(mockAlertsClient.CreatePolicyMutationCallCount()).To(Equal(1))
+
+				var appliedAlertsPolicy nrv1.AlertsPolicy
+				err = k8sClient.Get(ctx, namespacedName, &appliedAlertsPolicy)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(appliedAlertsPolicy.Status.PolicyID).To(Equal("333"))
+				Expect(appliedAlertsPolicy.Status.Plan).To(BeEmpty())
+				Expect(nrv1.FindCondition(appliedAlertsPolicy.Status.Conditions, nrv1.ConditionPlanned)).To(BeNil())
+			})
+		})
+
+		Context("when the condition resource of another policy has the name of its condition", func() {
+			var other *nrv1.AlertsNrqlCondition
+
+			BeforeEach(func() {
+				alertspolicy.Spec.Conditions[0].Key = "shared-cpu"
+
+				// the resource of the condition with the key "alertspolicy-shared-cpu" of the policy "test"
+				other = &nrv1.AlertsNrqlCondition{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-alertspolicy-shared-cpu",
+						Namespace: "default",
+					},
+					Spec: nrv1.AlertsNrqlConditionSpec{
+						AlertsGenericConditionSpec: conditionSpec.AlertsGenericConditionSpec,
+						AlertsNrqlSpecificSpec:     conditionSpec.AlertsNrqlSpecificSpec,
+					},
+				}
+				err := k8sClient.Create(ctx, other)
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			AfterEach(func() {
+				err := k8sClient.Delete(ctx, other)
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("should number the name of the condition", func() {
+				err := k8sClient.Create(ctx, alertspolicy)
+				Expect(err).ToNot(HaveOccurred())
+
+				_, err = r.Reconcile(request)
+				Expect(err).ToNot(HaveOccurred())
+
+				var endStateAlertsPolicy nrv1.AlertsPolicy
+				err = k8sClient.Get(ctx, namespacedName, &endStateAlertsPolicy)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(endStateAlertsPolicy.Spec.Conditions[0].Name).To(Equal("test-alertspolicy-shared-cpu-2"))
+
+				var untouched nrv1.AlertsNrqlCondition
+				err = k8sClient.Get(ctx, types.NamespacedName{Namespace: "default", Name: other.Name}, &untouched)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(untouched.OwnerReferences).To(BeEmpty())
+			})
 		})
 
 		Context("when creating a valid alertspolicy with apm conditions", func() {
@@ -569,6 +729,174 @@ var _ = Describe("alertspolicy reconciliation", func() {
 				Expect(err).ToNot(HaveOccurred())
 			})
 		})
+
+		Context("and referring to an AlertsChannel", func() {
+			var (
+				channel     *nrv1.AlertsChannel
+				channelName types.NamespacedName
+			)
+
+			BeforeEach(func() {
+				channelName = types.NamespacedName{Namespace: "default", Name: "test-channel-ref"}
+				channel = &nrv1.AlertsChannel{
+					ObjectMeta: metav1.ObjectMeta{Name: channelName.Name, Namespace: channelName.Namespace},
+					Spec:       nrv1.AlertsChannelSpec{Name: "test channel", Type: "email"},
+					Status:     nrv1.AlertsChannelStatus{ChannelID: 7},
+				}
+
+				alertspolicy.Spec.ChannelRefs = []nrv1.AlertsChannelReference{{Name: channelName.Name}}
+				err := k8sClient.Update(ctx, alertspolicy)
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("waits for the channel and links it once it is created", func() {
+				result, err := r.Reconcile(request)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result.RequeueAfter).To(Equal(policyRefRetryInterval))
+				Expect(mockAlertsClient.UpdatePolicyChannelsCallCount()).To(Equal(1))
+
+				var waitingAlertsPolicy nrv1.AlertsPolicy
+				err = k8sClient.Get(ctx, namespacedName, &waitingAlertsPolicy)
+				Expect(err).ToNot(HaveOccurred())
+				synced := nrv1.FindCondition(waitingAlertsPolicy.Status.Conditions, nrv1.ConditionSynced)
+				Expect(synced.Reason).To(Equal(nrv1.ReasonWaitingForChannels))
+				Expect(synced.Message).To(ContainSubstring("default/test-channel-ref"))
+
+				err = k8sClient.Create(ctx, channel)
+				Expect(err).ToNot(HaveOccurred())
+
+				_, err = r.Reconcile(request)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(mockAlertsClient.UpdatePolicyChannelsCallCount()).To(Equal(2))
+				policyID, channelIDs := mockAlertsClient.UpdatePolicyChannelsArgsForCall(1)
+				Expect(policyID).To(Equal(333))
+				Expect(channelIDs).To(Equal([]int{7}))
+
+				var endStateAlertsPolicy nrv1.AlertsPolicy
+				err = k8sClient.Get(ctx, namespacedName, &endStateAlertsPolicy)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(endStateAlertsPolicy.Status.ChannelIDs).To(Equal([]int{1, 2, 7}))
+				Expect(nrv1.IsConditionTrue(endStateAlertsPolicy.Status.Conditions, nrv1.ConditionSynced)).To(BeTrue())
+			})
+
+			It("unlinks the channel when the ref is removed", func() {
+				err := k8sClient.Create(ctx, channel)
+				Expect(err).ToNot(HaveOccurred())
+
+				_, err = r.Reconcile(request)
+				Expect(err).ToNot(HaveOccurred())
+
+				err = k8sClient.Get(ctx, namespacedName, alertspolicy)
+				Expect(err).ToNot(HaveOccurred())
+				alertspolicy.Spec.ChannelRefs = nil
+				err = k8sClient.Update(ctx, alertspolicy)
+				Expect(err).ToNot(HaveOccurred())
+
+				_, err = r.Reconcile(request)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(mockAlertsClient.DeletePolicyChannelCallCount()).To(Equal(1))
+				policyID, deletedChannel := mockAlertsClient.DeletePolicyChannelArgsForCall(0)
+				Expect(policyID).To(Equal(333))
+				Expect(deletedChannel).To(Equal(7))
+			})
+
+			It("leaves the link of a channel that selects the policy", func() {
+				// the links in New Relic, by policy ID
+				links := map[int]map[int]bool{}
+				mockAlertsClient.UpdatePolicyChannelsStub = func(policy int, channels []int) (*alerts.PolicyChannels, error) {
+					if links[policy] == nil {
+						links[policy] = map[int]bool{}
+					}
+					for _, channel := range channels {
+						links[policy][channel] = true
+					}
+					return &alerts.PolicyChannels{ID: policy, ChannelIDs: channels}, nil
+				}
+				mockAlertsClient.DeletePolicyChannelStub = func(policy int, channel int) (*alerts.Channel, error) {
+					delete(links[policy], channel)
+					return &alerts.Channel{ID: channel}, nil
+				}
+				mockAlertsClient.ListChannelsStub = func() ([]*alerts.Channel, error) {
+					remoteChannel := &alerts.Channel{ID: 7, Name: "test channel", Type: "email"}
+					for policy, channels := range links {
+						if channels[7] {
+							remoteChannel.Links.PolicyIDs = append(remoteChannel.Links.PolicyIDs, policy)
+						}
+					}
+					return []*alerts.Channel{remoteChannel}, nil
+				}
+
+				channelReconciler := &AlertsChannelReconciler{
+					Client:          k8sClient,
+					Log:             logf.Log,
+					Recorder:        record.NewFakeRecorder(100),
+					AlertClientFunc: fakeAlertFunc,
+					NewRelicAgent:   newrelic.Application{},
+					ResyncInterval:  time.Minute,
+				}
+				channelRequest := ctrl.Request{NamespacedName: channelName}
+
+				alertspolicy.Labels = map[string]string{"team": "sre"}
+				err := k8sClient.Update(ctx, alertspolicy)
+				Expect(err).ToNot(HaveOccurred())
+
+				channel.Spec.APIKey = "api-key"
+				channel.Spec.PolicySelector = &nrv1.AlertsPolicySelector{
+					LabelSelector: metav1.LabelSelector{MatchLabels: map[string]string{"team": "sre"}},
+				}
+				err = k8sClient.Create(ctx, channel)
+				Expect(err).ToNot(HaveOccurred())
+
+				_, err = r.Reconcile(request)
+				Expect(err).ToNot(HaveOccurred())
+				_, err = channelReconciler.Reconcile(channelRequest)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(links[333][7]).To(BeTrue())
+
+				err = k8sClient.Get(ctx, namespacedName, alertspolicy)
+				Expect(err).ToNot(HaveOccurred())
+				alertspolicy.Spec.ChannelRefs = nil
+				err = k8sClient.Update(ctx, alertspolicy)
+				Expect(err).ToNot(HaveOccurred())
+
+				_, err = r.Reconcile(request)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(mockAlertsClient.DeletePolicyChannelCallCount()).To(BeZero())
+
+				linkCalls := mockAlertsClient.UpdatePolicyChannelsCallCount()
+
+				// neither controller changes the links once they settled
+				for i := 0; i < 2; i++ {
+					_, err = channelReconciler.Reconcile(channelRequest)
+					Expect(err).ToNot(HaveOccurred())
+					_, err = r.Reconcile(request)
+					Expect(err).ToNot(HaveOccurred())
+				}
+
+				Expect(mockAlertsClient.DeletePolicyChannelCallCount()).To(BeZero())
+				Expect(mockAlertsClient.UpdatePolicyChannelsCallCount()).To(Equal(linkCalls))
+				Expect(links[333][7]).To(BeTrue())
+
+				// the channel is deleted without removing its links
+				err = k8sClient.Get(ctx, channelName, channel)
+				Expect(err).ToNot(HaveOccurred())
+				channel.Finalizers = nil
+				err = k8sClient.Update(ctx, channel)
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			AfterEach(func() {
+				err := k8sClient.Delete(ctx, channel)
+				Expect(err).ToNot(HaveOccurred())
+
+				err = k8sClient.Delete(ctx, alertspolicy)
+				Expect(err).ToNot(HaveOccurred())
+
+				// Need to call reconcile to delete finalizer
+				_, err = r.Reconcile(request)
+				Expect(err).ToNot(HaveOccurred())
+			})
+		})
 	})
 
 	Context("When starting with an existing alertspolicy with a NRQL condition", func() {
@@ -689,6 +1017,24 @@ var _ = Describe("alertspolicy reconciliation", func() {
 				Expect(mockAlertsClient.UpdatePolicyMutationCallCount()).To(Equal(1))
 
 			})
+
+			It("should record the changed fields", func() {
+				recorder := record.NewFakeRecorder(100)
+				r.Recorder = recorder
+
+				err := k8sClient.Update(ctx, alertspolicy)
+				Expect(err).ToNot(HaveOccurred())
+
+				_, err = r.Reconcile(request)
+				Expect(err).ToNot(HaveOccurred())
+
+				var endStateAlertsPolicy nrv1.AlertsPolicy
+				err = k8sClient.Get(ctx, namespacedName, &endStateAlertsPolicy)
+				Expect(err).To(BeNil())
+				Expect(endStateAlertsPolicy.Status.Changes).To(Equal([]string{`incidentPreference: "PER_POLICY" -> "PER_CONDITION_AND_TARGET"`}))
+				// the policy has no account ID, the warning about its ownership markers comes first
+				Eventually(recorder.Events).Should(Receive(Equal(`Normal SpecChanged Changed incidentPreference: "PER_POLICY" -> "PER_CONDITION_AND_TARGET"`)))
+			})
 		})
 
 		Context("and updating a condition name", func() {
@@ -748,6 +1094,261 @@ var _ = Describe("alertspolicy reconciliation", func() {
 			})
 		})
 
+		Context("with a condition resource created with a random name", func() {
+			var generatedName string
+
+			BeforeEach(func() {
+				var condition nrv1.AlertsNrqlCondition
+				err := k8sClient.Get(ctx, alertspolicy.Status.AppliedSpec.Conditions[0].GetNamespace(), &condition)
+				Expect(err).ToNot(HaveOccurred())
+
+				generatedName = alertspolicy.Name + "-condition-x7k2p"
+				generated := nrv1.AlertsNrqlCondition{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:            generatedName,
+						GenerateName:    alertspolicy.Name + "-condition-",
+						Namespace:       condition.Namespace,
+						OwnerReferences: condition.OwnerReferences,
+					},
+					Spec:   condition.Spec,
+					Status: condition.Status,
+				}
+				generated.Status.ConditionID = "111"
+
+				err = k8sClient.Create(ctx, &generated)
+				Expect(err).ToNot(HaveOccurred())
+
+				err = k8sClient.Delete(ctx, &condition)
+				Expect(err).ToNot(HaveOccurred())
+
+				alertspolicy.Spec.Conditions[0].Name = generatedName
+				alertspolicy.Status.AppliedSpec.Conditions[0].Name = generatedName
+				// the policy was created by an older version of the operator
+				alertspolicy.Status.ConditionNamesMigrated = false
+				err = k8sClient.Update(ctx, alertspolicy)
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("should move the condition to a resource named after the policy", func() {
+				_, err := r.Reconcile(request)
+				Expect(err).ToNot(HaveOccurred())
+
+				var endStateAlertsPolicy nrv1.AlertsPolicy
+				err = k8sClient.Get(ctx, namespacedName, &endStateAlertsPolicy)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(endStateAlertsPolicy.Spec.Conditions[0].Name).To(Equal("test-alertspolicy-nrql-condition"))
+				Expect(endStateAlertsPolicy.Status.AppliedSpec.Conditions[0].Name).To(Equal("test-alertspolicy-nrql-condition"))
+
+				var renamed nrv1.AlertsNrqlCondition
+				err = k8sClient.Get(ctx, types.NamespacedName{Name: "test-alertspolicy-nrql-condition", Namespace: "default"}, &renamed)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(renamed.Status.ConditionID).To(Equal("111"))
+
+				var generated nrv1.AlertsNrqlCondition
+				err = k8sClient.Get(ctx, types.NamespacedName{Name: generatedName, Namespace: "default"}, &generated)
+				if err == nil {
+					Expect(generated.DeletionTimestamp).ToNot(BeNil())
+					Expect(generated.Annotations[nrv1.DeletionPolicyAnnotation]).To(Equal(string(nrv1.DeletionPolicyRetain)))
+				}
+
+				Expect(endStateAlertsPolicy.Status.ConditionNamesMigrated).To(BeTrue())
+			})
+
+			It("should not rename the resource once the names were migrated", func() {
+				alertspolicy.Status.ConditionNamesMigrated = true
+				err := k8sClient.Update(ctx, alertspolicy)
+				Expect(err).ToNot(HaveOccurred())
+
+				_, err = r.Reconcile(request)
+				Expect(err).ToNot(HaveOccurred())
+
+				var generated nrv1.AlertsNrqlCondition
+				err = k8sClient.Get(ctx, types.NamespacedName{Name: generatedName, Namespace: "default"}, &generated)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(generated.DeletionTimestamp).To(BeNil())
+			})
+		})
+
+		Context("and reporting the state of its conditions", func() {
+			var conditionName types.NamespacedName
+
+			BeforeEach(func() {
+				conditionName = alertspolicy.Status.AppliedSpec.Conditions[0].GetNamespace()
+			})
+
+			setConditionStatus := func(mark func(condition *nrv1.AlertsNrqlCondition)) {
+				var condition nrv1.AlertsNrqlCondition
+				err := k8sClient.Get(ctx, conditionName, &condition)
+				Expect(err).ToNot(HaveOccurred())
+
+				mark(&condition)
+				err = k8sClient.Update(ctx, &condition)
+				Expect(err).ToNot(HaveOccurred())
+
+				_, err = r.Reconcile(request)
+				Expect(err).ToNot(HaveOccurred())
+			}
+
+			It("should list the condition resource while it is pending", func() {
+				var endStateAlertsPolicy nrv1.AlertsPolicy
+				err := k8sClient.Get(ctx, namespacedName, &endStateAlertsPolicy)
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(endStateAlertsPolicy.Status.InlineConditions).To(HaveLen(1))
+				inline := endStateAlertsPolicy.Status.InlineConditions[0]
+				Expect(inline.Name).To(Equal("NRQL Condition"))
+				Expect(inline.State).To(Equal(nrv1.InlineConditionPending))
+				Expect(inline.ConditionRef.Kind).To(Equal("AlertsNrqlCondition"))
+				Expect(inline.ConditionRef.Name).To(Equal(conditionName.Name))
+
+				Expect(nrv1.IsConditionTrue(endStateAlertsPolicy.Status.Conditions, nrv1.ConditionReady)).To(BeFalse())
+				Expect(nrv1.IsConditionTrue(endStateAlertsPolicy.Status.Conditions, nrv1.ConditionSynced)).To(BeTrue())
+				Expect(nrv1.FindCondition(endStateAlertsPolicy.Status.Conditions, nrv1.ConditionReady).Reason).To(Equal(nrv1.ReasonConditionsNotReady))
+			})
+
+			It("should be ready once the condition resource is", func() {
+				setConditionStatus(func(condition *nrv1.AlertsNrqlCondition) {
+					condition.Status.ConditionID = "456"
+					condition.Status.MarkSynced()
+				})
+
+				var endStateAlertsPolicy nrv1.AlertsPolicy
+				err := k8sClient.Get(ctx, namespacedName, &endStateAlertsPolicy)
+				Expect(err).ToNot(HaveOccurred())
+
+				inline := endStateAlertsPolicy.Status.InlineConditions[0]
+				Expect(inline.State).To(Equal(nrv1.InlineConditionReady))
+				Expect(inline.ConditionID).To(Equal("456"))
+				Expect(nrv1.IsConditionTrue(endStateAlertsPolicy.Status.Conditions, nrv1.ConditionReady)).To(BeTrue())
+			})
+
+			It("should report the error of a condition resource that failed", func() {
+				setConditionStatus(func(condition *nrv1.AlertsNrqlCondition) {
+					condition.Status.MarkFailed(nrv1.ReasonValidationFailed, errors.New("invalid nrql query"))
+				})
+
+				var endStateAlertsPolicy nrv1.AlertsPolicy
+				err := k8sClient.Get(ctx, namespacedName, &endStateAlertsPolicy)
+				Expect(err).ToNot(HaveOccurred())
+
+				inline := endStateAlertsPolicy.Status.InlineConditions[0]
+				Expect(inline.State).To(Equal(nrv1.InlineConditionFailed))
+				Expect(inline.LastError).To(Equal("invalid nrql query"))
+
+				ready := nrv1.FindCondition(endStateAlertsPolicy.Status.Conditions, nrv1.ConditionReady)
+				Expect(ready.Message).To(ContainSubstring("NRQL Condition (Failed)"))
+			})
+		})
+
+		Context("and changing its condition resource directly", func() {
+			var (
+				conditionName types.NamespacedName
+				condition     nrv1.AlertsNrqlCondition
+			)
+
+			BeforeEach(func() {
+				conditionName = alertspolicy.Status.AppliedSpec.Conditions[0].GetNamespace()
+
+				err := k8sClient.Get(ctx, conditionName, &condition)
+				Expect(err).ToNot(HaveOccurred())
+
+				condition.Spec.Terms[0].Threshold = "99"
+			})
+
+			It("should write the condition of the policy again", func() {
+				err := k8sClient.Update(ctx, &condition)
+				Expect(err).ToNot(HaveOccurred())
+
+				_, err = r.Reconcile(request)
+				Expect(err).ToNot(HaveOccurred())
+
+				var endStateCondition nrv1.AlertsNrqlCondition
+				err = k8sClient.Get(ctx, conditionName, &endStateCondition)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(endStateCondition.Spec.Terms[0].Threshold).To(Equal("5"))
+			})
+
+			It("should leave conditions with the break-glass annotation as they are", func() {
+				condition.Annotations = map[string]string{nrv1.BreakGlassAnnotation: "true"}
+				err := k8sClient.Update(ctx, &condition)
+				Expect(err).ToNot(HaveOccurred())
+
+				_, err = r.Reconcile(request)
+				Expect(err).ToNot(HaveOccurred())
+
+				var endStateCondition nrv1.AlertsNrqlCondition
+				err = k8sClient.Get(ctx, conditionName, &endStateCondition)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(endStateCondition.Spec.Terms[0].Threshold).To(Equal("99"))
+			})
+
+			It("should create a deleted condition resource again", func() {
+				err := k8sClient.Delete(ctx, &condition)
+				Expect(err).ToNot(HaveOccurred())
+
+				_, err = r.Reconcile(request)
+				Expect(err).ToNot(HaveOccurred())
+
+				var endStateCondition nrv1.AlertsNrqlCondition
+				Eventually(func() error {
+					return k8sClient.Get(ctx, conditionName, &endStateCondition)
+				}).Should(Succeed())
+				Expect(endStateCondition.Spec.Terms[0].Threshold).To(Equal("5"))
+				Expect(metav1.IsControlledBy(&endStateCondition, alertspolicy)).To(BeTrue())
+			})
+		})
+
+		Context("and giving the condition a key", func() {
+			var originalConditionName string
+
+			BeforeEach(func() {
+				originalConditionName = alertspolicy.Status.AppliedSpec.Conditions[0].Name
+
+				//clear out the Name and Namespace since those aren't stored in yaml so are blank when applying yaml
+				alertspolicy.Spec.Conditions[0].Name = ""
+				alertspolicy.Spec.Conditions[0].Namespace = ""
+				alertspolicy.Spec.Conditions[0].Key = "my-condition"
+
+				err := k8sClient.Update(ctx, alertspolicy)
+				Expect(err).ToNot(HaveOccurred())
+
+				_, err = r.Reconcile(request)
+				Expect(err).ToNot(HaveOccurred())
+
+				err = k8sClient.Get(ctx, namespacedName, alertspolicy)
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("should keep the condition", func() {
+				Expect(alertspolicy.Status.AppliedSpec.Conditions[0].Key).To(Equal("my-condition"))
+				Expect(alertspolicy.Status.AppliedSpec.Conditions[0].Name).To(Equal(originalConditionName))
+			})
+
+			It("should update the condition in place when it is renamed and its threshold changes", func() {
+				alertspolicy.Spec.Conditions[0].Name = ""
+				alertspolicy.Spec.Conditions[0].Namespace = ""
+				alertspolicy.Spec.Conditions[0].Spec.Name = "New conditionName"
+				alertspolicy.Spec.Conditions[0].Spec.Terms[0].Threshold = "10"
+
+				err := k8sClient.Update(ctx, alertspolicy)
+				Expect(err).ToNot(HaveOccurred())
+
+				_, err = r.Reconcile(request)
+				Expect(err).ToNot(HaveOccurred())
+
+				var endStateAlertsPolicy nrv1.AlertsPolicy
+				err = k8sClient.Get(ctx, namespacedName, &endStateAlertsPolicy)
+				Expect(err).To(BeNil())
+				Expect(endStateAlertsPolicy.Spec.Conditions[0].Name).To(Equal(originalConditionName))
+
+				var endStateCondition nrv1.AlertsNrqlCondition
+				err = k8sClient.Get(ctx, types.NamespacedName{Name: originalConditionName, Namespace: "default"}, &endStateCondition)
+				Expect(err).To(BeNil())
+				Expect(endStateCondition.Spec.Name).To(Equal("New conditionName"))
+				Expect(endStateCondition.Spec.Terms[0].Threshold).To(Equal("10"))
+			})
+		})
+
 		Context("and updating a condition ", func() {
 			BeforeEach(func() {
 				//clear out the Name and Namespace since those aren't stored in yaml so are blank when appling yaml
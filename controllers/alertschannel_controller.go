@@ -17,38 +17,61 @@ package controllers
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"reflect"
 	"sort"
 	"strconv"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
 	kErr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 
 	"github.com/go-logr/logr"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	"github.com/newrelic/go-agent/v3/newrelic"
 	"github.com/newrelic/newrelic-client-go/pkg/alerts"
 
 	nrv1 "github.com/newrelic/newrelic-kubernetes-operator/api/v1"
+	customErrors "github.com/newrelic/newrelic-kubernetes-operator/errors"
 	"github.com/newrelic/newrelic-kubernetes-operator/interfaces"
 )
 
 // AlertsChannelReconciler reconciles a AlertsChannel object
 type AlertsChannelReconciler struct {
 	client.Client
-	Log             logr.Logger
-	Scheme          *runtime.Scheme
-	AlertClientFunc func(string, string) (interfaces.NewRelicAlertsClient, error)
-	apiKey          string
-	Alerts          interfaces.NewRelicAlertsClient
-	ctx             context.Context
-	NewRelicAgent   newrelic.Application
-	txn             *newrelic.Transaction
+	stopper
+	Log                     logr.Logger
+	Recorder                record.EventRecorder
+	Scheme                  *runtime.Scheme
+	AlertClientFunc         func(string, string) (interfaces.NewRelicAlertsClient, error)
+	NewRelicAgent           newrelic.Application
+	ResyncInterval          time.Duration
+	MaxConcurrentReconciles int
+	Ownership               Ownership
+	Naming                  Naming
+	DryRun                  bool
+}
+
+// channelSecretFields are not returned by the New Relic API and can not be checked for drift.
+var channelSecretFields = []string{
+	"configuration.api_key",
+	"configuration.auth_password",
+	"configuration.auth_token",
+	"configuration.headers",
+	"configuration.key",
+	"configuration.service_key",
 }
 
 // +kubebuilder:rbac:groups=nr.k8s.newrelic.com,resources=alertschannels,verbs=get;list;watch;create;update;patch;delete
@@ -58,13 +81,14 @@ type AlertsChannelReconciler struct {
 func (r *AlertsChannelReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 	var alertsChannel nrv1.AlertsChannel
 
-	r.ctx = context.Background()
 	r.Log.WithValues("alertsChannel", req.NamespacedName)
 
-	r.txn = r.NewRelicAgent.StartTransaction("Reconcile/Alerts/AlertsPolicy")
-	defer r.txn.End()
+	txn := r.NewRelicAgent.StartTransaction("Reconcile/Alerts/AlertsPolicy")
+	defer txn.End()
+	ctx, cancel := r.withStop(newrelic.NewContext(context.Background(), txn))
+	defer cancel()
 
-	err := r.Client.Get(r.ctx, req.NamespacedName, &alertsChannel)
+	err := r.Client.Get(ctx, req.NamespacedName, &alertsChannel)
 	if err != nil {
 		if kErr.IsNotFound(err) {
 			r.Log.Info("AlertsChannel 'not found' after being deleted. This is expected and no cause for alarm", "error", err)
@@ -76,25 +100,53 @@ func (r *AlertsChannelReconciler) Reconcile(req ctrl.Request) (ctrl.Result, erro
 
 	r.Log.Info("alertsChannel", "alertsChannel.Spec", alertsChannel.Spec, "alertsChannel.status.applied", alertsChannel.Status.AppliedSpec)
 
-	r.apiKey, err = r.getAPIKeyOrSecret(alertsChannel)
+	original := alertsChannel.DeepCopy()
+
+	deleteFinalizer := "alertschannels.finalizers.nr.k8s.newrelic.com"
+
+	if isRetained(&alertsChannel, alertsChannel.Spec.DeletionPolicy) {
+		r.Log.Info("Retaining New Relic channel, just removing finalizer", "id", alertsChannel.Status.ChannelID)
+		return ctrl.Result{}, orphan(ctx, r.Client, r.Recorder, &alertsChannel, deleteFinalizer, "channel", fmt.Sprint(alertsChannel.Status.ChannelID))
+	}
+
+	creds, err := r.getCredentials(ctx, alertsChannel)
 	if err != nil {
-		return ctrl.Result{}, err
+		return ctrl.Result{}, r.markFailed(ctx, original, &alertsChannel, nrv1.ReasonCredentialsFailed, err)
 	}
 
-	if r.apiKey == "" {
-		return ctrl.Result{}, errors.New("api key is blank")
+	if creds.APIKey == "" {
+		return ctrl.Result{}, r.markFailed(ctx, original, &alertsChannel, nrv1.ReasonCredentialsFailed, errors.New("api key is blank"))
 	}
 
 	//initial alertsClient
-	alertsClient, errAlertsClient := r.AlertClientFunc(r.apiKey, alertsChannel.Spec.Region)
+	alertsClient, errAlertsClient := creds.AlertsClient(r.AlertClientFunc)
 
 	if errAlertsClient != nil {
 		r.Log.Error(errAlertsClient, "Failed to create AlertsClient")
-		return ctrl.Result{}, errAlertsClient
+		return ctrl.Result{}, r.markFailed(ctx, original, &alertsChannel, nrv1.ReasonCredentialsFailed, errAlertsClient)
 	}
-	r.Alerts = alertsClient
 
-	deleteFinalizer := "alertschannels.finalizers.nr.k8s.newrelic.com"
+	paused, err := isPaused(ctx, r.Client, &alertsChannel)
+	if err != nil {
+		r.Log.Error(err, "Failed to check if reconciliation is paused")
+		return ctrl.Result{}, err
+	}
+
+	if paused {
+		r.Log.Info("Reconciliation is paused, not writing to New Relic", "channelId", alertsChannel.Status.ChannelID)
+		if markPaused(r.Recorder, &alertsChannel, !reflect.DeepEqual(&alertsChannel.Spec, alertsChannel.Status.AppliedSpec) || linksFailed(&alertsChannel)) {
+			if _, err := r.checkForDrift(ctx, alertsClient, creds.AccountID, &alertsChannel); err != nil {
+				return retryLater(ctrl.Result{}, r.markFailed(ctx, original, &alertsChannel, nrv1.ReasonReadFailed, err))
+			}
+		}
+
+		return ctrl.Result{RequeueAfter: r.ResyncInterval}, updateResource(ctx, r.Client, original, &alertsChannel)
+	}
+
+	markResumed(r.Recorder, &alertsChannel)
+	warnNoAccountID(r.Recorder, creds.AccountID, &alertsChannel)
+	alertsClient = interfaces.WithContext(alertsClient, ctx)
+	ctx, alertsClient = startDryRun(ctx, r.DryRun, r.Recorder, alertsClient, &alertsChannel)
 
 	//examine DeletionTimestamp to determine if object is under deletion
 	if alertsChannel.DeletionTimestamp.IsZero() {
@@ -102,85 +154,391 @@ func (r *AlertsChannelReconciler) Reconcile(req ctrl.Request) (ctrl.Result, erro
 			alertsChannel.Finalizers = append(alertsChannel.Finalizers, deleteFinalizer)
 		}
 	} else {
-		err := r.deleteAlertsChannel(&alertsChannel, deleteFinalizer)
+		if planDeletion(ctx, &alertsChannel, "channel", strconv.Itoa(alertsChannel.Status.ChannelID)) {
+			return ctrl.Result{}, updateResource(ctx, r.Client, original, &alertsChannel)
+		}
+
+		err := r.deleteAlertsChannel(ctx, alertsClient, creds.AccountID, &alertsChannel, deleteFinalizer)
 		if err != nil {
 			r.Log.Error(err, "error deleting channel", "name", alertsChannel.Name)
-			return ctrl.Result{}, err
+			return retryLater(ctrl.Result{}, r.markFailed(ctx, original, &alertsChannel, nrv1.ReasonDeleteFailed, err))
 		}
 		return ctrl.Result{}, nil
 	}
 
-	if reflect.DeepEqual(&alertsChannel.Spec, alertsChannel.Status.AppliedSpec) {
-		return ctrl.Result{}, nil
+	selectionChanged, err := r.policySelectionChanged(ctx, &alertsChannel)
+	if err != nil {
+		r.Log.Error(err, "failed to select policies", "policySelector", alertsChannel.Spec.PolicySelector)
+		return retryLater(ctrl.Result{}, r.markFailed(ctx, original, &alertsChannel, nrv1.ReasonLinkFailed, err))
+	}
+
+	if reflect.DeepEqual(&alertsChannel.Spec, alertsChannel.Status.AppliedSpec) && !linksFailed(&alertsChannel) && !selectionChanged {
+		recreate, err := r.checkForDrift(ctx, alertsClient, creds.AccountID, &alertsChannel)
+		if err != nil {
+			r.Log.Error(err, "failed to read channel from New Relic API", "channelId", alertsChannel.Status.ChannelID)
+			return retryLater(ctrl.Result{}, r.markFailed(ctx, original, &alertsChannel, nrv1.ReasonReadFailed, err))
+		}
+
+		if !recreate {
+			recreate, err = r.checkHeaderSecrets(ctx, alertsClient, creds.AccountID, &alertsChannel)
+			if err != nil {
+				r.Log.Error(err, "failed to check the header secrets of channel", "channelId", alertsChannel.Status.ChannelID)
+				return retryLater(ctrl.Result{}, r.markFailed(ctx, original, &alertsChannel, nrv1.ReasonUpdateFailed, err))
+			}
+		}
+
+		if !recreate {
+			return ctrl.Result{RequeueAfter: r.ResyncInterval}, updateResource(ctx, r.Client, original, &alertsChannel)
+		}
 	}
 
 	r.Log.Info("Reconciling", "alertsChannel", alertsChannel.Name)
 
-	r.checkForExistingAlertsChannel(&alertsChannel)
+	if err := r.checkForExistingAlertsChannel(ctx, alertsClient, creds.AccountID, &alertsChannel); err != nil {
+		r.Log.Error(err, "failed to adopt existing channel", "importId", alertsChannel.Spec.ImportID)
+		return retryLater(ctrl.Result{}, r.markFailed(ctx, original, &alertsChannel, nrv1.ReasonImportFailed, err))
+	}
+
+	var linkErr *linkError
 
 	if alertsChannel.Status.ChannelID != 0 {
-		err := r.updateAlertsChannel(&alertsChannel)
-		if err != nil {
+		err := r.updateAlertsChannel(ctx, alertsClient, creds.AccountID, &alertsChannel)
+		if err != nil && !errors.As(err, &linkErr) {
 			r.Log.Error(err, "error updating alertsChannel")
-			return ctrl.Result{}, err
+			return retryLater(ctrl.Result{}, r.markFailed(ctx, original, &alertsChannel, nrv1.ReasonUpdateFailed, err))
 		}
 	} else {
-		err := r.createAlertsChannel(&alertsChannel)
-		if err != nil {
+		err := r.createAlertsChannel(ctx, alertsClient, creds.AccountID, &alertsChannel)
+		if err != nil && !errors.As(err, &linkErr) {
 			r.Log.Error(err, "Error creating alertsChannel")
-			return ctrl.Result{}, err
+			return retryLater(ctrl.Result{}, r.markFailed(ctx, original, &alertsChannel, nrv1.ReasonCreateFailed, err))
+		}
+	}
+
+	alertsChannel.Status.AppliedSpec = &alertsChannel.Spec
+
+	// the channel itself is applied, the links that failed are retried on the next reconcile
+	if linkErr != nil {
+		r.Log.Error(linkErr, "error linking alertsChannel to policies")
+		return retryLater(ctrl.Result{}, r.markFailed(ctx, original, &alertsChannel, nrv1.ReasonLinkFailed, linkErr))
+	}
+
+	alertsChannel.Status.MarkSynced()
+
+	err = updateResource(ctx, r.Client, original, &alertsChannel)
+	if err != nil {
+		r.Log.Error(err, "Error updating channel status", "name", alertsChannel.Name, "Namespace", alertsChannel.Namespace)
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{RequeueAfter: r.ResyncInterval}, nil
+}
+
+// checkForDrift compares the channel with the channel in New Relic when resyncing is enabled.
+// Missing links to policies are added back. It returns true if the channel has to be created
+// again, because it was deleted in New Relic, or because its configuration drifted and the drift
+// policy is recreate. Channels can not be updated in New Relic, so correcting their configuration
+// replaces them with a channel with a new ID, which is only done when asked for.
+func (r *AlertsChannelReconciler) checkForDrift(ctx context.Context, alertsClient interfaces.NewRelicAlertsClient, accountID int, alertsChannel *nrv1.AlertsChannel) (bool, error) {
+	if r.ResyncInterval == 0 || alertsChannel.Status.ChannelID == 0 {
+		alertsChannel.Status.MarkSynced()
+		return false, nil
+	}
+
+	defer newrelic.FromContext(ctx).StartSegment("checkForDrift").End()
+
+	remoteChannel, err := findChannel(alertsClient, alertsChannel.Status.ChannelID)
+	if err != nil {
+		return false, err
+	}
+
+	var drift string
+	var driftedConfiguration []nrv1.FieldChange
+	var missingLinks []int
+
+	if remoteChannel == nil {
+		drift = "channel was deleted in New Relic"
+	} else {
+		APIChannel, err := r.apiChannel(alertsChannel)
+		if err != nil {
+			return false, err
+		}
+
+		driftedConfiguration, err = driftedFields(APIChannel, remoteChannel, channelSecretFields...)
+		if err != nil {
+			return false, err
+		}
+
+		fields := driftedConfiguration
+		missingLinks = diffIntSlice(alertsChannel.Status.AppliedPolicyIDs, remoteChannel.Links.PolicyIDs)
+		if len(missingLinks) > 0 {
+			fields = append(fields, nrv1.FieldChange{Path: "links.policy_ids", To: fmt.Sprintf("missing %v", missingLinks)})
+		}
+
+		if len(fields) > 0 {
+			drift = describeDrift(fields)
+			alertsChannel.Status.Changes = nrv1.DescribeChanges(fields)
+		}
+	}
+
+	driftPolicy := alertsChannel.Spec.DriftPolicy
+	replace := remoteChannel != nil && len(driftedConfiguration) > 0
+
+	if replace && driftPolicy != nrv1.DriftPolicyRecreate {
+		// the links are still corrected, they don't change the ID of the channel
+		if driftPolicy != nrv1.DriftPolicyReportOnly && !nrv1.IsConditionTrue(alertsChannel.Status.Conditions, nrv1.ConditionPaused) {
+			if err := r.addMissingLinks(alertsClient, alertsChannel, missingLinks); err != nil {
+				return false, err
+			}
+		}
+
+		driftPolicy = nrv1.DriftPolicyReportOnly
+		drift += fmt.Sprintf("; the channel is only created again with drift_policy %s", nrv1.DriftPolicyRecreate)
+	}
+
+	if !recordDrift(r.Recorder, alertsChannel, driftPolicy, drift) {
+		return false, nil
+	}
+
+	r.Log.Info("correcting drift", "channelId", alertsChannel.Status.ChannelID, "drift", drift)
+
+	if !replace && remoteChannel != nil {
+		if err := r.addMissingLinks(alertsClient, alertsChannel, missingLinks); err != nil {
+			return false, err
 		}
+
+		alertsChannel.Status.MarkSynced()
+
+		return false, nil
+	}
+
+	if remoteChannel != nil {
+		err = r.replaceChannel(alertsClient, accountID, alertsChannel, remoteChannel, fmt.Sprintf("Deleted New Relic channel %d to create it again", remoteChannel.ID))
+		if err != nil {
+			return false, err
+		}
+	}
+
+	resetChannelStatus(alertsChannel)
+
+	return true, nil
+}
+
+// checkHeaderSecrets returns true if a secret holding a header value changed since the channel
+// was created and the drift policy of the channel is recreate. Channels can not be updated in
+// New Relic, so the channel is deleted and has to be created again with the new value. With
+// other drift policies the change is reported as drift.
+func (r *AlertsChannelReconciler) checkHeaderSecrets(ctx context.Context, alertsClient interfaces.NewRelicAlertsClient, accountID int, alertsChannel *nrv1.AlertsChannel) (bool, error) {
+	if alertsChannel.Status.ChannelID == 0 || alertsChannel.Status.AppliedHeadersHash == "" {
+		return false, nil
+	}
+
+	defer newrelic.FromContext(ctx).StartSegment("checkHeaderSecrets").End()
+
+	APIChannel, err := r.apiChannel(alertsChannel)
+	if err != nil {
+		return false, err
+	}
+
+	if headersHash(APIChannel) == alertsChannel.Status.AppliedHeadersHash {
+		return false, nil
+	}
+
+	r.Log.Info("header secret changed", "channelId", alertsChannel.Status.ChannelID)
+
+	if alertsChannel.Spec.DriftPolicy != nrv1.DriftPolicyRecreate {
+		drift := fmt.Sprintf("header secrets changed; the channel is only created again with drift_policy %s", nrv1.DriftPolicyRecreate)
+		recordDrift(r.Recorder, alertsChannel, nrv1.DriftPolicyReportOnly, drift)
+
+		return false, nil
+	}
+
+	remoteChannel, err := findChannel(alertsClient, alertsChannel.Status.ChannelID)
+	if err != nil {
+		return false, err
+	}
+
+	if remoteChannel != nil {
+		err = r.replaceChannel(alertsClient, accountID, alertsChannel, remoteChannel, fmt.Sprintf("Deleted New Relic channel %d to create it again with changed header secrets", remoteChannel.ID))
+		if err != nil {
+			return false, err
+		}
+	}
+
+	resetChannelStatus(alertsChannel)
+
+	return true, nil
+}
+
+// findChannel returns the channel with the given ID in New Relic, or nil if there is none.
+func findChannel(alertsClient interfaces.NewRelicAlertsClient, channelID int) (*alerts.Channel, error) {
+	retrievedChannels, err := alertsClient.ListChannels()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, channel := range retrievedChannels {
+		if channel.ID == channelID {
+			return channel, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// addMissingLinks links the channel to the policies it was linked to and no longer is in New Relic.
+func (r *AlertsChannelReconciler) addMissingLinks(alertsClient interfaces.NewRelicAlertsClient, alertsChannel *nrv1.AlertsChannel, missingLinks []int) error {
+	for _, policyID := range missingLinks {
+		_, err := alertsClient.UpdatePolicyChannels(policyID, []int{alertsChannel.Status.ChannelID})
+		if err != nil {
+			return err
+		}
+
+		r.Recorder.Eventf(alertsChannel, v1.EventTypeNormal, eventReasonLinked, "Linked channel to policy %d", policyID)
+	}
+
+	return nil
+}
+
+// replaceChannel deletes remoteChannel, the channel in New Relic, so it is created again. All the
+// policies it is linked to are carried over to the new channel, including the links made from
+// the policies themselves, so alerts keep being routed to it.
+func (r *AlertsChannelReconciler) replaceChannel(alertsClient interfaces.NewRelicAlertsClient, accountID int, alertsChannel *nrv1.AlertsChannel, remoteChannel *alerts.Channel, message string) error {
+	err := r.Ownership.ensureOwner(alertsClient, accountID, ownedChannel, strconv.Itoa(remoteChannel.ID), alertsChannel)
+	if err != nil {
+		return err
+	}
+
+	_, err = alertsClient.DeleteChannel(remoteChannel.ID)
+	if err != nil {
+		return err
+	}
+
+	r.Recorder.Event(alertsChannel, v1.EventTypeNormal, eventReasonRemoteReplaced, message)
+	alertsChannel.Status.CarriedPolicyIDs = remoteChannel.Links.PolicyIDs
+
+	return nil
+}
+
+// resetChannelStatus forgets the channel in New Relic, so it is created again.
+func resetChannelStatus(alertsChannel *nrv1.AlertsChannel) {
+	alertsChannel.Status.ChannelID = 0
+	alertsChannel.Status.AppliedPolicyIDs = nil
+	alertsChannel.Status.AppliedHeadersHash = ""
+	alertsChannel.Status.AppliedSpec = &nrv1.AlertsChannelSpec{}
+}
+
+// apiChannel returns the channel in New Relic for the spec of alertsChannel, with its rendered name.
+func (r *AlertsChannelReconciler) apiChannel(alertsChannel *nrv1.AlertsChannel) (alerts.Channel, error) {
+	APIChannel, err := alertsChannel.Spec.APIChannel(r.Client)
+	if err != nil {
+		return alerts.Channel{}, err
+	}
+
+	APIChannel.Name = r.Naming.remoteName(alertsChannel, alertsChannel.Spec.Name)
+
+	return APIChannel, nil
+}
+
+// headersHash returns a hash of the headers of channel, or "" if it has none.
+func headersHash(channel alerts.Channel) string {
+	if len(channel.Configuration.Headers) == 0 {
+		return ""
+	}
+
+	// maps are marshalled with sorted keys, so equal headers give the same hash
+	headers, err := json.Marshal(channel.Configuration.Headers)
+	if err != nil {
+		return ""
 	}
 
-	return ctrl.Result{}, nil
+	sum := sha256.Sum256(headers)
+
+	return hex.EncodeToString(sum[:])
+}
+
+// markFailed records err in the status of the channel and returns it.
+func (r *AlertsChannelReconciler) markFailed(ctx context.Context, original, alertsChannel *nrv1.AlertsChannel, reason string, err error) error {
+	reason = failureReason(reason, err)
+	alertsChannel.Status.MarkFailed(reason, err)
+	r.Recorder.Event(alertsChannel, v1.EventTypeWarning, reason, err.Error())
+
+	if updateErr := updateResource(ctx, r.Client, original, alertsChannel); updateErr != nil {
+		r.Log.Error(updateErr, "Error updating channel status", "name", alertsChannel.Name, "Namespace", alertsChannel.Namespace)
+	}
+
+	return err
 }
 
 //SetupWithManager - Sets up Controller for AlertsChannel
 func (r *AlertsChannelReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if err := indexSecretReferences(mgr, &nrv1.AlertsChannel{}); err != nil {
+		return err
+	}
+
+	if err := indexPolicyReferences(mgr, &nrv1.AlertsChannel{}); err != nil {
+		return err
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&nrv1.AlertsChannel{}).
+		Watches(&source.Kind{Type: &v1.Secret{}}, enqueueSecretDependents(mgr.GetClient(), &nrv1.AlertsChannelList{})).
+		Watches(&source.Kind{Type: &nrv1.NewRelicAccount{}}, enqueueAccountDependents(mgr.GetClient(), &nrv1.AlertsChannelList{})).
+		Watches(&source.Kind{Type: &nrv1.AlertsPolicy{}}, enqueuePolicyDependents(mgr.GetClient(), &nrv1.AlertsChannelList{})).
+		Watches(&source.Kind{Type: &nrv1.AlertsPolicy{}}, enqueueSelectingChannels(mgr.GetClient())).
+		Watches(&source.Kind{Type: &v1.Namespace{}}, enqueueNamespaceSelectingChannels(mgr.GetClient())).
+		Watches(&source.Kind{Type: &v1.Namespace{}}, enqueueNamespaceDependents(mgr.GetClient(), &nrv1.AlertsChannelList{})).
+		WithOptions(controller.Options{MaxConcurrentReconciles: r.MaxConcurrentReconciles}).
 		Complete(r)
 }
 
-func (r *AlertsChannelReconciler) getAPIKeyOrSecret(alertschannel nrv1.AlertsChannel) (string, error) {
-	defer r.txn.StartSegment("getAPIKeyOrSecret").End()
-	if alertschannel.Spec.APIKey != "" {
-		return alertschannel.Spec.APIKey, nil
-	}
+func (r *AlertsChannelReconciler) getCredentials(ctx context.Context, alertschannel nrv1.AlertsChannel) (nrv1.ResolvedCredentials, error) {
+	defer newrelic.FromContext(ctx).StartSegment("getCredentials").End()
 
-	if alertschannel.Spec.APIKeySecret != (nrv1.NewRelicAPIKeySecret{}) {
-		var apiKeySecret v1.Secret
-
-		key := types.NamespacedName{Namespace: alertschannel.Spec.APIKeySecret.Namespace, Name: alertschannel.Spec.APIKeySecret.Name}
-
-		getErr := r.Client.Get(context.Background(), key, &apiKeySecret)
-		if getErr != nil {
-			r.Log.Error(getErr, "Failed to retrieve secret", "secret", apiKeySecret)
-			return "", getErr
-		}
+	creds, err := alertschannel.Spec.Credentials().Resolve(ctx, r.Client, alertschannel.Namespace)
+	if err != nil {
+		r.Log.Error(err, "Failed to resolve credentials")
+		return creds, err
+	}
 
-		return string(apiKeySecret.Data[alertschannel.Spec.APIKeySecret.KeyName]), nil
+	if err := ownAPIKeySecret(ctx, r.Client, &alertschannel, alertschannel.Spec.APIKeySecret); err != nil {
+		r.Log.Error(err, "Failed to add the resource to the owners of its API key secret")
 	}
 
-	return "", nil
+	return creds, nil
 }
 
-func (r *AlertsChannelReconciler) deleteAlertsChannel(alertsChannel *nrv1.AlertsChannel, deleteFinalizer string) (err error) {
-	defer r.txn.StartSegment("deleteAlertsChannel").End()
+func (r *AlertsChannelReconciler) deleteAlertsChannel(ctx context.Context, alertsClient interfaces.NewRelicAlertsClient, accountID int, alertsChannel *nrv1.AlertsChannel, deleteFinalizer string) (err error) {
+	defer newrelic.FromContext(ctx).StartSegment("deleteAlertsChannel").End()
 	r.Log.Info("Deleting AlertsChannel", "name", alertsChannel.Name, "ChannelName", alertsChannel.Spec.Name)
 
 	if alertsChannel.Status.ChannelID != 0 {
-		_, err = r.Alerts.DeleteChannel(alertsChannel.Status.ChannelID)
+		channelID := strconv.Itoa(alertsChannel.Status.ChannelID)
+
+		skip, err := r.Ownership.skipDelete(r.Recorder, alertsClient, accountID, ownedChannel, channelID, alertsChannel)
 		if err != nil {
-			r.Log.Error(err, "error deleting AlertsChannel", "name", alertsChannel.Name, "ChannelName", alertsChannel.Spec.Name)
+			return err
 		}
 
+		if !skip {
+			_, err = alertsClient.DeleteChannel(alertsChannel.Status.ChannelID)
+			if err != nil {
+				r.Log.Error(err, "error deleting AlertsChannel", "name", alertsChannel.Name, "ChannelName", alertsChannel.Spec.Name)
+				r.Recorder.Eventf(alertsChannel, v1.EventTypeWarning, nrv1.ReasonDeleteFailed, "Failed to delete New Relic channel %d: %v", alertsChannel.Status.ChannelID, err)
+			} else {
+				r.Recorder.Eventf(alertsChannel, v1.EventTypeNormal, eventReasonDeleted, "Deleted New Relic channel %d", alertsChannel.Status.ChannelID)
+
+				if err := r.Ownership.release(alertsClient, accountID, ownedChannel, channelID); err != nil {
+					r.Log.Error(err, "failed to remove ownership marker of deleted channel", "channelId", channelID)
+				}
+			}
+		}
 	}
 
 	// Now remove finalizer
 	alertsChannel.Finalizers = removeString(alertsChannel.Finalizers, deleteFinalizer)
 
-	err = r.Client.Update(r.ctx, alertsChannel)
+	err = r.Client.Update(ctx, alertsChannel)
 	if err != nil {
 		r.Log.Error(err, "tried updating condition status", "name", alertsChannel.Name, "Namespace", alertsChannel.Namespace)
 		return err
@@ -189,187 +547,348 @@ func (r *AlertsChannelReconciler) deleteAlertsChannel(alertsChannel *nrv1.Alerts
 	return nil
 }
 
-func (r *AlertsChannelReconciler) createAlertsChannel(alertsChannel *nrv1.AlertsChannel) error {
-	defer r.txn.StartSegment("createAlertsChannel").End()
+func (r *AlertsChannelReconciler) createAlertsChannel(ctx context.Context, alertsClient interfaces.NewRelicAlertsClient, accountID int, alertsChannel *nrv1.AlertsChannel) error {
+	defer newrelic.FromContext(ctx).StartSegment("createAlertsChannel").End()
 	r.Log.Info("Creating AlertsChannel", "name", alertsChannel.Name, "ChannelName", alertsChannel.Spec.Name)
-	APIChannel, err := alertsChannel.Spec.APIChannel(r.Client)
+	APIChannel, err := r.apiChannel(alertsChannel)
 	if err != nil {
 		return err
 	}
 
 	r.Log.Info("API Payload before calling NR API", "APIChannel", APIChannel)
 
-	createdChannel, err := r.Alerts.CreateChannel(APIChannel)
+	createdChannel, err := alertsClient.CreateChannel(APIChannel)
 	if err != nil {
 		r.Log.Error(err, "Error creating AlertsChannel"+alertsChannel.Name)
 		return err
 	}
 
 	alertsChannel.Status.ChannelID = createdChannel.ID
+	alertsChannel.Status.AppliedHeadersHash = headersHash(APIChannel)
+	r.Recorder.Eventf(alertsChannel, v1.EventTypeNormal, eventReasonCreated, "Created New Relic channel %d", createdChannel.ID)
+
+	if err := r.Ownership.claim(alertsClient, accountID, ownedChannel, strconv.Itoa(createdChannel.ID), alertsChannel); err != nil {
+		r.Log.Error(err, "failed to mark channel as managed by this resource", "channelId", createdChannel.ID)
+	}
 
 	// Now create the links to policies
-	allPolicyIDs, err := r.getAllPolicyIDs(&alertsChannel.Spec)
+	allPolicyIDs, err := r.getAllPolicyIDs(ctx, alertsClient, alertsChannel)
 
 	if err != nil {
 		r.Log.Error(err, "Error getting list of policyIds")
 		return err
 	}
 
+	failedLinks := &linkError{}
+	r.linkCarriedPolicies(alertsClient, alertsChannel, allPolicyIDs, failedLinks)
+
 	for _, policyID := range allPolicyIDs {
-		policyChannels, errUpdatePolicies := r.Alerts.UpdatePolicyChannels(policyID, []int{createdChannel.ID})
+		policyChannels, errUpdatePolicies := alertsClient.UpdatePolicyChannels(policyID, []int{createdChannel.ID})
 		if errUpdatePolicies != nil {
 			r.Log.Error(errUpdatePolicies, "error updating policyAlertsChannels", "policyID", policyID, "conditionID", createdChannel.ID, "policyChannels", policyChannels)
+			r.Recorder.Eventf(alertsChannel, v1.EventTypeWarning, eventReasonLinkFailed, "Failed to link channel to policy %d: %v", policyID, errUpdatePolicies)
+			failedLinks.add(policyID, errUpdatePolicies)
 		} else {
 			alertsChannel.Status.AppliedPolicyIDs = append(alertsChannel.Status.AppliedPolicyIDs, policyID)
+			r.Recorder.Eventf(alertsChannel, v1.EventTypeNormal, eventReasonLinked, "Linked channel to policy %d", policyID)
 		}
 	}
 
-	alertsChannel.Status.AppliedSpec = &alertsChannel.Spec
-	errClientUpdate := r.Client.Update(r.ctx, alertsChannel)
-
-	if errClientUpdate != nil {
-		r.Log.Error(errClientUpdate, "Error updating channel status", "name", alertsChannel.Name, "Namespace", alertsChannel.Namespace)
-		return errClientUpdate
-	}
-
-	return nil
+	return failedLinks.orNil()
 }
 
-func (r *AlertsChannelReconciler) updateAlertsChannel(alertsChannel *nrv1.AlertsChannel) error {
-	defer r.txn.StartSegment("updateAlertsChannel").End()
+// updateAlertsChannel links the channel to the policies of the spec and unlinks it from the
+// policies it is no longer meant for. Status.AppliedPolicyIDs holds the policies the channel is
+// linked to, links that failed to change are retried on the next update.
+func (r *AlertsChannelReconciler) updateAlertsChannel(ctx context.Context, alertsClient interfaces.NewRelicAlertsClient, accountID int, alertsChannel *nrv1.AlertsChannel) error {
+	defer newrelic.FromContext(ctx).StartSegment("updateAlertsChannel").End()
 	r.Log.Info("Updating AlertsChannel", "name", alertsChannel.Name, "ChannelName", alertsChannel.Spec.Name)
+	recordChanges(r.Recorder, alertsChannel, alertsChannel.Status.AppliedSpec, &alertsChannel.Spec)
 
-	//Check to see if update is needed
-	AppliedPolicyIDs, AppliedErr := r.getAllPolicyIDs(alertsChannel.Status.AppliedSpec)
-
-	if AppliedErr != nil {
-		r.Log.Error(AppliedErr, "Error getting list of AppliedPolicyIds")
-		return AppliedErr
+	if err := r.Ownership.ensureOwner(alertsClient, accountID, ownedChannel, strconv.Itoa(alertsChannel.Status.ChannelID), alertsChannel); err != nil {
+		return err
 	}
 
-	IncomingPolicyIDs, incomingErr := r.getAllPolicyIDs(&alertsChannel.Spec)
+	IncomingPolicyIDs, incomingErr := r.getAllPolicyIDs(ctx, alertsClient, alertsChannel)
 
 	if incomingErr != nil {
 		r.Log.Error(incomingErr, "Error getting list of AppliedPolicyIds")
 		return incomingErr
 	}
 
+	AppliedPolicyIDs := alertsChannel.Status.AppliedPolicyIDs
+
 	r.Log.Info("Updating list of policies attached to AlertsChannel",
 		"policyIDs", IncomingPolicyIDs,
 		"AppliedPolicyIDs", AppliedPolicyIDs,
 	)
 
-	processedPolicyIDs := make(map[int]bool)
-
-	for _, incomingPolicyID := range IncomingPolicyIDs {
-		processedPolicyIDs[incomingPolicyID] = false
+	linkedPolicyIDs := []int{}
+	failedLinks := &linkError{}
+	r.linkCarriedPolicies(alertsClient, alertsChannel, IncomingPolicyIDs, failedLinks)
+
+	for _, appliedPolicyID := range diffIntSlice(AppliedPolicyIDs, IncomingPolicyIDs) {
+		r.Log.Info("Need to delete link to", "policyId", appliedPolicyID)
+		PolicyChannels, err := alertsClient.DeletePolicyChannel(appliedPolicyID, alertsChannel.Status.ChannelID)
+		if err != nil && !customErrors.IsNotFound(err) {
+			r.Log.Error(err, "error updating policyAlertsChannels",
+				"policyID", appliedPolicyID,
+				"conditionID", alertsChannel.Status.ChannelID,
+				"PolicyChannels", PolicyChannels,
+			)
+			r.Recorder.Eventf(alertsChannel, v1.EventTypeWarning, eventReasonLinkFailed, "Failed to unlink channel from policy %d: %v", appliedPolicyID, err)
+			failedLinks.add(appliedPolicyID, err)
+			linkedPolicyIDs = append(linkedPolicyIDs, appliedPolicyID)
+		} else {
+			r.Recorder.Eventf(alertsChannel, v1.EventTypeNormal, eventReasonUnlinked, "Unlinked channel from policy %d", appliedPolicyID)
+		}
 	}
 
-	for _, appliedPolicyID := range AppliedPolicyIDs {
-		if _, ok := processedPolicyIDs[appliedPolicyID]; ok {
-			processedPolicyIDs[appliedPolicyID] = true
+	newPolicyIDs := diffIntSlice(IncomingPolicyIDs, AppliedPolicyIDs)
+
+	for _, policyID := range IncomingPolicyIDs {
+		if !containsInt(newPolicyIDs, policyID) {
+			linkedPolicyIDs = append(linkedPolicyIDs, policyID)
+			continue
+		}
+
+		r.Log.Info("need to add ", "policyID", policyID)
+
+		policyChannels, err := alertsClient.UpdatePolicyChannels(policyID, []int{alertsChannel.Status.ChannelID})
+		if err != nil {
+			r.Log.Error(err, "error updating policyAlertsChannels",
+				"policyID", policyID,
+				"conditionID", alertsChannel.Status.ChannelID,
+				"policyChannels", policyChannels,
+			)
+			r.Recorder.Eventf(alertsChannel, v1.EventTypeWarning, eventReasonLinkFailed, "Failed to link channel to policy %d: %v", policyID, err)
+			failedLinks.add(policyID, err)
 		} else {
-			r.Log.Info("Need to delete link to", "policyId", appliedPolicyID)
-			PolicyChannels, err := r.Alerts.DeletePolicyChannel(appliedPolicyID, alertsChannel.Status.ChannelID)
-			if err != nil {
-				r.Log.Error(err, "error updating policyAlertsChannels",
-					"policyID", appliedPolicyID,
-					"conditionID", alertsChannel.Status.ChannelID,
-					"PolicyChannels", PolicyChannels,
-				)
-			}
+			linkedPolicyIDs = append(linkedPolicyIDs, policyID)
+			r.Recorder.Eventf(alertsChannel, v1.EventTypeNormal, eventReasonLinked, "Linked channel to policy %d", policyID)
 		}
 	}
 
-	//Clear out AppliedPolicyIds
-	alertsChannel.Status.AppliedPolicyIDs = []int{}
+	alertsChannel.Status.AppliedPolicyIDs = linkedPolicyIDs
 
-	for policyID, processed := range processedPolicyIDs {
-		r.Log.Info("processing ", "policyID", policyID, ":processed", processed)
-		alertsChannel.Status.AppliedPolicyIDs = append(alertsChannel.Status.AppliedPolicyIDs, policyID)
+	return failedLinks.orNil()
+}
 
-		if !processed {
-			r.Log.Info("need to add ", "policyID", policyID)
+// linkCarriedPolicies links the channel to the policies the channel it replaced was linked to,
+// other than the policies of its spec in policyIDs. These links are not applied links of the
+// channel, so they are left in place when the spec changes. The links that fail are retried on
+// the next reconcile.
+func (r *AlertsChannelReconciler) linkCarriedPolicies(alertsClient interfaces.NewRelicAlertsClient, alertsChannel *nrv1.AlertsChannel, policyIDs []int, failedLinks *linkError) {
+	var remaining []int
+
+	for _, policyID := range diffIntSlice(alertsChannel.Status.CarriedPolicyIDs, policyIDs) {
+		_, err := alertsClient.UpdatePolicyChannels(policyID, []int{alertsChannel.Status.ChannelID})
+		if err != nil && !customErrors.IsNotFound(err) {
+			r.Log.Error(err, "error linking replaced channel to policy", "policyID", policyID, "channelId", alertsChannel.Status.ChannelID)
+			r.Recorder.Eventf(alertsChannel, v1.EventTypeWarning, eventReasonLinkFailed, "Failed to link channel to policy %d: %v", policyID, err)
+			failedLinks.add(policyID, err)
+			remaining = append(remaining, policyID)
+
+			continue
+		}
 
-			policyChannels, err := r.Alerts.UpdatePolicyChannels(policyID, []int{alertsChannel.Status.ChannelID})
-			if err != nil {
-				r.Log.Error(err, "error updating policyAlertsChannels",
-					"policyID", policyID,
-					"conditionID", alertsChannel.Status.ChannelID,
-					"policyChannels", policyChannels,
-				)
-				r.Log.Info("policyChannels", "", policyChannels)
-			}
+		if err == nil {
+			r.Recorder.Eventf(alertsChannel, v1.EventTypeNormal, eventReasonLinked, "Linked channel to policy %d of the channel it replaced", policyID)
 		}
 	}
 
-	// Now update the AppliedSpec and the k8s object
-	alertsChannel.Status.AppliedSpec = &alertsChannel.Spec
+	alertsChannel.Status.CarriedPolicyIDs = remaining
+}
+
+// linkError is returned when some of the links between a channel and its policies could not be
+// changed. It unwraps to the last error, so it can be retried like that error.
+type linkError struct {
+	policyIDs []int
+	err       error
+}
 
-	err := r.Client.Update(r.ctx, alertsChannel)
+func (e *linkError) add(policyID int, err error) {
+	e.policyIDs = append(e.policyIDs, policyID)
+	e.err = err
+}
+
+// orNil returns nil if no link failed, so the result can be returned as an error.
+func (e *linkError) orNil() error {
+	if len(e.policyIDs) == 0 {
+		return nil
+	}
+
+	return e
+}
+
+func (e *linkError) Error() string {
+	return fmt.Sprintf("failed to change the links to policies %v: %v", e.policyIDs, e.err)
+}
+
+func (e *linkError) Unwrap() error {
+	return e.err
+}
+
+// linksFailed returns true if the last reconcile failed to change some of the links of the channel.
+func linksFailed(alertsChannel *nrv1.AlertsChannel) bool {
+	condition := nrv1.FindCondition(alertsChannel.Status.Conditions, nrv1.ConditionError)
+
+	return condition != nil && condition.Status == metav1.ConditionTrue && condition.Reason == nrv1.ReasonLinkFailed
+}
+
+// policySelectionChanged returns true if the policy selector of the channel selects other policies
+// than when the channel was last linked, as policies were labelled, unlabelled or created.
+func (r *AlertsChannelReconciler) policySelectionChanged(ctx context.Context, alertsChannel *nrv1.AlertsChannel) (bool, error) {
+	if alertsChannel.Spec.PolicySelector == nil {
+		return false, nil
+	}
+
+	selectedIDs, err := selectedPolicyIDs(ctx, r.Client, alertsChannel)
 	if err != nil {
-		r.Log.Error(err, "Tried updating channel status", "name", alertsChannel.Name, "Namespace", alertsChannel.Namespace)
-		return err
+		return false, err
 	}
 
-	return nil
+	return !sameInts(selectedIDs, alertsChannel.Status.SelectedPolicyIDs), nil
 }
 
-func (r *AlertsChannelReconciler) checkForExistingAlertsChannel(alertsChannel *nrv1.AlertsChannel) {
-	defer r.txn.StartSegment("checkForExistingAlertsChannel").End()
+func containsInt(slice []int, i int) bool {
+	for _, item := range slice {
+		if item == i {
+			return true
+		}
+	}
+
+	return false
+}
+
+// checkForExistingAlertsChannel looks for a channel in New Relic to adopt, when the resource has
+// none yet. Channels with the same name are adopted only if their type and configuration match the
+// spec and no other resource manages them, other channels are left in place. The secret fields of
+// the configuration are not returned by New Relic and are left out of the comparison.
+func (r *AlertsChannelReconciler) checkForExistingAlertsChannel(ctx context.Context, alertsClient interfaces.NewRelicAlertsClient, accountID int, alertsChannel *nrv1.AlertsChannel) error {
+	if alertsChannel.Status.ChannelID != 0 {
+		return nil
+	}
+
+	adoption := nrv1.GetAdoptionPolicy(alertsChannel.Spec.Adoption, alertsChannel.Spec.ImportID)
+	if adoption == nrv1.AdoptionNever {
+		return nil
+	}
+
+	defer newrelic.FromContext(ctx).StartSegment("checkForExistingAlertsChannel").End()
 	r.Log.Info("Checking for existing Channels matching name: " + alertsChannel.Spec.Name)
-	retrievedChannels, err := r.Alerts.ListChannels()
+	retrievedChannels, err := alertsClient.ListChannels()
 
 	if err != nil {
 		r.Log.Error(err, "error retrieving list of Channels")
-		return
+
+		return err
 	}
 
-	// need to delete all non-matching spec channels
-	for _, channel := range retrievedChannels {
-		if channel.Name == alertsChannel.Spec.Name {
-			channelID := channel.ID
-			channel.ID = 0
-			APIChannel, err := alertsChannel.Spec.APIChannel(r.Client)
-			if err != nil {
-				r.Log.Error(err, "Error parsing Alerts Channel configuration")
+	if adoption == nrv1.AdoptionByID {
+		for _, channel := range retrievedChannels {
+			if strconv.Itoa(channel.ID) != alertsChannel.Spec.ImportID {
 				continue
 			}
 
-			if reflect.DeepEqual(&APIChannel, channel) {
-				r.Log.Info("Found matching Alerts Channel name from the New Relic API", "ID", channel.ID)
-				alertsChannel.Status.ChannelID = channelID
-
-				alertsChannel.Status.AppliedSpec = &alertsChannel.Spec
+			if err := r.Ownership.checkImportable(alertsClient, accountID, ownedChannel, alertsChannel.Spec.ImportID); err != nil {
+				return err
 			}
 
-			r.Log.Info("Found non matching channel so need to delete and create channel")
+			r.Log.Info("Importing existing Alerts Channel", "ID", channel.ID)
+			alertsChannel.Status.ChannelID = channel.ID
+			alertsChannel.Status.AppliedPolicyIDs = channel.Links.PolicyIDs
+			r.Recorder.Eventf(alertsChannel, v1.EventTypeNormal, eventReasonImported, "Imported existing New Relic channel %d", channel.ID)
 
-			_, err = r.Alerts.DeleteChannel(channelID)
-			if err != nil {
-				r.Log.Error(err, "Error deleting non-matching AlertsChannel via New Relic API")
-				continue
-			}
+			return r.Ownership.claim(alertsClient, accountID, ownedChannel, alertsChannel.Spec.ImportID, alertsChannel)
+		}
+
+		return &customErrors.Error{
+			Kind:     customErrors.ErrNotFound,
+			Resource: ownedChannel,
+			ID:       alertsChannel.Spec.ImportID,
+			Err:      errors.New("no channel with this ID"),
+		}
+	}
+
+	APIChannel, err := r.apiChannel(alertsChannel)
+	if err != nil {
+		r.Log.Error(err, "Error parsing Alerts Channel configuration")
+		return err
+	}
+
+	for _, channel := range retrievedChannels {
+		if channel.Name != APIChannel.Name {
+			continue
+		}
+
+		channelID := channel.ID
+		candidate := *channel
+		candidate.ID = 0
+		candidate.Links = APIChannel.Links
+
+		changed, err := driftedFields(APIChannel, candidate, channelSecretFields...)
+		if err != nil {
+			return err
+		}
+
+		if len(changed) > 0 {
+			r.Log.Info("Found Alerts Channel with the same name but another configuration, not adopting it", "ID", channelID)
+			continue
 		}
+
+		unowned, err := r.Ownership.isUnowned(alertsClient, accountID, ownedChannel, strconv.Itoa(channelID), alertsChannel)
+		if err != nil {
+			return err
+		}
+
+		if !unowned {
+			r.Log.Info("Skipping Alerts Channel with the same name managed by another resource", "ID", channelID)
+			continue
+		}
+
+		r.Log.Info("Found matching Alerts Channel name from the New Relic API", "ID", channelID)
+		alertsChannel.Status.ChannelID = channelID
+		alertsChannel.Status.AppliedPolicyIDs = channel.Links.PolicyIDs
+		r.Recorder.Eventf(alertsChannel, v1.EventTypeNormal, eventReasonAdopted, "Adopted existing New Relic channel %d", channelID)
+
+		return r.Ownership.claim(alertsClient, accountID, ownedChannel, strconv.Itoa(channelID), alertsChannel)
 	}
+
+	return nil
 }
 
-func (r *AlertsChannelReconciler) getAllPolicyIDs(alertsChannelSpec *nrv1.AlertsChannelSpec) (policyIDs []int, err error) {
-	defer r.txn.StartSegment("getAllPolicyIDs").End()
+// getAllPolicyIDs returns the IDs of the policies to link the channel to, from its links and its
+// policy selector. The policies selected are recorded in the status of the channel.
+func (r *AlertsChannelReconciler) getAllPolicyIDs(ctx context.Context, alertsClient interfaces.NewRelicAlertsClient, alertsChannel *nrv1.AlertsChannel) (policyIDs []int, err error) {
+	defer newrelic.FromContext(ctx).StartSegment("getAllPolicyIDs").End()
 	var retrievedPolicies []alerts.Policy
+	alertsChannelSpec := &alertsChannel.Spec
 	policyIDMap := make(map[int]bool)
 
 	for _, policyID := range alertsChannelSpec.Links.PolicyIDs {
 		policyIDMap[policyID] = true
 	}
 
+	selectedIDs, err := selectedPolicyIDs(ctx, r.Client, alertsChannel)
+	if err != nil {
+		r.Log.Error(err, "Failed to select policies", "policySelector", alertsChannelSpec.PolicySelector)
+		return
+	}
+
+	for _, policyID := range selectedIDs {
+		policyIDMap[policyID] = true
+	}
+
+	alertsChannel.Status.SelectedPolicyIDs = selectedIDs
+
 	if len(alertsChannelSpec.Links.PolicyNames) > 0 {
 		for _, policyName := range alertsChannelSpec.Links.PolicyNames {
 			alertParams := &alerts.ListPoliciesParams{
 				Name: policyName,
 			}
 
-			retrievedPolicies, err = r.Alerts.ListPolicies(alertParams)
+			retrievedPolicies, err = alertsClient.ListPolicies(alertParams)
 			if err != nil {
 				r.Log.Error(err, "Error getting list of policies")
 				return
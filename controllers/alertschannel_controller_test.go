@@ -2,13 +2,17 @@ package controllers
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
 	"github.com/newrelic/go-agent/v3/newrelic"
@@ -70,6 +74,7 @@ var _ = Describe("AlertsChannel reconciliation", func() {
 		r = &AlertsChannelReconciler{
 			Client:          k8sClient,
 			Log:             logf.Log,
+			Recorder:        record.NewFakeRecorder(100),
 			AlertClientFunc: fakeAlertFunc,
 			NewRelicAgent:   newRelicAgent,
 		}
@@ -271,6 +276,50 @@ var _ = Describe("AlertsChannel reconciliation", func() {
 					Expect(err).ToNot(HaveOccurred())
 				})
 			})
+
+			Context("and linking to a policy fails with a retryable error", func() {
+				var result ctrl.Result
+
+				BeforeEach(func() {
+					alertsClient.UpdatePolicyChannelsStub = func(policyID int, channelIDs []int) (*alerts.PolicyChannels, error) {
+						if policyID == 2 && alertsClient.UpdatePolicyChannelsCallCount() <= 4 {
+							return nil, &interfaces.RetryableError{Err: errors.New("429 response returned"), RetryAfter: time.Minute}
+						}
+
+						return &alerts.PolicyChannels{ID: policyID, ChannelIDs: channelIDs}, nil
+					}
+
+					err = k8sClient.Create(ctx, alertsChannel)
+					Expect(err).ToNot(HaveOccurred())
+
+					result, err = r.Reconcile(request)
+				})
+
+				It("requeues the channel after the wait the client asked for", func() {
+					Expect(err).ToNot(HaveOccurred())
+					Expect(result.RequeueAfter).To(Equal(time.Minute))
+
+					var endStateAlertsChannel nrv1.AlertsChannel
+					Expect(k8sClient.Get(ctx, namespacedName, &endStateAlertsChannel)).To(Succeed())
+					Expect(endStateAlertsChannel.Status.ChannelID).To(Equal(543))
+					Expect(endStateAlertsChannel.Status.AppliedPolicyIDs).ToNot(ContainElement(2))
+					Expect(nrv1.FindCondition(endStateAlertsChannel.Status.Conditions, nrv1.ConditionError).Reason).To(Equal(nrv1.ReasonLinkFailed))
+				})
+
+				It("links the policy on the next reconcile", func() {
+					_, err = r.Reconcile(request)
+					Expect(err).ToNot(HaveOccurred())
+
+					Expect(alertsClient.UpdatePolicyChannelsCallCount()).To(Equal(5))
+					policyID, _ := alertsClient.UpdatePolicyChannelsArgsForCall(4)
+					Expect(policyID).To(Equal(2))
+
+					var endStateAlertsChannel nrv1.AlertsChannel
+					Expect(k8sClient.Get(ctx, namespacedName, &endStateAlertsChannel)).To(Succeed())
+					Expect(endStateAlertsChannel.Status.AppliedPolicyIDs).To(ContainElement(2))
+					Expect(nrv1.IsConditionTrue(endStateAlertsChannel.Status.Conditions, nrv1.ConditionSynced)).To(BeTrue())
+				})
+			})
 		})
 
 		Context("and given as new alertsChannel that exists in New Relic", func() {
@@ -349,7 +398,7 @@ var _ = Describe("AlertsChannel reconciliation", func() {
 
 				})
 
-				It("Should delete and create a new AlertsChannel in New Relic", func() {
+				It("Should leave it in place and create a new AlertsChannel in New Relic", func() {
 					err := k8sClient.Create(ctx, alertsChannel)
 					Expect(err).ToNot(HaveOccurred())
 
@@ -358,7 +407,7 @@ var _ = Describe("AlertsChannel reconciliation", func() {
 					Expect(err).ToNot(HaveOccurred())
 					Expect(alertsClient.ListChannelsCallCount()).To(Equal(1))
 					Expect(alertsClient.CreateChannelCallCount()).To(Equal(1))
-					Expect(alertsClient.DeleteChannelCallCount()).To(Equal(1))
+					Expect(alertsClient.DeleteChannelCallCount()).To(Equal(0))
 				})
 
 				It("Should update the ChannelId on the kubernetes object", func() {
@@ -388,6 +437,83 @@ var _ = Describe("AlertsChannel reconciliation", func() {
 				})
 			})
 
+			Context("when the existing Channel only differs in the secrets New Relic doesn't return", func() {
+				BeforeEach(func() {
+					alertsChannel.Spec.Type = "webhook"
+					alertsChannel.Spec.Configuration = nrv1.AlertsChannelConfiguration{
+						BaseURL:      "https://example.com/alerts",
+						AuthUsername: "alerts",
+						AuthPassword: "secret",
+						Headers:      []nrv1.ChannelHeader{{Name: "Authorization", Value: "Bearer token"}},
+					}
+
+					alertsClient.ListChannelsStub = func() ([]*alerts.Channel, error) {
+						return []*alerts.Channel{
+							{
+								ID:   112233,
+								Name: "my alert channel",
+								Type: "webhook",
+								Configuration: alerts.ChannelConfiguration{
+									BaseURL:      "https://example.com/alerts",
+									AuthUsername: "alerts",
+								},
+							},
+						}, nil
+					}
+				})
+
+				It("Should adopt it instead of creating a new AlertsChannel in New Relic", func() {
+					Expect(k8sClient.Create(ctx, alertsChannel)).To(Succeed())
+
+					_, err = r.Reconcile(request)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(alertsClient.CreateChannelCallCount()).To(Equal(0))
+
+					var endStateAlertsChannel nrv1.AlertsChannel
+					Expect(k8sClient.Get(ctx, namespacedName, &endStateAlertsChannel)).To(Succeed())
+					Expect(endStateAlertsChannel.Status.ChannelID).To(Equal(112233))
+				})
+			})
+
+			Context("when the channel names are rendered with a name template", func() {
+				BeforeEach(func() {
+					r.Naming.Template, err = ParseNameTemplate("{{.Cluster}}/{{.Namespace}}/{{.Name}}")
+					Expect(err).ToNot(HaveOccurred())
+					r.Naming.ClusterName = "prod-eu"
+				})
+
+				It("creates the channel with the rendered name", func() {
+					Expect(k8sClient.Create(ctx, alertsChannel)).To(Succeed())
+
+					_, err = r.Reconcile(request)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(alertsClient.CreateChannelCallCount()).To(Equal(1))
+					Expect(alertsClient.CreateChannelArgsForCall(0).Name).To(Equal("prod-eu/default/my alert channel"))
+				})
+
+				It("only adopts a channel with the rendered name", func() {
+					alertsClient.ListChannelsStub = func() ([]*alerts.Channel, error) {
+						return []*alerts.Channel{
+							{
+								ID:            112233,
+								Name:          "prod-eu/default/my alert channel",
+								Type:          "email",
+								Configuration: alerts.ChannelConfiguration{Recipients: "me@email.com"},
+							},
+						}, nil
+					}
+					Expect(k8sClient.Create(ctx, alertsChannel)).To(Succeed())
+
+					_, err = r.Reconcile(request)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(alertsClient.CreateChannelCallCount()).To(Equal(0))
+
+					var endStateAlertsChannel nrv1.AlertsChannel
+					Expect(k8sClient.Get(ctx, namespacedName, &endStateAlertsChannel)).To(Succeed())
+					Expect(endStateAlertsChannel.Status.ChannelID).To(Equal(112233))
+				})
+			})
+
 			Context("when multiple existing Channels are returned from the alerts API", func() {
 				BeforeEach(func() {
 					alertsClient.ListChannelsStub = func() ([]*alerts.Channel, error) {
@@ -416,7 +542,7 @@ var _ = Describe("AlertsChannel reconciliation", func() {
 					}
 				})
 
-				It("Should delete both and create a new AlertsChannel in New Relic", func() {
+				It("Should leave both in place and create a new AlertsChannel in New Relic", func() {
 					err := k8sClient.Create(ctx, alertsChannel)
 					Expect(err).ToNot(HaveOccurred())
 
@@ -425,7 +551,7 @@ var _ = Describe("AlertsChannel reconciliation", func() {
 					Expect(err).ToNot(HaveOccurred())
 					Expect(alertsClient.ListChannelsCallCount()).To(Equal(1))
 					Expect(alertsClient.CreateChannelCallCount()).To(Equal(1))
-					Expect(alertsClient.DeleteChannelCallCount()).To(Equal(2))
+					Expect(alertsClient.DeleteChannelCallCount()).To(Equal(0))
 				})
 
 				It("Should update the ChannelId on the kubernetes object", func() {
@@ -501,6 +627,39 @@ var _ = Describe("AlertsChannel reconciliation", func() {
 			})
 		})
 
+		Context("and deleting that alertsChannel with a Retain deletion policy", func() {
+			BeforeEach(func() {
+				err := k8sClient.Get(ctx, namespacedName, alertsChannel)
+				Expect(err).ToNot(HaveOccurred())
+
+				alertsChannel.SetAnnotations(map[string]string{nrv1.DeletionPolicyAnnotation: string(nrv1.DeletionPolicyRetain)})
+				err = k8sClient.Update(ctx, alertsChannel)
+				Expect(err).ToNot(HaveOccurred())
+
+				err = k8sClient.Delete(ctx, alertsChannel)
+				Expect(err).ToNot(HaveOccurred())
+
+				// call reconcile
+				_, err = r.Reconcile(request)
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("Should not delete via the NR API", func() {
+				Expect(alertsClient.DeleteChannelCallCount()).To(Equal(0))
+			})
+
+			It("Should delete the k8s object", func() {
+				var endStateAlertsChannel nrv1.AlertsChannel
+				err := k8sClient.Get(ctx, namespacedName, &endStateAlertsChannel)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring(" \"myalertschannel\" not found"))
+			})
+
+			It("Should record the retained channel", func() {
+				Eventually(r.Recorder.(*record.FakeRecorder).Events).Should(Receive(Equal("Normal Orphaned Retained New Relic channel 543, it is no longer managed by the operator")))
+			})
+		})
+
 		Context("and updating that alertsChannel", func() {
 			BeforeEach(func() {
 				//Get the object again after creation
@@ -560,4 +719,277 @@ var _ = Describe("AlertsChannel reconciliation", func() {
 			})
 		})
 	})
+
+	Context("When an alertsChannel selects policies by their labels", func() {
+		var teamPolicy *nrv1.AlertsPolicy
+
+		labelledPolicy := func(name, policyID string) *nrv1.AlertsPolicy {
+			return &nrv1.AlertsPolicy{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name,
+					Namespace: "default",
+					Labels:    map[string]string{"team": "sre"},
+				},
+				Spec: nrv1.AlertsPolicySpec{Name: name},
+				Status: nrv1.AlertsPolicyStatus{
+					AppliedSpec: &nrv1.AlertsPolicySpec{},
+					PolicyID:    policyID,
+				},
+			}
+		}
+
+		BeforeEach(func() {
+			teamPolicy = labelledPolicy("team-policy", "7788")
+			err := k8sClient.Create(ctx, teamPolicy)
+			Expect(err).ToNot(HaveOccurred())
+
+			alertsChannel.Spec.Links = nrv1.ChannelLinks{}
+			alertsChannel.Spec.PolicySelector = &nrv1.AlertsPolicySelector{
+				LabelSelector: metav1.LabelSelector{MatchLabels: map[string]string{"team": "sre"}},
+			}
+			err = k8sClient.Create(ctx, alertsChannel)
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = r.Reconcile(request)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("links the channel to the selected policies", func() {
+			Expect(alertsClient.UpdatePolicyChannelsCallCount()).To(Equal(1))
+			policyID, channelIDs := alertsClient.UpdatePolicyChannelsArgsForCall(0)
+			Expect(policyID).To(Equal(7788))
+			Expect(channelIDs).To(Equal([]int{543}))
+
+			var endStateAlertsChannel nrv1.AlertsChannel
+			err := k8sClient.Get(ctx, namespacedName, &endStateAlertsChannel)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(endStateAlertsChannel.Status.SelectedPolicyIDs).To(Equal([]int{7788}))
+		})
+
+		It("links the channel to policies labelled later", func() {
+			laterPolicy := labelledPolicy("later-policy", "9900")
+			err := k8sClient.Create(ctx, laterPolicy)
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = r.Reconcile(request)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(alertsClient.UpdatePolicyChannelsCallCount()).To(Equal(2))
+			policyID, _ := alertsClient.UpdatePolicyChannelsArgsForCall(1)
+			Expect(policyID).To(Equal(9900))
+
+			err = k8sClient.Delete(ctx, laterPolicy)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("unlinks the channel from policies no longer selected", func() {
+			err := k8sClient.Get(ctx, types.NamespacedName{Namespace: "default", Name: "team-policy"}, teamPolicy)
+			Expect(err).ToNot(HaveOccurred())
+			teamPolicy.Labels = map[string]string{"team": "platform"}
+			err = k8sClient.Update(ctx, teamPolicy)
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = r.Reconcile(request)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(alertsClient.DeletePolicyChannelCallCount()).To(Equal(1))
+			policyID, channelID := alertsClient.DeletePolicyChannelArgsForCall(0)
+			Expect(policyID).To(Equal(7788))
+			Expect(channelID).To(Equal(543))
+
+			var endStateAlertsChannel nrv1.AlertsChannel
+			err = k8sClient.Get(ctx, namespacedName, &endStateAlertsChannel)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(endStateAlertsChannel.Status.AppliedPolicyIDs).ToNot(ContainElement(7788))
+		})
+
+		It("enqueues the channel for events of the policies it selects", func() {
+			enqueue := enqueueSelectingChannels(k8sClient).(*handler.EnqueueRequestsFromMapFunc)
+
+			Expect(enqueue.ToRequests.Map(handler.MapObject{Meta: teamPolicy})).To(ConsistOf(request))
+			Expect(enqueue.ToRequests.Map(handler.MapObject{Meta: &testPolicy})).To(BeEmpty())
+		})
+
+		AfterEach(func() {
+			err := k8sClient.Delete(ctx, alertsChannel)
+			Expect(err).ToNot(HaveOccurred())
+			_, err = r.Reconcile(request)
+			Expect(err).ToNot(HaveOccurred())
+
+			err = k8sClient.Delete(ctx, teamPolicy)
+			Expect(err).ToNot(HaveOccurred())
+		})
+	})
+
+	Context("When the configuration of an existing alertsChannel drifts in New Relic", func() {
+		BeforeEach(func() {
+			r.ResyncInterval = time.Minute
+
+			err = k8sClient.Create(ctx, alertsChannel)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		JustBeforeEach(func() {
+			_, err = r.Reconcile(request)
+			Expect(err).ToNot(HaveOccurred())
+
+			// the recipients were changed and policy 1 was unlinked in New Relic, policy 9999 was
+			// linked from the policy side
+			alertsClient.ListChannelsStub = func() ([]*alerts.Channel, error) {
+				return []*alerts.Channel{
+					{
+						ID:            543,
+						Name:          "my alert channel",
+						Type:          "email",
+						Configuration: alerts.ChannelConfiguration{Recipients: "someone-else@email.com"},
+						Links:         alerts.ChannelLinks{PolicyIDs: []int{2, 1122, 665544, 9999}},
+					},
+				}, nil
+			}
+
+			_, err = r.Reconcile(request)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("reports the drift and links the channel to the missing policy again", func() {
+			Expect(alertsClient.DeleteChannelCallCount()).To(Equal(0))
+			Expect(alertsClient.CreateChannelCallCount()).To(Equal(1))
+
+			policyID, channelIDs := alertsClient.UpdatePolicyChannelsArgsForCall(alertsClient.UpdatePolicyChannelsCallCount() - 1)
+			Expect(policyID).To(Equal(1))
+			Expect(channelIDs).To(Equal([]int{543}))
+
+			var endState nrv1.AlertsChannel
+			err = k8sClient.Get(ctx, namespacedName, &endState)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(nrv1.IsConditionTrue(endState.Status.Conditions, nrv1.ConditionDrifted)).To(BeTrue())
+		})
+
+		Context("with the recreate drift policy", func() {
+			BeforeEach(func() {
+				alertsChannel.Spec.DriftPolicy = nrv1.DriftPolicyRecreate
+				err = k8sClient.Update(ctx, alertsChannel)
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("creates the channel again and keeps the links made from the policy side", func() {
+				Expect(alertsClient.DeleteChannelCallCount()).To(Equal(1))
+				Expect(alertsClient.CreateChannelCallCount()).To(Equal(2))
+
+				var linked []int
+				for i := 0; i < alertsClient.UpdatePolicyChannelsCallCount(); i++ {
+					policyID, _ := alertsClient.UpdatePolicyChannelsArgsForCall(i)
+					linked = append(linked, policyID)
+				}
+				Expect(linked).To(ContainElement(9999))
+			})
+		})
+
+		AfterEach(func() {
+			err := k8sClient.Delete(ctx, alertsChannel)
+			Expect(err).ToNot(HaveOccurred())
+			_, err = r.Reconcile(request)
+			Expect(err).ToNot(HaveOccurred())
+		})
+	})
+
+	Context("When a header secret of an existing alertsChannel changes", func() {
+		var headerSecret *v1.Secret
+
+		BeforeEach(func() {
+			headerSecret = &v1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "webhook-token",
+					Namespace: "default",
+				},
+				Data: map[string][]byte{
+					"token": []byte("old-token"),
+				},
+			}
+			err := k8sClient.Create(ctx, headerSecret)
+			Expect(err).ToNot(HaveOccurred())
+
+			// the channel is also linked to policy 9999 from the policy side
+			alertsClient.ListChannelsStub = func() ([]*alerts.Channel, error) {
+				return []*alerts.Channel{
+					{ID: 543, Name: "my alert channel", Links: alerts.ChannelLinks{PolicyIDs: []int{1, 2, 1122, 665544, 9999}}},
+				}, nil
+			}
+
+			alertsChannel.Spec.Type = "webhook"
+			alertsChannel.Spec.Configuration.Headers = []nrv1.ChannelHeader{
+				{Name: "TOKEN", Secret: "webhook-token", Namespace: "default", KeyName: "token"},
+			}
+		})
+
+		JustBeforeEach(func() {
+			err = k8sClient.Create(ctx, alertsChannel)
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = r.Reconcile(request)
+			Expect(err).ToNot(HaveOccurred())
+
+			headerSecret.Data["token"] = []byte("new-token")
+			err = k8sClient.Update(ctx, headerSecret)
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = r.Reconcile(request)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("reports the change without creating the channel again", func() {
+			Expect(alertsClient.DeleteChannelCallCount()).To(Equal(0))
+			Expect(alertsClient.CreateChannelCallCount()).To(Equal(1))
+
+			var endState nrv1.AlertsChannel
+			err = k8sClient.Get(ctx, namespacedName, &endState)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(endState.Status.ChannelID).To(Equal(543))
+			Expect(nrv1.IsConditionTrue(endState.Status.Conditions, nrv1.ConditionDrifted)).To(BeTrue())
+		})
+
+		Context("with the recreate drift policy", func() {
+			BeforeEach(func() {
+				alertsChannel.Spec.DriftPolicy = nrv1.DriftPolicyRecreate
+			})
+
+			It("creates the channel again with the new header value", func() {
+				Expect(alertsClient.DeleteChannelCallCount()).To(Equal(1))
+				Expect(alertsClient.CreateChannelCallCount()).To(Equal(2))
+
+				channel := alertsClient.CreateChannelArgsForCall(1)
+				Expect(channel.Configuration.Headers["TOKEN"]).To(Equal("new-token"))
+			})
+
+			It("links the new channel to the policies linked from the policy side", func() {
+				var linked []int
+				for i := 0; i < alertsClient.UpdatePolicyChannelsCallCount(); i++ {
+					policyID, _ := alertsClient.UpdatePolicyChannelsArgsForCall(i)
+					linked = append(linked, policyID)
+				}
+				Expect(linked).To(ContainElement(9999))
+
+				var endState nrv1.AlertsChannel
+				err = k8sClient.Get(ctx, namespacedName, &endState)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(endState.Status.AppliedPolicyIDs).ToNot(ContainElement(9999))
+				Expect(endState.Status.CarriedPolicyIDs).To(BeEmpty())
+			})
+
+			It("does not create the channel again while the secret is unchanged", func() {
+				_, err := r.Reconcile(request)
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(alertsClient.CreateChannelCallCount()).To(Equal(2))
+			})
+		})
+
+		AfterEach(func() {
+			err := k8sClient.Delete(ctx, alertsChannel)
+			Expect(err).ToNot(HaveOccurred())
+			_, err = r.Reconcile(request)
+			Expect(err).ToNot(HaveOccurred())
+
+			err = k8sClient.Delete(ctx, headerSecret)
+			Expect(err).ToNot(HaveOccurred())
+		})
+	})
 })
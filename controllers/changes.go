@@ -0,0 +1,37 @@
+package controllers
+
+import (
+	"reflect"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	nrv1 "github.com/newrelic/newrelic-kubernetes-operator/api/v1"
+)
+
+// recordChanges records the fields of spec that differ from applied, the spec last written to
+// New Relic, in the status of obj and in an event, when obj is about to be updated in New Relic.
+// Nothing is recorded when the applied spec is not known, as after drift was corrected, which
+// records the fields that differ from the object in New Relic instead.
+func recordChanges(recorder record.EventRecorder, obj nrv1.StatusObject, applied, spec interface{}) {
+	if applied == nil || reflect.ValueOf(applied).IsNil() {
+		return
+	}
+
+	changes, err := nrv1.Diff(applied, spec)
+	if err != nil {
+		ctrl.Log.Error(err, "unable to compare the spec with the applied spec", "name", obj.GetName())
+		return
+	}
+
+	if len(changes) == 0 {
+		return
+	}
+
+	status := obj.GetResourceStatus()
+	status.Changes = nrv1.DescribeChanges(changes)
+
+	recorder.Event(obj, v1.EventTypeNormal, eventReasonSpecChanged, "Changed "+strings.Join(status.Changes, ", "))
+}
@@ -0,0 +1,196 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/newrelic/go-agent/v3/newrelic"
+	kErr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	nrv1 "github.com/newrelic/newrelic-kubernetes-operator/api/v1"
+)
+
+// channelIndexField indexes policies by the "namespace/name" of the AlertsChannels they refer to,
+// and by the IDs of the channels they are linked to, as returned by channelIDKey.
+const channelIndexField = "nr.k8s.newrelic.com/channel"
+
+// channelIDKey returns the index key of the channel with channelID in New Relic.
+func channelIDKey(channelID int) string {
+	return "id:" + strconv.Itoa(channelID)
+}
+
+// channelRefKey returns the key of the AlertsChannel ref refers to from a resource in namespace.
+func channelRefKey(namespace string, ref nrv1.AlertsChannelReference) types.NamespacedName {
+	if ref.Namespace != "" {
+		namespace = ref.Namespace
+	}
+
+	return types.NamespacedName{Namespace: namespace, Name: ref.Name}
+}
+
+// channelReferences returns the "namespace/name" keys of the AlertsChannels the policy obj refers
+// to, and the keys of the channel IDs it is linked to.
+func channelReferences(obj runtime.Object) []string {
+	policy, ok := obj.(*nrv1.AlertsPolicy)
+	if !ok {
+		return nil
+	}
+
+	var keys []string
+
+	for _, ref := range policy.Spec.ChannelRefs {
+		key := channelRefKey(policy.Namespace, ref)
+		keys = append(keys, namespacedKey(key.Namespace, key.Name))
+	}
+
+	for _, channelID := range append(append([]int{}, policy.Spec.ChannelIDs...), appliedChannelIDs(policy)...) {
+		keys = append(keys, channelIDKey(channelID))
+	}
+
+	return keys
+}
+
+// indexChannelReferences indexes the policies by the AlertsChannels they refer to.
+func indexChannelReferences(mgr ctrl.Manager) error {
+	return mgr.GetFieldIndexer().IndexField(context.Background(), &nrv1.AlertsPolicy{}, channelIndexField, channelReferences)
+}
+
+// enqueueChannelDependents returns a handler for AlertsChannel events that enqueues the policies
+// referring to the channel, or linked to its ID. Updates are mapped with both the old and the new
+// object, so the policies linked to a channel that was created again with a new ID are enqueued.
+func enqueueChannelDependents(c client.Client) handler.EventHandler {
+	return &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(func(channel handler.MapObject) []reconcile.Request {
+			requests := listDependents(c, &nrv1.AlertsPolicyList{}, channelIndexField, namespacedKey(channel.Meta.GetNamespace(), channel.Meta.GetName()))
+
+			alertsChannel, ok := channel.Object.(*nrv1.AlertsChannel)
+			if !ok || alertsChannel.Status.ChannelID == 0 {
+				return requests
+			}
+
+			for _, request := range listDependents(c, &nrv1.AlertsPolicyList{}, channelIndexField, channelIDKey(alertsChannel.Status.ChannelID)) {
+				if !containsRequest(requests, request) {
+					requests = append(requests, request)
+				}
+			}
+
+			return requests
+		}),
+	}
+}
+
+// containsRequest returns true if requests contains request.
+func containsRequest(requests []reconcile.Request, request reconcile.Request) bool {
+	for _, r := range requests {
+		if r == request {
+			return true
+		}
+	}
+
+	return false
+}
+
+// resolveChannelIDs returns the IDs of the channels to link to the policy: its channel IDs and the
+// IDs of the AlertsChannels its channel refs refer to. Channels that don't exist in New Relic yet
+// are left out, the returned message says which ones the policy waits for.
+func (r *AlertsPolicyReconciler) resolveChannelIDs(ctx context.Context, policy *nrv1.AlertsPolicy) ([]int, string, error) {
+	defer newrelic.FromContext(ctx).StartSegment("resolveChannelIDs").End()
+
+	channelIDs := make(map[int]bool)
+
+	for _, channelID := range policy.Spec.ChannelIDs {
+		channelIDs[channelID] = true
+	}
+
+	var waiting []string
+
+	for _, ref := range policy.Spec.ChannelRefs {
+		key := channelRefKey(policy.Namespace, ref)
+
+		var channel nrv1.AlertsChannel
+
+		err := r.Client.Get(ctx, key, &channel)
+		if err != nil && !kErr.IsNotFound(err) {
+			return nil, "", err
+		}
+
+		if err != nil || !channel.DeletionTimestamp.IsZero() || channel.Status.ChannelID == 0 {
+			waiting = append(waiting, key.String())
+			continue
+		}
+
+		channelIDs[channel.Status.ChannelID] = true
+	}
+
+	resolved := make([]int, 0, len(channelIDs))
+	for channelID := range channelIDs {
+		resolved = append(resolved, channelID)
+	}
+
+	sort.Ints(resolved)
+
+	if len(waiting) == 0 {
+		return resolved, "", nil
+	}
+
+	return resolved, fmt.Sprintf("waiting for AlertsChannels %s to be created in New Relic", strings.Join(waiting, ", ")), nil
+}
+
+// appliedChannelIDs returns the IDs of the channels linked to the policy. Policies applied before
+// the linked channels were recorded have linked the channel IDs of their applied spec.
+func appliedChannelIDs(policy *nrv1.AlertsPolicy) []int {
+	if policy.Status.ChannelIDs != nil || policy.Status.AppliedSpec == nil {
+		return policy.Status.ChannelIDs
+	}
+
+	return policy.Status.AppliedSpec.ChannelIDs
+}
+
+// channelsLinkingPolicy returns the IDs of the channels whose AlertsChannel links them to the
+// policy with policyID itself, by its links or its policy selector, read from the cache.
+func channelsLinkingPolicy(ctx context.Context, c client.Client, policyID int) (map[int]bool, error) {
+	var channelList nrv1.AlertsChannelList
+	if err := c.List(ctx, &channelList); err != nil {
+		return nil, err
+	}
+
+	linked := make(map[int]bool)
+
+	for _, alertsChannel := range channelList.Items {
+		if alertsChannel.Status.ChannelID != 0 && containsInt(alertsChannel.Status.AppliedPolicyIDs, policyID) {
+			linked[alertsChannel.Status.ChannelID] = true
+		}
+	}
+
+	return linked, nil
+}
+
+// sameInts returns true if the slices contain the same ints, in any order.
+func sameInts(first, second []int) bool {
+	return len(diffIntSlice(first, second)) == 0 && len(diffIntSlice(second, first)) == 0
+}
+
+// waitForChannels marks the policy as waiting for the channels in message, if it waits for any,
+// and returns the result to check the channels again with.
+func waitForChannels(policy *nrv1.AlertsPolicy, message string, result ctrl.Result) ctrl.Result {
+	if message == "" {
+		return result
+	}
+
+	policy.Status.MarkWaiting(nrv1.ReasonWaitingForChannels, message)
+
+	if result.RequeueAfter == 0 || result.RequeueAfter > policyRefRetryInterval {
+		result.RequeueAfter = policyRefRetryInterval
+	}
+
+	return result
+}
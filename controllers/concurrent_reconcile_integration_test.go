@@ -0,0 +1,276 @@
+// +build integration
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	newrelic "github.com/newrelic/go-agent/v3/newrelic"
+	"github.com/newrelic/newrelic-client-go/pkg/alerts"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	nrv1 "github.com/newrelic/newrelic-kubernetes-operator/api/v1"
+	"github.com/newrelic/newrelic-kubernetes-operator/interfaces"
+	"github.com/newrelic/newrelic-kubernetes-operator/interfaces/interfacesfakes"
+	"github.com/newrelic/newrelic-kubernetes-operator/internal/testutil"
+)
+
+// reconcileInParallel calls reconcile for every request at the same time and returns the errors.
+func reconcileInParallel(requests []ctrl.Request, reconcile func(ctrl.Request) (ctrl.Result, error)) []error {
+	errs := make([]error, len(requests))
+
+	var wg sync.WaitGroup
+	for i := range requests {
+		wg.Add(1)
+		go func(i int) {
+			defer GinkgoRecover()
+			defer wg.Done()
+
+			_, errs[i] = reconcile(requests[i])
+		}(i)
+	}
+	wg.Wait()
+
+	return errs
+}
+
+// idFromName returns the number at the end of a name like "name-12" plus 1000, the ID of the
+// object with that name in New Relic.
+func idFromName(name string) int {
+	var i int
+	fmt.Sscanf(name[strings.LastIndex(name, "-")+1:], "%d", &i)
+
+	return 1000 + i
+}
+
+var _ = Describe("concurrent reconciles", func() {
+	const count = 20
+
+	var (
+		ctx              context.Context
+		t                *testing.T
+		k8sClient        client.Client
+		specEnv          *envtest.Environment
+		mockAlertsClient *interfacesfakes.FakeNewRelicAlertsClient
+		mockClientFunc   func(string, string) (interfaces.NewRelicAlertsClient, error)
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		t = &testing.T{}
+		k8sClient, specEnv = testutil.AlertsPolicyTestEnv(t)
+		mockAlertsClient = &interfacesfakes.FakeNewRelicAlertsClient{}
+
+		// every object gets an ID derived from its name, so a reconcile writing the
+		// result of another one shows up as a mismatched ID
+		mockAlertsClient.CreateNrqlConditionStaticMutationStub = func(accountID int, policyID string, a alerts.NrqlConditionInput) (*alerts.NrqlAlertCondition, error) {
+			return &alerts.NrqlAlertCondition{ID: "id-" + a.Name}, nil
+		}
+		mockAlertsClient.SearchNrqlConditionsQueryStub = func(accountID int, searchCriteria alerts.NrqlConditionsSearchCriteria) ([]*alerts.NrqlAlertCondition, error) {
+			return []*alerts.NrqlAlertCondition{}, nil
+		}
+		mockAlertsClient.CreatePolicyMutationStub = func(accountID int, policy alerts.AlertsPolicyInput) (*alerts.AlertsPolicy, error) {
+			return &alerts.AlertsPolicy{ID: "id-" + policy.Name, Name: policy.Name}, nil
+		}
+		mockAlertsClient.QueryPolicySearchStub = func(accountID int, params alerts.AlertsPoliciesSearchCriteriaInput) ([]*alerts.AlertsPolicy, error) {
+			return []*alerts.AlertsPolicy{}, nil
+		}
+		mockAlertsClient.CreateConditionStub = func(policyID int, a alerts.Condition) (*alerts.Condition, error) {
+			a.ID = idFromName(a.Name)
+			return &a, nil
+		}
+		mockAlertsClient.ListConditionsStub = func(int) ([]*alerts.Condition, error) {
+			return []*alerts.Condition{}, nil
+		}
+		mockAlertsClient.CreateChannelStub = func(a alerts.Channel) (*alerts.Channel, error) {
+			a.ID = idFromName(a.Name)
+			return &a, nil
+		}
+		mockAlertsClient.ListChannelsStub = func() ([]*alerts.Channel, error) {
+			return []*alerts.Channel{}, nil
+		}
+
+		mockClientFunc = func(string, string) (interfaces.NewRelicAlertsClient, error) {
+			return mockAlertsClient, nil
+		}
+	})
+
+	AfterEach(func() {
+		Expect(specEnv.Stop()).To(Succeed())
+	})
+
+	It("keeps the state of each AlertsNrqlCondition separate", func() {
+		r := &AlertsNrqlConditionReconciler{
+			Client:          k8sClient,
+			Log:             logf.Log,
+			Recorder:        record.NewFakeRecorder(count * 10),
+			AlertClientFunc: mockClientFunc,
+			NewRelicAgent:   newrelic.Application{},
+		}
+
+		var requests []ctrl.Request
+		for i := 0; i < count; i++ {
+			condition := testutil.NewTestAlertsNrqlCondition(t)
+			condition.Name = fmt.Sprintf("%s-%d", condition.Name, i)
+			condition.Spec.Name = condition.Name
+			Expect(k8sClient.Create(ctx, condition)).To(Succeed())
+
+			requests = append(requests, ctrl.Request{NamespacedName: types.NamespacedName{Namespace: condition.Namespace, Name: condition.Name}})
+		}
+
+		for _, err := range reconcileInParallel(requests, r.Reconcile) {
+			Expect(err).ToNot(HaveOccurred())
+		}
+
+		Expect(mockAlertsClient.CreateNrqlConditionStaticMutationCallCount()).To(Equal(count))
+
+		for _, request := range requests {
+			var condition nrv1.AlertsNrqlCondition
+			Expect(k8sClient.Get(ctx, request.NamespacedName, &condition)).To(Succeed())
+			Expect(condition.Status.ConditionID).To(Equal("id-" + request.Name))
+			Expect(condition.Status.AppliedSpec.Name).To(Equal(request.Name))
+		}
+	})
+
+	It("keeps the state of each AlertsPolicy separate", func() {
+		r := &AlertsPolicyReconciler{
+			Client:          k8sClient,
+			Log:             logf.Log,
+			Recorder:        record.NewFakeRecorder(count * 10),
+			AlertClientFunc: mockClientFunc,
+			NewRelicAgent:   newrelic.Application{},
+		}
+
+		var requests []ctrl.Request
+		for i := 0; i < count; i++ {
+			policy := testutil.NewTestAlertsPolicy(t)
+			policy.Name = fmt.Sprintf("%s-%d", policy.Name, i)
+			policy.Spec.Name = policy.Name
+			Expect(k8sClient.Create(ctx, policy)).To(Succeed())
+
+			requests = append(requests, ctrl.Request{NamespacedName: types.NamespacedName{Namespace: policy.Namespace, Name: policy.Name}})
+		}
+
+		for _, err := range reconcileInParallel(requests, r.Reconcile) {
+			Expect(err).ToNot(HaveOccurred())
+		}
+
+		Expect(mockAlertsClient.CreatePolicyMutationCallCount()).To(Equal(count))
+
+		for _, request := range requests {
+			var policy nrv1.AlertsPolicy
+			Expect(k8sClient.Get(ctx, request.NamespacedName, &policy)).To(Succeed())
+			Expect(policy.Status.PolicyID).To(Equal("id-" + request.Name))
+			Expect(policy.Status.AppliedSpec.Name).To(Equal(request.Name))
+		}
+	})
+
+	It("keeps the state of each AlertsAPMCondition separate", func() {
+		r := &AlertsAPMConditionReconciler{
+			Client:          k8sClient,
+			Log:             logf.Log,
+			Recorder:        record.NewFakeRecorder(count * 10),
+			AlertClientFunc: mockClientFunc,
+			NewRelicAgent:   newrelic.Application{},
+		}
+
+		var requests []ctrl.Request
+		for i := 0; i < count; i++ {
+			condition := &nrv1.AlertsAPMCondition{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      fmt.Sprintf("apm-condition-%d", i),
+					Namespace: "default",
+				},
+				Status: nrv1.AlertsAPMConditionStatus{
+					AppliedSpec: &nrv1.AlertsAPMConditionSpec{},
+				},
+			}
+			condition.Spec.Name = condition.Name
+			condition.Spec.Type = "apm_app_metric"
+			condition.Spec.APIKey = "api-key"
+			condition.Spec.ExistingPolicyID = "42"
+			condition.Spec.Metric = "apdex"
+			condition.Spec.Entities = []string{"333"}
+			condition.Spec.APMTerms = []nrv1.AlertConditionTerm{
+				{Duration: "30", Operator: "above", Priority: "critical", Threshold: "0.9", TimeFunction: "all"},
+			}
+			Expect(k8sClient.Create(ctx, condition)).To(Succeed())
+
+			requests = append(requests, ctrl.Request{NamespacedName: types.NamespacedName{Namespace: condition.Namespace, Name: condition.Name}})
+		}
+
+		for _, err := range reconcileInParallel(requests, r.Reconcile) {
+			Expect(err).ToNot(HaveOccurred())
+		}
+
+		Expect(mockAlertsClient.CreateConditionCallCount()).To(Equal(count))
+
+		for _, request := range requests {
+			var condition nrv1.AlertsAPMCondition
+			Expect(k8sClient.Get(ctx, request.NamespacedName, &condition)).To(Succeed())
+			Expect(condition.Status.ConditionID).To(Equal(idFromName(request.Name)))
+			Expect(condition.Status.AppliedSpec.Name).To(Equal(request.Name))
+		}
+	})
+
+	It("keeps the state of each AlertsChannel separate", func() {
+		r := &AlertsChannelReconciler{
+			Client:          k8sClient,
+			Log:             logf.Log,
+			Recorder:        record.NewFakeRecorder(count * 10),
+			AlertClientFunc: mockClientFunc,
+			NewRelicAgent:   newrelic.Application{},
+		}
+
+		var requests []ctrl.Request
+		for i := 0; i < count; i++ {
+			channel := &nrv1.AlertsChannel{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      fmt.Sprintf("channel-%d", i),
+					Namespace: "default",
+				},
+				Spec: nrv1.AlertsChannelSpec{
+					APIKey: "api-key",
+					Region: "US",
+					Type:   "email",
+					Configuration: nrv1.AlertsChannelConfiguration{
+						Recipients: "me@email.com",
+					},
+				},
+				Status: nrv1.AlertsChannelStatus{
+					AppliedSpec: &nrv1.AlertsChannelSpec{},
+				},
+			}
+			channel.Spec.Name = channel.Name
+			Expect(k8sClient.Create(ctx, channel)).To(Succeed())
+
+			requests = append(requests, ctrl.Request{NamespacedName: types.NamespacedName{Namespace: channel.Namespace, Name: channel.Name}})
+		}
+
+		for _, err := range reconcileInParallel(requests, r.Reconcile) {
+			Expect(err).ToNot(HaveOccurred())
+		}
+
+		Expect(mockAlertsClient.CreateChannelCallCount()).To(Equal(count))
+
+		for _, request := range requests {
+			var channel nrv1.AlertsChannel
+			Expect(k8sClient.Get(ctx, request.NamespacedName, &channel)).To(Succeed())
+			Expect(channel.Status.ChannelID).To(Equal(idFromName(request.Name)))
+			Expect(channel.Status.AppliedSpec.Name).To(Equal(request.Name))
+		}
+	})
+})
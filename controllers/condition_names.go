@@ -0,0 +1,64 @@
+package controllers
+
+import (
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	nrv1 "github.com/newrelic/newrelic-kubernetes-operator/api/v1"
+)
+
+const (
+	// generatedNameSuffixLength is the length of the random suffix of the names of condition
+	// resources created before their names were derived from their policy.
+	generatedNameSuffixLength = 5
+	// generatedNameAlphabet is the alphabet of the random suffixes the API server generates names
+	// with, see k8s.io/apiserver/pkg/storage/names.
+	generatedNameAlphabet = "bcdfghjklmnpqrstvwxz2456789"
+)
+
+// newConditionName returns the name of the resource created for a condition of a policy: the name
+// of the policy followed by the key of the condition, or the name in its spec when it has no key.
+// Names already taken, by other conditions of the policy or by the conditions of other policies in
+// the namespace, get a number as a suffix.
+func newConditionName(policyName string, condition *nrv1.AlertsPolicyCondition, taken map[string]bool) string {
+	name := nrv1.ConditionResourceName(policyName, condition, 1)
+	for n := 2; taken[name]; n++ {
+		name = nrv1.ConditionResourceName(policyName, condition, n)
+	}
+
+	return name
+}
+
+// generatedConditionNamePrefix is the prefix the resources of the conditions of the policy were
+// created with, by their generateName, before the names were derived from the policy.
+func generatedConditionNamePrefix(policyName string) string {
+	return policyName + "-condition-"
+}
+
+// isGeneratedConditionName returns true if name can be the random name of a condition resource of
+// the policy created before the names were derived from the policy: the prefix followed by a suffix
+// the API server could have generated.
+func isGeneratedConditionName(policyName, name string) bool {
+	prefix := generatedConditionNamePrefix(policyName)
+	if !strings.HasPrefix(name, prefix) || len(name) != len(prefix)+generatedNameSuffixLength {
+		return false
+	}
+
+	for _, r := range name[len(prefix):] {
+		if !strings.ContainsRune(generatedNameAlphabet, r) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isGeneratedCondition returns true if condition is a resource the policy created with a random
+// name: it has such a name, the generateName it was created with is still recorded in its metadata
+// and it is controlled by the policy. Resources named the same way by hand are left alone.
+func isGeneratedCondition(policy *nrv1.AlertsPolicy, condition metav1.Object) bool {
+	return isGeneratedConditionName(policy.Name, condition.GetName()) &&
+		condition.GetGenerateName() == generatedConditionNamePrefix(policy.Name) &&
+		metav1.IsControlledBy(condition, policy)
+}
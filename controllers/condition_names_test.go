@@ -0,0 +1,72 @@
+package controllers
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	nrv1 "github.com/newrelic/newrelic-kubernetes-operator/api/v1"
+)
+
+var _ = Describe("condition names", func() {
+	var condition *nrv1.AlertsPolicyCondition
+
+	BeforeEach(func() {
+		condition = &nrv1.AlertsPolicyCondition{}
+		condition.Spec.Name = "High CPU (prod)"
+	})
+
+	It("derives the name from the policy and the key", func() {
+		condition.Key = "high-cpu"
+
+		Expect(newConditionName("my-policy", condition, map[string]bool{})).To(Equal("my-policy-high-cpu"))
+	})
+
+	It("derives the name from the name in the spec when there is no key", func() {
+		Expect(newConditionName("my-policy", condition, map[string]bool{})).To(Equal("my-policy-high-cpu-prod"))
+	})
+
+	It("numbers names that are taken", func() {
+		taken := map[string]bool{"my-policy-high-cpu-prod": true, "my-policy-high-cpu-prod-2": true}
+
+		Expect(newConditionName("my-policy", condition, taken)).To(Equal("my-policy-high-cpu-prod-3"))
+	})
+
+	It("cuts long names to valid DNS labels that stay distinct", func() {
+		policyName := strings.Repeat("a", 70)
+		first := newConditionName(policyName, &nrv1.AlertsPolicyCondition{Key: "first"}, map[string]bool{})
+		second := newConditionName(policyName, &nrv1.AlertsPolicyCondition{Key: "second"}, map[string]bool{})
+
+		Expect(validation.IsDNS1123Label(first)).To(BeEmpty())
+		Expect(first).ToNot(Equal(second))
+	})
+
+	It("recognizes the random names of conditions created before", func() {
+		Expect(isGeneratedConditionName("my-policy", "my-policy-condition-x7k2p")).To(BeTrue())
+		Expect(isGeneratedConditionName("my-policy", "my-policy-high-cpu")).To(BeFalse())
+		Expect(isGeneratedConditionName("other-policy", "my-policy-condition-x7k2p")).To(BeFalse())
+		Expect(isGeneratedConditionName("my-policy", "my-policy-condition-cpu01")).To(BeFalse())
+		Expect(isGeneratedConditionName("my-policy", "my-policy-condition-disks")).To(BeFalse())
+	})
+
+	It("only takes resources created with a random name by the policy as generated", func() {
+		policy := &nrv1.AlertsPolicy{ObjectMeta: metav1.ObjectMeta{Name: "my-policy", UID: "policy-uid"}}
+		condition := &nrv1.AlertsNrqlCondition{ObjectMeta: metav1.ObjectMeta{
+			Name:            "my-policy-condition-x7k2p",
+			GenerateName:    "my-policy-condition-",
+			OwnerReferences: []metav1.OwnerReference{{Name: "my-policy", UID: "policy-uid", Controller: &trueVar}},
+		}}
+		Expect(isGeneratedCondition(policy, condition)).To(BeTrue())
+
+		named := condition.DeepCopy()
+		named.GenerateName = ""
+		Expect(isGeneratedCondition(policy, named)).To(BeFalse())
+
+		unowned := condition.DeepCopy()
+		unowned.OwnerReferences = nil
+		Expect(isGeneratedCondition(policy, unowned)).To(BeFalse())
+	})
+})
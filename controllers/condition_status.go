@@ -0,0 +1,203 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/newrelic/go-agent/v3/newrelic"
+	v1 "k8s.io/api/core/v1"
+	kErr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	nrv1 "github.com/newrelic/newrelic-kubernetes-operator/api/v1"
+	customErrors "github.com/newrelic/newrelic-kubernetes-operator/errors"
+)
+
+// conditionError is an error writing the resource of a condition of a policy. It names the
+// resource when the condition has one, and the index of the condition in the spec otherwise.
+type conditionError struct {
+	name  string
+	index int
+	label string
+	err   error
+}
+
+func (e *conditionError) Error() string {
+	return fmt.Sprintf("condition %s: %v", e.label, e.err)
+}
+
+func (e *conditionError) Unwrap() error {
+	return e.err
+}
+
+// newConditionError returns err as an error writing the resource of condition, the condition at
+// index in the spec. It returns nil if err is nil.
+func newConditionError(condition *nrv1.AlertsPolicyCondition, index int, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	label := condition.Name
+	switch {
+	case label != "":
+	case condition.Key != "":
+		label = condition.Key
+	default:
+		label = strconv.Quote(condition.Spec.Name)
+	}
+
+	return &conditionError{name: condition.Name, index: index, label: label, err: err}
+}
+
+// conditionErrors returns the errors writing condition resources in err, which is a single error
+// or the errors collected for the conditions of a policy.
+func conditionErrors(err error) []*conditionError {
+	if err == nil {
+		return nil
+	}
+
+	candidates := []error{err}
+
+	var collected *customErrors.ErrorCollector
+	if errors.As(err, &collected) {
+		candidates = *collected
+	}
+
+	var found []*conditionError
+
+	for _, candidate := range candidates {
+		var conditionErr *conditionError
+		if errors.As(candidate, &conditionErr) {
+			found = append(found, conditionErr)
+		}
+	}
+
+	return found
+}
+
+// rollUpConditions records the state of the resource of each condition in the spec of the policy,
+// read from the status of the resource, or from the error in writeErr writing it. Conditions
+// without a resource name are matched to the applied conditions, as their names are lost when the
+// spec is applied again without them.
+func (r *AlertsPolicyReconciler) rollUpConditions(ctx context.Context, policy *nrv1.AlertsPolicy, writeErr error) {
+	defer newrelic.FromContext(ctx).StartSegment("rollUpConditions").End()
+
+	var applied []nrv1.AlertsPolicyCondition
+	if policy.Status.AppliedSpec != nil {
+		applied = policy.Status.AppliedSpec.Conditions
+	}
+
+	matches := matchAppliedConditions(policy.Spec.Conditions, applied)
+
+	failed := make(map[string]error)
+	failedAt := make(map[int]error)
+
+	for _, conditionErr := range conditionErrors(writeErr) {
+		if conditionErr.name != "" {
+			failed[conditionErr.name] = conditionErr.err
+		} else {
+			failedAt[conditionErr.index] = conditionErr.err
+		}
+	}
+
+	statuses := make([]nrv1.InlineConditionStatus, 0, len(policy.Spec.Conditions))
+
+	for i, condition := range policy.Spec.Conditions {
+		if condition.Name == "" {
+			if match, ok := matches[i]; ok {
+				condition.Name = match.Name
+				condition.Namespace = match.Namespace
+			}
+		}
+
+		status := r.conditionStatus(ctx, &condition)
+
+		err := failed[condition.Name]
+		if condition.Name == "" {
+			err = failedAt[i]
+		}
+
+		if err != nil {
+			status.State = nrv1.InlineConditionFailed
+			status.LastError = err.Error()
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	policy.Status.SetInlineConditions(statuses)
+}
+
+// conditionStatus returns the state of the resource of condition, as reported in its status.
+func (r *AlertsPolicyReconciler) conditionStatus(ctx context.Context, condition *nrv1.AlertsPolicyCondition) nrv1.InlineConditionStatus {
+	status := nrv1.InlineConditionStatus{
+		Key:   condition.Key,
+		Name:  condition.Spec.Name,
+		State: nrv1.InlineConditionMissing,
+	}
+
+	if condition.Name == "" {
+		return status
+	}
+
+	var (
+		child       nrv1.StatusObject
+		kind        string
+		conditionID func() string
+	)
+
+	switch nrv1.GetAlertsConditionType(*condition) {
+	case "AlertsAPMCondition":
+		apmCondition := &nrv1.AlertsAPMCondition{}
+		child, kind = apmCondition, "AlertsAPMCondition"
+		conditionID = func() string { return strconv.Itoa(apmCondition.Status.ConditionID) }
+	default:
+		nrqlCondition := &nrv1.AlertsNrqlCondition{}
+		child, kind = nrqlCondition, "AlertsNrqlCondition"
+		conditionID = func() string { return nrqlCondition.Status.ConditionID }
+	}
+
+	namespacedName := types.NamespacedName{Name: condition.Name, Namespace: condition.Namespace}
+
+	err := r.Client.Get(ctx, namespacedName, child)
+	if kErr.IsNotFound(err) {
+		return status
+	}
+
+	if err != nil {
+		status.State = nrv1.InlineConditionFailed
+		status.LastError = err.Error()
+
+		return status
+	}
+
+	status.ConditionRef = &v1.ObjectReference{
+		APIVersion: nrv1.GroupVersion.String(),
+		Kind:       kind,
+		Namespace:  child.GetNamespace(),
+		Name:       child.GetName(),
+		UID:        child.GetUID(),
+	}
+
+	if id := conditionID(); id != "" && id != "0" {
+		status.ConditionID = id
+	}
+
+	childStatus := child.GetResourceStatus()
+
+	switch {
+	case childStatus.ObservedGeneration < child.GetGeneration():
+		status.State = nrv1.InlineConditionPending
+	case nrv1.IsConditionTrue(childStatus.Conditions, nrv1.ConditionReady):
+		status.State = nrv1.InlineConditionReady
+	case nrv1.IsConditionTrue(childStatus.Conditions, nrv1.ConditionError):
+		status.State = nrv1.InlineConditionFailed
+		status.LastError = nrv1.FindCondition(childStatus.Conditions, nrv1.ConditionError).Message
+	default:
+		status.State = nrv1.InlineConditionPending
+	}
+
+	return status
+}
@@ -0,0 +1,56 @@
+package controllers
+
+import (
+	"context"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	nrv1 "github.com/newrelic/newrelic-kubernetes-operator/api/v1"
+)
+
+// isRetained returns true if obj is being deleted and the object in New Relic is to be left
+// in place, following the deletion policy of obj.
+func isRetained(obj nrv1.StatusObject, specPolicy nrv1.DeletionPolicy) bool {
+	if obj.GetDeletionTimestamp() == nil {
+		return false
+	}
+
+	return nrv1.GetDeletionPolicy(obj, specPolicy) == nrv1.DeletionPolicyRetain
+}
+
+// orphan removes finalizer from obj without deleting the object in New Relic, which is no longer
+// managed by the operator. The ID of the object, if it was created, is recorded in an event so it
+// can be found or imported again.
+func orphan(ctx context.Context, c client.Client, recorder record.EventRecorder, obj nrv1.StatusObject, finalizer, kind, remoteID string) error {
+	if !containsString(obj.GetFinalizers(), finalizer) {
+		return nil
+	}
+
+	if remoteID != "" && remoteID != "0" {
+		recorder.Eventf(obj, v1.EventTypeNormal, eventReasonOrphaned, "Retained New Relic %s %s, it is no longer managed by the operator", kind, remoteID)
+	}
+
+	obj.SetFinalizers(removeString(obj.GetFinalizers(), finalizer))
+
+	return c.Update(ctx, obj)
+}
+
+// markRetained sets the deletion policy of a child resource to Retain, so deleting it along with
+// its parent leaves its object in New Relic in place.
+func markRetained(ctx context.Context, c client.Writer, child nrv1.StatusObject) error {
+	annotations := child.GetAnnotations()
+	if annotations[nrv1.DeletionPolicyAnnotation] == string(nrv1.DeletionPolicyRetain) {
+		return nil
+	}
+
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+
+	annotations[nrv1.DeletionPolicyAnnotation] = string(nrv1.DeletionPolicyRetain)
+	child.SetAnnotations(annotations)
+
+	return c.Update(ctx, child)
+}
@@ -0,0 +1,54 @@
+package controllers
+
+import (
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+
+	nrv1 "github.com/newrelic/newrelic-kubernetes-operator/api/v1"
+)
+
+// driftedFields compares the object the spec would be written as with the object
+// returned by the New Relic API and returns the fields that differ.
+// Only fields present in expected are compared, as New Relic returns defaults for
+// everything the spec leaves out. Paths listed in ignored are skipped, for example
+// secrets New Relic does not return.
+func driftedFields(expected, remote interface{}, ignored ...string) ([]nrv1.FieldChange, error) {
+	return nrv1.DiffSetFields(remote, expected, ignored...)
+}
+
+// describeDrift returns the message recorded for the drifted fields.
+func describeDrift(fields []nrv1.FieldChange) string {
+	return "changed in New Relic: " + strings.Join(nrv1.ChangedPaths(fields), ", ")
+}
+
+// recordDrift records the result of comparing an object with its copy in New Relic,
+// drift describes the difference and is empty when there is none.
+// It returns true if the object has to be written again to correct the drift. Drift of an
+// object whose reconciliation is paused is only reported, whatever its drift policy.
+func recordDrift(recorder record.EventRecorder, obj nrv1.StatusObject, driftPolicy nrv1.DriftPolicy, drift string) bool {
+	status := obj.GetResourceStatus()
+
+	switch {
+	case drift == "":
+		status.MarkSynced()
+		status.MarkInSync()
+
+		return false
+	case driftPolicy == nrv1.DriftPolicyReportOnly, nrv1.IsConditionTrue(status.Conditions, nrv1.ConditionPaused):
+		if !nrv1.IsConditionTrue(status.Conditions, nrv1.ConditionDrifted) {
+			recorder.Event(obj, v1.EventTypeWarning, nrv1.ReasonDriftDetected, drift)
+			driftTotal.WithLabelValues(kindOf(obj), "reported").Inc()
+		}
+		status.MarkDrifted(drift)
+
+		return false
+	default:
+		recorder.Event(obj, v1.EventTypeNormal, nrv1.ReasonDriftCorrected, drift)
+		driftTotal.WithLabelValues(kindOf(obj), "corrected").Inc()
+		status.MarkDriftCorrected(drift)
+
+		return true
+	}
+}
@@ -0,0 +1,113 @@
+package controllers
+
+import (
+	"github.com/newrelic/newrelic-client-go/pkg/alerts"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/client-go/tools/record"
+
+	nrv1 "github.com/newrelic/newrelic-kubernetes-operator/api/v1"
+)
+
+var _ = Describe("drift detection", func() {
+	Describe("driftedFields", func() {
+		var (
+			threshold float64
+			expected  alerts.NrqlConditionInput
+			remote    alerts.NrqlAlertCondition
+		)
+
+		BeforeEach(func() {
+			threshold = 1.5
+			expected = alerts.NrqlConditionInput{}
+			expected.Name = "NRQL Condition"
+			expected.Enabled = true
+			expected.Nrql.Query = "SELECT 1 FROM MyEvents"
+			expected.Terms = []alerts.NrqlConditionTerm{
+				{Priority: alerts.NrqlConditionPriorities.Critical, Threshold: &threshold, ThresholdDuration: 60},
+			}
+
+			remote = alerts.NrqlAlertCondition{ID: "111", PolicyID: "222"}
+			remote.NrqlConditionBase = expected.NrqlConditionBase
+			remote.Type = alerts.NrqlConditionTypes.Static
+			remote.ViolationTimeLimit = alerts.NrqlConditionViolationTimeLimits.OneHour
+		})
+
+		It("ignores fields the spec leaves out", func() {
+			fields, err := driftedFields(expected, remote)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(fields).To(BeEmpty())
+		})
+
+		It("returns the paths of the changed fields", func() {
+			changedThreshold := 3.0
+			remote.Name = "renamed in the UI"
+			remote.Enabled = false
+			remote.Terms = []alerts.NrqlConditionTerm{
+				{Priority: alerts.NrqlConditionPriorities.Critical, Threshold: &changedThreshold, ThresholdDuration: 60},
+			}
+
+			fields, err := driftedFields(expected, remote)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(nrv1.ChangedPaths(fields)).To(Equal([]string{"enabled", "name", "terms[0].threshold"}))
+			Expect(fields[1]).To(Equal(nrv1.FieldChange{Path: "name", From: `"renamed in the UI"`, To: `"NRQL Condition"`}))
+		})
+
+		It("reports a changed number of list items once", func() {
+			remote.Terms = nil
+
+			fields, err := driftedFields(expected, remote)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(nrv1.ChangedPaths(fields)).To(Equal([]string{"terms"}))
+		})
+
+		It("skips ignored paths", func() {
+			remote.Nrql.Query = "SELECT 2 FROM MyEvents"
+
+			fields, err := driftedFields(expected, remote, "nrql.query")
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(fields).To(BeEmpty())
+		})
+	})
+
+	Describe("recordDrift", func() {
+		var (
+			recorder  *record.FakeRecorder
+			condition *nrv1.AlertsNrqlCondition
+		)
+
+		BeforeEach(func() {
+			recorder = record.NewFakeRecorder(10)
+			condition = &nrv1.AlertsNrqlCondition{}
+		})
+
+		It("marks the resource as in sync without drift", func() {
+			Expect(recordDrift(recorder, condition, "", "")).To(BeFalse())
+
+			Expect(nrv1.IsConditionTrue(condition.Status.Conditions, nrv1.ConditionReady)).To(BeTrue())
+			Expect(nrv1.FindCondition(condition.Status.Conditions, nrv1.ConditionDrifted).Reason).To(Equal(nrv1.ReasonInSync))
+			Expect(recorder.Events).ToNot(Receive())
+		})
+
+		It("corrects drift by default", func() {
+			Expect(recordDrift(recorder, condition, "", "changed in New Relic: name")).To(BeTrue())
+
+			Expect(nrv1.FindCondition(condition.Status.Conditions, nrv1.ConditionDrifted).Reason).To(Equal(nrv1.ReasonDriftCorrected))
+			Expect(recorder.Events).To(Receive(Equal("Normal DriftCorrected changed in New Relic: name")))
+		})
+
+		It("only reports drift with the report-only policy", func() {
+			Expect(recordDrift(recorder, condition, nrv1.DriftPolicyReportOnly, "changed in New Relic: name")).To(BeFalse())
+			Expect(recordDrift(recorder, condition, nrv1.DriftPolicyReportOnly, "changed in New Relic: name")).To(BeFalse())
+
+			Expect(nrv1.IsConditionTrue(condition.Status.Conditions, nrv1.ConditionDrifted)).To(BeTrue())
+			Expect(nrv1.IsConditionTrue(condition.Status.Conditions, nrv1.ConditionReady)).To(BeFalse())
+			Expect(recorder.Events).To(Receive(Equal("Warning DriftDetected changed in New Relic: name")))
+			Expect(recorder.Events).ToNot(Receive())
+		})
+	})
+})
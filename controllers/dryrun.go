@@ -0,0 +1,325 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	nrv1 "github.com/newrelic/newrelic-kubernetes-operator/api/v1"
+	"github.com/newrelic/newrelic-kubernetes-operator/interfaces"
+	"github.com/newrelic/newrelic-kubernetes-operator/internal/redact"
+)
+
+// inDryRun returns true if the writes to New Relic obj needs are to be planned instead of sent.
+// dryRun puts all resources in dry-run mode, as the dry-run annotation does for a single resource.
+func inDryRun(dryRun bool, obj metav1.Object) bool {
+	return dryRun || nrv1.IsDryRun(obj)
+}
+
+// plan collects the writes planned in a reconcile of a resource in dry-run mode.
+type plan struct {
+	recorder   record.EventRecorder
+	operations []nrv1.PlannedOperation
+	// recorded is the number of operations events were recorded for.
+	recorded int
+}
+
+type planKey struct{}
+
+// planFrom returns the plan of the reconcile ctx belongs to, nil if the resource is not in
+// dry-run mode.
+func planFrom(ctx context.Context) *plan {
+	p, _ := ctx.Value(planKey{}).(*plan)
+	return p
+}
+
+// startDryRun returns ctx with a plan and alertsClient with its writes added to the plan instead of
+// sent, when obj is in dry-run mode or dryRun is set. Otherwise ctx and alertsClient are returned
+// as they are. The plan is recorded in the status of obj, and in events, by updateResource.
+func startDryRun(ctx context.Context, dryRun bool, recorder record.EventRecorder, alertsClient interfaces.NewRelicAlertsClient, obj metav1.Object) (context.Context, interfaces.NewRelicAlertsClient) {
+	if !inDryRun(dryRun, obj) {
+		return ctx, alertsClient
+	}
+
+	p := &plan{recorder: recorder}
+
+	return context.WithValue(ctx, planKey{}, p), interfaces.WithDryRun(alertsClient, p.addCall)
+}
+
+// planDeletion adds the deletion of the New Relic object with the given ID to the plan, the
+// finalizer of obj, a resource in dry-run mode, is kept until the object can be deleted. A warning
+// says the deletion waits for the dry run to end.
+// It returns false if the resource is not in dry-run mode and the object is to be deleted.
+func planDeletion(ctx context.Context, obj runtime.Object, resource, id string) bool {
+	p := planFrom(ctx)
+	if p == nil {
+		return false
+	}
+
+	if id != "" && id != "0" {
+		p.add(nrv1.PlannedOperation{Operation: interfaces.OperationDelete, Resource: resource, ID: id})
+	}
+
+	p.recorder.Event(obj, v1.EventTypeWarning, eventReasonDeletionPending, "Deletion waits for the dry run to end, the resource is kept until the dry-run annotation is removed or the manager runs without --dry-run")
+
+	return true
+}
+
+func (p *plan) add(operation nrv1.PlannedOperation) {
+	p.operations = append(p.operations, operation)
+}
+
+func (p *plan) addCall(call interfaces.PlannedCall) {
+	operation := nrv1.PlannedOperation{
+		Operation: call.Operation,
+		Resource:  call.Resource,
+		ID:        call.ID,
+	}
+
+	if call.Input != nil {
+		changes, err := plannedChanges(call.Input, call.Current)
+		if err != nil {
+			changes = []string{"unable to list the changes: " + err.Error()}
+		}
+
+		operation.Changes = changes
+	}
+
+	p.add(operation)
+}
+
+// write records the plan in the status of the stored resource, original, instead of obj, which
+// has the status the writes would have resulted in. A failure recorded in the status of obj is
+// recorded as a failure to plan. An event is recorded for each planned operation when the plan
+// changed since it was stored.
+func (p *plan) write(ctx context.Context, c client.Client, original, obj nrv1.StatusObject) error {
+	planned := original.DeepCopyObject().(nrv1.StatusObject)
+	planned.SetFinalizers(obj.GetFinalizers())
+
+	status := planned.GetResourceStatus()
+
+	failed := nrv1.FindCondition(obj.GetResourceStatus().Conditions, nrv1.ConditionError)
+	if failed != nil && failed.Status == metav1.ConditionTrue {
+		status.MarkPlanFailed(failed.Reason, failed.Message)
+	} else {
+		if equality.Semantic.DeepEqual(original.GetResourceStatus().Plan, p.operations) {
+			p.recorded = len(p.operations)
+		}
+
+		for _, operation := range p.operations[p.recorded:] {
+			p.recorder.Event(obj, v1.EventTypeNormal, eventReasonPlanned, describePlannedOperation(operation))
+		}
+
+		p.recorded = len(p.operations)
+
+		status.MarkPlanned(p.operations)
+	}
+
+	status.SetObservedGeneration(planned.GetGeneration())
+
+	if contentChanged(original, planned) {
+		status.SetObservedGeneration(planned.GetGeneration() + 1)
+	} else if equality.Semantic.DeepEqual(original.GetFinalizers(), planned.GetFinalizers()) {
+		return nil
+	}
+
+	if err := c.Update(ctx, planned); err != nil {
+		return err
+	}
+
+	lastSyncs.record(planned, time.Now())
+	copyInto(original, planned)
+
+	return nil
+}
+
+// describePlannedOperation returns the message of the event recorded for operation.
+func describePlannedOperation(operation nrv1.PlannedOperation) string {
+	message := fmt.Sprintf("Planned %s of %s", strings.ToLower(operation.Operation), operation.Resource)
+	if operation.ID != "" {
+		message += " " + operation.ID
+	}
+
+	if len(operation.Changes) > 0 {
+		message += ": " + strings.Join(operation.Changes, ", ")
+	}
+
+	return message
+}
+
+// plannedChanges returns the fields of input as JSON paths with the value they would be written
+// with. When current, the object as it is in New Relic, is known only the fields that differ are
+// returned, with the value they have now. Fields left empty in input are skipped, as New Relic
+// keeps or defaults them. At most nrv1.MaxRecordedChanges are returned.
+func plannedChanges(input, current interface{}) ([]string, error) {
+	changes, err := nrv1.DiffSetFields(current, input)
+	if err != nil {
+		return nil, err
+	}
+
+	return nrv1.DescribeChanges(changes), nil
+}
+
+// childWriter returns what the resources created for a policy are written with. In dry-run mode
+// their writes are added to the plan instead of sent to the API server, so they aren't reconciled
+// either.
+func childWriter(ctx context.Context, c client.Client) client.Writer {
+	p := planFrom(ctx)
+	if p == nil {
+		return c
+	}
+
+	return &planningWriter{reader: c, plan: p}
+}
+
+// planningWriter adds the writes of resources to a plan.
+type planningWriter struct {
+	reader client.Reader
+	plan   *plan
+}
+
+func (w *planningWriter) Create(ctx context.Context, obj runtime.Object, opts ...client.CreateOption) error {
+	return w.add(interfaces.OperationCreate, obj, nil)
+}
+
+func (w *planningWriter) Update(ctx context.Context, obj runtime.Object, opts ...client.UpdateOption) error {
+	current := obj.DeepCopyObject()
+
+	key, err := client.ObjectKeyFromObject(obj)
+	if err != nil {
+		return err
+	}
+
+	if err := w.reader.Get(ctx, key, current); err != nil {
+		current = nil
+	}
+
+	return w.add(interfaces.OperationUpdate, obj, current)
+}
+
+func (w *planningWriter) Patch(ctx context.Context, obj runtime.Object, patch client.Patch, opts ...client.PatchOption) error {
+	return w.add(interfaces.OperationUpdate, obj, nil)
+}
+
+func (w *planningWriter) Delete(ctx context.Context, obj runtime.Object, opts ...client.DeleteOption) error {
+	return w.add(interfaces.OperationDelete, obj, nil)
+}
+
+func (w *planningWriter) DeleteAllOf(ctx context.Context, obj runtime.Object, opts ...client.DeleteAllOfOption) error {
+	return w.add(interfaces.OperationDelete, obj, nil)
+}
+
+// add plans the write of obj, with the changes to its spec from current when it is known.
+func (w *planningWriter) add(operation string, obj, current runtime.Object) error {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return err
+	}
+
+	name := accessor.GetName()
+	if name == "" {
+		name = accessor.GetGenerateName()
+	}
+
+	planned := nrv1.PlannedOperation{
+		Operation: operation,
+		Resource:  kindOf(obj),
+		ID:        name,
+	}
+
+	if operation != interfaces.OperationDelete {
+		var currentSpec interface{}
+		if current != nil {
+			currentSpec = specOf(current)
+		}
+
+		changes, err := plannedChanges(specOf(obj), currentSpec)
+		if err != nil {
+			return err
+		}
+
+		planned.Changes = changes
+	}
+
+	w.plan.add(planned)
+
+	return nil
+}
+
+// specOf returns the spec of obj, as it is written in JSON.
+func specOf(obj runtime.Object) interface{} {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil
+	}
+
+	return content["spec"]
+}
+
+// WithDryRunEvents returns recorder without the events that report writes to New Relic for
+// resources in dry-run mode, all of them when dryRun is set, as the writes are only planned.
+// Warnings and the events of the plan are recorded as they are. New Relic keys in the messages
+// are masked.
+func WithDryRunEvents(recorder record.EventRecorder, dryRun bool) record.EventRecorder {
+	return &dryRunRecorder{EventRecorder: redact.Recorder(recorder), dryRun: dryRun}
+}
+
+// dryRunEventReasons are the reasons of the events that report writes.
+var dryRunEventReasons = map[string]bool{
+	eventReasonCreated:        true,
+	eventReasonUpdated:        true,
+	eventReasonDeleted:        true,
+	eventReasonAdopted:        true,
+	eventReasonImported:       true,
+	eventReasonLinked:         true,
+	eventReasonUnlinked:       true,
+	eventReasonChildCreated:   true,
+	eventReasonChildUpdated:   true,
+	eventReasonChildDeleted:   true,
+	eventReasonChildRenamed:   true,
+	eventReasonMoved:          true,
+	eventReasonRemoteReplaced: true,
+	nrv1.ReasonDriftCorrected: true,
+}
+
+type dryRunRecorder struct {
+	record.EventRecorder
+	dryRun bool
+}
+
+func (r *dryRunRecorder) skip(object runtime.Object, eventtype, reason string) bool {
+	if eventtype != v1.EventTypeNormal || !dryRunEventReasons[reason] {
+		return false
+	}
+
+	accessor, err := meta.Accessor(object)
+
+	return err == nil && inDryRun(r.dryRun, accessor)
+}
+
+func (r *dryRunRecorder) Event(object runtime.Object, eventtype, reason, message string) {
+	if !r.skip(object, eventtype, reason) {
+		r.EventRecorder.Event(object, eventtype, reason, message)
+	}
+}
+
+func (r *dryRunRecorder) Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...interface{}) {
+	if !r.skip(object, eventtype, reason) {
+		r.EventRecorder.Eventf(object, eventtype, reason, messageFmt, args...)
+	}
+}
+
+func (r *dryRunRecorder) AnnotatedEventf(object runtime.Object, annotations map[string]string, eventtype, reason, messageFmt string, args ...interface{}) {
+	if !r.skip(object, eventtype, reason) {
+		r.EventRecorder.AnnotatedEventf(object, annotations, eventtype, reason, messageFmt, args...)
+	}
+}
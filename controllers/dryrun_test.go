@@ -0,0 +1,123 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/newrelic/newrelic-client-go/pkg/alerts"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	nrv1 "github.com/newrelic/newrelic-kubernetes-operator/api/v1"
+	"github.com/newrelic/newrelic-kubernetes-operator/interfaces/interfacesfakes"
+)
+
+var _ = Describe("dry run", func() {
+	Describe("plannedChanges", func() {
+		It("lists the fields that would be written", func() {
+			changes, err := plannedChanges(alerts.AlertsPolicyInput{Name: "my policy", IncidentPreference: "PER_POLICY"}, nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(changes).To(Equal([]string{`incidentPreference: "PER_POLICY"`, `name: "my policy"`}))
+		})
+
+		It("lists only the fields that differ from the object in New Relic", func() {
+			changes, err := plannedChanges(
+				alerts.AlertsPolicyUpdateInput{Name: "my policy", IncidentPreference: "PER_POLICY"},
+				alerts.AlertsPolicy{ID: "123", Name: "old policy", IncidentPreference: "PER_POLICY"},
+			)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(changes).To(Equal([]string{`name: "old policy" -> "my policy"`}))
+		})
+
+		It("limits the number of changes", func() {
+			input := map[string]int{}
+			for i := 0; i < nrv1.MaxRecordedChanges+5; i++ {
+				input[fmt.Sprintf("field%02d", i)] = i + 1
+			}
+
+			changes, err := plannedChanges(input, nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(changes).To(HaveLen(nrv1.MaxRecordedChanges + 1))
+			Expect(changes[nrv1.MaxRecordedChanges]).To(Equal("and 5 more"))
+		})
+
+		It("redacts secrets", func() {
+			changes, err := plannedChanges(alerts.Channel{Name: "my channel", Configuration: alerts.ChannelConfiguration{APIKey: "secret"}}, nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(changes).To(Equal([]string{`configuration.api_key: "<redacted>"`, `name: "my channel"`}))
+		})
+	})
+
+	Describe("planDeletion", func() {
+		var (
+			fakeRecorder *record.FakeRecorder
+			policy       *nrv1.AlertsPolicy
+		)
+
+		BeforeEach(func() {
+			fakeRecorder = record.NewFakeRecorder(10)
+			policy = &nrv1.AlertsPolicy{ObjectMeta: metav1.ObjectMeta{Name: "my-policy"}}
+		})
+
+		It("plans the deletion and warns that it waits for the dry run to end", func() {
+			ctx, _ := startDryRun(context.Background(), true, fakeRecorder, &interfacesfakes.FakeNewRelicAlertsClient{}, policy)
+
+			Expect(planDeletion(ctx, policy, "policy", "123")).To(BeTrue())
+			Expect(planFrom(ctx).operations).To(Equal([]nrv1.PlannedOperation{{Operation: "Delete", Resource: "policy", ID: "123"}}))
+			Expect(fakeRecorder.Events).To(Receive(HavePrefix("Warning DeletionPending")))
+		})
+
+		It("leaves the deletion to the caller outside of a dry run", func() {
+			ctx, _ := startDryRun(context.Background(), false, fakeRecorder, &interfacesfakes.FakeNewRelicAlertsClient{}, policy)
+
+			Expect(planDeletion(ctx, policy, "policy", "123")).To(BeFalse())
+			Expect(fakeRecorder.Events).ToNot(Receive())
+		})
+	})
+
+	Describe("WithDryRunEvents", func() {
+		var (
+			fakeRecorder *record.FakeRecorder
+			policy       *nrv1.AlertsPolicy
+		)
+
+		BeforeEach(func() {
+			fakeRecorder = record.NewFakeRecorder(10)
+			policy = &nrv1.AlertsPolicy{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "my-policy",
+					Annotations: map[string]string{nrv1.DryRunAnnotation: "true"},
+				},
+			}
+		})
+
+		It("drops the events of writes for resources in dry-run mode", func() {
+			recorder := WithDryRunEvents(fakeRecorder, false)
+			recorder.Event(policy, "Normal", eventReasonCreated, "Created New Relic policy 0")
+			recorder.Event(policy, "Normal", eventReasonPlanned, "Planned create of policy")
+			recorder.Event(policy, "Warning", nrv1.ReasonCreateFailed, "failed")
+
+			Expect(fakeRecorder.Events).To(Receive(Equal("Normal Planned Planned create of policy")))
+			Expect(fakeRecorder.Events).To(Receive(Equal("Warning CreateFailed failed")))
+			Expect(fakeRecorder.Events).ToNot(Receive())
+		})
+
+		It("records the events of other resources", func() {
+			policy.Annotations = nil
+
+			WithDryRunEvents(fakeRecorder, false).Event(policy, "Normal", eventReasonCreated, "Created New Relic policy 123")
+
+			Expect(fakeRecorder.Events).To(Receive(Equal("Normal Created Created New Relic policy 123")))
+		})
+
+		It("drops the events of writes for all resources when all are in dry-run mode", func() {
+			policy.Annotations = nil
+
+			WithDryRunEvents(fakeRecorder, true).Event(policy, "Normal", eventReasonCreated, "Created New Relic policy 123")
+
+			Expect(fakeRecorder.Events).ToNot(Receive())
+		})
+	})
+})
@@ -0,0 +1,33 @@
+package controllers
+
+// Reasons for the events recorded on resources when the New Relic API is called.
+// Failures are recorded with the reasons used on the status conditions, see
+// nrv1.ReasonCreateFailed and friends.
+const (
+	eventReasonCreated        = "Created"
+	eventReasonUpdated        = "Updated"
+	eventReasonDeleted        = "Deleted"
+	eventReasonAdopted        = "Adopted"
+	eventReasonImported       = "Imported"
+	eventReasonLinked         = "Linked"
+	eventReasonUnlinked       = "Unlinked"
+	eventReasonLinkFailed     = "LinkFailed"
+	eventReasonChildCreated   = "ConditionCreated"
+	eventReasonChildUpdated   = "ConditionUpdated"
+	eventReasonChildDeleted   = "ConditionDeleted"
+	eventReasonChildRenamed   = "ConditionRenamed"
+	eventReasonMoved          = "Moved"
+	eventReasonRemoteReplaced = "Replaced"
+	eventReasonValidated      = "Validated"
+	eventReasonOrphaned       = "Orphaned"
+	eventReasonPaused         = "Paused"
+	eventReasonResumed        = "Resumed"
+	eventReasonPlanned        = "Planned"
+	eventReasonSpecChanged    = "SpecChanged"
+	// eventReasonOwnershipUnchecked warns that the ownership markers can't be kept for a resource.
+	eventReasonOwnershipUnchecked = "OwnershipUnchecked"
+	// eventReasonDeletionPending warns that a resource in dry-run mode is kept until the dry run ends.
+	eventReasonDeletionPending = "DeletionPending"
+)
+
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
@@ -0,0 +1,212 @@
+package controllers
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	nrv1 "github.com/newrelic/newrelic-kubernetes-operator/api/v1"
+)
+
+// States a resource is counted in by newrelic_operator_resources.
+const (
+	resourceStateSynced  = "synced"
+	resourceStateDrifted = "drifted"
+	resourceStateFailed  = "failed"
+	resourceStatePending = "pending"
+)
+
+var resourceStates = []string{resourceStateSynced, resourceStateDrifted, resourceStateFailed, resourceStatePending}
+
+var driftTotal = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "newrelic_operator_drift_total",
+		Help: "Number of times a resource was found changed in New Relic, by kind and whether the drift was corrected or only reported.",
+	},
+	[]string{"kind", "action"},
+)
+
+func init() {
+	metrics.Registry.MustRegister(driftTotal)
+}
+
+// lastSyncs holds when each resource was last written to or confirmed in New Relic
+// without an error, for newrelic_operator_last_successful_sync_age_seconds.
+var lastSyncs = &syncTracker{syncs: make(map[syncKey]time.Time)}
+
+type syncKey struct {
+	kind      string
+	namespace string
+	name      string
+}
+
+type syncTracker struct {
+	mu    sync.Mutex
+	syncs map[syncKey]time.Time
+}
+
+// record stores the time of the sync if obj is synced and has no error.
+func (t *syncTracker) record(obj nrv1.StatusObject, now time.Time) {
+	conditions := obj.GetResourceStatus().Conditions
+	if !nrv1.IsConditionTrue(conditions, nrv1.ConditionSynced) || nrv1.IsConditionTrue(conditions, nrv1.ConditionError) {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.syncs[syncKey{kind: kindOf(obj), namespace: obj.GetNamespace(), name: obj.GetName()}] = now
+}
+
+// get returns when obj was last synced, or false if it wasn't since the operator started.
+func (t *syncTracker) get(key syncKey) (time.Time, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	synced, ok := t.syncs[key]
+
+	return synced, ok
+}
+
+// retain forgets the resources that are not in keys, as they were deleted.
+func (t *syncTracker) retain(kind string, keys map[syncKey]bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for key := range t.syncs {
+		if key.kind == kind && !keys[key] {
+			delete(t.syncs, key)
+		}
+	}
+}
+
+// kindOf returns the kind of obj. Objects read through the typed client don't carry
+// their kind, so it's taken from the Go type.
+func kindOf(obj runtime.Object) string {
+	t := reflect.TypeOf(obj)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	return t.Name()
+}
+
+// resourceState returns the state obj is counted in by newrelic_operator_resources.
+func resourceState(obj nrv1.StatusObject) string {
+	conditions := obj.GetResourceStatus().Conditions
+
+	switch {
+	case nrv1.IsConditionTrue(conditions, nrv1.ConditionError):
+		return resourceStateFailed
+	case nrv1.IsConditionTrue(conditions, nrv1.ConditionDrifted):
+		return resourceStateDrifted
+	case nrv1.IsConditionTrue(conditions, nrv1.ConditionSynced):
+		return resourceStateSynced
+	}
+
+	return resourceStatePending
+}
+
+// resourceCollector reports the resources of every kind by state, and how long ago each was
+// last synced. The resources are listed from the cache of the manager when the metrics are scraped.
+type resourceCollector struct {
+	reader client.Reader
+	lists  []func() runtime.Object
+	now    func() time.Time
+
+	resources *prometheus.Desc
+	syncAge   *prometheus.Desc
+}
+
+func newResourceCollector(reader client.Reader) *resourceCollector {
+	return &resourceCollector{
+		reader: reader,
+		lists: []func() runtime.Object{
+			func() runtime.Object { return &nrv1.AlertsAPMConditionList{} },
+			func() runtime.Object { return &nrv1.AlertsChannelList{} },
+			func() runtime.Object { return &nrv1.AlertsNrqlConditionList{} },
+			func() runtime.Object { return &nrv1.AlertsPolicyList{} },
+			func() runtime.Object { return &nrv1.ApmAlertConditionList{} },
+			func() runtime.Object { return &nrv1.NewRelicAccountList{} },
+			func() runtime.Object { return &nrv1.NrqlAlertConditionList{} },
+			func() runtime.Object { return &nrv1.PolicyList{} },
+		},
+		now: time.Now,
+		resources: prometheus.NewDesc(
+			"newrelic_operator_resources",
+			"Number of resources by kind and sync state.",
+			[]string{"kind", "state"}, nil,
+		),
+		syncAge: prometheus.NewDesc(
+			"newrelic_operator_last_successful_sync_age_seconds",
+			"Seconds since a resource was last synced with New Relic without an error. Resources not synced since the operator started are left out.",
+			[]string{"kind", "namespace", "name"}, nil,
+		),
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (c *resourceCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.resources
+	ch <- c.syncAge
+}
+
+// Collect implements prometheus.Collector.
+func (c *resourceCollector) Collect(ch chan<- prometheus.Metric) {
+	now := c.now()
+
+	for _, newList := range c.lists {
+		list := newList()
+		if err := c.reader.List(context.Background(), list); err != nil {
+			ch <- prometheus.NewInvalidMetric(c.resources, err)
+			continue
+		}
+
+		items, err := meta.ExtractList(list)
+		if err != nil {
+			ch <- prometheus.NewInvalidMetric(c.resources, err)
+			continue
+		}
+
+		kind := strings.TrimSuffix(kindOf(list), "List")
+		counts := make(map[string]int)
+		keys := make(map[syncKey]bool)
+
+		for _, item := range items {
+			obj, ok := item.(nrv1.StatusObject)
+			if !ok {
+				continue
+			}
+
+			counts[resourceState(obj)]++
+
+			key := syncKey{kind: kind, namespace: obj.GetNamespace(), name: obj.GetName()}
+			keys[key] = true
+
+			if synced, ok := lastSyncs.get(key); ok {
+				ch <- prometheus.MustNewConstMetric(c.syncAge, prometheus.GaugeValue, now.Sub(synced).Seconds(), key.kind, key.namespace, key.name)
+			}
+		}
+
+		for _, state := range resourceStates {
+			ch <- prometheus.MustNewConstMetric(c.resources, prometheus.GaugeValue, float64(counts[state]), kind, state)
+		}
+
+		lastSyncs.retain(kind, keys)
+	}
+}
+
+// RegisterResourceMetrics adds the metrics about the resources managed by the operator to the
+// metrics endpoint of mgr.
+func RegisterResourceMetrics(mgr ctrl.Manager) error {
+	return metrics.Registry.Register(newResourceCollector(mgr.GetClient()))
+}
@@ -0,0 +1,106 @@
+package controllers
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	nrv1 "github.com/newrelic/newrelic-kubernetes-operator/api/v1"
+)
+
+var _ = Describe("resource metrics", func() {
+	var (
+		now       time.Time
+		collector *resourceCollector
+	)
+
+	policy := func(name string, mark func(*nrv1.ResourceStatus)) *nrv1.AlertsPolicy {
+		p := &nrv1.AlertsPolicy{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "metrics"}}
+		mark(&p.Status.ResourceStatus)
+
+		return p
+	}
+
+	// collect returns the values of the metrics named name, keyed by their label values
+	// joined in the order of the label names.
+	collect := func(name string) map[string]float64 {
+		registry := prometheus.NewPedanticRegistry()
+		Expect(registry.Register(collector)).To(Succeed())
+
+		families, err := registry.Gather()
+		Expect(err).ToNot(HaveOccurred())
+
+		values := make(map[string]float64)
+		for _, family := range families {
+			if family.GetName() != name {
+				continue
+			}
+
+			for _, metric := range family.GetMetric() {
+				var labels []string
+				for _, label := range metric.GetLabel() {
+					labels = append(labels, label.GetValue())
+				}
+
+				values[strings.Join(labels, "/")] = metric.GetGauge().GetValue()
+			}
+		}
+
+		return values
+	}
+
+	BeforeEach(func() {
+		now = time.Now()
+
+		s := runtime.NewScheme()
+		Expect(nrv1.AddToScheme(s)).To(Succeed())
+
+		synced := policy("synced", func(s *nrv1.ResourceStatus) { s.MarkSynced() })
+		lastSyncs.record(synced, now.Add(-time.Minute))
+
+		reader := fake.NewFakeClientWithScheme(s,
+			synced,
+			policy("drifted", func(s *nrv1.ResourceStatus) { s.MarkSynced(); s.MarkDrifted("name") }),
+			policy("failed", func(s *nrv1.ResourceStatus) {
+				s.MarkFailed(nrv1.ReasonUpdateFailed, errors.New("401 response returned"))
+			}),
+			policy("pending", func(*nrv1.ResourceStatus) {}),
+		)
+
+		collector = newResourceCollector(reader)
+		collector.now = func() time.Time { return now }
+	})
+
+	It("counts the resources of each kind by state", func() {
+		resources := collect("newrelic_operator_resources")
+
+		Expect(resources).To(HaveKeyWithValue("AlertsPolicy/synced", 1.0))
+		Expect(resources).To(HaveKeyWithValue("AlertsPolicy/drifted", 1.0))
+		Expect(resources).To(HaveKeyWithValue("AlertsPolicy/failed", 1.0))
+		Expect(resources).To(HaveKeyWithValue("AlertsPolicy/pending", 1.0))
+		Expect(resources).To(HaveKeyWithValue("AlertsChannel/synced", 0.0))
+	})
+
+	It("reports the age of the last successful sync", func() {
+		ages := collect("newrelic_operator_last_successful_sync_age_seconds")
+
+		Expect(ages).To(Equal(map[string]float64{"AlertsPolicy/synced/metrics": 60}))
+	})
+
+	It("forgets the resources that were deleted", func() {
+		deleted := policy("deleted", func(s *nrv1.ResourceStatus) { s.MarkSynced() })
+		lastSyncs.record(deleted, now)
+
+		collect("newrelic_operator_resources")
+
+		_, ok := lastSyncs.get(syncKey{kind: "AlertsPolicy", namespace: "metrics", name: "deleted"})
+		Expect(ok).To(BeFalse())
+	})
+})
@@ -0,0 +1,70 @@
+package controllers
+
+import (
+	"strings"
+	"text/template"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// DefaultNameTemplate names objects in New Relic after the name in the spec of their resource.
+const DefaultNameTemplate = "{{.Name}}"
+
+// Naming configures the names of the objects the operator manages in New Relic.
+type Naming struct {
+	// ClusterName identifies the cluster in the names, when Template uses it. It is set with the
+	// --cluster-name flag.
+	ClusterName string
+	// Template renders the names. It is set with the --name-template flag, when it is nil the name
+	// in the spec is used as it is.
+	Template *template.Template
+}
+
+// nameTemplateData is what the name template is rendered with.
+type nameTemplateData struct {
+	// Cluster is the name given with --cluster-name.
+	Cluster string
+	// Namespace is the namespace of the resource.
+	Namespace string
+	// Name is the name in the spec of the resource.
+	Name string
+}
+
+// ParseNameTemplate parses a name template and checks it renders, so names can't fail to render
+// once the operator is running.
+func ParseNameTemplate(text string) (*template.Template, error) {
+	tmpl, err := template.New("name").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tmpl.Execute(&strings.Builder{}, nameTemplateData{}); err != nil {
+		return nil, err
+	}
+
+	return tmpl, nil
+}
+
+// remoteName returns the name of the object in New Relic managed by obj, rendered from name, the
+// name in its spec. Adopting objects by name matches the rendered name as well.
+func (n Naming) remoteName(obj metav1.Object, name string) string {
+	if n.Template == nil {
+		return name
+	}
+
+	var rendered strings.Builder
+
+	err := n.Template.Execute(&rendered, nameTemplateData{
+		Cluster:   n.ClusterName,
+		Namespace: obj.GetNamespace(),
+		Name:      name,
+	})
+	if err != nil {
+		// the template was checked when it was parsed
+		ctrl.Log.Error(err, "unable to render the name of an object in New Relic", "name", name)
+		return name
+	}
+
+	return rendered.String()
+}
@@ -0,0 +1,39 @@
+package controllers
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("remote names", func() {
+	var obj *metav1.ObjectMeta
+
+	BeforeEach(func() {
+		obj = &metav1.ObjectMeta{Name: "my-policy", Namespace: "team-a"}
+	})
+
+	It("uses the name in the spec by default", func() {
+		Expect(Naming{}.remoteName(obj, "My Policy")).To(Equal("My Policy"))
+
+		tmpl, err := ParseNameTemplate(DefaultNameTemplate)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(Naming{Template: tmpl}.remoteName(obj, "My Policy")).To(Equal("My Policy"))
+	})
+
+	It("renders the name template", func() {
+		tmpl, err := ParseNameTemplate("{{.Cluster}}/{{.Namespace}}/{{.Name}}")
+		Expect(err).ToNot(HaveOccurred())
+
+		naming := Naming{ClusterName: "prod-eu", Template: tmpl}
+		Expect(naming.remoteName(obj, "My Policy")).To(Equal("prod-eu/team-a/My Policy"))
+	})
+
+	It("rejects templates that don't render", func() {
+		_, err := ParseNameTemplate("{{.Cluster")
+		Expect(err).To(HaveOccurred())
+
+		_, err = ParseNameTemplate("{{.Labels}}")
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,138 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/newrelic/go-agent/v3/newrelic"
+	"github.com/newrelic/newrelic-client-go/pkg/alerts"
+	v1 "k8s.io/api/core/v1"
+	kErr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	nrv1 "github.com/newrelic/newrelic-kubernetes-operator/api/v1"
+	"github.com/newrelic/newrelic-kubernetes-operator/interfaces"
+)
+
+// NewRelicAccountReconciler checks that the API key of a NewRelicAccount can be used with its account.
+type NewRelicAccountReconciler struct {
+	client.Client
+	stopper
+	Log                     logr.Logger
+	Recorder                record.EventRecorder
+	Scheme                  *runtime.Scheme
+	AlertClientFunc         func(string, string) (interfaces.NewRelicAlertsClient, error)
+	NewRelicAgent           newrelic.Application
+	ResyncInterval          time.Duration
+	MaxConcurrentReconciles int
+}
+
+// +kubebuilder:rbac:groups=nr.k8s.newrelic.com,resources=newrelicaccounts,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=nr.k8s.newrelic.com,resources=newrelicaccounts/status,verbs=get;update;patch
+
+// Reconcile validates the credentials of the account against the New Relic API and reports
+// the result in the status. Accounts are validated again every ResyncInterval, so a revoked
+// key shows up without the account being changed.
+func (r *NewRelicAccountReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
+	txn := r.NewRelicAgent.StartTransaction("Reconcile/Alerts/NewRelicAccount")
+	defer txn.End()
+	ctx, cancel := r.withStop(newrelic.NewContext(context.Background(), txn))
+	defer cancel()
+
+	var account nrv1.NewRelicAccount
+
+	err := r.Client.Get(ctx, req.NamespacedName, &account)
+	if err != nil {
+		if kErr.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		r.Log.Error(err, "Tried getting account", "name", req.NamespacedName.String())
+		return ctrl.Result{}, err
+	}
+
+	original := account.DeepCopy()
+
+	creds, err := account.Credentials().Resolve(ctx, r.Client, account.Namespace)
+	if err != nil {
+		return ctrl.Result{}, r.markFailed(ctx, original, &account, err)
+	}
+
+	if creds.APIKey == "" {
+		return ctrl.Result{}, r.markFailed(ctx, original, &account, errors.New("api key is blank"))
+	}
+
+	alertsClient, err := creds.AlertsClient(r.AlertClientFunc)
+	if err != nil {
+		return ctrl.Result{}, r.markFailed(ctx, original, &account, err)
+	}
+
+	alertsClient = interfaces.WithContext(alertsClient, ctx)
+
+	if err := r.validate(ctx, alertsClient, creds.AccountID); err != nil {
+		r.Log.Error(err, "failed to validate account",
+			"name", req.NamespacedName.String(),
+			"accountID", creds.AccountID,
+			"region", creds.Region,
+			"apiKey", interfaces.PartialAPIKey(creds.APIKey),
+		)
+		return ctrl.Result{}, r.markFailed(ctx, original, &account, err)
+	}
+
+	if !nrv1.IsConditionTrue(account.Status.Conditions, nrv1.ConditionReady) {
+		r.Recorder.Eventf(&account, v1.EventTypeNormal, eventReasonValidated, "Validated the API key for New Relic account %d", creds.AccountID)
+	}
+
+	account.Status.MarkSynced()
+
+	if err := updateResource(ctx, r.Client, original, &account); err != nil {
+		r.Log.Error(err, "Error updating account status", "name", account.Name, "Namespace", account.Namespace)
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{RequeueAfter: r.ResyncInterval}, nil
+}
+
+// validate makes a read-only call that needs both a working API key and access to the account.
+func (r *NewRelicAccountReconciler) validate(ctx context.Context, alertsClient interfaces.NewRelicAlertsClient, accountID int) error {
+	defer newrelic.FromContext(ctx).StartSegment("validate").End()
+
+	_, err := alertsClient.QueryPolicySearch(accountID, alerts.AlertsPoliciesSearchCriteriaInput{})
+
+	return err
+}
+
+// markFailed records err in the status of the account and returns it.
+func (r *NewRelicAccountReconciler) markFailed(ctx context.Context, original, account *nrv1.NewRelicAccount, err error) error {
+	account.Status.MarkFailed(nrv1.ReasonCredentialsFailed, err)
+	r.Recorder.Event(account, v1.EventTypeWarning, nrv1.ReasonCredentialsFailed, err.Error())
+
+	if updateErr := updateResource(ctx, r.Client, original, account); updateErr != nil {
+		r.Log.Error(updateErr, "Error updating account status", "name", account.Name, "Namespace", account.Namespace)
+	}
+
+	return err
+}
+
+// SetupWithManager sets up the controller for NewRelicAccount.
+func (r *NewRelicAccountReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if r.AlertClientFunc == nil {
+		r.AlertClientFunc = interfaces.DefaultClientPool.AlertsClient
+	}
+
+	if err := indexSecretReferences(mgr, &nrv1.NewRelicAccount{}); err != nil {
+		return err
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&nrv1.NewRelicAccount{}).
+		Watches(&source.Kind{Type: &v1.Secret{}}, enqueueSecretDependents(mgr.GetClient(), &nrv1.NewRelicAccountList{})).
+		WithOptions(controller.Options{MaxConcurrentReconciles: r.MaxConcurrentReconciles}).
+		Complete(r)
+}
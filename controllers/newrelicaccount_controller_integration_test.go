@@ -0,0 +1,178 @@
+// +build integration
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	newrelic "github.com/newrelic/go-agent/v3/newrelic"
+	"github.com/newrelic/newrelic-client-go/pkg/alerts"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	nrv1 "github.com/newrelic/newrelic-kubernetes-operator/api/v1"
+	"github.com/newrelic/newrelic-kubernetes-operator/interfaces"
+	"github.com/newrelic/newrelic-kubernetes-operator/interfaces/interfacesfakes"
+	"github.com/newrelic/newrelic-kubernetes-operator/internal/testutil"
+)
+
+var _ = Describe("NewRelicAccount reconciliation", func() {
+	var (
+		ctx              context.Context
+		t                *testing.T
+		k8sClient        client.Client
+		specEnv          *envtest.Environment
+		mockAlertsClient *interfacesfakes.FakeNewRelicAlertsClient
+		mockClientFunc   func(string, string) (interfaces.NewRelicAlertsClient, error)
+		clientAPIKey     string
+		clientRegion     string
+		recorder         *record.FakeRecorder
+		secret           *v1.Secret
+		account          *nrv1.NewRelicAccount
+		request          ctrl.Request
+		r                *NewRelicAccountReconciler
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		t = &testing.T{}
+		k8sClient, specEnv = testutil.AlertsPolicyTestEnv(t)
+		mockAlertsClient = &interfacesfakes.FakeNewRelicAlertsClient{}
+		mockAlertsClient.QueryPolicySearchStub = func(accountID int, params alerts.AlertsPoliciesSearchCriteriaInput) ([]*alerts.AlertsPolicy, error) {
+			return []*alerts.AlertsPolicy{}, nil
+		}
+		mockClientFunc = func(apiKey string, region string) (interfaces.NewRelicAlertsClient, error) {
+			clientAPIKey, clientRegion = apiKey, region
+			return mockAlertsClient, nil
+		}
+		recorder = record.NewFakeRecorder(10)
+
+		secret = &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "account-api-key",
+				Namespace: "default",
+			},
+			Data: map[string][]byte{
+				"api-key": []byte("account-api-key"),
+			},
+		}
+		Expect(k8sClient.Create(ctx, secret)).To(Succeed())
+
+		account = &nrv1.NewRelicAccount{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-account",
+				Namespace: "default",
+			},
+			Spec: nrv1.NewRelicAccountSpec{
+				APIKeySecret: nrv1.NewRelicAPIKeySecret{
+					Name:    "account-api-key",
+					KeyName: "api-key",
+				},
+				Region:    "EU",
+				AccountID: 4242,
+			},
+		}
+		Expect(k8sClient.Create(ctx, account)).To(Succeed())
+
+		request = ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "test-account"}}
+
+		r = &NewRelicAccountReconciler{
+			Client:          k8sClient,
+			Log:             logf.Log,
+			Recorder:        recorder,
+			AlertClientFunc: mockClientFunc,
+			NewRelicAgent:   newrelic.Application{},
+		}
+	})
+
+	AfterEach(func() {
+		k8sClient.Delete(ctx, account)
+		k8sClient.Delete(ctx, secret)
+
+		Expect(specEnv.Stop()).To(Succeed())
+	})
+
+	Context("when the API key works for the account", func() {
+		It("reports the account as ready", func() {
+			_, err := r.Reconcile(request)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(clientAPIKey).To(Equal("account-api-key"))
+			Expect(clientRegion).To(Equal("EU"))
+			Expect(mockAlertsClient.QueryPolicySearchCallCount()).To(Equal(1))
+			accountID, _ := mockAlertsClient.QueryPolicySearchArgsForCall(0)
+			Expect(accountID).To(Equal(4242))
+
+			var updated nrv1.NewRelicAccount
+			Expect(k8sClient.Get(ctx, request.NamespacedName, &updated)).To(Succeed())
+			Expect(nrv1.IsConditionTrue(updated.Status.Conditions, nrv1.ConditionReady)).To(BeTrue())
+			Expect(recorder.Events).To(Receive(ContainSubstring("Validated")))
+		})
+	})
+
+	Context("when the API key is rejected", func() {
+		It("reports the error", func() {
+			mockAlertsClient.QueryPolicySearchStub = func(int, alerts.AlertsPoliciesSearchCriteriaInput) ([]*alerts.AlertsPolicy, error) {
+				return nil, errors.New("401 unauthorized")
+			}
+
+			_, err := r.Reconcile(request)
+			Expect(err).To(HaveOccurred())
+
+			var updated nrv1.NewRelicAccount
+			Expect(k8sClient.Get(ctx, request.NamespacedName, &updated)).To(Succeed())
+			Expect(nrv1.IsConditionTrue(updated.Status.Conditions, nrv1.ConditionReady)).To(BeFalse())
+			errorCondition := nrv1.FindCondition(updated.Status.Conditions, nrv1.ConditionError)
+			Expect(errorCondition).ToNot(BeNil())
+			Expect(errorCondition.Reason).To(Equal(nrv1.ReasonCredentialsFailed))
+			Expect(errorCondition.Message).To(Equal("401 unauthorized"))
+		})
+	})
+
+	Context("when a condition refers to the account", func() {
+		It("creates the condition with the credentials of the account", func() {
+			mockAlertsClient.CreateNrqlConditionStaticMutationStub = func(int, string, alerts.NrqlConditionInput) (*alerts.NrqlAlertCondition, error) {
+				return &alerts.NrqlAlertCondition{ID: "111"}, nil
+			}
+			mockAlertsClient.SearchNrqlConditionsQueryStub = func(int, alerts.NrqlConditionsSearchCriteria) ([]*alerts.NrqlAlertCondition, error) {
+				return []*alerts.NrqlAlertCondition{}, nil
+			}
+
+			condition := testutil.NewTestAlertsNrqlCondition(t)
+			condition.Spec.APIKey = ""
+			condition.Spec.Region = ""
+			condition.Spec.AccountID = 0
+			condition.Spec.AccountRef = &nrv1.NewRelicAccountReference{Name: "test-account"}
+			Expect(k8sClient.Create(ctx, condition)).To(Succeed())
+			defer k8sClient.Delete(ctx, condition)
+
+			conditionReconciler := &AlertsNrqlConditionReconciler{
+				Client:          k8sClient,
+				Log:             logf.Log,
+				Recorder:        recorder,
+				AlertClientFunc: mockClientFunc,
+				NewRelicAgent:   newrelic.Application{},
+			}
+
+			_, err := conditionReconciler.Reconcile(ctrl.Request{NamespacedName: types.NamespacedName{Namespace: condition.Namespace, Name: condition.Name}})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(clientAPIKey).To(Equal("account-api-key"))
+			Expect(clientRegion).To(Equal("EU"))
+			Expect(mockAlertsClient.CreateNrqlConditionStaticMutationCallCount()).To(Equal(1))
+			accountID, _, _ := mockAlertsClient.CreateNrqlConditionStaticMutationArgsForCall(0)
+			Expect(accountID).To(Equal(4242))
+		})
+	})
+})
@@ -18,11 +18,12 @@ package controllers
 import (
 	"context"
 	"errors"
+	"fmt"
 	"reflect"
+	"strconv"
 
 	v1 "k8s.io/api/core/v1"
 	kErr "k8s.io/apimachinery/pkg/api/errors"
-	"k8s.io/apimachinery/pkg/types"
 
 	newrelic "github.com/newrelic/go-agent/v3/newrelic"
 
@@ -30,33 +31,42 @@ import (
 
 	"github.com/go-logr/logr"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	nralertsv1 "github.com/newrelic/newrelic-kubernetes-operator/api/v1"
+	customErrors "github.com/newrelic/newrelic-kubernetes-operator/errors"
 )
 
-// NrqlAlertConditionReconciler reconciles a NrqlAlertCondition object
+// NrqlAlertConditionReconciler reconciles a NrqlAlertCondition object. The legacy kinds are not checked for drift,
+// they are only written to New Relic when their spec changes and never report the Drifted condition.
 type NrqlAlertConditionReconciler struct {
 	client.Client
-	Alerts          interfaces.NewRelicAlertsClient
-	Log             logr.Logger
-	Scheme          *runtime.Scheme
-	AlertClientFunc func(string, string) (interfaces.NewRelicAlertsClient, error)
-	apiKey          string
-	NewRelicAgent   newrelic.Application
-	txn             *newrelic.Transaction
+	stopper
+	Log                     logr.Logger
+	Recorder                record.EventRecorder
+	Scheme                  *runtime.Scheme
+	AlertClientFunc         func(string, string) (interfaces.NewRelicAlertsClient, error)
+	NewRelicAgent           newrelic.Application
+	MaxConcurrentReconciles int
+	Ownership               Ownership
+	Naming                  Naming
+	DryRun                  bool
 }
 
 // +kubebuilder:rbac:groups=nr.k8s.newrelic.com,resources=nrqlalertconditions,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=nr.k8s.newrelic.com,resources=nrqlalertconditions/status,verbs=get;update;patch
 
 func (r *NrqlAlertConditionReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
-	ctx := context.Background()
 	_ = r.Log.WithValues("nrqlalertcondition", req.NamespacedName)
 
-	r.txn = r.NewRelicAgent.StartTransaction("Reconcile/NrqlCondition")
-	defer r.txn.End()
+	txn := r.NewRelicAgent.StartTransaction("Reconcile/NrqlCondition")
+	defer txn.End()
+	ctx, cancel := r.withStop(newrelic.NewContext(context.Background(), txn))
+	defer cancel()
 
 	r.Log.Info("Starting reconcile action")
 	var condition nralertsv1.NrqlAlertCondition
@@ -70,23 +80,47 @@ func (r *NrqlAlertConditionReconciler) Reconcile(req ctrl.Request) (ctrl.Result,
 		return ctrl.Result{}, err
 	}
 
-	r.apiKey, err = r.getAPIKeyOrSecret(condition)
+	original := condition.DeepCopy()
+
+	deleteFinalizer := "nrqlalertconditions.finalizers.nr.k8s.newrelic.com"
+
+	if isRetained(&condition, condition.Spec.DeletionPolicy) {
+		r.Log.Info("Retaining New Relic condition, just removing finalizer", "id", condition.Status.ConditionID)
+		return ctrl.Result{}, orphan(ctx, r.Client, r.Recorder, &condition, deleteFinalizer, "condition", fmt.Sprint(condition.Status.ConditionID))
+	}
+
+	creds, err := r.getCredentials(ctx, condition)
 	if err != nil {
-		return ctrl.Result{}, err
+		return ctrl.Result{}, r.markFailed(ctx, original, &condition, nralertsv1.ReasonCredentialsFailed, err)
 	}
 
-	if r.apiKey == "" {
-		return ctrl.Result{}, errors.New("api key is blank")
+	if creds.APIKey == "" {
+		return ctrl.Result{}, r.markFailed(ctx, original, &condition, nralertsv1.ReasonCredentialsFailed, errors.New("api key is blank"))
 	}
 	//initial alertsClient
-	alertsClient, errAlertsClient := r.AlertClientFunc(r.apiKey, condition.Spec.Region)
+	alertsClient, errAlertsClient := creds.AlertsClient(r.AlertClientFunc)
 	if errAlertsClient != nil {
 		r.Log.Error(errAlertsClient, "Error thrown")
-		return ctrl.Result{}, errAlertsClient
+		return ctrl.Result{}, r.markFailed(ctx, original, &condition, nralertsv1.ReasonCredentialsFailed, errAlertsClient)
 	}
-	r.Alerts = alertsClient
 
-	deleteFinalizer := "nrqlalertconditions.finalizers.nr.k8s.newrelic.com"
+	paused, err := isPaused(ctx, r.Client, &condition)
+	if err != nil {
+		r.Log.Error(err, "Failed to check if reconciliation is paused")
+		return ctrl.Result{}, err
+	}
+
+	if paused {
+		r.Log.Info("Reconciliation is paused, not writing to New Relic", "conditionId", condition.Status.ConditionID)
+		markPaused(r.Recorder, &condition, !reflect.DeepEqual(&condition.Spec, condition.Status.AppliedSpec))
+
+		return ctrl.Result{}, updateResource(ctx, r.Client, original, &condition)
+	}
+
+	markResumed(r.Recorder, &condition)
+	warnNoAccountID(r.Recorder, creds.AccountID, &condition)
+	alertsClient = interfaces.WithContext(alertsClient, ctx)
+	ctx, alertsClient = startDryRun(ctx, r.DryRun, r.Recorder, alertsClient, &condition)
 
 	//examine DeletionTimestamp to determine if object is under deletion
 	if condition.DeletionTimestamp.IsZero() {
@@ -94,6 +128,10 @@ func (r *NrqlAlertConditionReconciler) Reconcile(req ctrl.Request) (ctrl.Result,
 			condition.Finalizers = append(condition.Finalizers, deleteFinalizer)
 		}
 	} else {
+		if planDeletion(ctx, &condition, "nrql condition", strconv.Itoa(condition.Status.ConditionID)) {
+			return ctrl.Result{}, updateResource(ctx, r.Client, original, &condition)
+		}
+
 		// The object is being deleted
 		if containsString(condition.Finalizers, deleteFinalizer) {
 			// catch invalid state
@@ -106,23 +144,23 @@ func (r *NrqlAlertConditionReconciler) Reconcile(req ctrl.Request) (ctrl.Result,
 				}
 			} else {
 				// our finalizer is present, so lets handle any external dependency
-				if err := r.deleteNewRelicAlertCondition(condition); err != nil {
+				if err := r.deleteNewRelicAlertCondition(ctx, alertsClient, creds.AccountID, condition); err != nil {
 					// if fail to delete the external dependency here, return with error
 					// so that it can be retried
 					r.Log.Error(err, "Failed to delete API Condition",
 						"conditionId", condition.Status.ConditionID,
 						"region", condition.Spec.Region,
-						"Api Key", interfaces.PartialAPIKey(r.apiKey),
+						"Api Key", interfaces.PartialAPIKey(creds.APIKey),
 					)
-					if err.Error() == "resource not found" {
+					if customErrors.IsNotFound(err) {
 						r.Log.Info("New Relic API returned resource not found, deleting condition resource")
 					} else {
 						r.Log.Error(err, "Failed to delete API Condition",
 							"conditionId", condition.Status.ConditionID,
 							"region", condition.Spec.Region,
-							"Api Key", interfaces.PartialAPIKey(r.apiKey),
+							"Api Key", interfaces.PartialAPIKey(creds.APIKey),
 						)
-						return ctrl.Result{}, err
+						return retryLater(ctrl.Result{}, r.markFailed(ctx, original, &condition, nralertsv1.ReasonDeleteFailed, err))
 					}
 				}
 				// remove our finalizer from the list and update it.
@@ -142,61 +180,134 @@ func (r *NrqlAlertConditionReconciler) Reconcile(req ctrl.Request) (ctrl.Result,
 	}
 
 	if reflect.DeepEqual(&condition.Spec, condition.Status.AppliedSpec) {
-		return ctrl.Result{}, nil
+		condition.Status.MarkSynced()
+
+		return ctrl.Result{}, updateResource(ctx, r.Client, original, &condition)
 	}
 
 	r.Log.Info("Reconciling", "condition", condition.Name)
 
 	//check if condition has condition id
-	r.checkForExistingCondition(&condition)
+	if err := r.checkForExistingCondition(ctx, alertsClient, creds.AccountID, &condition); err != nil {
+		r.Log.Error(err, "failed to adopt existing condition", "importId", condition.Spec.ImportID)
+		return retryLater(ctrl.Result{}, r.markFailed(ctx, original, &condition, nralertsv1.ReasonImportFailed, err))
+	}
 
-	r.writeNewRelicAlertCondition(ctx, req, alertsClient, condition)
+	err = r.writeNewRelicAlertCondition(ctx, req, alertsClient, creds.AccountID, original, condition)
 
-	return ctrl.Result{}, nil
+	return retryLater(ctrl.Result{}, err)
 }
 
-func (r *NrqlAlertConditionReconciler) checkForExistingCondition(condition *nralertsv1.NrqlAlertCondition) {
-	defer r.txn.StartSegment("checkForExistingCondition").End()
-	if condition.Status.ConditionID == 0 {
-		r.Log.Info("Checking for existing condition", "conditionName", condition.Name)
-		//if no conditionId, get list of conditions and compare name
-		existingConditions, err := r.Alerts.ListNrqlConditions(condition.Spec.ExistingPolicyID)
-		if err != nil {
-			r.Log.Error(err, "failed to get list of NRQL conditions from New Relic API",
-				"conditionId", condition.Status.ConditionID,
-				"region", condition.Spec.Region,
-				"Api Key", interfaces.PartialAPIKey(r.apiKey),
-			)
-		} else {
-			for _, existingCondition := range existingConditions {
-				if existingCondition.Name == condition.Spec.Name {
-					r.Log.Info("Matched on existing condition, updating ConditionId", "conditionId", existingCondition.ID)
-					condition.Status.ConditionID = existingCondition.ID
-					break
-				}
+// markFailed records err in the status of the condition and returns it.
+func (r *NrqlAlertConditionReconciler) markFailed(ctx context.Context, original, condition *nralertsv1.NrqlAlertCondition, reason string, err error) error {
+	reason = failureReason(reason, err)
+	condition.Status.MarkFailed(reason, err)
+	r.Recorder.Event(condition, v1.EventTypeWarning, reason, err.Error())
+
+	if updateErr := updateResource(ctx, r.Client, original, condition); updateErr != nil {
+		r.Log.Error(updateErr, "tried updating condition status", "name", condition.Name)
+	}
+
+	return err
+}
+
+func (r *NrqlAlertConditionReconciler) checkForExistingCondition(ctx context.Context, alertsClient interfaces.NewRelicAlertsClient, accountID int, condition *nralertsv1.NrqlAlertCondition) error {
+	defer newrelic.FromContext(ctx).StartSegment("checkForExistingCondition").End()
+	if condition.Status.ConditionID != 0 {
+		return nil
+	}
+
+	adoption := nralertsv1.GetAdoptionPolicy(condition.Spec.Adoption, condition.Spec.ImportID)
+	if adoption == nralertsv1.AdoptionNever {
+		return nil
+	}
+
+	r.Log.Info("Checking for existing condition", "conditionName", condition.Name)
+	//if no conditionId, get list of conditions and compare name
+	existingConditions, err := alertsClient.ListNrqlConditions(condition.Spec.ExistingPolicyID)
+	if err != nil {
+		r.Log.Error(err, "failed to get list of NRQL conditions from New Relic API",
+			"conditionId", condition.Status.ConditionID,
+			"region", condition.Spec.Region,
+		)
+
+		return err
+	}
+
+	if adoption == nralertsv1.AdoptionByID {
+		for _, existingCondition := range existingConditions {
+			if strconv.Itoa(existingCondition.ID) != condition.Spec.ImportID {
+				continue
 			}
+
+			if err := r.Ownership.checkImportable(alertsClient, accountID, ownedNrqlCondition, condition.Spec.ImportID); err != nil {
+				return err
+			}
+
+			r.Log.Info("Importing existing condition", "conditionName", condition.Name, "conditionId", existingCondition.ID)
+			condition.Status.ConditionID = existingCondition.ID
+			r.Recorder.Eventf(condition, v1.EventTypeNormal, eventReasonImported, "Imported existing New Relic condition %v", existingCondition.ID)
+
+			return r.Ownership.claim(alertsClient, accountID, ownedNrqlCondition, condition.Spec.ImportID, condition)
+		}
+
+		return &customErrors.Error{
+			Kind:     customErrors.ErrNotFound,
+			Resource: ownedNrqlCondition,
+			ID:       condition.Spec.ImportID,
+			Err:      fmt.Errorf("no condition %s in policy %d", condition.Spec.ImportID, condition.Spec.ExistingPolicyID),
+		}
+	}
+
+	for _, existingCondition := range existingConditions {
+		if existingCondition.Name != r.Naming.remoteName(condition, condition.Spec.Name) {
+			continue
+		}
+
+		unowned, err := r.Ownership.isUnowned(alertsClient, accountID, ownedNrqlCondition, strconv.Itoa(existingCondition.ID), condition)
+		if err != nil {
+			return err
+		}
+
+		if !unowned {
+			r.Log.Info("Skipping condition with the same name managed by another resource", "conditionId", existingCondition.ID)
+			continue
 		}
+
+		r.Log.Info("Matched on existing condition, updating ConditionId", "conditionId", existingCondition.ID)
+		condition.Status.ConditionID = existingCondition.ID
+		r.Recorder.Eventf(condition, v1.EventTypeNormal, eventReasonAdopted, "Adopted existing New Relic condition %v", existingCondition.ID)
+
+		return r.Ownership.claim(alertsClient, accountID, ownedNrqlCondition, strconv.Itoa(existingCondition.ID), condition)
 	}
+
+	return nil
 }
 
-func (r *NrqlAlertConditionReconciler) writeNewRelicAlertCondition(ctx context.Context, req ctrl.Request, alertsClient interfaces.NewRelicAlertsClient, condition nralertsv1.NrqlAlertCondition) {
+func (r *NrqlAlertConditionReconciler) writeNewRelicAlertCondition(ctx context.Context, req ctrl.Request, alertsClient interfaces.NewRelicAlertsClient, accountID int, original *nralertsv1.NrqlAlertCondition, condition nralertsv1.NrqlAlertCondition) error {
 	APICondition := condition.Spec.APICondition()
+	APICondition.Name = r.Naming.remoteName(&condition, condition.Spec.Name)
 
 	if condition.Status.ConditionID != 0 && !reflect.DeepEqual(&condition.Spec, condition.Status.AppliedSpec) {
 		r.Log.Info("updating condition", "ConditionName", condition.Name, "API fields", APICondition)
+		recordChanges(r.Recorder, &condition, condition.Status.AppliedSpec, &condition.Spec)
 		APICondition.ID = condition.Status.ConditionID
-		updatedCondition, err := alertsClient.UpdateNrqlCondition(APICondition)
+
+		err := r.Ownership.ensureOwner(alertsClient, accountID, ownedNrqlCondition, strconv.Itoa(condition.Status.ConditionID), &condition)
 		if err != nil {
 			r.Log.Error(err, "failed to update condition")
-		} else {
-			condition.Status.AppliedSpec = &condition.Spec
-			condition.Status.ConditionID = updatedCondition.ID
+			return r.markFailed(ctx, original, &condition, nralertsv1.ReasonUpdateFailed, err)
 		}
 
-		err = r.Client.Update(ctx, &condition)
+		updatedCondition, err := alertsClient.UpdateNrqlCondition(APICondition)
 		if err != nil {
-			r.Log.Error(err, "tried updating condition status", "name", req.NamespacedName)
+			r.Log.Error(err, "failed to update condition")
+			return r.markFailed(ctx, original, &condition, nralertsv1.ReasonUpdateFailed, err)
 		}
+
+		condition.Status.AppliedSpec = &condition.Spec
+		condition.Status.ConditionID = updatedCondition.ID
+		r.Recorder.Eventf(&condition, v1.EventTypeNormal, eventReasonUpdated, "Updated New Relic condition %v", updatedCondition.ID)
 	} else {
 		r.Log.Info("Creating condition", "ConditionName", condition.Name, "API fields", APICondition)
 		createdCondition, err := alertsClient.CreateNrqlCondition(condition.Spec.ExistingPolicyID, APICondition)
@@ -204,25 +315,39 @@ func (r *NrqlAlertConditionReconciler) writeNewRelicAlertCondition(ctx context.C
 			r.Log.Error(err, "failed to create condition",
 				"conditionId", condition.Status.ConditionID,
 				"region", condition.Spec.Region,
-				"Api Key", interfaces.PartialAPIKey(r.apiKey),
 			)
-		} else {
-			condition.Status.AppliedSpec = &condition.Spec
-			condition.Status.ConditionID = createdCondition.ID
+			return r.markFailed(ctx, original, &condition, nralertsv1.ReasonCreateFailed, err)
 		}
 
-		err = r.Client.Update(ctx, &condition)
-		if err != nil {
-			r.Log.Error(err, "tried updating condition status", "name", req.NamespacedName)
+		condition.Status.AppliedSpec = &condition.Spec
+		condition.Status.ConditionID = createdCondition.ID
+		r.Recorder.Eventf(&condition, v1.EventTypeNormal, eventReasonCreated, "Created New Relic condition %v", createdCondition.ID)
+
+		if err := r.Ownership.claim(alertsClient, accountID, ownedNrqlCondition, strconv.Itoa(createdCondition.ID), &condition); err != nil {
+			r.Log.Error(err, "failed to mark condition as managed by this resource", "conditionId", createdCondition.ID)
 		}
 	}
+
+	condition.Status.MarkSynced()
+
+	err := updateResource(ctx, r.Client, original, &condition)
+	if err != nil {
+		r.Log.Error(err, "tried updating condition status", "name", req.NamespacedName)
+		return err
+	}
+
+	return nil
 }
 
 func (r *NrqlAlertConditionReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	r.AlertClientFunc = interfaces.InitializeAlertsClient
+	if r.AlertClientFunc == nil {
+		r.AlertClientFunc = interfaces.DefaultClientPool.AlertsClient
+	}
 
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&nralertsv1.NrqlAlertCondition{}).
+		Watches(&source.Kind{Type: &v1.Namespace{}}, enqueueNamespaceDependents(mgr.GetClient(), &nralertsv1.NrqlAlertConditionList{})).
+		WithOptions(controller.Options{MaxConcurrentReconciles: r.MaxConcurrentReconciles}).
 		Complete(r)
 }
 
@@ -236,20 +361,31 @@ func containsString(slice []string, s string) bool {
 	return false
 }
 
-func (r *NrqlAlertConditionReconciler) deleteNewRelicAlertCondition(condition nralertsv1.NrqlAlertCondition) error {
-	defer r.txn.StartSegment("deleteNewRelicAlertCondition").End()
+func (r *NrqlAlertConditionReconciler) deleteNewRelicAlertCondition(ctx context.Context, alertsClient interfaces.NewRelicAlertsClient, accountID int, condition nralertsv1.NrqlAlertCondition) error {
+	defer newrelic.FromContext(ctx).StartSegment("deleteNewRelicAlertCondition").End()
 	r.Log.Info("Deleting condition", "conditionName", condition.Spec.Name)
-	_, err := r.Alerts.DeleteNrqlCondition(condition.Status.ConditionID)
+
+	skip, err := r.Ownership.skipDelete(r.Recorder, alertsClient, accountID, ownedNrqlCondition, strconv.Itoa(condition.Status.ConditionID), &condition)
+	if skip || err != nil {
+		return err
+	}
+
+	_, err = alertsClient.DeleteNrqlCondition(condition.Status.ConditionID)
 	if err != nil {
 		r.Log.Error(err, "Error deleting condition",
 			"conditionId", condition.Status.ConditionID,
 			"region", condition.Spec.Region,
-			"Api Key", interfaces.PartialAPIKey(r.apiKey),
 		)
 
 		return err
 	}
 
+	r.Recorder.Eventf(&condition, v1.EventTypeNormal, eventReasonDeleted, "Deleted New Relic condition %v", condition.Status.ConditionID)
+
+	if err := r.Ownership.release(alertsClient, accountID, ownedNrqlCondition, strconv.Itoa(condition.Status.ConditionID)); err != nil {
+		r.Log.Error(err, "failed to remove ownership marker of deleted condition", "conditionId", condition.Status.ConditionID)
+	}
+
 	return nil
 }
 
@@ -264,22 +400,18 @@ func removeString(slice []string, s string) (result []string) {
 	return
 }
 
-func (r *NrqlAlertConditionReconciler) getAPIKeyOrSecret(condition nralertsv1.NrqlAlertCondition) (string, error) {
-	defer r.txn.StartSegment("getAPIKeyOrSecret").End()
-	if condition.Spec.APIKey != "" {
-		return condition.Spec.APIKey, nil
-	}
+func (r *NrqlAlertConditionReconciler) getCredentials(ctx context.Context, condition nralertsv1.NrqlAlertCondition) (nralertsv1.ResolvedCredentials, error) {
+	defer newrelic.FromContext(ctx).StartSegment("getCredentials").End()
 
-	if condition.Spec.APIKeySecret != (nralertsv1.NewRelicAPIKeySecret{}) {
-		key := types.NamespacedName{Namespace: condition.Spec.APIKeySecret.Namespace, Name: condition.Spec.APIKeySecret.Name}
-		var apiKeySecret v1.Secret
-		if getErr := r.Client.Get(context.Background(), key, &apiKeySecret); getErr != nil {
-			r.Log.Error(getErr, "Error retrieving secret", "secret", apiKeySecret)
-			return "", getErr
-		}
+	creds, err := condition.Spec.Credentials().Resolve(ctx, r.Client, condition.Namespace)
+	if err != nil {
+		r.Log.Error(err, "Failed to resolve credentials")
+		return creds, err
+	}
 
-		return string(apiKeySecret.Data[condition.Spec.APIKeySecret.KeyName]), nil
+	if err := ownAPIKeySecret(ctx, r.Client, &condition, condition.Spec.APIKeySecret); err != nil {
+		r.Log.Error(err, "Failed to add the resource to the owners of its API key secret")
 	}
 
-	return "", nil
+	return creds, nil
 }
@@ -0,0 +1,269 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/newrelic/newrelic-client-go/pkg/nerdstorage"
+
+	nrv1 "github.com/newrelic/newrelic-kubernetes-operator/api/v1"
+	customErrors "github.com/newrelic/newrelic-kubernetes-operator/errors"
+	"github.com/newrelic/newrelic-kubernetes-operator/interfaces"
+)
+
+const (
+	// ownershipCollection is the NerdStorage collection the ownership markers are kept in, one
+	// document per object in New Relic managed by the operator.
+	ownershipCollection = "kubernetesOperatorOwners"
+	// DefaultOwnershipPackageID is the NerdStorage package the ownership markers are kept in,
+	// unless another one is set with --ownership-package-id.
+	DefaultOwnershipPackageID = "6f3a1c2e-5b7d-4e0a-9c8f-2d4b6a8e0f13"
+)
+
+// Kinds of objects in New Relic the ownership markers are kept for. The IDs of policies and of
+// NRQL conditions are the same in the REST API and in NerdGraph.
+const (
+	ownedPolicy        = "policy"
+	ownedNrqlCondition = "nrql-condition"
+	ownedAPMCondition  = "apm-condition"
+	ownedChannel       = "channel"
+)
+
+// Ownership configures the ownership markers the operator keeps in NerdStorage, in the account of
+// each object it manages in New Relic, to tell which resource manages the object.
+//
+// Markers are kept per account, so the objects of resources without an account ID have none and
+// are adopted and imported without checking which resource manages them. warnNoAccountID records
+// a warning on those resources.
+type Ownership struct {
+	// ClusterUID identifies the cluster the operator runs in. It is set at startup with
+	// LookupClusterUID.
+	ClusterUID string
+	// PackageID is the NerdStorage package the markers are kept in. Operators only see the
+	// markers of the operators using the same package ID, so all of the operators managing
+	// objects in the same accounts must use the same one. Defaults to DefaultOwnershipPackageID.
+	PackageID string
+}
+
+func (o Ownership) packageID() string {
+	if o.PackageID == "" {
+		return DefaultOwnershipPackageID
+	}
+
+	return o.PackageID
+}
+
+// LookupClusterUID returns the UID of the kube-system namespace, which identifies the cluster.
+func LookupClusterUID(ctx context.Context, c client.Reader) (string, error) {
+	var namespace v1.Namespace
+
+	if err := c.Get(ctx, types.NamespacedName{Name: metav1.NamespaceSystem}, &namespace); err != nil {
+		return "", err
+	}
+
+	return string(namespace.UID), nil
+}
+
+// ownershipMarker records which resource manages an object in New Relic.
+type ownershipMarker struct {
+	ClusterUID string `json:"clusterUID"`
+	Namespace  string `json:"namespace"`
+	Name       string `json:"name"`
+}
+
+func (o Ownership) ownerOf(obj metav1.Object) ownershipMarker {
+	return ownershipMarker{
+		ClusterUID: o.ClusterUID,
+		Namespace:  obj.GetNamespace(),
+		Name:       obj.GetName(),
+	}
+}
+
+func (m ownershipMarker) String() string {
+	return fmt.Sprintf("%s/%s in cluster %s", m.Namespace, m.Name, m.ClusterUID)
+}
+
+func ownershipDocumentID(kind, id string) string {
+	return kind + "-" + id
+}
+
+// getOwner returns the marker of the object in New Relic of the given kind and ID, or nil if it
+// has none. Markers are kept per account, objects of resources without an account ID have none.
+func (o Ownership) getOwner(alertsClient interfaces.NewRelicAlertsClient, accountID int, kind, id string) (*ownershipMarker, error) {
+	if accountID == 0 || id == "" || id == "0" {
+		return nil, nil
+	}
+
+	document, err := alertsClient.GetDocumentWithAccountScope(accountID, nerdstorage.GetDocumentInput{
+		Collection: ownershipCollection,
+		DocumentID: ownershipDocumentID(kind, id),
+		PackageID:  o.packageID(),
+	})
+	if err != nil {
+		if customErrors.IsNotFound(err) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	if document == nil {
+		return nil, nil
+	}
+
+	documentJSON, err := json.Marshal(document)
+	if err != nil {
+		return nil, err
+	}
+
+	var marker ownershipMarker
+	if err := json.Unmarshal(documentJSON, &marker); err != nil {
+		return nil, err
+	}
+
+	if marker == (ownershipMarker{}) {
+		return nil, nil
+	}
+
+	return &marker, nil
+}
+
+// claim marks the object in New Relic of the given kind and ID as managed by obj. Nothing is marked
+// without an account ID, warnNoAccountID reports it.
+func (o Ownership) claim(alertsClient interfaces.NewRelicAlertsClient, accountID int, kind, id string, obj metav1.Object) error {
+	if accountID == 0 || id == "" || id == "0" {
+		return nil
+	}
+
+	_, err := alertsClient.WriteDocumentWithAccountScope(accountID, nerdstorage.WriteDocumentInput{
+		Collection: ownershipCollection,
+		DocumentID: ownershipDocumentID(kind, id),
+		Document:   o.ownerOf(obj),
+		PackageID:  o.packageID(),
+	})
+
+	return err
+}
+
+// release removes the marker of the object in New Relic of the given kind and ID, after it was
+// deleted. There is none to remove without an account ID.
+func (o Ownership) release(alertsClient interfaces.NewRelicAlertsClient, accountID int, kind, id string) error {
+	if accountID == 0 || id == "" || id == "0" {
+		return nil
+	}
+
+	_, err := alertsClient.DeleteDocumentWithAccountScope(accountID, nerdstorage.DeleteDocumentInput{
+		Collection: ownershipCollection,
+		DocumentID: ownershipDocumentID(kind, id),
+		PackageID:  o.packageID(),
+	})
+	if customErrors.IsNotFound(err) {
+		return nil
+	}
+
+	return err
+}
+
+// checkOwner returns a conflict error if the object in New Relic of the given kind and ID is
+// managed by another resource than obj. Objects without a marker are not checked, they were
+// created before the markers were introduced or outside of the operator.
+func (o Ownership) checkOwner(alertsClient interfaces.NewRelicAlertsClient, accountID int, kind, id string, obj metav1.Object) error {
+	marker, err := o.getOwner(alertsClient, accountID, kind, id)
+	if err != nil {
+		return err
+	}
+
+	if marker == nil || *marker == o.ownerOf(obj) {
+		return nil
+	}
+
+	return ownedByOtherError(kind, id, *marker)
+}
+
+// ensureOwner returns a conflict error if the object in New Relic of the given kind and ID is
+// managed by another resource than obj, and marks it as managed by obj if it has no marker yet.
+// It is called before every write, so objects created before the markers were introduced are
+// claimed by the resource that manages them.
+func (o Ownership) ensureOwner(alertsClient interfaces.NewRelicAlertsClient, accountID int, kind, id string, obj metav1.Object) error {
+	marker, err := o.getOwner(alertsClient, accountID, kind, id)
+	if err != nil {
+		return err
+	}
+
+	if marker == nil {
+		return o.claim(alertsClient, accountID, kind, id, obj)
+	}
+
+	if *marker != o.ownerOf(obj) {
+		return ownedByOtherError(kind, id, *marker)
+	}
+
+	return nil
+}
+
+// skipDelete returns true if the object in New Relic of the given kind and ID is to be left in
+// place when obj is deleted, as another resource manages it. The reason is recorded in an event.
+func (o Ownership) skipDelete(recorder record.EventRecorder, alertsClient interfaces.NewRelicAlertsClient, accountID int, kind, id string, obj nrv1.StatusObject) (bool, error) {
+	err := o.checkOwner(alertsClient, accountID, kind, id, obj)
+	if customErrors.IsConflict(err) {
+		recorder.Eventf(obj, v1.EventTypeWarning, nrv1.ReasonConflict, "Not deleting %s", err)
+		return true, nil
+	}
+
+	return false, err
+}
+
+// checkImportable returns a conflict error if the object in New Relic of the given kind and ID
+// can't be imported by obj because another cluster manages it.
+func (o Ownership) checkImportable(alertsClient interfaces.NewRelicAlertsClient, accountID int, kind, id string) error {
+	marker, err := o.getOwner(alertsClient, accountID, kind, id)
+	if err != nil {
+		return err
+	}
+
+	if marker == nil || marker.ClusterUID == o.ClusterUID {
+		return nil
+	}
+
+	return ownedByOtherError(kind, id, *marker)
+}
+
+// isUnowned returns true if the object in New Relic of the given kind and ID can be adopted by
+// name by obj: it has no marker, or is managed by obj already.
+func (o Ownership) isUnowned(alertsClient interfaces.NewRelicAlertsClient, accountID int, kind, id string, obj metav1.Object) (bool, error) {
+	marker, err := o.getOwner(alertsClient, accountID, kind, id)
+	if err != nil {
+		return false, err
+	}
+
+	return marker == nil || *marker == o.ownerOf(obj), nil
+}
+
+// warnNoAccountID records a warning on obj when it has no account ID, as the objects it manages in
+// New Relic are then not marked, nor checked for another resource managing them before they are
+// adopted, imported, written or deleted.
+func warnNoAccountID(recorder record.EventRecorder, accountID int, obj runtime.Object) {
+	if accountID != 0 {
+		return
+	}
+
+	recorder.Event(obj, v1.EventTypeWarning, eventReasonOwnershipUnchecked,
+		"No account ID is set, the objects in New Relic are not marked as managed by this resource, nor checked for another resource managing them")
+}
+
+func ownedByOtherError(kind, id string, marker ownershipMarker) error {
+	return &customErrors.Error{
+		Kind:     customErrors.ErrConflict,
+		Resource: kind,
+		ID:       id,
+		Err:      fmt.Errorf("managed by %s", marker),
+	}
+}
@@ -0,0 +1,70 @@
+package controllers
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/newrelic/newrelic-client-go/pkg/nerdstorage"
+
+	nrv1 "github.com/newrelic/newrelic-kubernetes-operator/api/v1"
+	"github.com/newrelic/newrelic-kubernetes-operator/interfaces/interfacesfakes"
+)
+
+var _ = Describe("ownership markers", func() {
+	var (
+		alertsClient *interfacesfakes.FakeNewRelicAlertsClient
+		ownership    Ownership
+		policy       *nrv1.AlertsPolicy
+	)
+
+	BeforeEach(func() {
+		alertsClient = &interfacesfakes.FakeNewRelicAlertsClient{}
+		ownership = Ownership{ClusterUID: "my-cluster"}
+		policy = &nrv1.AlertsPolicy{ObjectMeta: metav1.ObjectMeta{Name: "my-policy", Namespace: "default"}}
+	})
+
+	It("are kept in the default package", func() {
+		Expect(ownership.claim(alertsClient, 1, ownedPolicy, "42", policy)).To(Succeed())
+
+		_, input := alertsClient.WriteDocumentWithAccountScopeArgsForCall(0)
+		Expect(input.PackageID).To(Equal(DefaultOwnershipPackageID))
+	})
+
+	It("are kept in the package that is configured", func() {
+		ownership.PackageID = "my-package"
+
+		_, err := ownership.getOwner(alertsClient, 1, ownedPolicy, "42")
+		Expect(err).ToNot(HaveOccurred())
+
+		_, input := alertsClient.GetDocumentWithAccountScopeArgsForCall(0)
+		Expect(input).To(Equal(nerdstorage.GetDocumentInput{
+			Collection: ownershipCollection,
+			DocumentID: "policy-42",
+			PackageID:  "my-package",
+		}))
+	})
+
+	Context("without an account ID", func() {
+		It("doesn't read or write markers", func() {
+			marker, err := ownership.getOwner(alertsClient, 0, ownedPolicy, "42")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(marker).To(BeNil())
+
+			Expect(ownership.ensureOwner(alertsClient, 0, ownedPolicy, "42", policy)).To(Succeed())
+			Expect(alertsClient.GetDocumentWithAccountScopeCallCount()).To(BeZero())
+			Expect(alertsClient.WriteDocumentWithAccountScopeCallCount()).To(BeZero())
+		})
+
+		It("warns that the objects are not marked", func() {
+			recorder := record.NewFakeRecorder(1)
+
+			warnNoAccountID(recorder, 0, policy)
+			Expect(recorder.Events).To(Receive(ContainSubstring(eventReasonOwnershipUnchecked)))
+
+			warnNoAccountID(recorder, 1, policy)
+			Expect(recorder.Events).ToNot(Receive())
+		})
+	})
+})
@@ -0,0 +1,106 @@
+package controllers
+
+import (
+	"context"
+
+	v1 "k8s.io/api/core/v1"
+	kErr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	nrv1 "github.com/newrelic/newrelic-kubernetes-operator/api/v1"
+)
+
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
+
+// isPaused returns true if reconciliation of obj is paused, by an annotation on obj, on the
+// AlertsPolicy it was created for or on its namespace.
+func isPaused(ctx context.Context, c client.Client, obj metav1.Object) (bool, error) {
+	if nrv1.IsPaused(obj) {
+		return true, nil
+	}
+
+	if owner := metav1.GetControllerOf(obj); owner != nil && owner.Kind == "AlertsPolicy" && owner.APIVersion == nrv1.GroupVersion.String() {
+		var policy nrv1.AlertsPolicy
+
+		err := c.Get(ctx, types.NamespacedName{Namespace: obj.GetNamespace(), Name: owner.Name}, &policy)
+		if err != nil && !kErr.IsNotFound(err) {
+			return false, err
+		}
+
+		if err == nil && nrv1.IsPaused(&policy) {
+			return true, nil
+		}
+	}
+
+	var namespace v1.Namespace
+
+	err := c.Get(ctx, types.NamespacedName{Name: obj.GetNamespace()}, &namespace)
+	if err != nil {
+		if kErr.IsNotFound(err) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return nrv1.IsPaused(&namespace), nil
+}
+
+// markPaused records in the status of obj that its reconciliation is paused. pending is true if
+// the spec has changes that were not written to New Relic yet.
+// It returns true if the object in New Relic is to be checked for drift, which is only possible
+// when the spec was written as it is.
+func markPaused(recorder record.EventRecorder, obj nrv1.StatusObject, pending bool) bool {
+	status := obj.GetResourceStatus()
+
+	message := "nothing is written to New Relic until reconciliation is resumed"
+	switch {
+	case obj.GetDeletionTimestamp() != nil:
+		message = "the resource is deleted from New Relic when reconciliation is resumed"
+	case pending:
+		message = "changes to the spec are written to New Relic when reconciliation is resumed"
+	}
+
+	if !nrv1.IsConditionTrue(status.Conditions, nrv1.ConditionPaused) {
+		recorder.Event(obj, v1.EventTypeNormal, eventReasonPaused, "Reconciliation paused, "+message)
+	}
+
+	status.MarkPaused(message)
+
+	return obj.GetDeletionTimestamp() == nil && !pending
+}
+
+// markResumed records in the status of obj that its reconciliation is no longer paused, if it was.
+func markResumed(recorder record.EventRecorder, obj nrv1.StatusObject) {
+	status := obj.GetResourceStatus()
+
+	if nrv1.IsConditionTrue(status.Conditions, nrv1.ConditionPaused) {
+		recorder.Event(obj, v1.EventTypeNormal, eventReasonResumed, "Reconciliation resumed")
+	}
+
+	status.MarkResumed()
+}
+
+// enqueueNamespaceDependents returns a handler for Namespace events that enqueues the items of
+// list in the namespace, so pausing or resuming a namespace is applied right away.
+func enqueueNamespaceDependents(c client.Client, list runtime.Object) handler.EventHandler {
+	return &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(func(namespace handler.MapObject) []reconcile.Request {
+			items := list.DeepCopyObject()
+
+			if err := c.List(context.Background(), items, client.InNamespace(namespace.Meta.GetName())); err != nil {
+				ctrl.Log.Error(err, "unable to list the resources in a namespace", "namespace", namespace.Meta.GetName())
+				return nil
+			}
+
+			return requestsFor(items)
+		}),
+	}
+}
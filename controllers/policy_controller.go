@@ -18,49 +18,57 @@ package controllers
 import (
 	"context"
 	"errors"
+	"fmt"
 	"reflect"
+	"strconv"
 
 	newrelic "github.com/newrelic/go-agent/v3/newrelic"
 	"github.com/newrelic/newrelic-client-go/pkg/alerts"
 	v1 "k8s.io/api/core/v1"
 	kErr "k8s.io/apimachinery/pkg/api/errors"
-	"k8s.io/apimachinery/pkg/types"
 
 	"github.com/go-logr/logr"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	nrv1 "github.com/newrelic/newrelic-kubernetes-operator/api/v1"
 	customErrors "github.com/newrelic/newrelic-kubernetes-operator/errors"
 	"github.com/newrelic/newrelic-kubernetes-operator/interfaces"
 )
 
-// PolicyReconciler reconciles a Policy object
+// PolicyReconciler reconciles a Policy object. The legacy kinds are not checked for drift,
+// they are only written to New Relic when their spec changes and never report the Drifted condition.
 type PolicyReconciler struct {
 	client.Client
-	Log             logr.Logger
-	Scheme          *runtime.Scheme
-	AlertClientFunc func(string, string) (interfaces.NewRelicAlertsClient, error)
-	apiKey          string
-	Alerts          interfaces.NewRelicAlertsClient
-	ctx             context.Context
-	NewRelicAgent   newrelic.Application
-	txn             *newrelic.Transaction
+	stopper
+	Log                     logr.Logger
+	Recorder                record.EventRecorder
+	Scheme                  *runtime.Scheme
+	AlertClientFunc         func(string, string) (interfaces.NewRelicAlertsClient, error)
+	NewRelicAgent           newrelic.Application
+	MaxConcurrentReconciles int
+	Ownership               Ownership
+	Naming                  Naming
+	DryRun                  bool
 }
 
 // +kubebuilder:rbac:groups=nr.k8s.newrelic.com,resources=policies,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=nr.k8s.newrelic.com,resources=policies/status,verbs=get;update;patch
 
 func (r *PolicyReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
-	r.ctx = context.Background()
 	_ = r.Log.WithValues("policy", req.NamespacedName)
 
-	r.txn = r.NewRelicAgent.StartTransaction("Reconcile/Policy")
-	defer r.txn.End()
+	txn := r.NewRelicAgent.StartTransaction("Reconcile/Policy")
+	defer txn.End()
+	ctx, cancel := r.withStop(newrelic.NewContext(context.Background(), txn))
+	defer cancel()
 
 	var policy nrv1.Policy
-	err := r.Client.Get(r.ctx, req.NamespacedName, &policy)
+	err := r.Client.Get(ctx, req.NamespacedName, &policy)
 	if err != nil {
 		if kErr.IsNotFound(err) {
 			r.Log.Info("Policy 'not found' after being deleted. This is expected and no cause for alarm", "error", err)
@@ -72,23 +80,52 @@ func (r *PolicyReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 	r.Log.Info("Starting reconcile action")
 	r.Log.Info("policy", "policy.Spec.Condition", policy.Spec.Conditions, "policy.status.applied.conditions", policy.Status.AppliedSpec.Conditions)
 
-	r.apiKey, err = r.getAPIKeyOrSecret(policy)
+	original := policy.DeepCopy()
+
+	deleteFinalizer := "policies.finalizers.nr.k8s.newrelic.com"
+
+	if isRetained(&policy, policy.Spec.DeletionPolicy) {
+		r.Log.Info("Retaining New Relic policy, just removing finalizer", "id", policy.Status.PolicyID)
+		if err := r.retainConditions(ctx, &policy); err != nil {
+			r.Log.Error(err, "Failed to retain condition resources")
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{}, orphan(ctx, r.Client, r.Recorder, &policy, deleteFinalizer, "policy", fmt.Sprint(policy.Status.PolicyID))
+	}
+
+	creds, err := r.getCredentials(ctx, policy)
 	if err != nil {
-		return ctrl.Result{}, err
+		return ctrl.Result{}, r.markFailed(ctx, original, &policy, nrv1.ReasonCredentialsFailed, err)
 	}
 
-	if r.apiKey == "" {
-		return ctrl.Result{}, errors.New("api key is blank")
+	if creds.APIKey == "" {
+		return ctrl.Result{}, r.markFailed(ctx, original, &policy, nrv1.ReasonCredentialsFailed, errors.New("api key is blank"))
 	}
 	//initial alertsClient
-	alertsClient, errAlertsClient := r.AlertClientFunc(r.apiKey, policy.Spec.Region)
+	alertsClient, errAlertsClient := creds.AlertsClient(r.AlertClientFunc)
 	if errAlertsClient != nil {
 		r.Log.Error(errAlertsClient, "Failed to create AlertsClient")
-		return ctrl.Result{}, errAlertsClient
+		return ctrl.Result{}, r.markFailed(ctx, original, &policy, nrv1.ReasonCredentialsFailed, errAlertsClient)
 	}
-	r.Alerts = alertsClient
 
-	deleteFinalizer := "policies.finalizers.nr.k8s.newrelic.com"
+	paused, err := isPaused(ctx, r.Client, &policy)
+	if err != nil {
+		r.Log.Error(err, "Failed to check if reconciliation is paused")
+		return ctrl.Result{}, err
+	}
+
+	if paused {
+		r.Log.Info("Reconciliation is paused, not writing to New Relic", "policyId", policy.Status.PolicyID)
+		markPaused(r.Recorder, &policy, !policy.Spec.Equals(*policy.Status.AppliedSpec))
+
+		return ctrl.Result{}, updateResource(ctx, r.Client, original, &policy)
+	}
+
+	markResumed(r.Recorder, &policy)
+	warnNoAccountID(r.Recorder, creds.AccountID, &policy)
+	alertsClient = interfaces.WithContext(alertsClient, ctx)
+	ctx, alertsClient = startDryRun(ctx, r.DryRun, r.Recorder, alertsClient, &policy)
 
 	//examine DeletionTimestamp to determine if object is under deletion
 	if policy.DeletionTimestamp.IsZero() {
@@ -96,51 +133,92 @@ func (r *PolicyReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 			policy.Finalizers = append(policy.Finalizers, deleteFinalizer)
 		}
 	} else {
-		return r.deletePolicy(r.ctx, &policy, deleteFinalizer)
+		if planDeletion(ctx, &policy, "policy", strconv.Itoa(policy.Status.PolicyID)) {
+			return ctrl.Result{}, updateResource(ctx, r.Client, original, &policy)
+		}
+
+		result, err := r.deletePolicy(ctx, alertsClient, creds.AccountID, &policy, deleteFinalizer)
+		if err != nil {
+			return result, r.markFailed(ctx, original, &policy, nrv1.ReasonDeleteFailed, err)
+		}
+
+		return result, nil
 	}
 
 	if policy.Spec.Equals(*policy.Status.AppliedSpec) {
-		return ctrl.Result{}, nil
+		policy.Status.MarkSynced()
+
+		return ctrl.Result{}, updateResource(ctx, r.Client, original, &policy)
 	}
 
 	r.Log.Info("Reconciling", "policy", policy.Name)
 
-	r.checkForExistingPolicy(&policy)
+	if err := r.checkForExistingPolicy(ctx, alertsClient, creds.AccountID, &policy); err != nil {
+		r.Log.Error(err, "failed to adopt existing policy", "importId", policy.Spec.ImportID)
+		return retryLater(ctrl.Result{}, r.markFailed(ctx, original, &policy, nrv1.ReasonImportFailed, err))
+	}
 
 	if policy.Status.PolicyID != 0 {
-		err := r.updatePolicy(&policy)
+		err := r.updatePolicy(ctx, alertsClient, creds.AccountID, &policy)
 		if err != nil {
 			r.Log.Error(err, "error updating policy")
-			return ctrl.Result{}, err
+			return retryLater(ctrl.Result{}, r.markFailed(ctx, original, &policy, nrv1.ReasonUpdateFailed, err))
 		}
 	} else {
-		err := r.createPolicy(&policy)
+		err := r.createPolicy(ctx, alertsClient, creds.AccountID, &policy)
 		if err != nil {
 			r.Log.Error(err, "Error creating policy")
-			return ctrl.Result{}, err
+			return retryLater(ctrl.Result{}, r.markFailed(ctx, original, &policy, nrv1.ReasonCreateFailed, err))
 		}
 	}
 
+	policy.Status.AppliedSpec = &policy.Spec
+	policy.Status.MarkSynced()
+
+	err = updateResource(ctx, r.Client, original, &policy)
+	if err != nil {
+		r.Log.Error(err, "failed to update policy status", "name", policy.Name)
+		return ctrl.Result{}, err
+	}
+
 	return ctrl.Result{}, nil
 }
 
-func (r *PolicyReconciler) createPolicy(policy *nrv1.Policy) error {
-	defer r.txn.StartSegment("createPolicy").End()
+// markFailed records err in the status of the policy and returns it.
+func (r *PolicyReconciler) markFailed(ctx context.Context, original, policy *nrv1.Policy, reason string, err error) error {
+	reason = failureReason(reason, err)
+	policy.Status.MarkFailed(reason, err)
+	r.Recorder.Event(policy, v1.EventTypeWarning, reason, err.Error())
+
+	if updateErr := updateResource(ctx, r.Client, original, policy); updateErr != nil {
+		r.Log.Error(updateErr, "failed to update policy status", "name", policy.Name)
+	}
+
+	return err
+}
+
+func (r *PolicyReconciler) createPolicy(ctx context.Context, alertsClient interfaces.NewRelicAlertsClient, accountID int, policy *nrv1.Policy) error {
+	defer newrelic.FromContext(ctx).StartSegment("createPolicy").End()
 	r.Log.Info("Creating policy", "PolicyName", policy.Name)
 	APIPolicy := policy.Spec.APIPolicy()
-	createdPolicy, err := r.Alerts.CreatePolicy(APIPolicy)
+	APIPolicy.Name = r.Naming.remoteName(policy, policy.Spec.Name)
+	createdPolicy, err := alertsClient.CreatePolicy(APIPolicy)
 	if err != nil {
 		r.Log.Error(err, "failed to create policy via New Relic API",
 			"policyId", policy.Status.PolicyID,
 			"region", policy.Spec.Region,
-			"Api Key", interfaces.PartialAPIKey(r.apiKey),
 		)
 
 		return err
 	}
 	policy.Status.PolicyID = createdPolicy.ID
+	r.Recorder.Eventf(policy, v1.EventTypeNormal, eventReasonCreated, "Created New Relic policy %d", createdPolicy.ID)
 
-	errConditions := r.createConditions(policy)
+	if err := r.Ownership.claim(alertsClient, accountID, ownedPolicy, strconv.Itoa(createdPolicy.ID), policy); err != nil {
+		r.Log.Error(err, "failed to mark policy as managed by this resource", "policyId", createdPolicy.ID)
+	}
+
+	errConditions := r.createConditions(ctx, policy)
 	if errConditions != nil {
 		r.Log.Error(errConditions, "error creating or updating conditions")
 
@@ -148,20 +226,11 @@ func (r *PolicyReconciler) createPolicy(policy *nrv1.Policy) error {
 	}
 	r.Log.Info("policy after condition creation", "policyCondition", policy.Spec.Conditions, "pointer", &policy)
 
-	policy.Status.AppliedSpec = &policy.Spec
-
-	err = r.Client.Update(r.ctx, policy)
-	if err != nil {
-		r.Log.Error(err, "tried updating policy status", "name", policy.Name)
-
-		return err
-	}
-
 	return nil
 }
 
-func (r *PolicyReconciler) createConditions(policy *nrv1.Policy) error {
-	defer r.txn.StartSegment("createConditions").End()
+func (r *PolicyReconciler) createConditions(ctx context.Context, policy *nrv1.Policy) error {
+	defer newrelic.FromContext(ctx).StartSegment("createConditions").End()
 	r.Log.Info("initial policy creation so create all policies")
 	collectedErrors := new(customErrors.ErrorCollector)
 	for i, condition := range policy.Spec.Conditions {
@@ -169,9 +238,9 @@ func (r *PolicyReconciler) createConditions(policy *nrv1.Policy) error {
 		var err error
 		switch nrv1.GetConditionType(condition) {
 		case "ApmAlertCondition":
-			err = r.createApmCondition(policy, &condition)
+			err = r.createApmCondition(ctx, policy, &condition)
 		case "NrqlAlertCondition":
-			err = r.createNrqlCondition(policy, &condition)
+			err = r.createNrqlCondition(ctx, policy, &condition)
 		}
 
 		if err != nil {
@@ -195,8 +264,8 @@ type processedConditions struct {
 	condition nrv1.PolicyCondition
 }
 
-func (r *PolicyReconciler) createOrUpdateCondition(policy *nrv1.Policy, condition *nrv1.PolicyCondition) (*nrv1.PolicyCondition, error) {
-	defer r.txn.StartSegment("createOrUpdateCondition").End()
+func (r *PolicyReconciler) createOrUpdateCondition(ctx context.Context, policy *nrv1.Policy, condition *nrv1.PolicyCondition) (*nrv1.PolicyCondition, error) {
+	defer newrelic.FromContext(ctx).StartSegment("createOrUpdateCondition").End()
 	//loop through the policies, creating/updating as needed
 	r.Log.Info("Checking on condition", "resourceName", condition.Name, "conditionName", condition.Spec.Name)
 	//first we check to see if the name is set
@@ -219,9 +288,9 @@ func (r *PolicyReconciler) createOrUpdateCondition(policy *nrv1.Policy, conditio
 			var err error
 			switch nrv1.GetConditionType(*condition) {
 			case "ApmAlertCondition":
-				err = r.createApmCondition(policy, condition)
+				err = r.createApmCondition(ctx, policy, condition)
 			case "NrqlAlertCondition":
-				err = r.createNrqlCondition(policy, condition)
+				err = r.createNrqlCondition(ctx, policy, condition)
 			}
 			return condition, err
 		}
@@ -230,17 +299,17 @@ func (r *PolicyReconciler) createOrUpdateCondition(policy *nrv1.Policy, conditio
 	var err error
 	switch nrv1.GetConditionType(*condition) {
 	case "ApmAlertCondition":
-		err = r.updateApmCondition(policy, condition)
+		err = r.updateApmCondition(ctx, policy, condition)
 	case "NrqlAlertCondition":
-		err = r.updateNrqlCondition(policy, condition)
+		err = r.updateNrqlCondition(ctx, policy, condition)
 	}
 
 	return condition, err
 }
 
-func (r *PolicyReconciler) updateNrqlCondition(policy *nrv1.Policy, condition *nrv1.PolicyCondition) error {
-	defer r.txn.StartSegment("updateNrqlCondition").End()
-	nrqlAlertCondition := r.getNrqlConditionFromPolicyCondition(condition)
+func (r *PolicyReconciler) updateNrqlCondition(ctx context.Context, policy *nrv1.Policy, condition *nrv1.PolicyCondition) error {
+	defer newrelic.FromContext(ctx).StartSegment("updateNrqlCondition").End()
+	nrqlAlertCondition := r.getNrqlConditionFromPolicyCondition(ctx, condition)
 
 	r.Log.Info("Found nrql condition to update", "retrievedCondition", nrqlAlertCondition)
 
@@ -263,13 +332,19 @@ func (r *PolicyReconciler) updateNrqlCondition(policy *nrv1.Policy, condition *n
 	nrqlAlertCondition.Spec.ExistingPolicyID = policy.Status.PolicyID
 	nrqlAlertCondition.Spec.APIKey = policy.Spec.APIKey
 	nrqlAlertCondition.Spec.APIKeySecret = policy.Spec.APIKeySecret
+	nrqlAlertCondition.Spec.AccountRef = policy.Spec.AccountRef
 
-	return r.Client.Update(r.ctx, &nrqlAlertCondition)
+	err := childWriter(ctx, r.Client).Update(ctx, &nrqlAlertCondition)
+	if err == nil {
+		r.Recorder.Eventf(policy, v1.EventTypeNormal, eventReasonChildUpdated, "Updated NrqlAlertCondition %s", nrqlAlertCondition.Name)
+	}
+
+	return err
 }
 
-func (r *PolicyReconciler) updateApmCondition(policy *nrv1.Policy, condition *nrv1.PolicyCondition) error {
-	defer r.txn.StartSegment("updateApmCondition").End()
-	apmAlertCondition := r.getApmConditionFromPolicyCondition(condition)
+func (r *PolicyReconciler) updateApmCondition(ctx context.Context, policy *nrv1.Policy, condition *nrv1.PolicyCondition) error {
+	defer newrelic.FromContext(ctx).StartSegment("updateApmCondition").End()
+	apmAlertCondition := r.getApmConditionFromPolicyCondition(ctx, condition)
 
 	r.Log.Info("Found apm condition to update", "retrievedCondition", apmAlertCondition)
 
@@ -291,14 +366,20 @@ func (r *PolicyReconciler) updateApmCondition(policy *nrv1.Policy, condition *nr
 	apmAlertCondition.Spec.ExistingPolicyID = policy.Status.PolicyID
 	apmAlertCondition.Spec.APIKey = policy.Spec.APIKey
 	apmAlertCondition.Spec.APIKeySecret = policy.Spec.APIKeySecret
+	apmAlertCondition.Spec.AccountRef = policy.Spec.AccountRef
 
 	r.Log.Info("updating existing condition", "apmAlertCondition", apmAlertCondition)
 
-	return r.Client.Update(r.ctx, &apmAlertCondition)
+	err := childWriter(ctx, r.Client).Update(ctx, &apmAlertCondition)
+	if err == nil {
+		r.Recorder.Eventf(policy, v1.EventTypeNormal, eventReasonChildUpdated, "Updated ApmAlertCondition %s", apmAlertCondition.Name)
+	}
+
+	return err
 }
 
-func (r *PolicyReconciler) createOrUpdateConditions(policy *nrv1.Policy) error {
-	defer r.txn.StartSegment("createOrUpdateConditions").End()
+func (r *PolicyReconciler) createOrUpdateConditions(ctx context.Context, policy *nrv1.Policy) error {
+	defer newrelic.FromContext(ctx).StartSegment("createOrUpdateConditions").End()
 	if reflect.DeepEqual(policy.Spec.Conditions, policy.Status.AppliedSpec.Conditions) {
 		return nil
 	}
@@ -315,7 +396,7 @@ func (r *PolicyReconciler) createOrUpdateConditions(policy *nrv1.Policy) error {
 	collectedErrors := new(customErrors.ErrorCollector)
 
 	for i, condition := range policy.Spec.Conditions {
-		condition, err := r.createOrUpdateCondition(policy, &condition)
+		condition, err := r.createOrUpdateCondition(ctx, policy, &condition)
 		if err != nil {
 			r.Log.Error(err, "error creating condition")
 			collectedErrors.Collect(err)
@@ -340,7 +421,7 @@ func (r *PolicyReconciler) createOrUpdateConditions(policy *nrv1.Policy) error {
 		r.Log.Info("checking "+processedCondition.condition.Name, "bool is", processedCondition.processed)
 		if !processedCondition.processed {
 			r.Log.Info("Need to delete", "ppliedConditionName", conditionName)
-			err := r.deleteCondition(&processedCondition.condition)
+			err := r.deleteCondition(ctx, policy, &processedCondition.condition)
 			if err != nil {
 				r.Log.Error(err, "error deleting condition resource")
 				collectedErrors.Collect(err)
@@ -358,8 +439,8 @@ func (r *PolicyReconciler) createOrUpdateConditions(policy *nrv1.Policy) error {
 	return nil
 }
 
-func (r *PolicyReconciler) createNrqlCondition(policy *nrv1.Policy, condition *nrv1.PolicyCondition) error {
-	defer r.txn.StartSegment("createNrqlCondition").End()
+func (r *PolicyReconciler) createNrqlCondition(ctx context.Context, policy *nrv1.Policy, condition *nrv1.PolicyCondition) error {
+	defer newrelic.FromContext(ctx).StartSegment("createNrqlCondition").End()
 	var nrqlAlertCondition nrv1.NrqlAlertCondition
 	nrqlAlertCondition.GenerateName = policy.Name + "-condition-"
 	nrqlAlertCondition.Namespace = policy.Namespace
@@ -372,24 +453,26 @@ func (r *PolicyReconciler) createNrqlCondition(policy *nrv1.Policy, condition *n
 	nrqlAlertCondition.Spec.ExistingPolicyID = policy.Status.PolicyID
 	nrqlAlertCondition.Spec.APIKey = policy.Spec.APIKey
 	nrqlAlertCondition.Spec.APIKeySecret = policy.Spec.APIKeySecret
+	nrqlAlertCondition.Spec.AccountRef = policy.Spec.AccountRef
 	nrqlAlertCondition.Status.AppliedSpec = &nrv1.NrqlAlertConditionSpec{}
 
 	r.Log.Info("creating nrql condition", "condition", condition.Name, "conditionName", condition.Spec.Name, "nrqlAlertCondition", nrqlAlertCondition)
-	errCondition := r.Create(r.ctx, &nrqlAlertCondition)
+	errCondition := childWriter(ctx, r.Client).Create(ctx, &nrqlAlertCondition)
 	if errCondition != nil {
 		r.Log.Error(errCondition, "error creating condition")
 		return errCondition
 	}
 	condition.Name = nrqlAlertCondition.Name //created from generated name
 	condition.Namespace = nrqlAlertCondition.Namespace
+	r.Recorder.Eventf(policy, v1.EventTypeNormal, eventReasonChildCreated, "Created NrqlAlertCondition %s", nrqlAlertCondition.Name)
 
 	r.Log.Info("created condition", "condition", condition.Name, "conditionName", condition.Spec.Name, "nrqlAlertCondition", nrqlAlertCondition, "actualCondition", condition.Spec)
 
 	return nil
 }
 
-func (r *PolicyReconciler) createApmCondition(policy *nrv1.Policy, condition *nrv1.PolicyCondition) error {
-	defer r.txn.StartSegment("createApmCondition").End()
+func (r *PolicyReconciler) createApmCondition(ctx context.Context, policy *nrv1.Policy, condition *nrv1.PolicyCondition) error {
+	defer newrelic.FromContext(ctx).StartSegment("createApmCondition").End()
 	var apmAlertCondition nrv1.ApmAlertCondition
 	apmAlertCondition.GenerateName = policy.Name + "-condition-"
 	apmAlertCondition.Namespace = policy.Namespace
@@ -402,96 +485,147 @@ func (r *PolicyReconciler) createApmCondition(policy *nrv1.Policy, condition *nr
 	apmAlertCondition.Spec.ExistingPolicyID = policy.Status.PolicyID
 	apmAlertCondition.Spec.APIKey = policy.Spec.APIKey
 	apmAlertCondition.Spec.APIKeySecret = policy.Spec.APIKeySecret
+	apmAlertCondition.Spec.AccountRef = policy.Spec.AccountRef
 	apmAlertCondition.Status.AppliedSpec = &nrv1.ApmAlertConditionSpec{}
 
 	r.Log.Info("creating apm condition", "condition", condition.Name, "conditionName", condition.Spec.Name, "apmAlertCondition", apmAlertCondition)
-	errCondition := r.Create(r.ctx, &apmAlertCondition)
+	errCondition := childWriter(ctx, r.Client).Create(ctx, &apmAlertCondition)
 	if errCondition != nil {
 		r.Log.Error(errCondition, "error creating condition")
 		return errCondition
 	}
 	condition.Name = apmAlertCondition.Name //created from generated name
 	condition.Namespace = apmAlertCondition.Namespace
+	r.Recorder.Eventf(policy, v1.EventTypeNormal, eventReasonChildCreated, "Created ApmAlertCondition %s", apmAlertCondition.Name)
 
 	r.Log.Info("created apm condition", "condition", condition.Name, "conditionName", condition.Spec.Name, "apmAlertCondition", apmAlertCondition, "actualCondition", condition.Spec)
 
 	return nil
 }
 
-func (r *PolicyReconciler) deleteCondition(condition *nrv1.PolicyCondition) error {
-	defer r.txn.StartSegment("deleteCondition").End()
+func (r *PolicyReconciler) deleteCondition(ctx context.Context, policy *nrv1.Policy, condition *nrv1.PolicyCondition) error {
+	defer newrelic.FromContext(ctx).StartSegment("deleteCondition").End()
 	r.Log.Info("Deleting condition", "condition", condition.Name, "conditionName", condition.Spec.Name)
 
 	var retrievedCondition runtime.Object
 	switch nrv1.GetConditionType(*condition) {
 	case "ApmAlertCondition":
-		returnedCondition := r.getApmConditionFromPolicyCondition(condition)
+		returnedCondition := r.getApmConditionFromPolicyCondition(ctx, condition)
 		retrievedCondition = &returnedCondition
 	case "NrqlAlertCondition":
-		returnedCondition := r.getNrqlConditionFromPolicyCondition(condition)
+		returnedCondition := r.getNrqlConditionFromPolicyCondition(ctx, condition)
 		retrievedCondition = &returnedCondition
 	}
 
 	r.Log.Info("retrieved condition for deletion", "retrievedCondition", retrievedCondition)
-	err := r.Delete(r.ctx, retrievedCondition)
+	err := childWriter(ctx, r.Client).Delete(ctx, retrievedCondition)
 	if err != nil {
 		r.Log.Error(err, "error deleting condition resource")
 
 		return err
 	}
 
+	r.Recorder.Eventf(policy, v1.EventTypeNormal, eventReasonChildDeleted, "Deleted %s %s", nrv1.GetConditionType(*condition), condition.Name)
+
+	return nil
+}
+
+// retainConditions deletes the condition resources of a policy that is retained, after setting
+// their deletion policy to Retain so the conditions in New Relic are left in place with the policy.
+func (r *PolicyReconciler) retainConditions(ctx context.Context, policy *nrv1.Policy) error {
+	if policy.Status.AppliedSpec == nil {
+		return nil
+	}
+
+	collectedErrors := new(customErrors.ErrorCollector)
+
+	for _, condition := range policy.Status.AppliedSpec.Conditions {
+		var child nrv1.StatusObject
+		switch nrv1.GetConditionType(condition) {
+		case "ApmAlertCondition":
+			returnedCondition := r.getApmConditionFromPolicyCondition(ctx, &condition)
+			child = &returnedCondition
+		case "NrqlAlertCondition":
+			returnedCondition := r.getNrqlConditionFromPolicyCondition(ctx, &condition)
+			child = &returnedCondition
+		default:
+			continue
+		}
+
+		if child.GetName() == "" {
+			continue
+		}
+
+		if err := markRetained(ctx, r.Client, child); err != nil {
+			collectedErrors.Collect(err)
+			continue
+		}
+
+		collectedErrors.Collect(r.deleteCondition(ctx, policy, &condition))
+	}
+
+	if len(*collectedErrors) > 0 {
+		return collectedErrors
+	}
+
 	return nil
 }
 
-func (r *PolicyReconciler) getNrqlConditionFromPolicyCondition(condition *nrv1.PolicyCondition) (nrqlAlertCondition nrv1.NrqlAlertCondition) {
-	defer r.txn.StartSegment("getNrqlConditionFromPolicyCondition").End()
+func (r *PolicyReconciler) getNrqlConditionFromPolicyCondition(ctx context.Context, condition *nrv1.PolicyCondition) (nrqlAlertCondition nrv1.NrqlAlertCondition) {
+	defer newrelic.FromContext(ctx).StartSegment("getNrqlConditionFromPolicyCondition").End()
 	r.Log.Info("nrql condition before retrieval", "condition", condition)
 	//throw away the error since empty conditions are expected
-	_ = r.Client.Get(r.ctx, condition.GetNamespace(), &nrqlAlertCondition)
+	_ = r.Client.Get(ctx, condition.GetNamespace(), &nrqlAlertCondition)
 	r.Log.Info("retrieved condition", "nrqlAlertCondition", nrqlAlertCondition, "namespace", condition.GetNamespace())
 
 	return
 }
 
-func (r *PolicyReconciler) getApmConditionFromPolicyCondition(condition *nrv1.PolicyCondition) (apmAlertCondition nrv1.ApmAlertCondition) {
-	defer r.txn.StartSegment("getApmConditionFromPolicyCondition").End()
+func (r *PolicyReconciler) getApmConditionFromPolicyCondition(ctx context.Context, condition *nrv1.PolicyCondition) (apmAlertCondition nrv1.ApmAlertCondition) {
+	defer newrelic.FromContext(ctx).StartSegment("getApmConditionFromPolicyCondition").End()
 	r.Log.Info("apm condition before retrieval", "condition", condition)
 	//throw away the error since empty conditions are expected
-	_ = r.Client.Get(r.ctx, condition.GetNamespace(), &apmAlertCondition)
+	_ = r.Client.Get(ctx, condition.GetNamespace(), &apmAlertCondition)
 	r.Log.Info("retrieved condition", "apmAlertCondition", apmAlertCondition, "namespace", condition.GetNamespace())
 
 	return
 }
 
-func (r *PolicyReconciler) updatePolicy(policy *nrv1.Policy) error {
-	defer r.txn.StartSegment("updatePolicy").End()
+func (r *PolicyReconciler) updatePolicy(ctx context.Context, alertsClient interfaces.NewRelicAlertsClient, accountID int, policy *nrv1.Policy) error {
+	defer newrelic.FromContext(ctx).StartSegment("updatePolicy").End()
 	r.Log.Info("updating policy", "PolicyName", policy.Name)
+	recordChanges(r.Recorder, policy, policy.Status.AppliedSpec, &policy.Spec)
 
 	//only update policy if policy fields have changed
 	APIPolicy := policy.Spec.APIPolicy()
 	APIPolicy.ID = policy.Status.PolicyID
+	APIPolicy.Name = r.Naming.remoteName(policy, policy.Spec.Name)
 	var updatedPolicy *alerts.Policy
-	var err error
 
-	if string(APIPolicy.IncidentPreference) != policy.Status.AppliedSpec.IncidentPreference || APIPolicy.Name != policy.Status.AppliedSpec.Name {
+	err := r.Ownership.ensureOwner(alertsClient, accountID, ownedPolicy, strconv.Itoa(policy.Status.PolicyID), policy)
+	if err != nil {
+		return err
+	}
+
+	if string(APIPolicy.IncidentPreference) != policy.Status.AppliedSpec.IncidentPreference || APIPolicy.Name != r.Naming.remoteName(policy, policy.Status.AppliedSpec.Name) {
 		r.Log.Info("need to update alert policy via New Relic API",
 			"Alert Policy Name", APIPolicy.Name,
 			"incident preference ", policy.Status.AppliedSpec.IncidentPreference,
 		)
-		updatedPolicy, err = r.Alerts.UpdatePolicy(APIPolicy)
+		updatedPolicy, err = alertsClient.UpdatePolicy(APIPolicy)
 		if err != nil {
 			r.Log.Error(err, "failed to update policy via New Relic API",
 				"policyId", policy.Status.PolicyID,
 				"region", policy.Spec.Region,
-				"Api Key", interfaces.PartialAPIKey(r.apiKey),
 			)
 
 			return err
 		}
 		policy.Status.PolicyID = updatedPolicy.ID
+		r.Recorder.Eventf(policy, v1.EventTypeNormal, eventReasonUpdated, "Updated New Relic policy %d", updatedPolicy.ID)
 	}
 
-	errConditions := r.createOrUpdateConditions(policy)
+	errConditions := r.createOrUpdateConditions(ctx, policy)
 	if errConditions != nil {
 		r.Log.Error(errConditions, "error creating or updating conditions")
 
@@ -499,20 +633,11 @@ func (r *PolicyReconciler) updatePolicy(policy *nrv1.Policy) error {
 	}
 	r.Log.Info("policySpecx before update", "policy.Spec", policy.Spec)
 
-	policy.Status.AppliedSpec = &policy.Spec
-
-	err = r.Client.Update(r.ctx, policy)
-	if err != nil {
-		r.Log.Error(err, "failed to update policy status", "name", policy.Name)
-
-		return err
-	}
-
 	return nil
 }
 
-func (r *PolicyReconciler) deletePolicy(ctx context.Context, policy *nrv1.Policy, deleteFinalizer string) (ctrl.Result, error) {
-	defer r.txn.StartSegment("deletePolicy").End()
+func (r *PolicyReconciler) deletePolicy(ctx context.Context, alertsClient interfaces.NewRelicAlertsClient, accountID int, policy *nrv1.Policy, deleteFinalizer string) (ctrl.Result, error) {
+	defer newrelic.FromContext(ctx).StartSegment("deletePolicy").End()
 	// The object is being deleted
 	if containsString(policy.Finalizers, deleteFinalizer) {
 		// catch invalid state
@@ -523,7 +648,7 @@ func (r *PolicyReconciler) deletePolicy(ctx context.Context, policy *nrv1.Policy
 			// our finalizer is present, so lets handle any external dependency
 			collectedErrors := new(customErrors.ErrorCollector)
 			for _, condition := range policy.Status.AppliedSpec.Conditions {
-				err := r.deleteCondition(&condition)
+				err := r.deleteCondition(ctx, policy, &condition)
 				if err != nil {
 					r.Log.Error(err, "error deleting condition resources")
 					collectedErrors.Collect(err)
@@ -534,15 +659,14 @@ func (r *PolicyReconciler) deletePolicy(ctx context.Context, policy *nrv1.Policy
 				return ctrl.Result{}, collectedErrors
 			}
 
-			if err := r.deleteNewRelicAlertPolicy(policy); err != nil {
+			if err := r.deleteNewRelicAlertPolicy(ctx, alertsClient, accountID, policy); err != nil {
 				// if fail to delete the external dependency here, return with error
 				// so that it can be retried
 				r.Log.Error(err, "Failed to delete Alert Policy via New Relic API",
 					"policyId", policy.Status.PolicyID,
 					"region", policy.Spec.Region,
-					"Api Key", interfaces.PartialAPIKey(r.apiKey),
 				)
-				return ctrl.Result{}, err
+				return retryLater(ctrl.Result{}, err)
 			}
 			// remove our finalizer from the list and update it.
 			r.Log.Info("New Relic Alert policy deleted, Removing finalizer")
@@ -564,70 +688,123 @@ func (r *PolicyReconciler) deletePolicy(ctx context.Context, policy *nrv1.Policy
 func (r *PolicyReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&nrv1.Policy{}).
+		Watches(&source.Kind{Type: &v1.Namespace{}}, enqueueNamespaceDependents(mgr.GetClient(), &nrv1.PolicyList{})).
+		WithOptions(controller.Options{MaxConcurrentReconciles: r.MaxConcurrentReconciles}).
 		Complete(r)
 }
 
-func (r *PolicyReconciler) checkForExistingPolicy(policy *nrv1.Policy) {
-	defer r.txn.StartSegment("checkForExistingPolicy").End()
-	if policy.Status.PolicyID == 0 {
-		r.Log.Info("Checking for existing policy", "policy", policy.Name, "policyName", policy.Spec.Name)
-		//if no policyId, get list of policies and compare name
-		alertParams := &alerts.ListPoliciesParams{
-			Name: policy.Spec.Name,
+func (r *PolicyReconciler) checkForExistingPolicy(ctx context.Context, alertsClient interfaces.NewRelicAlertsClient, accountID int, policy *nrv1.Policy) error {
+	defer newrelic.FromContext(ctx).StartSegment("checkForExistingPolicy").End()
+	if policy.Status.PolicyID != 0 {
+		return nil
+	}
+
+	switch nrv1.GetAdoptionPolicy(policy.Spec.Adoption, policy.Spec.ImportID) {
+	case nrv1.AdoptionNever:
+		return nil
+	case nrv1.AdoptionByID:
+		r.Log.Info("Importing existing policy", "policy", policy.Name, "policyId", policy.Spec.ImportID)
+
+		policyID, err := strconv.Atoi(policy.Spec.ImportID)
+		if err != nil {
+			return err
 		}
-		existingPolicies, err := r.Alerts.ListPolicies(alertParams)
+
+		existingPolicy, err := alertsClient.GetPolicy(policyID)
 		if err != nil {
-			r.Log.Error(err, "failed to get list of policies from New Relic API",
-				"policyId", policy.Status.PolicyID,
-				"region", policy.Spec.Region,
-				"Api Key", interfaces.PartialAPIKey(r.apiKey),
-			)
-		} else {
-			for _, existingPolicy := range existingPolicies {
-				if existingPolicy.Name == policy.Spec.Name {
-					r.Log.Info("Matched on existing policy, updating PolicyId", "policyId", existingPolicy.ID)
-					policy.Status.PolicyID = existingPolicy.ID
-					break
-				}
-			}
+			return err
+		}
+
+		if err := r.Ownership.checkImportable(alertsClient, accountID, ownedPolicy, policy.Spec.ImportID); err != nil {
+			return err
+		}
+
+		policy.Status.PolicyID = existingPolicy.ID
+		r.Recorder.Eventf(policy, v1.EventTypeNormal, eventReasonImported, "Imported existing New Relic policy %d", existingPolicy.ID)
+
+		return r.Ownership.claim(alertsClient, accountID, ownedPolicy, policy.Spec.ImportID, policy)
+	}
+
+	r.Log.Info("Checking for existing policy", "policy", policy.Name, "policyName", policy.Spec.Name)
+	//if no policyId, get list of policies and compare name
+	alertParams := &alerts.ListPoliciesParams{
+		Name: r.Naming.remoteName(policy, policy.Spec.Name),
+	}
+	existingPolicies, err := alertsClient.ListPolicies(alertParams)
+	if err != nil {
+		r.Log.Error(err, "failed to get list of policies from New Relic API",
+			"policyId", policy.Status.PolicyID,
+			"region", policy.Spec.Region,
+		)
+
+		return err
+	}
+
+	for _, existingPolicy := range existingPolicies {
+		if existingPolicy.Name != r.Naming.remoteName(policy, policy.Spec.Name) {
+			continue
+		}
+
+		unowned, err := r.Ownership.isUnowned(alertsClient, accountID, ownedPolicy, strconv.Itoa(existingPolicy.ID), policy)
+		if err != nil {
+			return err
+		}
+
+		if !unowned {
+			r.Log.Info("Skipping policy with the same name managed by another resource", "policyId", existingPolicy.ID)
+			continue
 		}
+
+		r.Log.Info("Matched on existing policy, updating PolicyId", "policyId", existingPolicy.ID)
+		policy.Status.PolicyID = existingPolicy.ID
+		r.Recorder.Eventf(policy, v1.EventTypeNormal, eventReasonAdopted, "Adopted existing New Relic policy %d", existingPolicy.ID)
+
+		return r.Ownership.claim(alertsClient, accountID, ownedPolicy, strconv.Itoa(existingPolicy.ID), policy)
 	}
+
+	return nil
 }
 
-func (r *PolicyReconciler) deleteNewRelicAlertPolicy(policy *nrv1.Policy) error {
-	defer r.txn.StartSegment("deleteNewRelicAlertPolicy").End()
+func (r *PolicyReconciler) deleteNewRelicAlertPolicy(ctx context.Context, alertsClient interfaces.NewRelicAlertsClient, accountID int, policy *nrv1.Policy) error {
+	defer newrelic.FromContext(ctx).StartSegment("deleteNewRelicAlertPolicy").End()
 	r.Log.Info("Deleting policy", "policyName", policy.Spec.Name)
-	_, err := r.Alerts.DeletePolicy(policy.Status.PolicyID)
+
+	skip, err := r.Ownership.skipDelete(r.Recorder, alertsClient, accountID, ownedPolicy, strconv.Itoa(policy.Status.PolicyID), policy)
+	if skip || err != nil {
+		return err
+	}
+
+	_, err = alertsClient.DeletePolicy(policy.Status.PolicyID)
 	if err != nil {
 		r.Log.Error(err, "Error deleting policy via New Relic API",
 			"policyId", policy.Status.PolicyID,
 			"region", policy.Spec.Region,
-			"Api Key", interfaces.PartialAPIKey(r.apiKey),
 		)
 
 		return err
 	}
 
+	r.Recorder.Eventf(policy, v1.EventTypeNormal, eventReasonDeleted, "Deleted New Relic policy %d", policy.Status.PolicyID)
+
+	if err := r.Ownership.release(alertsClient, accountID, ownedPolicy, strconv.Itoa(policy.Status.PolicyID)); err != nil {
+		r.Log.Error(err, "failed to remove ownership marker of deleted policy", "policyId", policy.Status.PolicyID)
+	}
+
 	return nil
 }
 
-func (r *PolicyReconciler) getAPIKeyOrSecret(policy nrv1.Policy) (string, error) {
-	defer r.txn.StartSegment("getAPIKeyOrSecret").End()
-	if policy.Spec.APIKey != "" {
-		return policy.Spec.APIKey, nil
-	}
+func (r *PolicyReconciler) getCredentials(ctx context.Context, policy nrv1.Policy) (nrv1.ResolvedCredentials, error) {
+	defer newrelic.FromContext(ctx).StartSegment("getCredentials").End()
 
-	if policy.Spec.APIKeySecret != (nrv1.NewRelicAPIKeySecret{}) {
-		key := types.NamespacedName{Namespace: policy.Spec.APIKeySecret.Namespace, Name: policy.Spec.APIKeySecret.Name}
-		var apiKeySecret v1.Secret
-		getErr := r.Client.Get(context.Background(), key, &apiKeySecret)
-		if getErr != nil {
-			r.Log.Error(getErr, "Failed to retrieve secret", "secret", apiKeySecret)
-			return "", getErr
-		}
+	creds, err := policy.Spec.Credentials().Resolve(ctx, r.Client, policy.Namespace)
+	if err != nil {
+		r.Log.Error(err, "Failed to resolve credentials")
+		return creds, err
+	}
 
-		return string(apiKeySecret.Data[policy.Spec.APIKeySecret.KeyName]), nil
+	if err := ownAPIKeySecret(ctx, r.Client, &policy, policy.Spec.APIKeySecret); err != nil {
+		r.Log.Error(err, "Failed to add the resource to the owners of its API key secret")
 	}
 
-	return "", nil
+	return creds, nil
 }
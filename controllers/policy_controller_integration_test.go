@@ -8,6 +8,7 @@ import (
 
 	newrelic "github.com/newrelic/go-agent/v3/newrelic"
 	"github.com/newrelic/newrelic-client-go/pkg/alerts"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	. "github.com/onsi/ginkgo"
@@ -61,6 +62,7 @@ var _ = Describe("policy reconciliation", func() {
 		r = &PolicyReconciler{
 			Client:          k8sClient,
 			Log:             logf.Log,
+			Recorder:        record.NewFakeRecorder(100),
 			AlertClientFunc: fakeAlertFunc,
 			NewRelicAgent:   newRelicAgent,
 		}
@@ -97,6 +99,7 @@ var _ = Describe("policy reconciliation", func() {
 			r = &PolicyReconciler{
 				Client:          k8sClient,
 				Log:             logf.Log,
+				Recorder:        record.NewFakeRecorder(100),
 				AlertClientFunc: fakeAlertFunc,
 				NewRelicAgent:   newrelic.Application{},
 			}
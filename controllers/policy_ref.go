@@ -0,0 +1,149 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	kErr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	nrv1 "github.com/newrelic/newrelic-kubernetes-operator/api/v1"
+)
+
+const (
+	// policyIndexField indexes conditions and channels by the "namespace/name" of the AlertsPolicies
+	// they refer to.
+	policyIndexField = "nr.k8s.newrelic.com/policy"
+	// policyRefRetryInterval is how often a condition waiting for the policy it refers to checks
+	// the policy again, in case an event of the policy was missed.
+	policyRefRetryInterval = 30 * time.Second
+)
+
+// policyReferences returns the "namespace/name" keys of the AlertsPolicies obj refers to: the
+// policy of a condition, or the policies a channel is linked to.
+func policyReferences(obj runtime.Object) []string {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return nil
+	}
+
+	var refs []*nrv1.AlertsPolicyReference
+
+	switch o := obj.(type) {
+	case *nrv1.AlertsNrqlCondition:
+		refs = append(refs, o.Spec.PolicyRef)
+	case *nrv1.AlertsAPMCondition:
+		refs = append(refs, o.Spec.PolicyRef)
+	case *nrv1.AlertsChannel:
+		for _, policy := range o.Spec.Links.PolicyKubernetesObjects {
+			refs = append(refs, &nrv1.AlertsPolicyReference{Name: policy.Name, Namespace: policy.Namespace})
+		}
+	}
+
+	var keys []string
+
+	for _, ref := range refs {
+		if ref == nil {
+			continue
+		}
+
+		key := policyRefKey(accessor.GetNamespace(), ref)
+		keys = append(keys, namespacedKey(key.Namespace, key.Name))
+	}
+
+	return keys
+}
+
+// policyRefKey returns the key of the AlertsPolicy ref refers to from a resource in namespace.
+func policyRefKey(namespace string, ref *nrv1.AlertsPolicyReference) types.NamespacedName {
+	if ref.Namespace != "" {
+		namespace = ref.Namespace
+	}
+
+	return types.NamespacedName{Namespace: namespace, Name: ref.Name}
+}
+
+// indexPolicyReferences indexes the resources of the kind of obj by the AlertsPolicies they refer to.
+func indexPolicyReferences(mgr ctrl.Manager, obj runtime.Object) error {
+	return mgr.GetFieldIndexer().IndexField(context.Background(), obj, policyIndexField, policyReferences)
+}
+
+// enqueuePolicyDependents returns a handler for AlertsPolicy events that enqueues the items of
+// list referring to the policy. The kind of list has to be indexed with indexPolicyReferences.
+func enqueuePolicyDependents(c client.Client, list runtime.Object) handler.EventHandler {
+	return &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(func(policy handler.MapObject) []reconcile.Request {
+			return listDependents(c, list, policyIndexField, namespacedKey(policy.Meta.GetNamespace(), policy.Meta.GetName()))
+		}),
+	}
+}
+
+// resolvePolicyRef sets the existing policy ID of spec, the spec of a condition in namespace, to the
+// ID of the AlertsPolicy its policy ref refers to. While the policy doesn't exist in New Relic yet
+// the ID is left as it is and the returned message says what the condition waits for. The ID is
+// only set for the reconcile, it is recorded in the applied spec in the status but not stored in
+// the spec, see withoutResolvedPolicyRef.
+func resolvePolicyRef(ctx context.Context, c client.Client, namespace string, spec *nrv1.AlertsGenericConditionSpec) (string, error) {
+	if spec.PolicyRef == nil {
+		return "", nil
+	}
+
+	key := policyRefKey(namespace, spec.PolicyRef)
+
+	var policy nrv1.AlertsPolicy
+
+	err := c.Get(ctx, key, &policy)
+	if kErr.IsNotFound(err) {
+		return fmt.Sprintf("waiting for AlertsPolicy %s to be created", key), nil
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	if !policy.DeletionTimestamp.IsZero() || policy.Status.PolicyID == "" {
+		return fmt.Sprintf("waiting for AlertsPolicy %s to be created in New Relic", key), nil
+	}
+
+	spec.ExistingPolicyID = policy.Status.PolicyID
+
+	return "", nil
+}
+
+// withoutResolvedPolicyRef returns obj as it is stored: a condition with a policy ref is stored
+// without the existing policy ID resolvePolicyRef set, so a policy created again with a new ID is
+// never competed with by the ID of the old one. Other objects are returned as they are.
+func withoutResolvedPolicyRef(obj nrv1.StatusObject) nrv1.StatusObject {
+	switch condition := obj.(type) {
+	case *nrv1.AlertsNrqlCondition:
+		if condition.Spec.PolicyRef != nil && condition.Spec.ExistingPolicyID != "" {
+			stored := condition.DeepCopy()
+			stored.Spec.ExistingPolicyID = ""
+
+			return stored
+		}
+	case *nrv1.AlertsAPMCondition:
+		if condition.Spec.PolicyRef != nil && condition.Spec.ExistingPolicyID != "" {
+			stored := condition.DeepCopy()
+			stored.Spec.ExistingPolicyID = ""
+
+			return stored
+		}
+	}
+
+	return obj
+}
+
+// movedPolicy returns true if the condition with the given ID was created in the policy with the
+// applied ID and is now to be in another policy. Conditions can't be moved from one policy to
+// another in New Relic, they are deleted and created again in the new policy.
+func movedPolicy(conditionID, appliedPolicyID, policyID string) bool {
+	return conditionID != "" && conditionID != "0" && appliedPolicyID != "" && appliedPolicyID != policyID
+}
@@ -0,0 +1,159 @@
+package controllers
+
+import (
+	"context"
+	"sort"
+	"strconv"
+
+	"github.com/newrelic/go-agent/v3/newrelic"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	nrv1 "github.com/newrelic/newrelic-kubernetes-operator/api/v1"
+)
+
+// selectedPolicyIDs returns the IDs of the AlertsPolicies selected by the policy selector of the
+// channel, read from the cache. Policies not created in New Relic yet are left out, the channel is
+// linked to them once they are.
+func selectedPolicyIDs(ctx context.Context, c client.Client, alertsChannel *nrv1.AlertsChannel) ([]int, error) {
+	if alertsChannel.Spec.PolicySelector == nil {
+		return nil, nil
+	}
+
+	defer newrelic.FromContext(ctx).StartSegment("selectedPolicyIDs").End()
+
+	policySelector, namespaceSelector, err := alertsChannel.Spec.PolicySelector.Selectors()
+	if err != nil {
+		return nil, err
+	}
+
+	namespaces := []string{alertsChannel.Namespace}
+
+	if namespaceSelector != nil {
+		var namespaceList v1.NamespaceList
+		if err := c.List(ctx, &namespaceList, client.MatchingLabelsSelector{Selector: namespaceSelector}); err != nil {
+			return nil, err
+		}
+
+		namespaces = namespaces[:0]
+		for _, namespace := range namespaceList.Items {
+			namespaces = append(namespaces, namespace.Name)
+		}
+	}
+
+	policyIDs := []int{}
+
+	for _, namespace := range namespaces {
+		var policyList nrv1.AlertsPolicyList
+		if err := c.List(ctx, &policyList, client.InNamespace(namespace), client.MatchingLabelsSelector{Selector: policySelector}); err != nil {
+			return nil, err
+		}
+
+		for _, policy := range policyList.Items {
+			if !policy.DeletionTimestamp.IsZero() {
+				continue
+			}
+
+			policyID, err := strconv.Atoi(policy.Status.PolicyID)
+			if err != nil || policyID == 0 {
+				continue
+			}
+
+			policyIDs = append(policyIDs, policyID)
+		}
+	}
+
+	sort.Ints(policyIDs)
+
+	return policyIDs, nil
+}
+
+// selectsPolicy returns true if the policy selector of the channel selects policy, by its labels
+// and the labels of its namespace.
+func selectsPolicy(ctx context.Context, c client.Client, alertsChannel *nrv1.AlertsChannel, policy metav1.Object) bool {
+	if alertsChannel.Spec.PolicySelector == nil {
+		return false
+	}
+
+	policySelector, namespaceSelector, err := alertsChannel.Spec.PolicySelector.Selectors()
+	if err != nil || !policySelector.Matches(labels.Set(policy.GetLabels())) {
+		return false
+	}
+
+	if namespaceSelector == nil {
+		return policy.GetNamespace() == alertsChannel.Namespace
+	}
+
+	var namespace v1.Namespace
+	if err := c.Get(ctx, types.NamespacedName{Name: policy.GetNamespace()}, &namespace); err != nil {
+		return false
+	}
+
+	return namespaceSelector.Matches(labels.Set(namespace.Labels))
+}
+
+// enqueueSelectingChannels returns a handler for AlertsPolicy events that enqueues the channels
+// selecting the policy. Updates are mapped with both the old and the new labels of the policy, so
+// a channel no longer selecting the policy is enqueued to unlink it.
+func enqueueSelectingChannels(c client.Client) handler.EventHandler {
+	return &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(func(policy handler.MapObject) []reconcile.Request {
+			ctx := context.Background()
+
+			var channelList nrv1.AlertsChannelList
+			if err := c.List(ctx, &channelList); err != nil {
+				ctrl.Log.Error(err, "unable to list the channels selecting a policy", "policy", namespacedKey(policy.Meta.GetNamespace(), policy.Meta.GetName()))
+				return nil
+			}
+
+			var requests []reconcile.Request
+
+			for i := range channelList.Items {
+				alertsChannel := &channelList.Items[i]
+
+				if selectsPolicy(ctx, c, alertsChannel, policy.Meta) {
+					requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{
+						Namespace: alertsChannel.Namespace,
+						Name:      alertsChannel.Name,
+					}})
+				}
+			}
+
+			return requests
+		}),
+	}
+}
+
+// enqueueNamespaceSelectingChannels returns a handler for Namespace events that enqueues the
+// channels selecting the namespaces of their policies by labels, so they follow changes to the
+// labels of namespaces.
+func enqueueNamespaceSelectingChannels(c client.Client) handler.EventHandler {
+	return &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(func(namespace handler.MapObject) []reconcile.Request {
+			var channelList nrv1.AlertsChannelList
+			if err := c.List(context.Background(), &channelList); err != nil {
+				ctrl.Log.Error(err, "unable to list the channels selecting namespaces", "namespace", namespace.Meta.GetName())
+				return nil
+			}
+
+			var requests []reconcile.Request
+
+			for _, alertsChannel := range channelList.Items {
+				if alertsChannel.Spec.PolicySelector != nil && alertsChannel.Spec.PolicySelector.NamespaceSelector != nil {
+					requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{
+						Namespace: alertsChannel.Namespace,
+						Name:      alertsChannel.Name,
+					}})
+				}
+			}
+
+			return requests
+		}),
+	}
+}
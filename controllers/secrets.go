@@ -0,0 +1,250 @@
+package controllers
+
+import (
+	"context"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	toolscache "k8s.io/client-go/tools/cache"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	nrv1 "github.com/newrelic/newrelic-kubernetes-operator/api/v1"
+	"github.com/newrelic/newrelic-kubernetes-operator/interfaces"
+)
+
+// EvictClientsOnSecretChange removes the clients for the API keys held in a secret from
+// pool when the keys are changed or the secret is deleted.
+func EvictClientsOnSecretChange(mgr ctrl.Manager, pool *interfaces.ClientPool) error {
+	informer, err := mgr.GetCache().GetInformer(context.Background(), &v1.Secret{})
+	if err != nil {
+		return err
+	}
+
+	informer.AddEventHandler(toolscache.ResourceEventHandlerFuncs{
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			oldSecret, oldOk := oldObj.(*v1.Secret)
+			newSecret, newOk := newObj.(*v1.Secret)
+			if oldOk && newOk {
+				evictChangedAPIKeys(pool, oldSecret, newSecret)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(toolscache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+
+			if secret, ok := obj.(*v1.Secret); ok {
+				evictChangedAPIKeys(pool, secret, nil)
+			}
+		},
+	})
+
+	return nil
+}
+
+// evictChangedAPIKeys evicts the clients for the values in oldSecret that are not in
+// newSecret anymore, newSecret is nil if the secret was deleted.
+// Values that were never used as an API key are not in the pool, so evicting them does nothing.
+func evictChangedAPIKeys(pool *interfaces.ClientPool, oldSecret, newSecret *v1.Secret) {
+	for name, value := range oldSecret.Data {
+		if newSecret != nil && string(newSecret.Data[name]) == string(value) {
+			continue
+		}
+
+		pool.Evict(string(value))
+	}
+}
+
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;patch
+
+// ownAPIKeySecret adds owner to the owners of the secret its API key was moved into by the
+// defaulting webhooks, so the secret is garbage collected once no resource using it is left.
+// The webhooks can't set the owner themselves, the resource doesn't exist yet when they run.
+// Secrets not managed by the operator, and secrets in other namespaces, are left alone.
+func ownAPIKeySecret(ctx context.Context, c client.Client, owner runtime.Object, ref nrv1.NewRelicAPIKeySecret) error {
+	ownerMeta, err := meta.Accessor(owner)
+	if err != nil {
+		return err
+	}
+
+	if ref.Name == "" || ref.Namespace != ownerMeta.GetNamespace() || ownerMeta.GetUID() == "" || ownerMeta.GetDeletionTimestamp() != nil {
+		return nil
+	}
+
+	var secret v1.Secret
+	if err := c.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, &secret); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+
+	if secret.Labels[nrv1.ManagedByLabel] != nrv1.ManagedByOperator {
+		return nil
+	}
+
+	for _, ownerRef := range secret.OwnerReferences {
+		if ownerRef.UID == ownerMeta.GetUID() {
+			return nil
+		}
+	}
+
+	secret.OwnerReferences = append(secret.OwnerReferences, metav1.OwnerReference{
+		APIVersion: nrv1.GroupVersion.String(),
+		Kind:       kindOf(owner),
+		Name:       ownerMeta.GetName(),
+		UID:        ownerMeta.GetUID(),
+	})
+
+	return c.Update(ctx, &secret)
+}
+
+const (
+	// secretsIndexField indexes resources by the "namespace/name" of every secret they read.
+	secretsIndexField = "nr.k8s.newrelic.com/secrets"
+	// accountIndexField indexes resources by the "namespace/name" of the NewRelicAccount they use.
+	accountIndexField = "nr.k8s.newrelic.com/account"
+)
+
+func namespacedKey(namespace, name string) string {
+	return namespace + "/" + name
+}
+
+// secretReferences returns the secrets obj reads when it is reconciled and the
+// NewRelicAccount it uses, if any, as "namespace/name" keys.
+func secretReferences(obj runtime.Object) (secrets []string, account string) {
+	var (
+		creds     nrv1.Credentials
+		namespace string
+	)
+
+	switch o := obj.(type) {
+	case *nrv1.AlertsPolicy:
+		creds, namespace = o.Spec.Credentials(), o.Namespace
+	case *nrv1.AlertsNrqlCondition:
+		creds, namespace = o.Spec.Credentials(), o.Namespace
+	case *nrv1.AlertsAPMCondition:
+		creds, namespace = o.Spec.Credentials(), o.Namespace
+	case *nrv1.AlertsChannel:
+		creds, namespace = o.Spec.Credentials(), o.Namespace
+
+		for _, header := range o.Spec.Configuration.Headers {
+			if header.Value == "" && header.Secret != "" {
+				secrets = append(secrets, namespacedKey(header.Namespace, header.Secret))
+			}
+		}
+	case *nrv1.NewRelicAccount:
+		creds, namespace = o.Credentials(), o.Namespace
+	default:
+		return nil, ""
+	}
+
+	if creds.APIKey == "" && creds.APIKeySecret.Name != "" {
+		secrets = append(secrets, namespacedKey(creds.APIKeySecret.Namespace, creds.APIKeySecret.Name))
+	}
+
+	if creds.AccountRef != nil {
+		accountNamespace := creds.AccountRef.Namespace
+		if accountNamespace == "" {
+			accountNamespace = namespace
+		}
+
+		account = namespacedKey(accountNamespace, creds.AccountRef.Name)
+	}
+
+	return secrets, account
+}
+
+// indexSecretReferences adds the secrets and account indexes for the kind of obj to the cache of mgr.
+func indexSecretReferences(mgr ctrl.Manager, obj runtime.Object) error {
+	indexer := mgr.GetFieldIndexer()
+
+	err := indexer.IndexField(context.Background(), obj, secretsIndexField, func(o runtime.Object) []string {
+		secrets, _ := secretReferences(o)
+		return secrets
+	})
+	if err != nil {
+		return err
+	}
+
+	return indexer.IndexField(context.Background(), obj, accountIndexField, func(o runtime.Object) []string {
+		if _, account := secretReferences(o); account != "" {
+			return []string{account}
+		}
+
+		return nil
+	})
+}
+
+// enqueueSecretDependents returns a handler for secret events that enqueues the items of
+// list reading the secret, either directly or through the NewRelicAccount they use.
+// The kind of list has to be indexed with indexSecretReferences.
+func enqueueSecretDependents(c client.Client, list runtime.Object) handler.EventHandler {
+	return &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(func(secret handler.MapObject) []reconcile.Request {
+			key := namespacedKey(secret.Meta.GetNamespace(), secret.Meta.GetName())
+			requests := listDependents(c, list, secretsIndexField, key)
+
+			var accounts nrv1.NewRelicAccountList
+			if err := c.List(context.Background(), &accounts, client.MatchingFields{secretsIndexField: key}); err != nil {
+				ctrl.Log.Error(err, "unable to list the accounts reading a secret", "secret", key)
+				return requests
+			}
+
+			for _, account := range accounts.Items {
+				requests = append(requests, listDependents(c, list, accountIndexField, namespacedKey(account.Namespace, account.Name))...)
+			}
+
+			return requests
+		}),
+	}
+}
+
+// enqueueAccountDependents returns a handler for NewRelicAccount events that enqueues the
+// items of list using the account.
+func enqueueAccountDependents(c client.Client, list runtime.Object) handler.EventHandler {
+	return &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(func(account handler.MapObject) []reconcile.Request {
+			return listDependents(c, list, accountIndexField, namespacedKey(account.Meta.GetNamespace(), account.Meta.GetName()))
+		}),
+	}
+}
+
+// listDependents returns a request for every item of list whose field index holds key.
+func listDependents(c client.Client, list runtime.Object, field, key string) []reconcile.Request {
+	list = list.DeepCopyObject()
+
+	if err := c.List(context.Background(), list, client.MatchingFields{field: key}); err != nil {
+		ctrl.Log.Error(err, "unable to list the resources depending on an object", "field", field, "object", key)
+		return nil
+	}
+
+	return requestsFor(list)
+}
+
+// requestsFor returns a request for every item of list.
+func requestsFor(list runtime.Object) []reconcile.Request {
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(items))
+
+	for _, item := range items {
+		itemMeta, err := meta.Accessor(item)
+		if err != nil {
+			continue
+		}
+
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{
+			Namespace: itemMeta.GetNamespace(),
+			Name:      itemMeta.GetName(),
+		}})
+	}
+
+	return requests
+}
@@ -0,0 +1,169 @@
+package controllers
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	nrv1 "github.com/newrelic/newrelic-kubernetes-operator/api/v1"
+	"github.com/newrelic/newrelic-kubernetes-operator/interfaces"
+	"github.com/newrelic/newrelic-kubernetes-operator/interfaces/interfacesfakes"
+)
+
+var _ = Describe("evictChangedAPIKeys", func() {
+	var (
+		pool   *interfaces.ClientPool
+		secret *v1.Secret
+	)
+
+	BeforeEach(func() {
+		pool = interfaces.NewClientPool(func(interfaces.ClientConfig) (interfaces.NewRelicAlertsClient, error) {
+			return &interfacesfakes.FakeNewRelicAlertsClient{}, nil
+		})
+		secret = &v1.Secret{
+			Data: map[string][]byte{
+				"api-key":   []byte("old-api-key"),
+				"other-key": []byte("unchanged-api-key"),
+			},
+		}
+
+		_, _ = pool.AlertsClient("old-api-key", "US")
+		_, _ = pool.AlertsClient("unchanged-api-key", "US")
+	})
+
+	It("evicts the clients for changed keys", func() {
+		updated := secret.DeepCopy()
+		updated.Data["api-key"] = []byte("new-api-key")
+
+		evictChangedAPIKeys(pool, secret, updated)
+
+		Expect(pool.Len()).To(Equal(1))
+	})
+
+	It("keeps the clients when the keys did not change", func() {
+		evictChangedAPIKeys(pool, secret, secret.DeepCopy())
+
+		Expect(pool.Len()).To(Equal(2))
+	})
+
+	It("evicts all clients of a deleted secret", func() {
+		evictChangedAPIKeys(pool, secret, nil)
+
+		Expect(pool.Len()).To(Equal(0))
+	})
+})
+
+var _ = Describe("ownAPIKeySecret", func() {
+	var (
+		ctx    context.Context
+		c      client.Client
+		policy *nrv1.AlertsPolicy
+		secret *v1.Secret
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		s := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(s)).To(Succeed())
+		c = fake.NewFakeClientWithScheme(s)
+
+		secret = &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "newrelic-api-key-0123456789abcdef",
+				Namespace: "team",
+				Labels:    map[string]string{nrv1.ManagedByLabel: nrv1.ManagedByOperator},
+			},
+		}
+		Expect(c.Create(ctx, secret)).To(Succeed())
+
+		policy = &nrv1.AlertsPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "my-policy", Namespace: "team", UID: "policy-uid"},
+		}
+		policy.Spec.APIKeySecret = nrv1.NewRelicAPIKeySecret{Name: secret.Name, Namespace: "team", KeyName: "api-key"}
+	})
+
+	It("adds the resource to the owners of its managed secret once", func() {
+		Expect(ownAPIKeySecret(ctx, c, policy, policy.Spec.APIKeySecret)).To(Succeed())
+		Expect(ownAPIKeySecret(ctx, c, policy, policy.Spec.APIKeySecret)).To(Succeed())
+
+		Expect(c.Get(ctx, types.NamespacedName{Namespace: "team", Name: secret.Name}, secret)).To(Succeed())
+		Expect(secret.OwnerReferences).To(Equal([]metav1.OwnerReference{
+			{APIVersion: "nr.k8s.newrelic.com/v1", Kind: "AlertsPolicy", Name: "my-policy", UID: "policy-uid"},
+		}))
+	})
+
+	It("leaves secrets not managed by the operator alone", func() {
+		secret.Labels = nil
+		Expect(c.Update(ctx, secret)).To(Succeed())
+
+		Expect(ownAPIKeySecret(ctx, c, policy, policy.Spec.APIKeySecret)).To(Succeed())
+
+		Expect(c.Get(ctx, types.NamespacedName{Namespace: "team", Name: secret.Name}, secret)).To(Succeed())
+		Expect(secret.OwnerReferences).To(BeEmpty())
+	})
+})
+
+var _ = Describe("secretReferences", func() {
+	It("returns the API key secret and the account of a condition", func() {
+		condition := &nrv1.AlertsNrqlCondition{
+			ObjectMeta: metav1.ObjectMeta{Name: "my-condition", Namespace: "team"},
+		}
+		condition.Spec.APIKeySecret = nrv1.NewRelicAPIKeySecret{Name: "api-key", Namespace: "secrets", KeyName: "key"}
+		condition.Spec.AccountRef = &nrv1.NewRelicAccountReference{Name: "production"}
+
+		secrets, account := secretReferences(condition)
+		Expect(secrets).To(ConsistOf("secrets/api-key"))
+		Expect(account).To(Equal("team/production"))
+	})
+
+	It("ignores the API key secret when an API key is set", func() {
+		policy := &nrv1.AlertsPolicy{
+			Spec: nrv1.AlertsPolicySpec{
+				APIKey:       "api-key",
+				APIKeySecret: nrv1.NewRelicAPIKeySecret{Name: "api-key", Namespace: "secrets", KeyName: "key"},
+			},
+		}
+
+		secrets, account := secretReferences(policy)
+		Expect(secrets).To(BeEmpty())
+		Expect(account).To(BeEmpty())
+	})
+
+	It("returns the header secrets of a channel", func() {
+		channel := &nrv1.AlertsChannel{
+			Spec: nrv1.AlertsChannelSpec{
+				APIKey: "api-key",
+				Configuration: nrv1.AlertsChannelConfiguration{
+					Headers: []nrv1.ChannelHeader{
+						{Name: "TOKEN", Secret: "webhook-token", Namespace: "default", KeyName: "token"},
+						{Name: "STATIC", Value: "value"},
+					},
+				},
+			},
+		}
+
+		secrets, _ := secretReferences(channel)
+		Expect(secrets).To(ConsistOf("default/webhook-token"))
+	})
+
+	It("returns the secret of an account in the namespace of the account by default", func() {
+		account := &nrv1.NewRelicAccount{
+			ObjectMeta: metav1.ObjectMeta{Name: "production", Namespace: "accounts"},
+			Spec: nrv1.NewRelicAccountSpec{
+				APIKeySecret: nrv1.NewRelicAPIKeySecret{Name: "api-key", KeyName: "key"},
+			},
+		}
+
+		secrets, _ := secretReferences(account)
+		Expect(secrets).To(ConsistOf("accounts/api-key"))
+	})
+})
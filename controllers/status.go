@@ -0,0 +1,122 @@
+package controllers
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	nrv1 "github.com/newrelic/newrelic-kubernetes-operator/api/v1"
+	customErrors "github.com/newrelic/newrelic-kubernetes-operator/errors"
+	"github.com/newrelic/newrelic-kubernetes-operator/interfaces"
+)
+
+// updateResource writes obj, including its status, back to the API server.
+// Nothing is written when obj does not differ from original, the object as it was
+// read at the start of the reconcile.
+//
+// None of the kinds declare a status subresource, so the API server advances
+// metadata.generation on every write that changes anything outside of metadata,
+// status included. observedGeneration is set to the generation the write results
+// in so that it matches metadata.generation once the object is stored.
+//
+// A successful sync recorded in the status is also recorded for the sync age metric.
+//
+// The writes of a resource in dry-run mode are only planned, the plan is written to the status
+// of original instead of the status of obj.
+func updateResource(ctx context.Context, c client.Client, original, obj nrv1.StatusObject) error {
+	if p := planFrom(ctx); p != nil {
+		return p.write(ctx, c, original, obj)
+	}
+
+	status := obj.GetResourceStatus()
+	status.ClearPlan()
+	status.SetObservedGeneration(obj.GetGeneration())
+
+	if contentChanged(original, withoutResolvedPolicyRef(obj)) {
+		status.SetObservedGeneration(obj.GetGeneration() + 1)
+	} else if equality.Semantic.DeepEqual(original.GetFinalizers(), obj.GetFinalizers()) {
+		lastSyncs.record(obj, time.Now())
+		return nil
+	}
+
+	stored := withoutResolvedPolicyRef(obj)
+	if err := c.Update(ctx, stored); err != nil {
+		return err
+	}
+
+	obj.SetResourceVersion(stored.GetResourceVersion())
+	lastSyncs.record(obj, time.Now())
+
+	copyInto(original, stored)
+
+	return nil
+}
+
+// copyInto sets original to a copy of stored, the object just written, so later writes in the
+// same reconcile compare against what was stored.
+func copyInto(original, stored runtime.Object) {
+	reflect.ValueOf(original).Elem().Set(reflect.ValueOf(stored.DeepCopyObject()).Elem())
+}
+
+// contentChanged returns true if anything outside of metadata differs between the objects.
+func contentChanged(original, obj runtime.Object) bool {
+	originalContent, err := runtime.DefaultUnstructuredConverter.ToUnstructured(original)
+	if err != nil {
+		return true
+	}
+
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return true
+	}
+
+	delete(originalContent, "metadata")
+	delete(content, "metadata")
+
+	return !equality.Semantic.DeepEqual(originalContent, content)
+}
+
+// failedRetryInterval is how often a resource is retried after a terminal failure, like a
+// rejected API key, in case whatever it depends on was fixed without the resource changing.
+const failedRetryInterval = 5 * time.Minute
+
+// retryLater picks how a failed reconcile is retried. A failure New Relic may recover from, like
+// rate limiting, is requeued after the wait the client suggests, instead of returned as an error
+// the work queue retries with its own backoff. A terminal failure, that retrying the same
+// request won't fix, is only retried after failedRetryInterval. Other results are returned as they are.
+func retryLater(result ctrl.Result, err error) (ctrl.Result, error) {
+	if after, ok := interfaces.RetryAfter(err); ok && after > 0 {
+		return ctrl.Result{RequeueAfter: after}, nil
+	}
+
+	if customErrors.IsTerminal(err) {
+		return ctrl.Result{RequeueAfter: failedRetryInterval}, nil
+	}
+
+	return result, err
+}
+
+// failureReason returns the reason a failure is reported with in the status and events: reason,
+// unless the kind of err says more about what has to be fixed.
+func failureReason(reason string, err error) string {
+	// the alerts channel controller tells failed links apart by their reason
+	if reason == nrv1.ReasonLinkFailed {
+		return reason
+	}
+
+	switch {
+	case customErrors.IsUnauthorized(err):
+		return nrv1.ReasonCredentialsFailed
+	case customErrors.IsValidation(err):
+		return nrv1.ReasonValidationFailed
+	case customErrors.IsConflict(err):
+		return nrv1.ReasonConflict
+	}
+
+	return reason
+}
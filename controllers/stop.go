@@ -0,0 +1,36 @@
+package controllers
+
+import (
+	"context"
+)
+
+// stopper is embedded in the reconcilers so the reconciles in flight, and the retries of their
+// calls to New Relic, stop when the manager stops. The manager injects its stop channel.
+type stopper struct {
+	stop <-chan struct{}
+}
+
+// InjectStopChannel is called by the manager with the channel that is closed when it stops.
+func (s *stopper) InjectStopChannel(stop <-chan struct{}) error {
+	s.stop = stop
+	return nil
+}
+
+// withStop returns a copy of ctx that is done once the manager stops, and the function that
+// releases it when the reconcile is over.
+func (s *stopper) withStop(ctx context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(ctx)
+	if s.stop == nil {
+		return ctx, cancel
+	}
+
+	go func() {
+		select {
+		case <-s.stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	return ctx, cancel
+}
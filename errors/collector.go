@@ -1,6 +1,9 @@
 package errors
 
-import "strings"
+import (
+	stderrors "errors"
+	"strings"
+)
 
 type ErrorCollector []error
 
@@ -17,3 +20,30 @@ func (c *ErrorCollector) Error() (errorString string) {
 	}
 	return strings.Join(messages, "\n")
 }
+
+// Unwrap returns the collected errors.
+func (c *ErrorCollector) Unwrap() []error {
+	return *c
+}
+
+// Is returns true if any of the collected errors matches target.
+func (c *ErrorCollector) Is(target error) bool {
+	for i := range *c {
+		if stderrors.Is((*c)[i], target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// As finds the first of the collected errors that matches target and sets target to it.
+func (c *ErrorCollector) As(target interface{}) bool {
+	for i := range *c {
+		if stderrors.As((*c)[i], target) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,194 @@
+package errors
+
+import (
+	stderrors "errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+
+	nrErrors "github.com/newrelic/newrelic-client-go/pkg/errors"
+)
+
+// Kinds of the errors returned by the New Relic APIs. An *Error matches its kind with errors.Is.
+var (
+	ErrNotFound     = stderrors.New("not found")
+	ErrUnauthorized = stderrors.New("unauthorized")
+	ErrRateLimited  = stderrors.New("rate limited")
+	ErrValidation   = stderrors.New("validation failed")
+	ErrConflict     = stderrors.New("conflict")
+)
+
+var (
+	// statusCodePrefix matches the message of the errors the client returns for unexpected responses.
+	statusCodePrefix = regexp.MustCompile(`^(\d{3}) response returned`)
+	// notFoundMessage matches the messages NerdGraph, and the client for the REST API, use for
+	// objects that don't exist. NerdGraph errors only come with a message.
+	notFoundMessage = regexp.MustCompile(`(?i)\bnot found\b|\bno .+ found for id\b|\bdoes not exist\b`)
+)
+
+// Error is an error returned by the New Relic APIs for a resource.
+type Error struct {
+	// Kind is one of ErrNotFound, ErrUnauthorized, ErrRateLimited, ErrValidation and ErrConflict.
+	Kind error
+	// Code is the HTTP status New Relic responded with, if there was one.
+	Code string
+	// Resource and ID identify what the call was made for, for example a policy and its ID.
+	// ID is empty for calls that create or list resources.
+	Resource string
+	ID       string
+	// Err is the error returned by the client.
+	Err error
+}
+
+func (e *Error) Error() string {
+	switch {
+	case e.Resource == "":
+		return e.Err.Error()
+	case e.ID == "":
+		return fmt.Sprintf("%s: %s", e.Resource, e.Err)
+	}
+
+	return fmt.Sprintf("%s %s: %s", e.Resource, e.ID, e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is returns true if target is the kind of e.
+func (e *Error) Is(target error) bool {
+	return target == e.Kind
+}
+
+// Wrap returns err as an *Error for the resource with the given ID, if err is one of the kinds
+// of errors New Relic returns. Other errors, and errors that are an *Error already, are
+// returned as they are.
+func Wrap(err error, resource, id string) error {
+	if err == nil {
+		return nil
+	}
+
+	var typed *Error
+	if stderrors.As(err, &typed) {
+		return err
+	}
+
+	kind, code := classify(err)
+	if kind == nil {
+		return err
+	}
+
+	return &Error{Kind: kind, Code: code, Resource: resource, ID: id, Err: err}
+}
+
+// classify returns the kind of an error returned by the client and the HTTP status it was
+// returned for, or a nil kind for errors of no known kind.
+func classify(err error) (error, string) {
+	var typed *Error
+	if stderrors.As(err, &typed) {
+		return typed.Kind, typed.Code
+	}
+
+	status, ok := HTTPStatus(err)
+	code := ""
+	if ok {
+		code = strconv.Itoa(status)
+	}
+
+	switch status {
+	case http.StatusNotFound:
+		return ErrNotFound, code
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return ErrUnauthorized, code
+	case http.StatusTooManyRequests:
+		return ErrRateLimited, code
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		return ErrValidation, code
+	case http.StatusConflict:
+		return ErrConflict, code
+	}
+
+	if !ok && notFoundMessage.MatchString(err.Error()) {
+		return ErrNotFound, code
+	}
+
+	return nil, code
+}
+
+// HTTPStatus returns the HTTP status of the response a call failed with, if there was one.
+func HTTPStatus(err error) (int, bool) {
+	if err == nil {
+		return 0, false
+	}
+
+	var typed *Error
+	if stderrors.As(err, &typed) && typed.Code != "" {
+		status, convErr := strconv.Atoi(typed.Code)
+		return status, convErr == nil
+	}
+
+	var notFound *nrErrors.NotFound
+	if stderrors.As(err, &notFound) {
+		return http.StatusNotFound, true
+	}
+
+	var unauthorized *nrErrors.UnauthorizedError
+	if stderrors.As(err, &unauthorized) {
+		return http.StatusUnauthorized, true
+	}
+
+	match := statusCodePrefix.FindStringSubmatch(err.Error())
+	if match == nil {
+		return 0, false
+	}
+
+	status, convErr := strconv.Atoi(match[1])
+
+	return status, convErr == nil
+}
+
+func isKind(err error, kind error) bool {
+	if err == nil {
+		return false
+	}
+
+	if stderrors.Is(err, kind) {
+		return true
+	}
+
+	found, _ := classify(err)
+
+	return found == kind
+}
+
+// IsNotFound returns true if New Relic reported that the resource does not exist.
+func IsNotFound(err error) bool {
+	return isKind(err, ErrNotFound)
+}
+
+// IsUnauthorized returns true if New Relic rejected the API key.
+func IsUnauthorized(err error) bool {
+	return isKind(err, ErrUnauthorized)
+}
+
+// IsRateLimited returns true if New Relic asked for requests to be slowed down.
+func IsRateLimited(err error) bool {
+	return isKind(err, ErrRateLimited)
+}
+
+// IsValidation returns true if New Relic rejected the request as invalid.
+func IsValidation(err error) bool {
+	return isKind(err, ErrValidation)
+}
+
+// IsConflict returns true if the request conflicts with a resource in New Relic.
+func IsConflict(err error) bool {
+	return isKind(err, ErrConflict)
+}
+
+// IsTerminal returns true for errors that retrying the same request won't fix: the API key,
+// the spec or the resources in New Relic have to change first.
+func IsTerminal(err error) bool {
+	return IsUnauthorized(err) || IsValidation(err) || IsConflict(err)
+}
@@ -0,0 +1,91 @@
+package errors_test
+
+import (
+	"errors"
+	"fmt"
+
+	nrErrors "github.com/newrelic/newrelic-client-go/pkg/errors"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	customErrors "github.com/newrelic/newrelic-kubernetes-operator/errors"
+)
+
+var _ = Describe("Wrap", func() {
+	It("types the errors of the client by kind", func() {
+		for err, kind := range map[error]error{
+			nrErrors.NewNotFound(""):                                       customErrors.ErrNotFound,
+			errors.New("no alert policy found for id 49092"):               customErrors.ErrNotFound,
+			nrErrors.NewUnauthorizedError():                                customErrors.ErrUnauthorized,
+			nrErrors.NewUnexpectedStatusCode(403, "forbidden"):             customErrors.ErrUnauthorized,
+			nrErrors.NewUnexpectedStatusCode(429, ""):                      customErrors.ErrRateLimited,
+			nrErrors.NewUnexpectedStatusCode(422, "name is required"):      customErrors.ErrValidation,
+			nrErrors.NewUnexpectedStatusCode(409, "name is already taken"): customErrors.ErrConflict,
+		} {
+			wrapped := customErrors.Wrap(err, "policy", "42")
+
+			Expect(errors.Is(wrapped, kind)).To(BeTrue(), err.Error())
+			Expect(errors.Is(wrapped, err)).To(BeTrue(), err.Error())
+		}
+	})
+
+	It("carries the HTTP status and the resource", func() {
+		err := customErrors.Wrap(nrErrors.NewUnexpectedStatusCode(422, "name is required"), "policy", "42")
+
+		var typed *customErrors.Error
+		Expect(errors.As(err, &typed)).To(BeTrue())
+		Expect(typed.Code).To(Equal("422"))
+		Expect(typed.Resource).To(Equal("policy"))
+		Expect(typed.ID).To(Equal("42"))
+		Expect(err.Error()).To(Equal("policy 42: 422 response returned: name is required"))
+	})
+
+	It("returns errors of no known kind as they are", func() {
+		err := nrErrors.NewUnexpectedStatusCode(502, "")
+
+		Expect(customErrors.Wrap(err, "policy", "42")).To(BeIdenticalTo(err))
+		Expect(customErrors.Wrap(nil, "policy", "42")).To(BeNil())
+	})
+})
+
+var _ = Describe("classification", func() {
+	It("classifies typed and raw errors alike", func() {
+		raw := nrErrors.NewUnauthorizedError()
+		wrapped := fmt.Errorf("failed to create policy: %w", customErrors.Wrap(raw, "policy", ""))
+
+		Expect(customErrors.IsUnauthorized(raw)).To(BeTrue())
+		Expect(customErrors.IsUnauthorized(wrapped)).To(BeTrue())
+		Expect(customErrors.IsTerminal(wrapped)).To(BeTrue())
+		Expect(customErrors.IsNotFound(wrapped)).To(BeFalse())
+	})
+
+	It("does not treat rate limiting or unknown errors as terminal", func() {
+		Expect(customErrors.IsTerminal(nrErrors.NewUnexpectedStatusCode(429, ""))).To(BeFalse())
+		Expect(customErrors.IsTerminal(errors.New("connection reset"))).To(BeFalse())
+		Expect(customErrors.IsTerminal(nil)).To(BeFalse())
+	})
+
+	It("reads the HTTP status of typed and raw errors", func() {
+		status, ok := customErrors.HTTPStatus(customErrors.Wrap(nrErrors.NewNotFound("gone"), "channel", "7"))
+		Expect(ok).To(BeTrue())
+		Expect(status).To(Equal(404))
+
+		_, ok = customErrors.HTTPStatus(errors.New("connection reset"))
+		Expect(ok).To(BeFalse())
+	})
+})
+
+var _ = Describe("ErrorCollector", func() {
+	It("matches any of the collected errors", func() {
+		collected := new(customErrors.ErrorCollector)
+		collected.Collect(errors.New("first"))
+		collected.Collect(customErrors.Wrap(nrErrors.NewUnexpectedStatusCode(409, ""), "condition", "3"))
+
+		var typed *customErrors.Error
+		Expect(errors.As(collected, &typed)).To(BeTrue())
+		Expect(typed.ID).To(Equal("3"))
+		Expect(errors.Is(collected, customErrors.ErrConflict)).To(BeTrue())
+		Expect(customErrors.IsConflict(collected)).To(BeTrue())
+		Expect(collected.Unwrap()).To(HaveLen(2))
+	})
+})
@@ -0,0 +1,233 @@
+package interfaces
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	clientPoolRequests = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "newrelic_operator_client_pool_requests_total",
+			Help: "Number of New Relic clients requested from the client pool, by whether a cached client was returned.",
+		},
+		[]string{"result"},
+	)
+	clientPoolEvictions = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "newrelic_operator_client_pool_evictions_total",
+			Help: "Number of New Relic clients removed from the client pool, by whether their API key changed, they were idle too long or the pool was full.",
+		},
+		[]string{"reason"},
+	)
+)
+
+// Reasons a client is evicted from the pool, used as the reason label of the evictions metric.
+const (
+	evictionKeyChanged = "key_changed"
+	evictionIdle       = "idle"
+	evictionFull       = "full"
+)
+
+const (
+	// DefaultClientPoolSize is the number of clients the pool keeps by default.
+	DefaultClientPoolSize = 100
+	// DefaultClientPoolIdleTTL is how long the pool keeps a client that is not used by default.
+	DefaultClientPoolIdleTTL = time.Hour
+)
+
+func init() {
+	metrics.Registry.MustRegister(clientPoolRequests, clientPoolEvictions)
+}
+
+// DefaultClientPool is shared by the controllers and webhooks so they reuse the same clients.
+var DefaultClientPool = NewClientPool(InitializeAlertsClientWithConfig)
+
+type clientPoolKey struct {
+	apiKeyHash   string
+	region       string
+	restURL      string
+	nerdGraphURL string
+}
+
+type pooledClient struct {
+	client   NewRelicAlertsClient
+	lastUsed time.Time
+}
+
+// ClientPool caches New Relic clients by API key, region and endpoints, so a client and its
+// connections are only set up once per set of credentials.
+// API keys are only kept as a hash. Clients not used for the idle TTL are dropped, and when the
+// pool is full the least recently used client makes room for a new one.
+type ClientPool struct {
+	newClient func(ClientConfig) (NewRelicAlertsClient, error)
+
+	mu           sync.Mutex
+	clients      map[clientPoolKey]*pooledClient
+	maxSize      int
+	idleTTL      time.Duration
+	retryOptions RetryOptions
+	hits         int
+	misses       int
+}
+
+// NewClientPool returns an empty pool that creates clients with newClient, bounded by
+// DefaultClientPoolSize and DefaultClientPoolIdleTTL, and retried with DefaultRetryOptions.
+func NewClientPool(newClient func(ClientConfig) (NewRelicAlertsClient, error)) *ClientPool {
+	return &ClientPool{
+		newClient:    newClient,
+		clients:      make(map[clientPoolKey]*pooledClient),
+		maxSize:      DefaultClientPoolSize,
+		idleTTL:      DefaultClientPoolIdleTTL,
+		retryOptions: DefaultRetryOptions(),
+	}
+}
+
+// SetLimits changes the number of clients the pool keeps and how long it keeps a client that is
+// not used. A maxSize or idleTTL of 0 or less removes that bound.
+func (p *ClientPool) SetLimits(maxSize int, idleTTL time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.maxSize = maxSize
+	p.idleTTL = idleTTL
+	p.evictIdle(time.Now())
+}
+
+// SetRetryOptions changes the options the clients of the pool are retried with. The cached clients
+// are dropped, so the next clients are created with the new options.
+func (p *ClientPool) SetRetryOptions(opts RetryOptions) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.retryOptions = opts
+	p.clients = make(map[clientPoolKey]*pooledClient)
+}
+
+// AlertsClient returns the cached client for apiKey and region, creating it on first use.
+// It can be used in place of InitializeAlertsClient.
+func (p *ClientPool) AlertsClient(apiKey string, region string) (NewRelicAlertsClient, error) {
+	return p.AlertsClientWithConfig(ClientConfig{APIKey: apiKey, Region: region})
+}
+
+// AlertsClientWithConfig returns the cached client for clientConfig, creating it on first use.
+func (p *ClientPool) AlertsClientWithConfig(clientConfig ClientConfig) (NewRelicAlertsClient, error) {
+	key := clientPoolKey{
+		apiKeyHash:   hashAPIKey(clientConfig.APIKey),
+		region:       clientConfig.Region,
+		restURL:      clientConfig.RestURL,
+		nerdGraphURL: clientConfig.NerdGraphURL,
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	p.evictIdle(now)
+
+	if pooled, ok := p.clients[key]; ok {
+		p.hits++
+		clientPoolRequests.WithLabelValues("hit").Inc()
+		pooled.lastUsed = now
+
+		return pooled.client, nil
+	}
+
+	p.misses++
+	clientPoolRequests.WithLabelValues("miss").Inc()
+
+	retryOptions := p.retryOptions
+	clientConfig.RetryOptions = &retryOptions
+
+	client, err := p.newClient(clientConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.maxSize > 0 {
+		for len(p.clients) >= p.maxSize {
+			p.evictLeastRecentlyUsed()
+		}
+	}
+
+	p.clients[key] = &pooledClient{client: client, lastUsed: now}
+
+	return client, nil
+}
+
+// evictIdle removes the clients not used for longer than the idle TTL. The lock must be held.
+func (p *ClientPool) evictIdle(now time.Time) {
+	if p.idleTTL <= 0 {
+		return
+	}
+
+	for key, pooled := range p.clients {
+		if now.Sub(pooled.lastUsed) > p.idleTTL {
+			delete(p.clients, key)
+			clientPoolEvictions.WithLabelValues(evictionIdle).Inc()
+		}
+	}
+}
+
+// evictLeastRecentlyUsed removes the client that was used the longest time ago. The lock must
+// be held. The pool is small, so it is found by looking at every client.
+func (p *ClientPool) evictLeastRecentlyUsed() {
+	var (
+		oldestKey clientPoolKey
+		oldest    *pooledClient
+	)
+
+	for key, pooled := range p.clients {
+		if oldest == nil || pooled.lastUsed.Before(oldest.lastUsed) {
+			oldestKey, oldest = key, pooled
+		}
+	}
+
+	if oldest != nil {
+		delete(p.clients, oldestKey)
+		clientPoolEvictions.WithLabelValues(evictionFull).Inc()
+	}
+}
+
+// Evict removes the clients for apiKey in all regions and endpoints, for example when the secret
+// holding the key was changed or deleted.
+func (p *ClientPool) Evict(apiKey string) {
+	apiKeyHash := hashAPIKey(apiKey)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for key := range p.clients {
+		if key.apiKeyHash == apiKeyHash {
+			delete(p.clients, key)
+			clientPoolEvictions.WithLabelValues(evictionKeyChanged).Inc()
+		}
+	}
+}
+
+// Len returns the number of cached clients.
+func (p *ClientPool) Len() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return len(p.clients)
+}
+
+// Stats returns how often a cached client was returned and how often one had to be created.
+func (p *ClientPool) Stats() (hits int, misses int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.hits, p.misses
+}
+
+func hashAPIKey(apiKey string) string {
+	sum := sha256.Sum256([]byte(apiKey))
+
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,147 @@
+package interfaces_test
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/newrelic/newrelic-kubernetes-operator/interfaces"
+	"github.com/newrelic/newrelic-kubernetes-operator/interfaces/interfacesfakes"
+)
+
+var _ = Describe("ClientPool", func() {
+	var (
+		pool    *interfaces.ClientPool
+		created []string
+	)
+
+	BeforeEach(func() {
+		created = nil
+		pool = interfaces.NewClientPool(func(clientConfig interfaces.ClientConfig) (interfaces.NewRelicAlertsClient, error) {
+			if clientConfig.APIKey == "invalid" {
+				return nil, errors.New("unable to create New Relic client")
+			}
+
+			created = append(created, clientConfig.APIKey+"/"+clientConfig.Region)
+
+			return &interfacesfakes.FakeNewRelicAlertsClient{}, nil
+		})
+	})
+
+	It("reuses the client for the same API key and region", func() {
+		first, err := pool.AlertsClient("api-key", "US")
+		Expect(err).ToNot(HaveOccurred())
+
+		second, err := pool.AlertsClient("api-key", "US")
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(second).To(BeIdenticalTo(first))
+		Expect(created).To(Equal([]string{"api-key/US"}))
+
+		hits, misses := pool.Stats()
+		Expect(hits).To(Equal(1))
+		Expect(misses).To(Equal(1))
+	})
+
+	It("creates a client per API key and region", func() {
+		us, _ := pool.AlertsClient("api-key", "US")
+		eu, _ := pool.AlertsClient("api-key", "EU")
+		other, _ := pool.AlertsClient("other-api-key", "US")
+		overridden, _ := pool.AlertsClientWithConfig(interfaces.ClientConfig{APIKey: "api-key", Region: "US", NerdGraphURL: "https://nerdgraph.example.com/graphql"})
+
+		Expect(eu).ToNot(BeIdenticalTo(us))
+		Expect(other).ToNot(BeIdenticalTo(us))
+		Expect(overridden).ToNot(BeIdenticalTo(us))
+		Expect(pool.Len()).To(Equal(4))
+	})
+
+	It("creates clients with its retry options", func() {
+		var retryOptions []*interfaces.RetryOptions
+		pool = interfaces.NewClientPool(func(clientConfig interfaces.ClientConfig) (interfaces.NewRelicAlertsClient, error) {
+			retryOptions = append(retryOptions, clientConfig.RetryOptions)
+
+			return &interfacesfakes.FakeNewRelicAlertsClient{}, nil
+		})
+
+		_, _ = pool.AlertsClient("api-key", "US")
+		Expect(retryOptions).To(HaveLen(1))
+		Expect(*retryOptions[0]).To(Equal(interfaces.DefaultRetryOptions()))
+
+		opts := interfaces.DefaultRetryOptions()
+		opts.MaxRetries = 5
+		pool.SetRetryOptions(opts)
+		Expect(pool.Len()).To(Equal(0))
+
+		_, _ = pool.AlertsClient("api-key", "US")
+		Expect(retryOptions).To(HaveLen(2))
+		Expect(retryOptions[1].MaxRetries).To(Equal(5))
+	})
+
+	It("does not cache failures", func() {
+		_, err := pool.AlertsClient("invalid", "US")
+		Expect(err).To(HaveOccurred())
+
+		Expect(pool.Len()).To(Equal(0))
+	})
+
+	It("evicts the clients for an API key in all regions", func() {
+		_, _ = pool.AlertsClient("api-key", "US")
+		_, _ = pool.AlertsClient("api-key", "EU")
+		_, _ = pool.AlertsClient("other-api-key", "US")
+
+		pool.Evict("api-key")
+		Expect(pool.Len()).To(Equal(1))
+
+		_, _ = pool.AlertsClient("api-key", "US")
+		Expect(created).To(HaveLen(4))
+	})
+
+	It("evicts the least recently used client when it is full", func() {
+		pool.SetLimits(2, 0)
+
+		first, _ := pool.AlertsClient("first-api-key", "US")
+		_, _ = pool.AlertsClient("second-api-key", "US")
+		_, _ = pool.AlertsClient("first-api-key", "US")
+		_, _ = pool.AlertsClient("third-api-key", "US")
+		Expect(pool.Len()).To(Equal(2))
+
+		again, _ := pool.AlertsClient("first-api-key", "US")
+		Expect(again).To(BeIdenticalTo(first))
+
+		_, _ = pool.AlertsClient("second-api-key", "US")
+		Expect(created).To(Equal([]string{"first-api-key/US", "second-api-key/US", "third-api-key/US", "second-api-key/US"}))
+	})
+
+	It("evicts the clients that were not used for the idle TTL", func() {
+		pool.SetLimits(0, 10*time.Millisecond)
+
+		_, _ = pool.AlertsClient("api-key", "US")
+		time.Sleep(20 * time.Millisecond)
+
+		_, _ = pool.AlertsClient("other-api-key", "US")
+		Expect(pool.Len()).To(Equal(1))
+
+		_, _ = pool.AlertsClient("api-key", "US")
+		Expect(created).To(Equal([]string{"api-key/US", "other-api-key/US", "api-key/US"}))
+	})
+
+	It("can be used from many goroutines", func() {
+		var wg sync.WaitGroup
+		for i := 0; i < 50; i++ {
+			wg.Add(1)
+			go func() {
+				defer GinkgoRecover()
+				defer wg.Done()
+
+				_, err := pool.AlertsClient("api-key", "US")
+				Expect(err).ToNot(HaveOccurred())
+			}()
+		}
+		wg.Wait()
+
+		Expect(created).To(HaveLen(1))
+	})
+})
@@ -0,0 +1,209 @@
+package interfaces
+
+import (
+	"strconv"
+
+	"github.com/newrelic/newrelic-client-go/pkg/alerts"
+	"github.com/newrelic/newrelic-client-go/pkg/nerdstorage"
+)
+
+// Operations of a PlannedCall.
+const (
+	OperationCreate = "Create"
+	OperationUpdate = "Update"
+	OperationDelete = "Delete"
+)
+
+// PlannedCall is a call that writes to New Relic and was not sent by a client returned by WithDryRun.
+type PlannedCall struct {
+	// Method is the name of the client method.
+	Method string
+	// Operation is one of OperationCreate, OperationUpdate or OperationDelete.
+	Operation string
+	// Resource is the kind of object written, as it is named in errors.
+	Resource string
+	// ID is the ID of the object written, empty for objects that would be created.
+	ID string
+	// Input is what would be written.
+	Input interface{}
+	// Current is the object as it is in New Relic, when it can be read by its ID. Only
+	// updates of policies and NerdGraph NRQL conditions read it.
+	Current interface{}
+}
+
+// WithDryRun returns client with the calls that write to New Relic passed to plan instead of
+// sent. They return what was written as if it succeeded, with 0 in place of the IDs New Relic
+// would give objects it creates. Calls that read from New Relic are sent as they are, so
+// adopting objects and checking for drift work as they would without a dry run.
+// NerdStorage documents are only written to mark the objects the operator manages, writing and
+// deleting them is skipped without a PlannedCall.
+func WithDryRun(client NewRelicAlertsClient, plan func(PlannedCall)) NewRelicAlertsClient {
+	return &dryRunClient{NewRelicAlertsClient: client, plan: plan}
+}
+
+type dryRunClient struct {
+	NewRelicAlertsClient
+	plan func(PlannedCall)
+}
+
+func (c *dryRunClient) CreateNrqlCondition(policyID int, nrqlCondition alerts.NrqlCondition) (*alerts.NrqlCondition, error) {
+	c.plan(PlannedCall{Method: "CreateNrqlCondition", Operation: OperationCreate, Resource: "nrql condition", Input: nrqlCondition})
+	return &nrqlCondition, nil
+}
+
+func (c *dryRunClient) UpdateNrqlCondition(nrqlCondition alerts.NrqlCondition) (*alerts.NrqlCondition, error) {
+	c.plan(PlannedCall{Method: "UpdateNrqlCondition", Operation: OperationUpdate, Resource: "nrql condition", ID: plannedID(nrqlCondition.ID), Input: nrqlCondition})
+	return &nrqlCondition, nil
+}
+
+func (c *dryRunClient) DeleteNrqlCondition(id int) (*alerts.NrqlCondition, error) {
+	c.plan(PlannedCall{Method: "DeleteNrqlCondition", Operation: OperationDelete, Resource: "nrql condition", ID: plannedID(id)})
+	return &alerts.NrqlCondition{ID: id}, nil
+}
+
+func (c *dryRunClient) CreateCondition(policyID int, condition alerts.Condition) (*alerts.Condition, error) {
+	c.plan(PlannedCall{Method: "CreateCondition", Operation: OperationCreate, Resource: "condition", Input: condition})
+	return &condition, nil
+}
+
+func (c *dryRunClient) UpdateCondition(condition alerts.Condition) (*alerts.Condition, error) {
+	c.plan(PlannedCall{Method: "UpdateCondition", Operation: OperationUpdate, Resource: "condition", ID: plannedID(condition.ID), Input: condition})
+	return &condition, nil
+}
+
+func (c *dryRunClient) DeleteCondition(id int) (*alerts.Condition, error) {
+	c.plan(PlannedCall{Method: "DeleteCondition", Operation: OperationDelete, Resource: "condition", ID: plannedID(id)})
+	return &alerts.Condition{ID: id}, nil
+}
+
+func (c *dryRunClient) CreatePolicy(policy alerts.Policy) (*alerts.Policy, error) {
+	c.plan(PlannedCall{Method: "CreatePolicy", Operation: OperationCreate, Resource: "policy", Input: policy})
+	return &policy, nil
+}
+
+func (c *dryRunClient) UpdatePolicy(policy alerts.Policy) (*alerts.Policy, error) {
+	call := PlannedCall{Method: "UpdatePolicy", Operation: OperationUpdate, Resource: "policy", ID: plannedID(policy.ID), Input: policy}
+	if current, err := c.NewRelicAlertsClient.GetPolicy(policy.ID); err == nil && current != nil {
+		call.Current = *current
+	}
+
+	c.plan(call)
+
+	return &policy, nil
+}
+
+func (c *dryRunClient) DeletePolicy(id int) (*alerts.Policy, error) {
+	c.plan(PlannedCall{Method: "DeletePolicy", Operation: OperationDelete, Resource: "policy", ID: plannedID(id)})
+	return &alerts.Policy{ID: id}, nil
+}
+
+func (c *dryRunClient) CreateChannel(channel alerts.Channel) (*alerts.Channel, error) {
+	c.plan(PlannedCall{Method: "CreateChannel", Operation: OperationCreate, Resource: "channel", Input: channel})
+	return &channel, nil
+}
+
+func (c *dryRunClient) DeleteChannel(id int) (*alerts.Channel, error) {
+	c.plan(PlannedCall{Method: "DeleteChannel", Operation: OperationDelete, Resource: "channel", ID: plannedID(id)})
+	return &alerts.Channel{ID: id}, nil
+}
+
+func (c *dryRunClient) UpdatePolicyChannels(policyID int, channelIDs []int) (*alerts.PolicyChannels, error) {
+	policyChannels := alerts.PolicyChannels{ID: policyID, ChannelIDs: channelIDs}
+	c.plan(PlannedCall{Method: "UpdatePolicyChannels", Operation: OperationUpdate, Resource: "policy channels", ID: plannedID(policyID), Input: policyChannels})
+
+	return &policyChannels, nil
+}
+
+func (c *dryRunClient) DeletePolicyChannel(policyID int, channelID int) (*alerts.Channel, error) {
+	c.plan(PlannedCall{Method: "DeletePolicyChannel", Operation: OperationDelete, Resource: "policy channels", ID: plannedID(policyID), Input: alerts.PolicyChannels{ID: policyID, ChannelIDs: []int{channelID}}})
+	return &alerts.Channel{ID: channelID}, nil
+}
+
+func (c *dryRunClient) CreatePolicyMutation(accountID int, policy alerts.AlertsPolicyInput) (*alerts.AlertsPolicy, error) {
+	c.plan(PlannedCall{Method: "CreatePolicyMutation", Operation: OperationCreate, Resource: "policy", Input: policy})
+	return &alerts.AlertsPolicy{AccountID: accountID, ID: "0", IncidentPreference: policy.IncidentPreference, Name: policy.Name}, nil
+}
+
+func (c *dryRunClient) UpdatePolicyMutation(accountID int, policyID string, policy alerts.AlertsPolicyUpdateInput) (*alerts.AlertsPolicy, error) {
+	call := PlannedCall{Method: "UpdatePolicyMutation", Operation: OperationUpdate, Resource: "policy", ID: policyID, Input: policy}
+	if current, err := c.NewRelicAlertsClient.QueryPolicy(accountID, policyID); err == nil && current != nil {
+		call.Current = *current
+	}
+
+	c.plan(call)
+
+	return &alerts.AlertsPolicy{AccountID: accountID, ID: policyID, IncidentPreference: policy.IncidentPreference, Name: policy.Name}, nil
+}
+
+func (c *dryRunClient) DeletePolicyMutation(accountID int, id string) (*alerts.AlertsPolicy, error) {
+	c.plan(PlannedCall{Method: "DeletePolicyMutation", Operation: OperationDelete, Resource: "policy", ID: id})
+	return &alerts.AlertsPolicy{AccountID: accountID, ID: id}, nil
+}
+
+func (c *dryRunClient) CreateNrqlConditionStaticMutation(accountID int, policyID string, nrqlCondition alerts.NrqlConditionInput) (*alerts.NrqlAlertCondition, error) {
+	c.plan(PlannedCall{Method: "CreateNrqlConditionStaticMutation", Operation: OperationCreate, Resource: "nrql condition", Input: nrqlCondition})
+	return plannedNrqlCondition("0", policyID, nrqlCondition), nil
+}
+
+func (c *dryRunClient) UpdateNrqlConditionStaticMutation(accountID int, conditionID string, nrqlCondition alerts.NrqlConditionInput) (*alerts.NrqlAlertCondition, error) {
+	return c.updateNrqlCondition("UpdateNrqlConditionStaticMutation", accountID, conditionID, nrqlCondition), nil
+}
+
+func (c *dryRunClient) CreateNrqlConditionBaselineMutation(accountID int, policyID string, nrqlCondition alerts.NrqlConditionInput) (*alerts.NrqlAlertCondition, error) {
+	c.plan(PlannedCall{Method: "CreateNrqlConditionBaselineMutation", Operation: OperationCreate, Resource: "nrql condition", Input: nrqlCondition})
+	return plannedNrqlCondition("0", policyID, nrqlCondition), nil
+}
+
+func (c *dryRunClient) UpdateNrqlConditionBaselineMutation(accountID int, conditionID string, nrqlCondition alerts.NrqlConditionInput) (*alerts.NrqlAlertCondition, error) {
+	return c.updateNrqlCondition("UpdateNrqlConditionBaselineMutation", accountID, conditionID, nrqlCondition), nil
+}
+
+func (c *dryRunClient) updateNrqlCondition(method string, accountID int, conditionID string, nrqlCondition alerts.NrqlConditionInput) *alerts.NrqlAlertCondition {
+	call := PlannedCall{Method: method, Operation: OperationUpdate, Resource: "nrql condition", ID: conditionID, Input: nrqlCondition}
+
+	policyID := ""
+	if current, err := c.NewRelicAlertsClient.GetNrqlConditionQuery(accountID, conditionID); err == nil && current != nil {
+		call.Current = *current
+		policyID = current.PolicyID
+	}
+
+	c.plan(call)
+
+	return plannedNrqlCondition(conditionID, policyID, nrqlCondition)
+}
+
+func (c *dryRunClient) DeleteConditionMutation(accountID int, conditionID string) (string, error) {
+	c.plan(PlannedCall{Method: "DeleteConditionMutation", Operation: OperationDelete, Resource: "nrql condition", ID: conditionID})
+	return conditionID, nil
+}
+
+func (c *dryRunClient) WriteDocumentWithAccountScope(accountID int, input nerdstorage.WriteDocumentInput) (interface{}, error) {
+	return input.Document, nil
+}
+
+func (c *dryRunClient) DeleteDocumentWithAccountScope(accountID int, input nerdstorage.DeleteDocumentInput) (bool, error) {
+	return true, nil
+}
+
+// plannedID returns the ID of a PlannedCall for the object with the given ID, which is 0 for
+// objects that would be created.
+func plannedID(id int) string {
+	if id == 0 {
+		return ""
+	}
+
+	return strconv.Itoa(id)
+}
+
+// plannedNrqlCondition returns the condition New Relic would return for a NerdGraph NRQL
+// condition written with nrqlCondition.
+func plannedNrqlCondition(id, policyID string, nrqlCondition alerts.NrqlConditionInput) *alerts.NrqlAlertCondition {
+	return &alerts.NrqlAlertCondition{
+		NrqlConditionBase: nrqlCondition.NrqlConditionBase,
+		ID:                id,
+		PolicyID:          policyID,
+		BaselineDirection: nrqlCondition.BaselineDirection,
+		ValueFunction:     nrqlCondition.ValueFunction,
+		ExpectedGroups:    nrqlCondition.ExpectedGroups,
+	}
+}
@@ -0,0 +1,91 @@
+package interfaces_test
+
+import (
+	"github.com/newrelic/newrelic-client-go/pkg/alerts"
+	"github.com/newrelic/newrelic-client-go/pkg/nerdstorage"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/newrelic/newrelic-kubernetes-operator/interfaces"
+	"github.com/newrelic/newrelic-kubernetes-operator/interfaces/interfacesfakes"
+)
+
+var _ = Describe("WithDryRun", func() {
+	var (
+		fake    *interfacesfakes.FakeNewRelicAlertsClient
+		client  interfaces.NewRelicAlertsClient
+		planned []interfaces.PlannedCall
+	)
+
+	BeforeEach(func() {
+		fake = &interfacesfakes.FakeNewRelicAlertsClient{}
+		planned = nil
+		client = interfaces.WithDryRun(fake, func(call interfaces.PlannedCall) {
+			planned = append(planned, call)
+		})
+	})
+
+	It("passes reads through to the client", func() {
+		fake.QueryPolicyReturns(&alerts.AlertsPolicy{ID: "42"}, nil)
+
+		policy, err := client.QueryPolicy(1, "42")
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(policy.ID).To(Equal("42"))
+		Expect(planned).To(BeEmpty())
+	})
+
+	It("plans creates instead of sending them", func() {
+		input := alerts.AlertsPolicyInput{Name: "my policy", IncidentPreference: "PER_POLICY"}
+
+		policy, err := client.CreatePolicyMutation(1, input)
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(policy.ID).To(Equal("0"))
+		Expect(policy.Name).To(Equal("my policy"))
+		Expect(fake.CreatePolicyMutationCallCount()).To(Equal(0))
+		Expect(planned).To(Equal([]interfaces.PlannedCall{{
+			Method:    "CreatePolicyMutation",
+			Operation: interfaces.OperationCreate,
+			Resource:  "policy",
+			Input:     input,
+		}}))
+	})
+
+	It("plans updates with the object as it is in New Relic", func() {
+		current := alerts.AlertsPolicy{ID: "42", Name: "old policy"}
+		fake.QueryPolicyReturns(&current, nil)
+
+		_, err := client.UpdatePolicyMutation(1, "42", alerts.AlertsPolicyUpdateInput{Name: "my policy"})
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(fake.UpdatePolicyMutationCallCount()).To(Equal(0))
+		Expect(planned).To(HaveLen(1))
+		Expect(planned[0].ID).To(Equal("42"))
+		Expect(planned[0].Current).To(Equal(current))
+	})
+
+	It("plans deletes and links", func() {
+		_, err := client.DeleteChannel(7)
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = client.UpdatePolicyChannels(42, []int{7})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(fake.DeleteChannelCallCount()).To(Equal(0))
+		Expect(fake.UpdatePolicyChannelsCallCount()).To(Equal(0))
+		Expect(planned).To(HaveLen(2))
+		Expect(planned[0].Operation).To(Equal(interfaces.OperationDelete))
+		Expect(planned[0].ID).To(Equal("7"))
+		Expect(planned[1].Operation).To(Equal(interfaces.OperationUpdate))
+		Expect(planned[1].ID).To(Equal("42"))
+	})
+
+	It("skips writing the documents that mark managed objects", func() {
+		_, err := client.WriteDocumentWithAccountScope(1, nerdstorage.WriteDocumentInput{DocumentID: "policy-42"})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(fake.WriteDocumentWithAccountScopeCallCount()).To(Equal(0))
+		Expect(planned).To(BeEmpty())
+	})
+})
@@ -0,0 +1,234 @@
+package interfaces
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/newrelic/newrelic-client-go/pkg/alerts"
+	"github.com/newrelic/newrelic-client-go/pkg/nerdstorage"
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	customErrors "github.com/newrelic/newrelic-kubernetes-operator/errors"
+)
+
+var (
+	apiRequests = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "newrelic_operator_api_requests_total",
+			Help: "Number of New Relic API calls, by client method, outcome and HTTP status.",
+		},
+		[]string{"method", "outcome", "status"},
+	)
+	apiRequestDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "newrelic_operator_api_request_duration_seconds",
+			Help:    "Duration of New Relic API calls, including the retries of the client, by client method and outcome.",
+			Buckets: prometheus.ExponentialBuckets(0.05, 2, 10),
+		},
+		[]string{"method", "outcome"},
+	)
+)
+
+func init() {
+	metrics.Registry.MustRegister(apiRequests, apiRequestDuration)
+}
+
+// WithMetrics returns client with every call counted and timed in the
+// newrelic_operator_api_* metrics.
+func WithMetrics(client NewRelicAlertsClient) NewRelicAlertsClient {
+	return &instrumentedClient{client: client}
+}
+
+type instrumentedClient struct {
+	client NewRelicAlertsClient
+}
+
+func observe(method string, start time.Time, err *error) {
+	outcome := "success"
+	if *err != nil {
+		outcome = "error"
+	}
+
+	apiRequests.WithLabelValues(method, outcome, statusOf(*err)).Inc()
+	apiRequestDuration.WithLabelValues(method, outcome).Observe(time.Since(start).Seconds())
+}
+
+// statusOf returns the HTTP status of the response a call failed with, "2xx" for calls that
+// succeeded and "none" for errors that didn't come with a response.
+func statusOf(err error) string {
+	if err == nil {
+		return "2xx"
+	}
+
+	if status, ok := customErrors.HTTPStatus(err); ok {
+		return strconv.Itoa(status)
+	}
+
+	return "none"
+}
+
+func (c *instrumentedClient) CreateNrqlCondition(policyID int, nrqlCondition alerts.NrqlCondition) (result *alerts.NrqlCondition, err error) {
+	defer observe("CreateNrqlCondition", time.Now(), &err)
+	return c.client.CreateNrqlCondition(policyID, nrqlCondition)
+}
+
+func (c *instrumentedClient) UpdateNrqlCondition(nrqlCondition alerts.NrqlCondition) (result *alerts.NrqlCondition, err error) {
+	defer observe("UpdateNrqlCondition", time.Now(), &err)
+	return c.client.UpdateNrqlCondition(nrqlCondition)
+}
+
+func (c *instrumentedClient) ListNrqlConditions(policyID int) (result []*alerts.NrqlCondition, err error) {
+	defer observe("ListNrqlConditions", time.Now(), &err)
+	return c.client.ListNrqlConditions(policyID)
+}
+
+func (c *instrumentedClient) DeleteNrqlCondition(id int) (result *alerts.NrqlCondition, err error) {
+	defer observe("DeleteNrqlCondition", time.Now(), &err)
+	return c.client.DeleteNrqlCondition(id)
+}
+
+func (c *instrumentedClient) ListConditions(policyID int) (result []*alerts.Condition, err error) {
+	defer observe("ListConditions", time.Now(), &err)
+	return c.client.ListConditions(policyID)
+}
+
+func (c *instrumentedClient) CreateCondition(policyID int, condition alerts.Condition) (result *alerts.Condition, err error) {
+	defer observe("CreateCondition", time.Now(), &err)
+	return c.client.CreateCondition(policyID, condition)
+}
+
+func (c *instrumentedClient) UpdateCondition(condition alerts.Condition) (result *alerts.Condition, err error) {
+	defer observe("UpdateCondition", time.Now(), &err)
+	return c.client.UpdateCondition(condition)
+}
+
+func (c *instrumentedClient) DeleteCondition(id int) (result *alerts.Condition, err error) {
+	defer observe("DeleteCondition", time.Now(), &err)
+	return c.client.DeleteCondition(id)
+}
+
+func (c *instrumentedClient) GetPolicy(id int) (result *alerts.Policy, err error) {
+	defer observe("GetPolicy", time.Now(), &err)
+	return c.client.GetPolicy(id)
+}
+
+func (c *instrumentedClient) CreatePolicy(policy alerts.Policy) (result *alerts.Policy, err error) {
+	defer observe("CreatePolicy", time.Now(), &err)
+	return c.client.CreatePolicy(policy)
+}
+
+func (c *instrumentedClient) UpdatePolicy(policy alerts.Policy) (result *alerts.Policy, err error) {
+	defer observe("UpdatePolicy", time.Now(), &err)
+	return c.client.UpdatePolicy(policy)
+}
+
+func (c *instrumentedClient) DeletePolicy(id int) (result *alerts.Policy, err error) {
+	defer observe("DeletePolicy", time.Now(), &err)
+	return c.client.DeletePolicy(id)
+}
+
+func (c *instrumentedClient) ListPolicies(params *alerts.ListPoliciesParams) (result []alerts.Policy, err error) {
+	defer observe("ListPolicies", time.Now(), &err)
+	return c.client.ListPolicies(params)
+}
+
+func (c *instrumentedClient) CreateChannel(channel alerts.Channel) (result *alerts.Channel, err error) {
+	defer observe("CreateChannel", time.Now(), &err)
+	return c.client.CreateChannel(channel)
+}
+
+func (c *instrumentedClient) DeleteChannel(id int) (result *alerts.Channel, err error) {
+	defer observe("DeleteChannel", time.Now(), &err)
+	return c.client.DeleteChannel(id)
+}
+
+func (c *instrumentedClient) ListChannels() (result []*alerts.Channel, err error) {
+	defer observe("ListChannels", time.Now(), &err)
+	return c.client.ListChannels()
+}
+
+func (c *instrumentedClient) UpdatePolicyChannels(policyID int, channelIDs []int) (result *alerts.PolicyChannels, err error) {
+	defer observe("UpdatePolicyChannels", time.Now(), &err)
+	return c.client.UpdatePolicyChannels(policyID, channelIDs)
+}
+
+func (c *instrumentedClient) DeletePolicyChannel(policyID int, channelID int) (result *alerts.Channel, err error) {
+	defer observe("DeletePolicyChannel", time.Now(), &err)
+	return c.client.DeletePolicyChannel(policyID, channelID)
+}
+
+func (c *instrumentedClient) CreatePolicyMutation(accountID int, policy alerts.AlertsPolicyInput) (result *alerts.AlertsPolicy, err error) {
+	defer observe("CreatePolicyMutation", time.Now(), &err)
+	return c.client.CreatePolicyMutation(accountID, policy)
+}
+
+func (c *instrumentedClient) UpdatePolicyMutation(accountID int, policyID string, policy alerts.AlertsPolicyUpdateInput) (result *alerts.AlertsPolicy, err error) {
+	defer observe("UpdatePolicyMutation", time.Now(), &err)
+	return c.client.UpdatePolicyMutation(accountID, policyID, policy)
+}
+
+func (c *instrumentedClient) DeletePolicyMutation(accountID int, id string) (result *alerts.AlertsPolicy, err error) {
+	defer observe("DeletePolicyMutation", time.Now(), &err)
+	return c.client.DeletePolicyMutation(accountID, id)
+}
+
+func (c *instrumentedClient) QueryPolicySearch(accountID int, params alerts.AlertsPoliciesSearchCriteriaInput) (result []*alerts.AlertsPolicy, err error) {
+	defer observe("QueryPolicySearch", time.Now(), &err)
+	return c.client.QueryPolicySearch(accountID, params)
+}
+
+func (c *instrumentedClient) QueryPolicy(accountID int, id string) (result *alerts.AlertsPolicy, err error) {
+	defer observe("QueryPolicy", time.Now(), &err)
+	return c.client.QueryPolicy(accountID, id)
+}
+
+func (c *instrumentedClient) CreateNrqlConditionStaticMutation(accountID int, policyID string, nrqlCondition alerts.NrqlConditionInput) (result *alerts.NrqlAlertCondition, err error) {
+	defer observe("CreateNrqlConditionStaticMutation", time.Now(), &err)
+	return c.client.CreateNrqlConditionStaticMutation(accountID, policyID, nrqlCondition)
+}
+
+func (c *instrumentedClient) UpdateNrqlConditionStaticMutation(accountID int, conditionID string, nrqlCondition alerts.NrqlConditionInput) (result *alerts.NrqlAlertCondition, err error) {
+	defer observe("UpdateNrqlConditionStaticMutation", time.Now(), &err)
+	return c.client.UpdateNrqlConditionStaticMutation(accountID, conditionID, nrqlCondition)
+}
+
+func (c *instrumentedClient) CreateNrqlConditionBaselineMutation(accountID int, policyID string, nrqlCondition alerts.NrqlConditionInput) (result *alerts.NrqlAlertCondition, err error) {
+	defer observe("CreateNrqlConditionBaselineMutation", time.Now(), &err)
+	return c.client.CreateNrqlConditionBaselineMutation(accountID, policyID, nrqlCondition)
+}
+
+func (c *instrumentedClient) UpdateNrqlConditionBaselineMutation(accountID int, conditionID string, nrqlCondition alerts.NrqlConditionInput) (result *alerts.NrqlAlertCondition, err error) {
+	defer observe("UpdateNrqlConditionBaselineMutation", time.Now(), &err)
+	return c.client.UpdateNrqlConditionBaselineMutation(accountID, conditionID, nrqlCondition)
+}
+
+func (c *instrumentedClient) DeleteConditionMutation(accountID int, conditionID string) (result string, err error) {
+	defer observe("DeleteConditionMutation", time.Now(), &err)
+	return c.client.DeleteConditionMutation(accountID, conditionID)
+}
+
+func (c *instrumentedClient) SearchNrqlConditionsQuery(accountID int, searchCriteria alerts.NrqlConditionsSearchCriteria) (result []*alerts.NrqlAlertCondition, err error) {
+	defer observe("SearchNrqlConditionsQuery", time.Now(), &err)
+	return c.client.SearchNrqlConditionsQuery(accountID, searchCriteria)
+}
+
+func (c *instrumentedClient) GetNrqlConditionQuery(accountID int, conditionID string) (result *alerts.NrqlAlertCondition, err error) {
+	defer observe("GetNrqlConditionQuery", time.Now(), &err)
+	return c.client.GetNrqlConditionQuery(accountID, conditionID)
+}
+
+func (c *instrumentedClient) GetDocumentWithAccountScope(accountID int, input nerdstorage.GetDocumentInput) (result interface{}, err error) {
+	defer observe("GetDocumentWithAccountScope", time.Now(), &err)
+	return c.client.GetDocumentWithAccountScope(accountID, input)
+}
+
+func (c *instrumentedClient) WriteDocumentWithAccountScope(accountID int, input nerdstorage.WriteDocumentInput) (result interface{}, err error) {
+	defer observe("WriteDocumentWithAccountScope", time.Now(), &err)
+	return c.client.WriteDocumentWithAccountScope(accountID, input)
+}
+
+func (c *instrumentedClient) DeleteDocumentWithAccountScope(accountID int, input nerdstorage.DeleteDocumentInput) (result bool, err error) {
+	defer observe("DeleteDocumentWithAccountScope", time.Now(), &err)
+	return c.client.DeleteDocumentWithAccountScope(accountID, input)
+}
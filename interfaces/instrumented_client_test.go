@@ -0,0 +1,87 @@
+package interfaces_test
+
+import (
+	"errors"
+
+	"github.com/newrelic/newrelic-client-go/pkg/alerts"
+	nrErrors "github.com/newrelic/newrelic-client-go/pkg/errors"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/newrelic/newrelic-kubernetes-operator/interfaces"
+	"github.com/newrelic/newrelic-kubernetes-operator/interfaces/interfacesfakes"
+)
+
+// requests returns the value of newrelic_operator_api_requests_total for the given labels.
+func requests(method, outcome, status string) float64 {
+	families, err := metrics.Registry.Gather()
+	Expect(err).ToNot(HaveOccurred())
+
+	for _, family := range families {
+		if family.GetName() != "newrelic_operator_api_requests_total" {
+			continue
+		}
+
+		for _, metric := range family.GetMetric() {
+			labels := map[string]string{}
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+
+			if labels["method"] == method && labels["outcome"] == outcome && labels["status"] == status {
+				return metric.GetCounter().GetValue()
+			}
+		}
+	}
+
+	return 0
+}
+
+var _ = Describe("WithMetrics", func() {
+	var (
+		fake   *interfacesfakes.FakeNewRelicAlertsClient
+		client interfaces.NewRelicAlertsClient
+	)
+
+	BeforeEach(func() {
+		fake = &interfacesfakes.FakeNewRelicAlertsClient{}
+		client = interfaces.WithMetrics(fake)
+	})
+
+	It("passes calls through to the client", func() {
+		fake.GetPolicyReturns(&alerts.Policy{ID: 42}, nil)
+
+		policy, err := client.GetPolicy(42)
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(policy.ID).To(Equal(42))
+		Expect(fake.GetPolicyArgsForCall(0)).To(Equal(42))
+	})
+
+	It("counts successful calls", func() {
+		before := requests("CreatePolicy", "success", "2xx")
+
+		_, _ = client.CreatePolicy(alerts.Policy{})
+
+		Expect(requests("CreatePolicy", "success", "2xx")).To(Equal(before + 1))
+	})
+
+	It("labels failed calls with the HTTP status", func() {
+		fake.DeletePolicyReturns(nil, nrErrors.NewNotFound("policy"))
+		fake.ListChannelsReturns(nil, nrErrors.NewUnexpectedStatusCode(503, "unavailable"))
+		fake.ListPoliciesReturns(nil, errors.New("connection refused"))
+
+		notFound := requests("DeletePolicy", "error", "404")
+		unavailable := requests("ListChannels", "error", "503")
+		none := requests("ListPolicies", "error", "none")
+
+		_, _ = client.DeletePolicy(1)
+		_, _ = client.ListChannels()
+		_, _ = client.ListPolicies(nil)
+
+		Expect(requests("DeletePolicy", "error", "404")).To(Equal(notFound + 1))
+		Expect(requests("ListChannels", "error", "503")).To(Equal(unavailable + 1))
+		Expect(requests("ListPolicies", "error", "none")).To(Equal(none + 1))
+	})
+})
@@ -5,6 +5,7 @@ import (
 	"sync"
 
 	"github.com/newrelic/newrelic-client-go/pkg/alerts"
+	"github.com/newrelic/newrelic-client-go/pkg/nerdstorage"
 
 	"github.com/newrelic/newrelic-kubernetes-operator/interfaces"
 )
@@ -148,6 +149,20 @@ type FakeNewRelicAlertsClient struct {
 		result1 string
 		result2 error
 	}
+	DeleteDocumentWithAccountScopeStub        func(int, nerdstorage.DeleteDocumentInput) (bool, error)
+	deleteDocumentWithAccountScopeMutex       sync.RWMutex
+	deleteDocumentWithAccountScopeArgsForCall []struct {
+		arg1 int
+		arg2 nerdstorage.DeleteDocumentInput
+	}
+	deleteDocumentWithAccountScopeReturns struct {
+		result1 bool
+		result2 error
+	}
+	deleteDocumentWithAccountScopeReturnsOnCall map[int]struct {
+		result1 bool
+		result2 error
+	}
 	DeleteNrqlConditionStub        func(int) (*alerts.NrqlCondition, error)
 	deleteNrqlConditionMutex       sync.RWMutex
 	deleteNrqlConditionArgsForCall []struct {
@@ -202,6 +217,20 @@ type FakeNewRelicAlertsClient struct {
 		result1 *alerts.AlertsPolicy
 		result2 error
 	}
+	GetDocumentWithAccountScopeStub        func(int, nerdstorage.GetDocumentInput) (interface{}, error)
+	getDocumentWithAccountScopeMutex       sync.RWMutex
+	getDocumentWithAccountScopeArgsForCall []struct {
+		arg1 int
+		arg2 nerdstorage.GetDocumentInput
+	}
+	getDocumentWithAccountScopeReturns struct {
+		result1 interface{}
+		result2 error
+	}
+	getDocumentWithAccountScopeReturnsOnCall map[int]struct {
+		result1 interface{}
+		result2 error
+	}
 	GetNrqlConditionQueryStub        func(int, string) (*alerts.NrqlAlertCondition, error)
 	getNrqlConditionQueryMutex       sync.RWMutex
 	getNrqlConditionQueryArgsForCall []struct {
@@ -420,6 +449,20 @@ type FakeNewRelicAlertsClient struct {
 		result1 *alerts.AlertsPolicy
 		result2 error
 	}
+	WriteDocumentWithAccountScopeStub        func(int, nerdstorage.WriteDocumentInput) (interface{}, error)
+	writeDocumentWithAccountScopeMutex       sync.RWMutex
+	writeDocumentWithAccountScopeArgsForCall []struct {
+		arg1 int
+		arg2 nerdstorage.WriteDocumentInput
+	}
+	writeDocumentWithAccountScopeReturns struct {
+		result1 interface{}
+		result2 error
+	}
+	writeDocumentWithAccountScopeReturnsOnCall map[int]struct {
+		result1 interface{}
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -1062,6 +1105,70 @@ func (fake *FakeNewRelicAlertsClient) DeleteConditionMutationReturnsOnCall(i int
 	}{result1, result2}
 }
 
+func (fake *FakeNewRelicAlertsClient) DeleteDocumentWithAccountScope(arg1 int, arg2 nerdstorage.DeleteDocumentInput) (bool, error) {
+	fake.deleteDocumentWithAccountScopeMutex.Lock()
+	ret, specificReturn := fake.deleteDocumentWithAccountScopeReturnsOnCall[len(fake.deleteDocumentWithAccountScopeArgsForCall)]
+	fake.deleteDocumentWithAccountScopeArgsForCall = append(fake.deleteDocumentWithAccountScopeArgsForCall, struct {
+		arg1 int
+		arg2 nerdstorage.DeleteDocumentInput
+	}{arg1, arg2})
+	fake.recordInvocation("DeleteDocumentWithAccountScope", []interface{}{arg1, arg2})
+	fake.deleteDocumentWithAccountScopeMutex.Unlock()
+	if fake.DeleteDocumentWithAccountScopeStub != nil {
+		return fake.DeleteDocumentWithAccountScopeStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.deleteDocumentWithAccountScopeReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeNewRelicAlertsClient) DeleteDocumentWithAccountScopeCallCount() int {
+	fake.deleteDocumentWithAccountScopeMutex.RLock()
+	defer fake.deleteDocumentWithAccountScopeMutex.RUnlock()
+	return len(fake.deleteDocumentWithAccountScopeArgsForCall)
+}
+
+func (fake *FakeNewRelicAlertsClient) DeleteDocumentWithAccountScopeCalls(stub func(int, nerdstorage.DeleteDocumentInput) (bool, error)) {
+	fake.deleteDocumentWithAccountScopeMutex.Lock()
+	defer fake.deleteDocumentWithAccountScopeMutex.Unlock()
+	fake.DeleteDocumentWithAccountScopeStub = stub
+}
+
+func (fake *FakeNewRelicAlertsClient) DeleteDocumentWithAccountScopeArgsForCall(i int) (int, nerdstorage.DeleteDocumentInput) {
+	fake.deleteDocumentWithAccountScopeMutex.RLock()
+	defer fake.deleteDocumentWithAccountScopeMutex.RUnlock()
+	argsForCall := fake.deleteDocumentWithAccountScopeArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeNewRelicAlertsClient) DeleteDocumentWithAccountScopeReturns(result1 bool, result2 error) {
+	fake.deleteDocumentWithAccountScopeMutex.Lock()
+	defer fake.deleteDocumentWithAccountScopeMutex.Unlock()
+	fake.DeleteDocumentWithAccountScopeStub = nil
+	fake.deleteDocumentWithAccountScopeReturns = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeNewRelicAlertsClient) DeleteDocumentWithAccountScopeReturnsOnCall(i int, result1 bool, result2 error) {
+	fake.deleteDocumentWithAccountScopeMutex.Lock()
+	defer fake.deleteDocumentWithAccountScopeMutex.Unlock()
+	fake.DeleteDocumentWithAccountScopeStub = nil
+	if fake.deleteDocumentWithAccountScopeReturnsOnCall == nil {
+		fake.deleteDocumentWithAccountScopeReturnsOnCall = make(map[int]struct {
+			result1 bool
+			result2 error
+		})
+	}
+	fake.deleteDocumentWithAccountScopeReturnsOnCall[i] = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeNewRelicAlertsClient) DeleteNrqlCondition(arg1 int) (*alerts.NrqlCondition, error) {
 	fake.deleteNrqlConditionMutex.Lock()
 	ret, specificReturn := fake.deleteNrqlConditionReturnsOnCall[len(fake.deleteNrqlConditionArgsForCall)]
@@ -1316,6 +1423,70 @@ func (fake *FakeNewRelicAlertsClient) DeletePolicyMutationReturnsOnCall(i int, r
 	}{result1, result2}
 }
 
+func (fake *FakeNewRelicAlertsClient) GetDocumentWithAccountScope(arg1 int, arg2 nerdstorage.GetDocumentInput) (interface{}, error) {
+	fake.getDocumentWithAccountScopeMutex.Lock()
+	ret, specificReturn := fake.getDocumentWithAccountScopeReturnsOnCall[len(fake.getDocumentWithAccountScopeArgsForCall)]
+	fake.getDocumentWithAccountScopeArgsForCall = append(fake.getDocumentWithAccountScopeArgsForCall, struct {
+		arg1 int
+		arg2 nerdstorage.GetDocumentInput
+	}{arg1, arg2})
+	fake.recordInvocation("GetDocumentWithAccountScope", []interface{}{arg1, arg2})
+	fake.getDocumentWithAccountScopeMutex.Unlock()
+	if fake.GetDocumentWithAccountScopeStub != nil {
+		return fake.GetDocumentWithAccountScopeStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getDocumentWithAccountScopeReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeNewRelicAlertsClient) GetDocumentWithAccountScopeCallCount() int {
+	fake.getDocumentWithAccountScopeMutex.RLock()
+	defer fake.getDocumentWithAccountScopeMutex.RUnlock()
+	return len(fake.getDocumentWithAccountScopeArgsForCall)
+}
+
+func (fake *FakeNewRelicAlertsClient) GetDocumentWithAccountScopeCalls(stub func(int, nerdstorage.GetDocumentInput) (interface{}, error)) {
+	fake.getDocumentWithAccountScopeMutex.Lock()
+	defer fake.getDocumentWithAccountScopeMutex.Unlock()
+	fake.GetDocumentWithAccountScopeStub = stub
+}
+
+func (fake *FakeNewRelicAlertsClient) GetDocumentWithAccountScopeArgsForCall(i int) (int, nerdstorage.GetDocumentInput) {
+	fake.getDocumentWithAccountScopeMutex.RLock()
+	defer fake.getDocumentWithAccountScopeMutex.RUnlock()
+	argsForCall := fake.getDocumentWithAccountScopeArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeNewRelicAlertsClient) GetDocumentWithAccountScopeReturns(result1 interface{}, result2 error) {
+	fake.getDocumentWithAccountScopeMutex.Lock()
+	defer fake.getDocumentWithAccountScopeMutex.Unlock()
+	fake.GetDocumentWithAccountScopeStub = nil
+	fake.getDocumentWithAccountScopeReturns = struct {
+		result1 interface{}
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeNewRelicAlertsClient) GetDocumentWithAccountScopeReturnsOnCall(i int, result1 interface{}, result2 error) {
+	fake.getDocumentWithAccountScopeMutex.Lock()
+	defer fake.getDocumentWithAccountScopeMutex.Unlock()
+	fake.GetDocumentWithAccountScopeStub = nil
+	if fake.getDocumentWithAccountScopeReturnsOnCall == nil {
+		fake.getDocumentWithAccountScopeReturnsOnCall = make(map[int]struct {
+			result1 interface{}
+			result2 error
+		})
+	}
+	fake.getDocumentWithAccountScopeReturnsOnCall[i] = struct {
+		result1 interface{}
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeNewRelicAlertsClient) GetNrqlConditionQuery(arg1 int, arg2 string) (*alerts.NrqlAlertCondition, error) {
 	fake.getNrqlConditionQueryMutex.Lock()
 	ret, specificReturn := fake.getNrqlConditionQueryReturnsOnCall[len(fake.getNrqlConditionQueryArgsForCall)]
@@ -2332,6 +2503,70 @@ func (fake *FakeNewRelicAlertsClient) UpdatePolicyMutationReturnsOnCall(i int, r
 	}{result1, result2}
 }
 
+func (fake *FakeNewRelicAlertsClient) WriteDocumentWithAccountScope(arg1 int, arg2 nerdstorage.WriteDocumentInput) (interface{}, error) {
+	fake.writeDocumentWithAccountScopeMutex.Lock()
+	ret, specificReturn := fake.writeDocumentWithAccountScopeReturnsOnCall[len(fake.writeDocumentWithAccountScopeArgsForCall)]
+	fake.writeDocumentWithAccountScopeArgsForCall = append(fake.writeDocumentWithAccountScopeArgsForCall, struct {
+		arg1 int
+		arg2 nerdstorage.WriteDocumentInput
+	}{arg1, arg2})
+	fake.recordInvocation("WriteDocumentWithAccountScope", []interface{}{arg1, arg2})
+	fake.writeDocumentWithAccountScopeMutex.Unlock()
+	if fake.WriteDocumentWithAccountScopeStub != nil {
+		return fake.WriteDocumentWithAccountScopeStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.writeDocumentWithAccountScopeReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeNewRelicAlertsClient) WriteDocumentWithAccountScopeCallCount() int {
+	fake.writeDocumentWithAccountScopeMutex.RLock()
+	defer fake.writeDocumentWithAccountScopeMutex.RUnlock()
+	return len(fake.writeDocumentWithAccountScopeArgsForCall)
+}
+
+func (fake *FakeNewRelicAlertsClient) WriteDocumentWithAccountScopeCalls(stub func(int, nerdstorage.WriteDocumentInput) (interface{}, error)) {
+	fake.writeDocumentWithAccountScopeMutex.Lock()
+	defer fake.writeDocumentWithAccountScopeMutex.Unlock()
+	fake.WriteDocumentWithAccountScopeStub = stub
+}
+
+func (fake *FakeNewRelicAlertsClient) WriteDocumentWithAccountScopeArgsForCall(i int) (int, nerdstorage.WriteDocumentInput) {
+	fake.writeDocumentWithAccountScopeMutex.RLock()
+	defer fake.writeDocumentWithAccountScopeMutex.RUnlock()
+	argsForCall := fake.writeDocumentWithAccountScopeArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeNewRelicAlertsClient) WriteDocumentWithAccountScopeReturns(result1 interface{}, result2 error) {
+	fake.writeDocumentWithAccountScopeMutex.Lock()
+	defer fake.writeDocumentWithAccountScopeMutex.Unlock()
+	fake.WriteDocumentWithAccountScopeStub = nil
+	fake.writeDocumentWithAccountScopeReturns = struct {
+		result1 interface{}
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeNewRelicAlertsClient) WriteDocumentWithAccountScopeReturnsOnCall(i int, result1 interface{}, result2 error) {
+	fake.writeDocumentWithAccountScopeMutex.Lock()
+	defer fake.writeDocumentWithAccountScopeMutex.Unlock()
+	fake.WriteDocumentWithAccountScopeStub = nil
+	if fake.writeDocumentWithAccountScopeReturnsOnCall == nil {
+		fake.writeDocumentWithAccountScopeReturnsOnCall = make(map[int]struct {
+			result1 interface{}
+			result2 error
+		})
+	}
+	fake.writeDocumentWithAccountScopeReturnsOnCall[i] = struct {
+		result1 interface{}
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeNewRelicAlertsClient) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -2355,6 +2590,8 @@ func (fake *FakeNewRelicAlertsClient) Invocations() map[string][][]interface{} {
 	defer fake.deleteConditionMutex.RUnlock()
 	fake.deleteConditionMutationMutex.RLock()
 	defer fake.deleteConditionMutationMutex.RUnlock()
+	fake.deleteDocumentWithAccountScopeMutex.RLock()
+	defer fake.deleteDocumentWithAccountScopeMutex.RUnlock()
 	fake.deleteNrqlConditionMutex.RLock()
 	defer fake.deleteNrqlConditionMutex.RUnlock()
 	fake.deletePolicyMutex.RLock()
@@ -2363,6 +2600,8 @@ func (fake *FakeNewRelicAlertsClient) Invocations() map[string][][]interface{} {
 	defer fake.deletePolicyChannelMutex.RUnlock()
 	fake.deletePolicyMutationMutex.RLock()
 	defer fake.deletePolicyMutationMutex.RUnlock()
+	fake.getDocumentWithAccountScopeMutex.RLock()
+	defer fake.getDocumentWithAccountScopeMutex.RUnlock()
 	fake.getNrqlConditionQueryMutex.RLock()
 	defer fake.getNrqlConditionQueryMutex.RUnlock()
 	fake.getPolicyMutex.RLock()
@@ -2395,6 +2634,8 @@ func (fake *FakeNewRelicAlertsClient) Invocations() map[string][][]interface{} {
 	defer fake.updatePolicyChannelsMutex.RUnlock()
 	fake.updatePolicyMutationMutex.RLock()
 	defer fake.updatePolicyMutationMutex.RUnlock()
+	fake.writeDocumentWithAccountScopeMutex.RLock()
+	defer fake.writeDocumentWithAccountScopeMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
@@ -2,10 +2,12 @@ package interfaces
 
 import (
 	"fmt"
+	"net/http"
 
 	"github.com/newrelic/newrelic-client-go/newrelic"
 	"github.com/newrelic/newrelic-client-go/pkg/alerts"
 	"github.com/newrelic/newrelic-client-go/pkg/config"
+	"github.com/newrelic/newrelic-client-go/pkg/nerdstorage"
 
 	"github.com/newrelic/newrelic-kubernetes-operator/internal/info"
 )
@@ -45,19 +47,57 @@ type NewRelicAlertsClient interface {
 	DeleteConditionMutation(accountID int, conditionID string) (string, error)
 	SearchNrqlConditionsQuery(accountID int, searchCriteria alerts.NrqlConditionsSearchCriteria) ([]*alerts.NrqlAlertCondition, error)
 	GetNrqlConditionQuery(accountID int, conditionID string) (*alerts.NrqlAlertCondition, error)
+
+	// NerdStorage
+	GetDocumentWithAccountScope(accountID int, input nerdstorage.GetDocumentInput) (interface{}, error)
+	WriteDocumentWithAccountScope(accountID int, input nerdstorage.WriteDocumentInput) (interface{}, error)
+	DeleteDocumentWithAccountScope(accountID int, input nerdstorage.DeleteDocumentInput) (bool, error)
+}
+
+// newRelicClient is the NewRelicAlertsClient of a New Relic client.
+type newRelicClient struct {
+	*alerts.Alerts
+	*nerdstorage.NerdStorage
+}
+
+// ClientConfig is what a New Relic client is created with.
+type ClientConfig struct {
+	APIKey string
+	Region string
+	// RestURL and NerdGraphURL replace the endpoints of the region when set.
+	RestURL      string
+	NerdGraphURL string
+	// RetryOptions replace DefaultRetryOptions for the client when set.
+	RetryOptions *RetryOptions
 }
 
 func NewClient(apiKey string, regionValue string) (*newrelic.NewRelic, error) {
+	return NewClientWithConfig(ClientConfig{APIKey: apiKey, Region: regionValue})
+}
+
+// NewClientWithConfig returns a New Relic client for clientConfig. Its requests are limited by
+// DefaultRateLimiter.
+func NewClientWithConfig(clientConfig ClientConfig) (*newrelic.NewRelic, error) {
 	cfg := config.New()
 
-	client, err := newrelic.New(
-		newrelic.ConfigPersonalAPIKey(apiKey),
+	opts := []newrelic.ConfigOption{
+		newrelic.ConfigHTTPTransport(DefaultRateLimiter.Transport(http.DefaultTransport)),
+		newrelic.ConfigPersonalAPIKey(clientConfig.APIKey),
 		newrelic.ConfigLogLevel(cfg.LogLevel),
-		newrelic.ConfigRegion(regionValue),
+		newrelic.ConfigRegion(clientConfig.Region),
 		newrelic.ConfigUserAgent(info.UserAgent()),
 		newrelic.ConfigServiceName(info.Name),
-	)
+	}
 
+	if clientConfig.RestURL != "" {
+		opts = append(opts, newrelic.ConfigBaseURL(clientConfig.RestURL))
+	}
+
+	if clientConfig.NerdGraphURL != "" {
+		opts = append(opts, newrelic.ConfigNerdGraphBaseURL(clientConfig.NerdGraphURL))
+	}
+
+	client, err := newrelic.New(opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -66,12 +106,23 @@ func NewClient(apiKey string, regionValue string) (*newrelic.NewRelic, error) {
 }
 
 func InitializeAlertsClient(apiKey string, regionName string) (NewRelicAlertsClient, error) {
-	client, err := NewClient(apiKey, regionName)
+	return InitializeAlertsClientWithConfig(ClientConfig{APIKey: apiKey, Region: regionName})
+}
+
+// InitializeAlertsClientWithConfig returns an alerts client for clientConfig, with its calls
+// recorded in the API metrics and retried with its RetryOptions, and its errors typed.
+func InitializeAlertsClientWithConfig(clientConfig ClientConfig) (NewRelicAlertsClient, error) {
+	client, err := NewClientWithConfig(clientConfig)
 	if err != nil {
 		return nil, fmt.Errorf("unable to create New Relic client with error: %s", err)
 	}
 
-	return &client.Alerts, nil
+	retryOptions := DefaultRetryOptions()
+	if clientConfig.RetryOptions != nil {
+		retryOptions = *clientConfig.RetryOptions
+	}
+
+	return WithRetries(WithMetrics(WithTypedErrors(&newRelicClient{Alerts: &client.Alerts, NerdStorage: &client.NerdStorage})), DefaultRateLimiter, retryOptions), nil
 }
 
 //PartialAPIKey - Returns a partial API key to ensure we don't log the full API Key
@@ -0,0 +1,147 @@
+package interfaces
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// DefaultRateLimiter is shared by all clients created with NewClientWithConfig, so the limit
+// applies to the operator as a whole rather than to each controller or API key.
+var DefaultRateLimiter = NewRateLimiter(10, 20)
+
+// RateLimiter is a token bucket for the requests sent to the New Relic APIs. When New Relic
+// asks for requests to be slowed down with a Retry-After header, all requests are held back
+// until that time has passed.
+type RateLimiter struct {
+	limiter *rate.Limiter
+
+	mu          sync.Mutex
+	pausedUntil time.Time
+}
+
+// NewRateLimiter returns a limiter allowing requestsPerSecond requests with bursts of up to
+// burst requests. A requestsPerSecond of 0 or less disables the limit.
+func NewRateLimiter(requestsPerSecond float64, burst int) *RateLimiter {
+	l := &RateLimiter{limiter: rate.NewLimiter(rate.Inf, 0)}
+	l.SetLimit(requestsPerSecond, burst)
+
+	return l
+}
+
+// SetLimit changes the rate and burst of the limiter.
+func (l *RateLimiter) SetLimit(requestsPerSecond float64, burst int) {
+	if requestsPerSecond <= 0 {
+		l.limiter.SetLimit(rate.Inf)
+		return
+	}
+
+	if burst < 1 {
+		burst = 1
+	}
+
+	l.limiter.SetBurst(burst)
+	l.limiter.SetLimit(rate.Limit(requestsPerSecond))
+}
+
+// Wait blocks until a request may be sent or ctx is done.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	if paused := l.Paused(); paused > 0 {
+		timer := time.NewTimer(paused)
+		defer timer.Stop()
+
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	return l.limiter.Wait(ctx)
+}
+
+// PauseFor holds back all requests for d, unless they are held back for longer already.
+func (l *RateLimiter) PauseFor(d time.Duration) {
+	until := time.Now().Add(d)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if until.After(l.pausedUntil) {
+		l.pausedUntil = until
+	}
+}
+
+// Paused returns how much longer requests are held back for.
+func (l *RateLimiter) Paused() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if paused := time.Until(l.pausedUntil); paused > 0 {
+		return paused
+	}
+
+	return 0
+}
+
+// Transport returns a RoundTripper that waits for the limiter before sending each request with
+// next, and pauses the limiter when a response comes with a Retry-After header.
+// Every request counts, including the ones the New Relic client retries on its own.
+func (l *RateLimiter) Transport(next http.RoundTripper) http.RoundTripper {
+	return &rateLimitedTransport{next: next, limiter: l}
+}
+
+type rateLimitedTransport struct {
+	next    http.RoundTripper
+	limiter *RateLimiter
+}
+
+func (t *rateLimitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.limiter.Wait(req.Context()); err != nil {
+		return nil, err
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable {
+		if wait, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+			t.limiter.PauseFor(wait)
+		}
+	}
+
+	return resp, nil
+}
+
+// parseRetryAfter returns the wait a Retry-After header asks for. The header holds either a
+// number of seconds or an HTTP date.
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	date, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+
+	if wait := date.Sub(now); wait > 0 {
+		return wait, true
+	}
+
+	return 0, true
+}
@@ -0,0 +1,482 @@
+package interfaces
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/newrelic/newrelic-client-go/pkg/alerts"
+	nrErrors "github.com/newrelic/newrelic-client-go/pkg/errors"
+	"github.com/newrelic/newrelic-client-go/pkg/nerdstorage"
+
+	customErrors "github.com/newrelic/newrelic-kubernetes-operator/errors"
+)
+
+// RetryOptions configure how often and how long the calls of a client are retried.
+type RetryOptions struct {
+	// MaxRetries is the number of times a failed call is retried before giving up.
+	MaxRetries int
+	// MinBackoff is the wait before the first retry, it doubles with every retry.
+	MinBackoff time.Duration
+	// MaxBackoff is the longest a call is waited for. Calls that would have to wait longer,
+	// for example because of a Retry-After header, are not retried but returned as a
+	// RetryableError for the caller to try again later.
+	MaxBackoff time.Duration
+}
+
+// DefaultRetryOptions returns the options the clients are retried with unless ClientConfig or the
+// client pool they come from set others.
+func DefaultRetryOptions() RetryOptions {
+	return RetryOptions{
+		MaxRetries: 3,
+		MinBackoff: time.Second,
+		MaxBackoff: 30 * time.Second,
+	}
+}
+
+// RetryableError is returned for a call that failed with an error New Relic may recover from,
+// once the retries are used up.
+type RetryableError struct {
+	Err error
+	// RetryAfter is how long to wait before trying again.
+	RetryAfter time.Duration
+}
+
+func (e *RetryableError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *RetryableError) Unwrap() error {
+	return e.Err
+}
+
+// RetryAfter returns how long to wait before trying a failed call again, or false if the
+// call should not be retried as it is.
+func RetryAfter(err error) (time.Duration, bool) {
+	var retryable *RetryableError
+	if errors.As(err, &retryable) {
+		return retryable.RetryAfter, true
+	}
+
+	return 0, false
+}
+
+// IsRetryable returns true for errors New Relic may recover from: rate limiting, server errors,
+// and connections that failed or timed out. Errors about the request itself are terminal.
+// 500 responses are terminal too, as the New Relic APIs use them for validation errors, and so are
+// the errors the client library already retried and gave up on with MaxRetriesReached.
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var retryable *RetryableError
+	if errors.As(err, &retryable) {
+		return true
+	}
+
+	var maxRetries *nrErrors.MaxRetriesReached
+	if errors.As(err, &maxRetries) {
+		return false
+	}
+
+	switch {
+	case customErrors.IsRateLimited(err):
+		return true
+	case customErrors.IsNotFound(err), customErrors.IsTerminal(err):
+		return false
+	}
+
+	if status, ok := customErrors.HTTPStatus(err); ok {
+		return status >= http.StatusBadGateway
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+
+	// the client gives up on requests that kept failing without a response it could read
+	return strings.Contains(err.Error(), "giving up after")
+}
+
+// WithRetries returns client with the calls that fail with a retryable error retried, using a
+// jittered exponential backoff. The retries wait for limiter when New Relic asked for requests
+// to be slowed down.
+//
+// Calls that create objects are only retried when New Relic rejected the request with a 429, as
+// a create that failed in any other way may still have created the object. Those errors are returned as
+// a RetryableError right away.
+func WithRetries(client NewRelicAlertsClient, limiter *RateLimiter, opts RetryOptions) NewRelicAlertsClient {
+	return &retryingClient{
+		ctx:     context.Background(),
+		client:  client,
+		limiter: limiter,
+		opts:    opts,
+	}
+}
+
+// WithContext returns client with its waits between retries stopped when ctx is done, the call
+// is then returned as a RetryableError. Clients that don't retry are returned as they are.
+func WithContext(client NewRelicAlertsClient, ctx context.Context) NewRelicAlertsClient {
+	retrying, ok := client.(*retryingClient)
+	if !ok {
+		return client
+	}
+
+	withContext := *retrying
+	withContext.ctx = ctx
+
+	return &withContext
+}
+
+type retryingClient struct {
+	ctx     context.Context
+	client  NewRelicAlertsClient
+	limiter *RateLimiter
+	opts    RetryOptions
+}
+
+func (c *retryingClient) retry(idempotent bool, call func() error) error {
+	for attempt := 0; ; attempt++ {
+		err := call()
+		if !IsRetryable(err) {
+			return err
+		}
+
+		wait := c.backoff(attempt)
+		if paused := c.limiter.Paused(); paused > wait {
+			wait = paused
+		}
+
+		rejected := customErrors.IsRateLimited(err)
+
+		if attempt >= c.opts.MaxRetries || wait > c.opts.MaxBackoff || (!idempotent && !rejected) {
+			return &RetryableError{Err: err, RetryAfter: wait}
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-c.ctx.Done():
+			timer.Stop()
+			return &RetryableError{Err: err, RetryAfter: wait}
+		case <-timer.C:
+		}
+	}
+}
+
+// backoff returns the wait before the retry after attempt, somewhere between half and all
+// of the exponential backoff so clients that failed together don't retry together.
+func (c *retryingClient) backoff(attempt int) time.Duration {
+	backoff := c.opts.MaxBackoff
+	if attempt < 30 && c.opts.MinBackoff<<uint(attempt) < backoff {
+		backoff = c.opts.MinBackoff << uint(attempt)
+	}
+
+	if backoff <= 0 {
+		return 0
+	}
+
+	return backoff/2 + time.Duration(rand.Int63n(int64(backoff/2)+1))
+}
+
+func (c *retryingClient) CreateNrqlCondition(policyID int, nrqlCondition alerts.NrqlCondition) (result *alerts.NrqlCondition, err error) {
+	err = c.retry(false, func() error {
+		result, err = c.client.CreateNrqlCondition(policyID, nrqlCondition)
+		return err
+	})
+
+	return result, err
+}
+
+func (c *retryingClient) UpdateNrqlCondition(nrqlCondition alerts.NrqlCondition) (result *alerts.NrqlCondition, err error) {
+	err = c.retry(true, func() error {
+		result, err = c.client.UpdateNrqlCondition(nrqlCondition)
+		return err
+	})
+
+	return result, err
+}
+
+func (c *retryingClient) ListNrqlConditions(policyID int) (result []*alerts.NrqlCondition, err error) {
+	err = c.retry(true, func() error {
+		result, err = c.client.ListNrqlConditions(policyID)
+		return err
+	})
+
+	return result, err
+}
+
+func (c *retryingClient) DeleteNrqlCondition(id int) (result *alerts.NrqlCondition, err error) {
+	err = c.retry(true, func() error {
+		result, err = c.client.DeleteNrqlCondition(id)
+		return err
+	})
+
+	return result, err
+}
+
+func (c *retryingClient) ListConditions(policyID int) (result []*alerts.Condition, err error) {
+	err = c.retry(true, func() error {
+		result, err = c.client.ListConditions(policyID)
+		return err
+	})
+
+	return result, err
+}
+
+func (c *retryingClient) CreateCondition(policyID int, condition alerts.Condition) (result *alerts.Condition, err error) {
+	err = c.retry(false, func() error {
+		result, err = c.client.CreateCondition(policyID, condition)
+		return err
+	})
+
+	return result, err
+}
+
+func (c *retryingClient) UpdateCondition(condition alerts.Condition) (result *alerts.Condition, err error) {
+	err = c.retry(true, func() error {
+		result, err = c.client.UpdateCondition(condition)
+		return err
+	})
+
+	return result, err
+}
+
+func (c *retryingClient) DeleteCondition(id int) (result *alerts.Condition, err error) {
+	err = c.retry(true, func() error {
+		result, err = c.client.DeleteCondition(id)
+		return err
+	})
+
+	return result, err
+}
+
+func (c *retryingClient) GetPolicy(id int) (result *alerts.Policy, err error) {
+	err = c.retry(true, func() error {
+		result, err = c.client.GetPolicy(id)
+		return err
+	})
+
+	return result, err
+}
+
+func (c *retryingClient) CreatePolicy(policy alerts.Policy) (result *alerts.Policy, err error) {
+	err = c.retry(false, func() error {
+		result, err = c.client.CreatePolicy(policy)
+		return err
+	})
+
+	return result, err
+}
+
+func (c *retryingClient) UpdatePolicy(policy alerts.Policy) (result *alerts.Policy, err error) {
+	err = c.retry(true, func() error {
+		result, err = c.client.UpdatePolicy(policy)
+		return err
+	})
+
+	return result, err
+}
+
+func (c *retryingClient) DeletePolicy(id int) (result *alerts.Policy, err error) {
+	err = c.retry(true, func() error {
+		result, err = c.client.DeletePolicy(id)
+		return err
+	})
+
+	return result, err
+}
+
+func (c *retryingClient) ListPolicies(params *alerts.ListPoliciesParams) (result []alerts.Policy, err error) {
+	err = c.retry(true, func() error {
+		result, err = c.client.ListPolicies(params)
+		return err
+	})
+
+	return result, err
+}
+
+func (c *retryingClient) CreateChannel(channel alerts.Channel) (result *alerts.Channel, err error) {
+	err = c.retry(false, func() error {
+		result, err = c.client.CreateChannel(channel)
+		return err
+	})
+
+	return result, err
+}
+
+func (c *retryingClient) DeleteChannel(id int) (result *alerts.Channel, err error) {
+	err = c.retry(true, func() error {
+		result, err = c.client.DeleteChannel(id)
+		return err
+	})
+
+	return result, err
+}
+
+func (c *retryingClient) ListChannels() (result []*alerts.Channel, err error) {
+	err = c.retry(true, func() error {
+		result, err = c.client.ListChannels()
+		return err
+	})
+
+	return result, err
+}
+
+func (c *retryingClient) UpdatePolicyChannels(policyID int, channelIDs []int) (result *alerts.PolicyChannels, err error) {
+	err = c.retry(true, func() error {
+		result, err = c.client.UpdatePolicyChannels(policyID, channelIDs)
+		return err
+	})
+
+	return result, err
+}
+
+func (c *retryingClient) DeletePolicyChannel(policyID int, channelID int) (result *alerts.Channel, err error) {
+	err = c.retry(true, func() error {
+		result, err = c.client.DeletePolicyChannel(policyID, channelID)
+		return err
+	})
+
+	return result, err
+}
+
+func (c *retryingClient) CreatePolicyMutation(accountID int, policy alerts.AlertsPolicyInput) (result *alerts.AlertsPolicy, err error) {
+	err = c.retry(false, func() error {
+		result, err = c.client.CreatePolicyMutation(accountID, policy)
+		return err
+	})
+
+	return result, err
+}
+
+func (c *retryingClient) UpdatePolicyMutation(accountID int, policyID string, policy alerts.AlertsPolicyUpdateInput) (result *alerts.AlertsPolicy, err error) {
+	err = c.retry(true, func() error {
+		result, err = c.client.UpdatePolicyMutation(accountID, policyID, policy)
+		return err
+	})
+
+	return result, err
+}
+
+func (c *retryingClient) DeletePolicyMutation(accountID int, id string) (result *alerts.AlertsPolicy, err error) {
+	err = c.retry(true, func() error {
+		result, err = c.client.DeletePolicyMutation(accountID, id)
+		return err
+	})
+
+	return result, err
+}
+
+func (c *retryingClient) QueryPolicySearch(accountID int, params alerts.AlertsPoliciesSearchCriteriaInput) (result []*alerts.AlertsPolicy, err error) {
+	err = c.retry(true, func() error {
+		result, err = c.client.QueryPolicySearch(accountID, params)
+		return err
+	})
+
+	return result, err
+}
+
+func (c *retryingClient) QueryPolicy(accountID int, id string) (result *alerts.AlertsPolicy, err error) {
+	err = c.retry(true, func() error {
+		result, err = c.client.QueryPolicy(accountID, id)
+		return err
+	})
+
+	return result, err
+}
+
+func (c *retryingClient) CreateNrqlConditionStaticMutation(accountID int, policyID string, nrqlCondition alerts.NrqlConditionInput) (result *alerts.NrqlAlertCondition, err error) {
+	err = c.retry(false, func() error {
+		result, err = c.client.CreateNrqlConditionStaticMutation(accountID, policyID, nrqlCondition)
+		return err
+	})
+
+	return result, err
+}
+
+func (c *retryingClient) UpdateNrqlConditionStaticMutation(accountID int, conditionID string, nrqlCondition alerts.NrqlConditionInput) (result *alerts.NrqlAlertCondition, err error) {
+	err = c.retry(true, func() error {
+		result, err = c.client.UpdateNrqlConditionStaticMutation(accountID, conditionID, nrqlCondition)
+		return err
+	})
+
+	return result, err
+}
+
+func (c *retryingClient) CreateNrqlConditionBaselineMutation(accountID int, policyID string, nrqlCondition alerts.NrqlConditionInput) (result *alerts.NrqlAlertCondition, err error) {
+	err = c.retry(false, func() error {
+		result, err = c.client.CreateNrqlConditionBaselineMutation(accountID, policyID, nrqlCondition)
+		return err
+	})
+
+	return result, err
+}
+
+func (c *retryingClient) UpdateNrqlConditionBaselineMutation(accountID int, conditionID string, nrqlCondition alerts.NrqlConditionInput) (result *alerts.NrqlAlertCondition, err error) {
+	err = c.retry(true, func() error {
+		result, err = c.client.UpdateNrqlConditionBaselineMutation(accountID, conditionID, nrqlCondition)
+		return err
+	})
+
+	return result, err
+}
+
+func (c *retryingClient) DeleteConditionMutation(accountID int, conditionID string) (result string, err error) {
+	err = c.retry(true, func() error {
+		result, err = c.client.DeleteConditionMutation(accountID, conditionID)
+		return err
+	})
+
+	return result, err
+}
+
+func (c *retryingClient) SearchNrqlConditionsQuery(accountID int, searchCriteria alerts.NrqlConditionsSearchCriteria) (result []*alerts.NrqlAlertCondition, err error) {
+	err = c.retry(true, func() error {
+		result, err = c.client.SearchNrqlConditionsQuery(accountID, searchCriteria)
+		return err
+	})
+
+	return result, err
+}
+
+func (c *retryingClient) GetNrqlConditionQuery(accountID int, conditionID string) (result *alerts.NrqlAlertCondition, err error) {
+	err = c.retry(true, func() error {
+		result, err = c.client.GetNrqlConditionQuery(accountID, conditionID)
+		return err
+	})
+
+	return result, err
+}
+
+func (c *retryingClient) GetDocumentWithAccountScope(accountID int, input nerdstorage.GetDocumentInput) (result interface{}, err error) {
+	err = c.retry(true, func() error {
+		result, err = c.client.GetDocumentWithAccountScope(accountID, input)
+		return err
+	})
+
+	return result, err
+}
+
+func (c *retryingClient) WriteDocumentWithAccountScope(accountID int, input nerdstorage.WriteDocumentInput) (result interface{}, err error) {
+	err = c.retry(true, func() error {
+		result, err = c.client.WriteDocumentWithAccountScope(accountID, input)
+		return err
+	})
+
+	return result, err
+}
+
+func (c *retryingClient) DeleteDocumentWithAccountScope(accountID int, input nerdstorage.DeleteDocumentInput) (result bool, err error) {
+	err = c.retry(true, func() error {
+		result, err = c.client.DeleteDocumentWithAccountScope(accountID, input)
+		return err
+	})
+
+	return result, err
+}
@@ -0,0 +1,150 @@
+package interfaces_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/newrelic/newrelic-client-go/pkg/alerts"
+	nrErrors "github.com/newrelic/newrelic-client-go/pkg/errors"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/newrelic/newrelic-kubernetes-operator/interfaces"
+	"github.com/newrelic/newrelic-kubernetes-operator/interfaces/interfacesfakes"
+)
+
+var _ = Describe("WithRetries", func() {
+	var (
+		fake    *interfacesfakes.FakeNewRelicAlertsClient
+		limiter *interfaces.RateLimiter
+		client  interfaces.NewRelicAlertsClient
+	)
+
+	BeforeEach(func() {
+		fake = &interfacesfakes.FakeNewRelicAlertsClient{}
+		limiter = interfaces.NewRateLimiter(0, 0)
+		client = interfaces.WithRetries(fake, limiter, interfaces.RetryOptions{
+			MaxRetries: 2,
+			MinBackoff: time.Millisecond,
+			MaxBackoff: 10 * time.Millisecond,
+		})
+	})
+
+	It("retries calls that failed with a retryable error", func() {
+		fake.GetPolicyReturnsOnCall(0, nil, nrErrors.NewUnexpectedStatusCode(503, "unavailable"))
+		fake.GetPolicyReturnsOnCall(1, &alerts.Policy{ID: 42}, nil)
+
+		policy, err := client.GetPolicy(42)
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(policy.ID).To(Equal(42))
+		Expect(fake.GetPolicyCallCount()).To(Equal(2))
+	})
+
+	It("returns terminal errors right away", func() {
+		fake.UpdatePolicyReturns(nil, nrErrors.NewUnexpectedStatusCode(400, "invalid policy"))
+
+		_, err := client.UpdatePolicy(alerts.Policy{})
+
+		Expect(err).To(HaveOccurred())
+		Expect(interfaces.IsRetryable(err)).To(BeFalse())
+		Expect(fake.UpdatePolicyCallCount()).To(Equal(1))
+	})
+
+	It("gives up after the retries with an error that says when to try again", func() {
+		cause := nrErrors.NewUnexpectedStatusCode(429, "too many requests")
+		fake.ListChannelsReturns(nil, cause)
+
+		_, err := client.ListChannels()
+
+		Expect(fake.ListChannelsCallCount()).To(Equal(3))
+		Expect(errors.Is(err, cause)).To(BeTrue())
+
+		after, ok := interfaces.RetryAfter(err)
+		Expect(ok).To(BeTrue())
+		Expect(after).To(BeNumerically(">", 0))
+	})
+
+	It("does not retry creates that may have succeeded", func() {
+		fake.CreatePolicyReturns(nil, errors.New("POST https://api.newrelic.com/v2/alerts_policies.json giving up after 4 attempt(s)"))
+
+		_, err := client.CreatePolicy(alerts.Policy{})
+
+		Expect(fake.CreatePolicyCallCount()).To(Equal(1))
+		_, ok := interfaces.RetryAfter(err)
+		Expect(ok).To(BeTrue())
+	})
+
+	It("retries creates New Relic rejected", func() {
+		fake.CreatePolicyReturnsOnCall(0, nil, nrErrors.NewUnexpectedStatusCode(429, "too many requests"))
+		fake.CreatePolicyReturnsOnCall(1, &alerts.Policy{ID: 42}, nil)
+
+		_, err := client.CreatePolicy(alerts.Policy{})
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(fake.CreatePolicyCallCount()).To(Equal(2))
+	})
+
+	It("leaves waits longer than the backoff to the caller", func() {
+		limiter.PauseFor(time.Minute)
+		fake.ListPoliciesReturns(nil, nrErrors.NewUnexpectedStatusCode(429, "too many requests"))
+
+		_, err := client.ListPolicies(nil)
+
+		Expect(fake.ListPoliciesCallCount()).To(Equal(1))
+		after, _ := interfaces.RetryAfter(err)
+		Expect(after).To(BeNumerically(">", 50*time.Second))
+	})
+
+	It("stops waiting for a retry when the context is done", func() {
+		client = interfaces.WithRetries(fake, limiter, interfaces.RetryOptions{
+			MaxRetries: 2,
+			MinBackoff: time.Hour,
+			MaxBackoff: 2 * time.Hour,
+		})
+		fake.GetPolicyReturns(nil, nrErrors.NewUnexpectedStatusCode(503, "unavailable"))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := interfaces.WithContext(client, ctx).GetPolicy(42)
+
+		Expect(fake.GetPolicyCallCount()).To(Equal(1))
+		_, ok := interfaces.RetryAfter(err)
+		Expect(ok).To(BeTrue())
+	})
+})
+
+var _ = Describe("IsRetryable", func() {
+	It("tells retryable errors from terminal ones", func() {
+		Expect(interfaces.IsRetryable(nrErrors.NewUnexpectedStatusCode(429, ""))).To(BeTrue())
+		Expect(interfaces.IsRetryable(nrErrors.NewUnexpectedStatusCode(502, ""))).To(BeTrue())
+		Expect(interfaces.IsRetryable(nrErrors.NewUnexpectedStatusCode(500, "invalid"))).To(BeFalse())
+		Expect(interfaces.IsRetryable(nrErrors.NewMaxRetriesReached("timeout"))).To(BeFalse())
+		Expect(interfaces.IsRetryable(nrErrors.NewUnauthorizedError())).To(BeFalse())
+		Expect(interfaces.IsRetryable(nrErrors.NewNotFound(""))).To(BeFalse())
+		Expect(interfaces.IsRetryable(errors.New("Argument \"name\" has invalid value"))).To(BeFalse())
+	})
+})
+
+var _ = Describe("RateLimiter", func() {
+	It("pauses all requests when New Relic sends a Retry-After header", func() {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Retry-After", "120")
+			w.WriteHeader(http.StatusTooManyRequests)
+		}))
+		defer server.Close()
+
+		limiter := interfaces.NewRateLimiter(0, 0)
+		httpClient := &http.Client{Transport: limiter.Transport(http.DefaultTransport)}
+
+		resp, err := httpClient.Get(server.URL)
+		Expect(err).ToNot(HaveOccurred())
+		resp.Body.Close()
+
+		Expect(limiter.Paused()).To(BeNumerically(">", 110*time.Second))
+	})
+})
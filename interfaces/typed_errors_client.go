@@ -0,0 +1,190 @@
+package interfaces
+
+import (
+	"strconv"
+
+	"github.com/newrelic/newrelic-client-go/pkg/alerts"
+	"github.com/newrelic/newrelic-client-go/pkg/nerdstorage"
+
+	customErrors "github.com/newrelic/newrelic-kubernetes-operator/errors"
+)
+
+// WithTypedErrors returns client with the errors of its calls returned as a *errors.Error
+// for the resource the call was made for, when they are of a kind the errors package knows.
+// The controllers and webhooks pick how to handle a failure from the kind of the error.
+func WithTypedErrors(client NewRelicAlertsClient) NewRelicAlertsClient {
+	return &typedErrorsClient{client: client}
+}
+
+type typedErrorsClient struct {
+	client NewRelicAlertsClient
+}
+
+func wrapError(err *error, resource, id string) {
+	*err = customErrors.Wrap(*err, resource, id)
+}
+
+func (c *typedErrorsClient) CreateNrqlCondition(policyID int, nrqlCondition alerts.NrqlCondition) (result *alerts.NrqlCondition, err error) {
+	defer wrapError(&err, "nrql condition", "")
+	return c.client.CreateNrqlCondition(policyID, nrqlCondition)
+}
+
+func (c *typedErrorsClient) UpdateNrqlCondition(nrqlCondition alerts.NrqlCondition) (result *alerts.NrqlCondition, err error) {
+	defer wrapError(&err, "nrql condition", strconv.Itoa(nrqlCondition.ID))
+	return c.client.UpdateNrqlCondition(nrqlCondition)
+}
+
+func (c *typedErrorsClient) ListNrqlConditions(policyID int) (result []*alerts.NrqlCondition, err error) {
+	defer wrapError(&err, "nrql condition", "")
+	return c.client.ListNrqlConditions(policyID)
+}
+
+func (c *typedErrorsClient) DeleteNrqlCondition(id int) (result *alerts.NrqlCondition, err error) {
+	defer wrapError(&err, "nrql condition", strconv.Itoa(id))
+	return c.client.DeleteNrqlCondition(id)
+}
+
+func (c *typedErrorsClient) ListConditions(policyID int) (result []*alerts.Condition, err error) {
+	defer wrapError(&err, "condition", "")
+	return c.client.ListConditions(policyID)
+}
+
+func (c *typedErrorsClient) CreateCondition(policyID int, condition alerts.Condition) (result *alerts.Condition, err error) {
+	defer wrapError(&err, "condition", "")
+	return c.client.CreateCondition(policyID, condition)
+}
+
+func (c *typedErrorsClient) UpdateCondition(condition alerts.Condition) (result *alerts.Condition, err error) {
+	defer wrapError(&err, "condition", strconv.Itoa(condition.ID))
+	return c.client.UpdateCondition(condition)
+}
+
+func (c *typedErrorsClient) DeleteCondition(id int) (result *alerts.Condition, err error) {
+	defer wrapError(&err, "condition", strconv.Itoa(id))
+	return c.client.DeleteCondition(id)
+}
+
+func (c *typedErrorsClient) GetPolicy(id int) (result *alerts.Policy, err error) {
+	defer wrapError(&err, "policy", strconv.Itoa(id))
+	return c.client.GetPolicy(id)
+}
+
+func (c *typedErrorsClient) CreatePolicy(policy alerts.Policy) (result *alerts.Policy, err error) {
+	defer wrapError(&err, "policy", "")
+	return c.client.CreatePolicy(policy)
+}
+
+func (c *typedErrorsClient) UpdatePolicy(policy alerts.Policy) (result *alerts.Policy, err error) {
+	defer wrapError(&err, "policy", strconv.Itoa(policy.ID))
+	return c.client.UpdatePolicy(policy)
+}
+
+func (c *typedErrorsClient) DeletePolicy(id int) (result *alerts.Policy, err error) {
+	defer wrapError(&err, "policy", strconv.Itoa(id))
+	return c.client.DeletePolicy(id)
+}
+
+func (c *typedErrorsClient) ListPolicies(params *alerts.ListPoliciesParams) (result []alerts.Policy, err error) {
+	defer wrapError(&err, "policy", "")
+	return c.client.ListPolicies(params)
+}
+
+func (c *typedErrorsClient) CreateChannel(channel alerts.Channel) (result *alerts.Channel, err error) {
+	defer wrapError(&err, "channel", "")
+	return c.client.CreateChannel(channel)
+}
+
+func (c *typedErrorsClient) DeleteChannel(id int) (result *alerts.Channel, err error) {
+	defer wrapError(&err, "channel", strconv.Itoa(id))
+	return c.client.DeleteChannel(id)
+}
+
+func (c *typedErrorsClient) ListChannels() (result []*alerts.Channel, err error) {
+	defer wrapError(&err, "channel", "")
+	return c.client.ListChannels()
+}
+
+func (c *typedErrorsClient) UpdatePolicyChannels(policyID int, channelIDs []int) (result *alerts.PolicyChannels, err error) {
+	defer wrapError(&err, "policy", strconv.Itoa(policyID))
+	return c.client.UpdatePolicyChannels(policyID, channelIDs)
+}
+
+func (c *typedErrorsClient) DeletePolicyChannel(policyID int, channelID int) (result *alerts.Channel, err error) {
+	defer wrapError(&err, "channel", strconv.Itoa(channelID))
+	return c.client.DeletePolicyChannel(policyID, channelID)
+}
+
+func (c *typedErrorsClient) CreatePolicyMutation(accountID int, policy alerts.AlertsPolicyInput) (result *alerts.AlertsPolicy, err error) {
+	defer wrapError(&err, "policy", "")
+	return c.client.CreatePolicyMutation(accountID, policy)
+}
+
+func (c *typedErrorsClient) UpdatePolicyMutation(accountID int, policyID string, policy alerts.AlertsPolicyUpdateInput) (result *alerts.AlertsPolicy, err error) {
+	defer wrapError(&err, "policy", policyID)
+	return c.client.UpdatePolicyMutation(accountID, policyID, policy)
+}
+
+func (c *typedErrorsClient) DeletePolicyMutation(accountID int, id string) (result *alerts.AlertsPolicy, err error) {
+	defer wrapError(&err, "policy", id)
+	return c.client.DeletePolicyMutation(accountID, id)
+}
+
+func (c *typedErrorsClient) QueryPolicySearch(accountID int, params alerts.AlertsPoliciesSearchCriteriaInput) (result []*alerts.AlertsPolicy, err error) {
+	defer wrapError(&err, "policy", "")
+	return c.client.QueryPolicySearch(accountID, params)
+}
+
+func (c *typedErrorsClient) QueryPolicy(accountID int, id string) (result *alerts.AlertsPolicy, err error) {
+	defer wrapError(&err, "policy", id)
+	return c.client.QueryPolicy(accountID, id)
+}
+
+func (c *typedErrorsClient) CreateNrqlConditionStaticMutation(accountID int, policyID string, nrqlCondition alerts.NrqlConditionInput) (result *alerts.NrqlAlertCondition, err error) {
+	defer wrapError(&err, "nrql condition", "")
+	return c.client.CreateNrqlConditionStaticMutation(accountID, policyID, nrqlCondition)
+}
+
+func (c *typedErrorsClient) UpdateNrqlConditionStaticMutation(accountID int, conditionID string, nrqlCondition alerts.NrqlConditionInput) (result *alerts.NrqlAlertCondition, err error) {
+	defer wrapError(&err, "nrql condition", conditionID)
+	return c.client.UpdateNrqlConditionStaticMutation(accountID, conditionID, nrqlCondition)
+}
+
+func (c *typedErrorsClient) CreateNrqlConditionBaselineMutation(accountID int, policyID string, nrqlCondition alerts.NrqlConditionInput) (result *alerts.NrqlAlertCondition, err error) {
+	defer wrapError(&err, "nrql condition", "")
+	return c.client.CreateNrqlConditionBaselineMutation(accountID, policyID, nrqlCondition)
+}
+
+func (c *typedErrorsClient) UpdateNrqlConditionBaselineMutation(accountID int, conditionID string, nrqlCondition alerts.NrqlConditionInput) (result *alerts.NrqlAlertCondition, err error) {
+	defer wrapError(&err, "nrql condition", conditionID)
+	return c.client.UpdateNrqlConditionBaselineMutation(accountID, conditionID, nrqlCondition)
+}
+
+func (c *typedErrorsClient) DeleteConditionMutation(accountID int, conditionID string) (result string, err error) {
+	defer wrapError(&err, "condition", conditionID)
+	return c.client.DeleteConditionMutation(accountID, conditionID)
+}
+
+func (c *typedErrorsClient) SearchNrqlConditionsQuery(accountID int, searchCriteria alerts.NrqlConditionsSearchCriteria) (result []*alerts.NrqlAlertCondition, err error) {
+	defer wrapError(&err, "nrql condition", "")
+	return c.client.SearchNrqlConditionsQuery(accountID, searchCriteria)
+}
+
+func (c *typedErrorsClient) GetNrqlConditionQuery(accountID int, conditionID string) (result *alerts.NrqlAlertCondition, err error) {
+	defer wrapError(&err, "nrql condition", conditionID)
+	return c.client.GetNrqlConditionQuery(accountID, conditionID)
+}
+
+func (c *typedErrorsClient) GetDocumentWithAccountScope(accountID int, input nerdstorage.GetDocumentInput) (result interface{}, err error) {
+	defer wrapError(&err, "document", input.DocumentID)
+	return c.client.GetDocumentWithAccountScope(accountID, input)
+}
+
+func (c *typedErrorsClient) WriteDocumentWithAccountScope(accountID int, input nerdstorage.WriteDocumentInput) (result interface{}, err error) {
+	defer wrapError(&err, "document", input.DocumentID)
+	return c.client.WriteDocumentWithAccountScope(accountID, input)
+}
+
+func (c *typedErrorsClient) DeleteDocumentWithAccountScope(accountID int, input nerdstorage.DeleteDocumentInput) (result bool, err error) {
+	defer wrapError(&err, "document", input.DocumentID)
+	return c.client.DeleteDocumentWithAccountScope(accountID, input)
+}
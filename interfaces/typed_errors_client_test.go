@@ -0,0 +1,58 @@
+package interfaces_test
+
+import (
+	"errors"
+
+	"github.com/newrelic/newrelic-client-go/pkg/alerts"
+	nrErrors "github.com/newrelic/newrelic-client-go/pkg/errors"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	customErrors "github.com/newrelic/newrelic-kubernetes-operator/errors"
+	"github.com/newrelic/newrelic-kubernetes-operator/interfaces"
+	"github.com/newrelic/newrelic-kubernetes-operator/interfaces/interfacesfakes"
+)
+
+var _ = Describe("WithTypedErrors", func() {
+	var (
+		fake   *interfacesfakes.FakeNewRelicAlertsClient
+		client interfaces.NewRelicAlertsClient
+	)
+
+	BeforeEach(func() {
+		fake = &interfacesfakes.FakeNewRelicAlertsClient{}
+		client = interfaces.WithTypedErrors(fake)
+	})
+
+	It("returns the errors of a known kind with the resource they are for", func() {
+		fake.QueryPolicyReturns(nil, nrErrors.NewNotFound("no alert policy found for id 42"))
+
+		_, err := client.QueryPolicy(1, "42")
+
+		var typed *customErrors.Error
+		Expect(errors.As(err, &typed)).To(BeTrue())
+		Expect(typed.Kind).To(Equal(customErrors.ErrNotFound))
+		Expect(typed.Code).To(Equal("404"))
+		Expect(typed.Resource).To(Equal("policy"))
+		Expect(typed.ID).To(Equal("42"))
+	})
+
+	It("returns other errors and results as they are", func() {
+		cause := errors.New("connection reset")
+		fake.UpdateConditionReturns(&alerts.Condition{ID: 7}, cause)
+
+		condition, err := client.UpdateCondition(alerts.Condition{ID: 7})
+
+		Expect(err).To(BeIdenticalTo(cause))
+		Expect(condition.ID).To(Equal(7))
+	})
+
+	It("does not make typed errors retryable", func() {
+		fake.UpdatePolicyReturns(nil, nrErrors.NewUnexpectedStatusCode(422, "name is required"))
+
+		_, err := client.UpdatePolicy(alerts.Policy{ID: 42})
+
+		Expect(customErrors.IsValidation(err)).To(BeTrue())
+		Expect(interfaces.IsRetryable(err)).To(BeFalse())
+	})
+})
@@ -0,0 +1,350 @@
+// Package redact masks API keys, passwords and other secrets in what the operator logs.
+//
+// Fields are recognised by name rather than by type, so every object logged through Logger
+// is covered, including the New Relic client types the controllers send to the API.
+package redact
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/go-logr/logr"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+)
+
+// Mask replaces the value of a sensitive field.
+const Mask = "REDACTED"
+
+// lastAppliedAnnotation holds the manifest an object was applied with, secrets included.
+const lastAppliedAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+
+// newRelicKey matches New Relic user, admin and insights keys and license keys.
+var newRelicKey = regexp.MustCompile(`\bNRA[AKI]-[A-Za-z0-9]{27}\b|\b[0-9a-fA-F]{36}NRAL\b`)
+
+// sensitiveNames are the normalized names of the fields and log keys whose value must not be
+// logged. They are listed one by one, a field named key or ending in key is often harmless, like
+// the key of a condition or of a label selector requirement.
+var sensitiveNames = map[string]bool{
+	"apikey":            true,
+	"adminapikey":       true,
+	"userapikey":        true,
+	"personalapikey":    true,
+	"licensekey":        true,
+	"insertkey":         true,
+	"insightsinsertkey": true,
+	"querykey":          true,
+	"servicekey":        true,
+	"integrationkey":    true,
+	"routingkey":        true,
+	"routekey":          true,
+	"password":          true,
+	"authpassword":      true,
+	"token":             true,
+	"authtoken":         true,
+	"accesstoken":       true,
+	"bearertoken":       true,
+}
+
+// channelConfiguration is the field holding the configuration of a channel, whose key field is
+// the key of a VictorOps channel.
+const channelConfiguration = "configuration"
+
+// Sensitive returns true for the names of fields and log keys whose value must not be logged,
+// for example api_key, apiKey, service_key, auth_password and auth_token.
+func Sensitive(name string) bool {
+	return sensitiveNames[normalize(name)]
+}
+
+// String masks anything in s that looks like a New Relic key.
+func String(s string) string {
+	return newRelicKey.ReplaceAllString(s, Mask)
+}
+
+// Error returns err with anything in its message that looks like a New Relic key masked.
+func Error(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	msg := String(err.Error())
+	if msg == err.Error() {
+		return err
+	}
+
+	return &redactedError{msg: msg, err: err}
+}
+
+type redactedError struct {
+	msg string
+	err error
+}
+
+func (e *redactedError) Error() string {
+	return e.msg
+}
+
+func (e *redactedError) Unwrap() error {
+	return e.err
+}
+
+// Value returns v with its sensitive fields masked. Structs, maps and slices are returned in
+// the JSON form they are logged in, other values are returned as they are.
+func Value(v interface{}) interface{} {
+	switch t := v.(type) {
+	case nil:
+		return nil
+	case string:
+		return String(t)
+	case error:
+		return Error(t)
+	case v1.Secret:
+		return secret(&t)
+	case *v1.Secret:
+		return secret(t)
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice:
+		if rv.IsNil() {
+			return v
+		}
+	case reflect.Struct, reflect.Array:
+	default:
+		return v
+	}
+
+	generic, err := toJSON(v)
+	if err != nil {
+		// Never fall back to printing v, it may be what holds the secret.
+		return fmt.Sprintf("%T", v)
+	}
+
+	return walk(generic, "")
+}
+
+// KeysAndValues returns a copy of the key value pairs of a log call with the values masked.
+func KeysAndValues(keysAndValues []interface{}) []interface{} {
+	redacted := make([]interface{}, len(keysAndValues))
+
+	for i := range keysAndValues {
+		if i%2 == 1 {
+			if key, ok := keysAndValues[i-1].(string); ok && Sensitive(key) {
+				redacted[i] = mask(keysAndValues[i])
+				continue
+			}
+
+			redacted[i] = Value(keysAndValues[i])
+			continue
+		}
+
+		redacted[i] = keysAndValues[i]
+	}
+
+	return redacted
+}
+
+func toJSON(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var generic interface{}
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return nil, err
+	}
+
+	return generic, nil
+}
+
+// walk masks the sensitive fields of v, which is held in the field named parent.
+func walk(v interface{}, parent string) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for key, value := range t {
+			switch {
+			case Sensitive(key), key == "key" && normalize(parent) == channelConfiguration:
+				t[key] = mask(value)
+			case normalize(key) == "headers":
+				t[key] = headers(value)
+			case key == lastAppliedAnnotation:
+				t[key] = embeddedJSON(value)
+			default:
+				t[key] = walk(value, key)
+			}
+		}
+
+		return t
+	case []interface{}:
+		for i := range t {
+			t[i] = walk(t[i], parent)
+		}
+
+		return t
+	case string:
+		return String(t)
+	}
+
+	return v
+}
+
+// headers masks the values of webhook channel headers, which usually authenticate the request.
+// They are either a map of header names to values or a list of ChannelHeader.
+func headers(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for key, value := range t {
+			t[key] = mask(value)
+		}
+
+		return t
+	case []interface{}:
+		for i := range t {
+			if header, ok := t[i].(map[string]interface{}); ok {
+				if value, ok := header["value"]; ok {
+					header["value"] = mask(value)
+				}
+				continue
+			}
+
+			t[i] = mask(t[i])
+		}
+
+		return t
+	}
+
+	return mask(v)
+}
+
+// embeddedJSON masks the sensitive fields of a JSON document held in a string.
+func embeddedJSON(v interface{}) interface{} {
+	s, ok := v.(string)
+	if !ok {
+		return mask(v)
+	}
+
+	var generic interface{}
+	if err := json.Unmarshal([]byte(s), &generic); err != nil {
+		return mask(v)
+	}
+
+	data, err := json.Marshal(walk(generic, ""))
+	if err != nil {
+		return mask(v)
+	}
+
+	return string(data)
+}
+
+func secret(s *v1.Secret) interface{} {
+	if s == nil {
+		return nil
+	}
+
+	generic, err := toJSON(s)
+	if err != nil {
+		return fmt.Sprintf("%T", s)
+	}
+
+	if fields, ok := generic.(map[string]interface{}); ok {
+		for _, key := range []string{"data", "stringData"} {
+			if data, ok := fields[key].(map[string]interface{}); ok {
+				for name, value := range data {
+					data[name] = mask(value)
+				}
+			}
+		}
+	}
+
+	return walk(generic, "")
+}
+
+// mask hides a value but keeps empty strings, so an unset key can still be told apart.
+func mask(v interface{}) interface{} {
+	if s, ok := v.(string); ok && s == "" {
+		return s
+	}
+
+	if v == nil {
+		return nil
+	}
+
+	return Mask
+}
+
+func normalize(name string) string {
+	return strings.ToLower(strings.NewReplacer("_", "", "-", "", " ", "").Replace(name))
+}
+
+// Logger wraps l so the values and errors of every log call are passed through Value.
+func Logger(l logr.Logger) logr.Logger {
+	return &logger{Logger: l}
+}
+
+type logger struct {
+	logr.Logger
+}
+
+func (l *logger) Info(msg string, keysAndValues ...interface{}) {
+	if !l.Logger.Enabled() {
+		return
+	}
+
+	l.Logger.Info(String(msg), KeysAndValues(keysAndValues)...)
+}
+
+func (l *logger) Error(err error, msg string, keysAndValues ...interface{}) {
+	l.Logger.Error(Error(err), String(msg), KeysAndValues(keysAndValues)...)
+}
+
+func (l *logger) V(level int) logr.InfoLogger {
+	return &infoLogger{InfoLogger: l.Logger.V(level)}
+}
+
+func (l *logger) WithValues(keysAndValues ...interface{}) logr.Logger {
+	return &logger{Logger: l.Logger.WithValues(KeysAndValues(keysAndValues)...)}
+}
+
+func (l *logger) WithName(name string) logr.Logger {
+	return &logger{Logger: l.Logger.WithName(name)}
+}
+
+type infoLogger struct {
+	logr.InfoLogger
+}
+
+func (l *infoLogger) Info(msg string, keysAndValues ...interface{}) {
+	if !l.InfoLogger.Enabled() {
+		return
+	}
+
+	l.InfoLogger.Info(String(msg), KeysAndValues(keysAndValues)...)
+}
+
+// Recorder wraps r so the message of every event is passed through String. Events usually carry
+// the error of a failed call to New Relic.
+func Recorder(r record.EventRecorder) record.EventRecorder {
+	return &recorder{EventRecorder: r}
+}
+
+type recorder struct {
+	record.EventRecorder
+}
+
+func (r *recorder) Event(object runtime.Object, eventtype, reason, message string) {
+	r.EventRecorder.Event(object, eventtype, reason, String(message))
+}
+
+func (r *recorder) Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.EventRecorder.Event(object, eventtype, reason, String(fmt.Sprintf(messageFmt, args...)))
+}
+
+func (r *recorder) AnnotatedEventf(object runtime.Object, annotations map[string]string, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.EventRecorder.AnnotatedEventf(object, annotations, eventtype, reason, "%s", String(fmt.Sprintf(messageFmt, args...)))
+}
@@ -0,0 +1,253 @@
+package redact_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/newrelic/newrelic-client-go/pkg/alerts"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	nrv1 "github.com/newrelic/newrelic-kubernetes-operator/api/v1"
+	"github.com/newrelic/newrelic-kubernetes-operator/internal/redact"
+)
+
+// sensitiveField matches the Go names of fields that must never show up in a log. The Key field
+// of a channel configuration is sensitive as well.
+var sensitiveField = regexp.MustCompile(`^(APIKey|ServiceKey|RouteKey|RoutingKey|IntegrationKey|LicenseKey|InsertKey|(Auth)?Password|(Auth)?Token)$`)
+
+// filler sets every string reachable from a value to a unique sentinel and remembers
+// which of them belong to sensitive fields.
+type filler struct {
+	sensitive []string
+	plain     []string
+}
+
+func (f *filler) fill(v reflect.Value, path string, sensitive, inHeaders bool, depth int) {
+	if depth > 10 {
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		value := "sentinel(" + path + ")"
+		v.SetString(value)
+
+		if sensitive {
+			f.sensitive = append(f.sensitive, value)
+		} else {
+			f.plain = append(f.plain, value)
+		}
+	case reflect.Ptr:
+		v.Set(reflect.New(v.Type().Elem()))
+		f.fill(v.Elem(), path, sensitive, inHeaders, depth+1)
+	case reflect.Slice:
+		v.Set(reflect.MakeSlice(v.Type(), 1, 1))
+		f.fill(v.Index(0), path+"[0]", sensitive, inHeaders, depth+1)
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return
+		}
+
+		elem := reflect.New(v.Type().Elem()).Elem()
+		if elem.Kind() == reflect.Interface {
+			value := reflect.New(reflect.TypeOf("")).Elem()
+			f.fill(value, path+".k", sensitive || inHeaders, inHeaders, depth+1)
+			elem.Set(value)
+		} else {
+			f.fill(elem, path+".k", sensitive || inHeaders, inHeaders, depth+1)
+		}
+
+		v.Set(reflect.MakeMap(v.Type()))
+		v.SetMapIndex(reflect.ValueOf("k").Convert(v.Type().Key()), elem)
+	case reflect.Struct:
+		// Kubernetes metadata is logged as it is, only the operator's own types are filled.
+		if strings.HasPrefix(v.Type().PkgPath(), "k8s.io/") {
+			return
+		}
+
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if field.PkgPath != "" {
+				continue
+			}
+
+			f.fill(v.Field(i), path+"."+field.Name,
+				sensitive || sensitiveField.MatchString(field.Name) || (inHeaders && field.Name == "Value") ||
+					(field.Name == "Key" && strings.HasSuffix(v.Type().Name(), "ChannelConfiguration")),
+				inHeaders || field.Name == "Headers",
+				depth+1,
+			)
+		}
+	}
+}
+
+func logTo(buf *bytes.Buffer) func(msg string, keysAndValues ...interface{}) {
+	logger := redact.Logger(zap.New(zap.WriteTo(buf)))
+	return logger.Info
+}
+
+var _ = Describe("Logger", func() {
+	var (
+		buf *bytes.Buffer
+		log func(msg string, keysAndValues ...interface{})
+	)
+
+	BeforeEach(func() {
+		buf = &bytes.Buffer{}
+		log = logTo(buf)
+	})
+
+	Describe("every type of the API", func() {
+		var types []reflect.Type
+
+		s := runtime.NewScheme()
+		if err := nrv1.AddToScheme(s); err != nil {
+			panic(err)
+		}
+
+		apiPackage := reflect.TypeOf(nrv1.AlertsPolicy{}).PkgPath()
+		for _, t := range s.KnownTypes(nrv1.GroupVersion) {
+			if t.PkgPath() == apiPackage {
+				types = append(types, t)
+			}
+		}
+
+		// The New Relic client types are logged as the payload of API calls.
+		types = append(types,
+			reflect.TypeOf(alerts.Channel{}),
+			reflect.TypeOf(alerts.Condition{}),
+			reflect.TypeOf(alerts.NrqlConditionInput{}),
+			reflect.TypeOf(alerts.NrqlCondition{}),
+			reflect.TypeOf(alerts.Policy{}),
+		)
+
+		for _, t := range types {
+			t := t
+
+			It(fmt.Sprintf("masks the secrets of %s", t.Name()), func() {
+				f := &filler{}
+				object := reflect.New(t)
+				f.fill(object.Elem(), t.Name(), false, false, 0)
+
+				log("object", "object", object.Interface())
+
+				for _, value := range f.sensitive {
+					Expect(buf.String()).ToNot(ContainSubstring(value))
+				}
+
+				found := false
+				for _, value := range f.plain {
+					found = found || strings.Contains(buf.String(), value)
+				}
+				Expect(found).To(BeTrue(), "nothing of %s was logged: %s", t.Name(), buf.String())
+			})
+		}
+	})
+
+	It("masks values logged under a sensitive key", func() {
+		log("creating client", "API Key", "my-api-key", "region", "US")
+
+		Expect(buf.String()).ToNot(ContainSubstring("my-api-key"))
+		Expect(buf.String()).To(ContainSubstring("US"))
+	})
+
+	It("masks New Relic keys in errors", func() {
+		logger := redact.Logger(zap.New(zap.WriteTo(buf)))
+		cause := errors.New("401 unauthorized: NRAK-ABCDEFGHIJKLMNOPQRSTUVWXYZ0")
+
+		logger.Error(cause, "failed")
+
+		Expect(buf.String()).ToNot(ContainSubstring("NRAK-ABCDEFGHIJKLMNOPQRSTUVWXYZ0"))
+		Expect(buf.String()).To(ContainSubstring("401 unauthorized"))
+		Expect(errors.Is(redact.Error(cause), cause)).To(BeTrue())
+	})
+
+	It("masks the data of secrets", func() {
+		log("secret changed", "secret", &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "new-relic"},
+			Data:       map[string][]byte{"api-key": []byte("my-api-key")},
+			StringData: map[string]string{"other": "other-value"},
+		})
+
+		Expect(buf.String()).To(ContainSubstring("new-relic"))
+		Expect(buf.String()).ToNot(ContainSubstring("other-value"))
+		Expect(buf.String()).ToNot(ContainSubstring("bXktYXBpLWtleQ"))
+	})
+
+	It("masks the manifest of the last kubectl apply", func() {
+		policy := &nrv1.AlertsPolicy{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "my-policy",
+				Annotations: map[string]string{
+					"kubectl.kubernetes.io/last-applied-configuration": `{"spec":{"api_key":"my-api-key","name":"my-policy"}}`,
+				},
+			},
+		}
+
+		log("reconciling", "policy", policy)
+
+		Expect(buf.String()).ToNot(ContainSubstring("my-api-key"))
+		Expect(buf.String()).To(ContainSubstring("my-policy"))
+	})
+
+	It("keeps the fields that are not sensitive", func() {
+		log("creating channel", "channel", nrv1.AlertsChannelSpec{
+			Name:   "my-channel",
+			APIKey: "my-api-key",
+			Type:   "webhook",
+			Configuration: nrv1.AlertsChannelConfiguration{
+				BaseURL:      "https://example.com",
+				AuthPassword: "my-password",
+				Headers:      []nrv1.ChannelHeader{{Name: "X-Token", Value: "my-header-value"}},
+			},
+		})
+
+		Expect(buf.String()).To(ContainSubstring("my-channel"))
+		Expect(buf.String()).To(ContainSubstring("https://example.com"))
+		Expect(buf.String()).To(ContainSubstring("X-Token"))
+		Expect(buf.String()).ToNot(ContainSubstring("my-api-key"))
+		Expect(buf.String()).ToNot(ContainSubstring("my-password"))
+		Expect(buf.String()).ToNot(ContainSubstring("my-header-value"))
+	})
+
+	It("keeps the keys of conditions and label selectors", func() {
+		log("reconciling", "policy", nrv1.AlertsPolicySpec{
+			Conditions: []nrv1.AlertsPolicyCondition{{Key: "my-condition-key"}},
+		}, "channel", nrv1.AlertsChannelSpec{
+			PolicySelector: &nrv1.AlertsPolicySelector{
+				LabelSelector: metav1.LabelSelector{
+					MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "my-label-key", Operator: "Exists"}},
+				},
+			},
+			Configuration: nrv1.AlertsChannelConfiguration{Key: "my-victorops-key"},
+		}, "key", "my-logged-key")
+
+		Expect(buf.String()).To(ContainSubstring("my-condition-key"))
+		Expect(buf.String()).To(ContainSubstring("my-label-key"))
+		Expect(buf.String()).To(ContainSubstring("my-logged-key"))
+		Expect(buf.String()).ToNot(ContainSubstring("my-victorops-key"))
+	})
+})
+
+var _ = Describe("Recorder", func() {
+	It("masks New Relic keys in the messages of events", func() {
+		fakeRecorder := record.NewFakeRecorder(2)
+		recorder := redact.Recorder(fakeRecorder)
+
+		recorder.Eventf(&v1.Secret{}, "Warning", "Failed", "failed to create policy: %v", errors.New("401 unauthorized: NRAK-ABCDEFGHIJKLMNOPQRSTUVWXYZ0"))
+		recorder.Event(&v1.Secret{}, "Normal", "Created", "Created New Relic policy 123")
+
+		Expect(<-fakeRecorder.Events).To(Equal("Warning Failed failed to create policy: 401 unauthorized: REDACTED"))
+		Expect(<-fakeRecorder.Events).To(Equal("Normal Created Created New Relic policy 123"))
+	})
+})
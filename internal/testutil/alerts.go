@@ -20,6 +20,14 @@ import (
 )
 
 func AlertsPolicyTestSetup(t *testing.T) client.Client {
+	k8sClient, _ := AlertsPolicyTestEnv(t)
+
+	return k8sClient
+}
+
+// AlertsPolicyTestEnv starts a test API server with the CRDs of the operator and returns a client
+// for it, and the environment to stop once the test is done with it.
+func AlertsPolicyTestEnv(t *testing.T) (client.Client, *envtest.Environment) {
 	testEnv := &envtest.Environment{
 		CRDDirectoryPaths: []string{filepath.Join("..", "config", "crd", "bases")},
 	}
@@ -39,7 +47,7 @@ func AlertsPolicyTestSetup(t *testing.T) client.Client {
 	require.NoError(t, err)
 	require.NotNil(t, k8sClient)
 
-	return k8sClient
+	return k8sClient, testEnv
 }
 
 func NewTestAlertsPolicy(t *testing.T) *nrv1.AlertsPolicy {
@@ -16,9 +16,11 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"os"
+	"time"
 
 	"k8s.io/apimachinery/pkg/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
@@ -27,7 +29,10 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
 	nrv1 "github.com/newrelic/newrelic-kubernetes-operator/api/v1"
+	"github.com/newrelic/newrelic-kubernetes-operator/controllers"
+	"github.com/newrelic/newrelic-kubernetes-operator/interfaces"
 	"github.com/newrelic/newrelic-kubernetes-operator/internal/info"
+	"github.com/newrelic/newrelic-kubernetes-operator/internal/redact"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -48,11 +53,31 @@ func main() {
 	var enableLeaderElection bool
 	var showVersion bool
 	var devMode bool
+	var alertsOpts alertsOptions
+	var apiRateLimit float64
+	var apiBurst int
+	var nameTemplate string
+	var clientPoolSize int
+	var clientPoolIdleTTL time.Duration
+	retryOptions := interfaces.DefaultRetryOptions()
 
 	flag.StringVar(&metricsAddr, "metrics-addr", ":8080", "The address the metric endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "enable-leader-election", false, "Enable leader election for controller manager. Enabling this will ensure there is only one active controller manager.")
 	flag.BoolVar(&showVersion, "version", false, "Show version information.")
 	flag.BoolVar(&devMode, "dev-mode", false, "Enable development level logging (stacktraces on warnings, no sampling)")
+	flag.DurationVar(&alertsOpts.ResyncInterval, "resync-interval", 10*time.Minute, "How often resources are compared with New Relic to detect drift. Set to 0 to disable. The legacy Policy, NrqlAlertCondition and ApmAlertCondition kinds are not checked.")
+	flag.IntVar(&alertsOpts.MaxConcurrentReconciles, "max-concurrent-reconciles", 1, "The number of resources of each kind that can be reconciled at the same time.")
+	alertsOpts.addConcurrencyFlags(flag.CommandLine)
+	flag.BoolVar(&alertsOpts.Webhooks.RejectPlaintextAPIKeys, "reject-plaintext-api-keys", false, "Reject resources with a plaintext api_key instead of moving the key into a secret.")
+	flag.Float64Var(&apiRateLimit, "api-rate-limit", 10, "The number of requests per second sent to the New Relic APIs by all controllers together. Set to 0 to disable.")
+	flag.IntVar(&apiBurst, "api-burst", 20, "The number of requests that can be sent to the New Relic APIs at once before --api-rate-limit applies.")
+	flag.IntVar(&clientPoolSize, "client-pool-size", interfaces.DefaultClientPoolSize, "The number of New Relic clients kept for reuse, the least recently used one is dropped when there are more. Set to 0 to disable.")
+	flag.DurationVar(&clientPoolIdleTTL, "client-pool-idle-ttl", interfaces.DefaultClientPoolIdleTTL, "How long a New Relic client that is not used is kept for reuse. Set to 0 to disable.")
+	flag.IntVar(&retryOptions.MaxRetries, "api-max-retries", retryOptions.MaxRetries, "The number of times a New Relic API call that failed with a retryable error is retried before the resource is requeued.")
+	flag.StringVar(&alertsOpts.Naming.ClusterName, "cluster-name", "", "The name of the cluster, used in the names of the objects in New Relic by --name-template.")
+	flag.StringVar(&nameTemplate, "name-template", controllers.DefaultNameTemplate, "The Go template the names of the objects in New Relic are rendered with, from .Cluster, .Namespace and .Name, the name in the spec. For example {{.Cluster}}/{{.Namespace}}/{{.Name}}.")
+	flag.StringVar(&alertsOpts.Ownership.PackageID, "ownership-package-id", controllers.DefaultOwnershipPackageID, "The NerdStorage package the markers of the objects managed in New Relic are kept in. All of the operators managing objects in the same accounts must use the same one.")
+	flag.BoolVar(&alertsOpts.DryRun, "dry-run", false, "Plan the writes to New Relic and record them in the status of the resources and in events instead of sending them. Deleted resources are kept, with their finalizer, until the manager runs without --dry-run.")
 	flag.Parse()
 
 	if showVersion {
@@ -60,8 +85,19 @@ func main() {
 		os.Exit(0)
 	}
 
+	interfaces.DefaultRateLimiter.SetLimit(apiRateLimit, apiBurst)
+	interfaces.DefaultClientPool.SetLimits(clientPoolSize, clientPoolIdleTTL)
+	interfaces.DefaultClientPool.SetRetryOptions(retryOptions)
+
 	logger := zap.New(zap.UseDevMode(devMode))
-	ctrl.SetLogger(logger)
+	ctrl.SetLogger(redact.Logger(logger))
+
+	tmpl, err := controllers.ParseNameTemplate(nameTemplate)
+	if err != nil {
+		setupLog.Error(err, "invalid --name-template")
+		os.Exit(1)
+	}
+	alertsOpts.Naming.Template = tmpl
 
 	opts := ctrl.Options{
 		Scheme:             scheme,
@@ -77,11 +113,18 @@ func main() {
 		os.Exit(1)
 	}
 
+	// the cache is not started yet, the objects the operator manages in New Relic are marked with the UID read directly
+	alertsOpts.Ownership.ClusterUID, err = controllers.LookupClusterUID(context.Background(), mgr.GetAPIReader())
+	if err != nil {
+		setupLog.Error(err, "unable to read the cluster UID")
+		os.Exit(1)
+	}
+
 	// initialize NR go agent
 	nrApp := InitializeNRAgent()
 
 	//Register Alerts
-	err = registerAlerts(&mgr, &nrApp)
+	err = registerAlerts(&mgr, &nrApp, alertsOpts)
 	if err != nil {
 		setupLog.Error(err, "unable to register alerts")
 		os.Exit(1)